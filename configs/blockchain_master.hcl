@@ -26,3 +26,123 @@ path "blockchain/wallets/+/create" {
 path "blockchain/wallets/+/import" {
     capabilities = [ "create", "update" ]
 }
+
+path "blockchain/config" {
+    capabilities = [ "create", "read", "update", "delete" ]
+}
+
+path "blockchain/chains/*" {
+    capabilities = [ "create", "read", "update", "delete", "list" ]
+}
+
+path "blockchain/wallets/+/policy" {
+    capabilities = [ "create", "read", "update", "delete" ]
+}
+
+path "blockchain/accounts/+/policy" {
+    capabilities = [ "create", "read", "update", "delete" ]
+}
+
+path "blockchain/wallets/+/limits" {
+    capabilities = [ "create", "read", "update", "delete" ]
+}
+
+path "blockchain/wallets/+/xpub" {
+    capabilities = [ "read" ]
+}
+
+path "blockchain/wallets/+/xpub/addresses" {
+    capabilities = [ "read" ]
+}
+
+path "blockchain/wallets/+/bitcoin/accounts/*" {
+    capabilities = [ "read" ]
+}
+
+path "blockchain/wallets/+/solana/accounts/+" {
+    capabilities = [ "read" ]
+}
+
+path "blockchain/wallets/+" {
+    capabilities = [ "read", "delete" ]
+}
+
+path "blockchain/wallets/+/restore" {
+    capabilities = [ "create", "update" ]
+}
+
+path "blockchain/wallets/+/purge" {
+    capabilities = [ "create", "update" ]
+}
+
+path "blockchain/wallets/+/backup" {
+    capabilities = [ "create", "read", "update" ]
+}
+
+path "blockchain/wallets/+/backup/restore" {
+    capabilities = [ "create", "update" ]
+}
+
+path "blockchain/wallets/+/shamir" {
+    capabilities = [ "create", "update" ]
+}
+
+path "blockchain/wallets/+/shamir/import" {
+    capabilities = [ "create", "read", "update", "delete" ]
+}
+
+path "blockchain/accounts/+" {
+    capabilities = [ "read", "delete" ]
+}
+
+path "blockchain/accounts/+/restore" {
+    capabilities = [ "create", "update" ]
+}
+
+path "blockchain/accounts/+/purge" {
+    capabilities = [ "create", "update" ]
+}
+
+path "blockchain/accounts/+/rotate" {
+    capabilities = [ "create", "update" ]
+}
+
+path "blockchain/accounts/+/keys" {
+    capabilities = [ "create", "read", "update" ]
+}
+
+path "blockchain/accounts/+/export" {
+    capabilities = [ "create", "update" ]
+}
+
+path "blockchain/addresses/+" {
+    capabilities = [ "read" ]
+}
+
+path "blockchain/tx/decode" {
+    capabilities = [ "create", "update" ]
+}
+
+path "blockchain/wallets/+/accounts/+/journal/*" {
+    capabilities = [ "read", "list" ]
+}
+
+path "blockchain/wallets/+/accounts/+/+/journal/*" {
+    capabilities = [ "read", "list" ]
+}
+
+path "blockchain/accounts/+/journal/*" {
+    capabilities = [ "read", "list" ]
+}
+
+path "blockchain/wallets/+/accounts/+/nonces/*" {
+    capabilities = [ "create", "read", "update" ]
+}
+
+path "blockchain/wallets/+/accounts/+/+/nonces/*" {
+    capabilities = [ "create", "read", "update" ]
+}
+
+path "blockchain/accounts/+/nonces/*" {
+    capabilities = [ "create", "read", "update" ]
+}
@@ -10,3 +10,68 @@ path "blockchain/accounts/{{identity.entity.name}}/*" {
     capabilities = [ "create", "read", "update", "list" ]
 }
 
+
+# Destination policies and velocity limits are managed by the master token; users may only read their own.
+path "blockchain/wallets/{{identity.entity.name}}/policy" {
+    capabilities = [ "read" ]
+}
+
+path "blockchain/wallets/{{identity.entity.name}}/limits" {
+    capabilities = [ "read" ]
+}
+
+# Deletion is managed by the master token.
+path "blockchain/wallets/{{identity.entity.name}}/restore" {
+    capabilities = [ "deny" ]
+}
+
+path "blockchain/wallets/{{identity.entity.name}}/purge" {
+    capabilities = [ "deny" ]
+}
+
+path "blockchain/wallets/{{identity.entity.name}}/backup" {
+    capabilities = [ "deny" ]
+}
+
+path "blockchain/wallets/{{identity.entity.name}}/backup/restore" {
+    capabilities = [ "deny" ]
+}
+
+path "blockchain/wallets/{{identity.entity.name}}/shamir" {
+    capabilities = [ "deny" ]
+}
+
+path "blockchain/wallets/{{identity.entity.name}}/shamir/import" {
+    capabilities = [ "deny" ]
+}
+
+path "blockchain/accounts/{{identity.entity.name}}/policy" {
+    capabilities = [ "read" ]
+}
+
+path "blockchain/accounts/{{identity.entity.name}}/restore" {
+    capabilities = [ "deny" ]
+}
+
+path "blockchain/accounts/{{identity.entity.name}}/purge" {
+    capabilities = [ "deny" ]
+}
+
+path "blockchain/accounts/{{identity.entity.name}}/rotate" {
+    capabilities = [ "deny" ]
+}
+
+path "blockchain/accounts/{{identity.entity.name}}/export" {
+    capabilities = [ "deny" ]
+}
+
+path "blockchain/accounts/{{identity.entity.name}}/keys" {
+    capabilities = [ "read" ]
+}
+
+# Sign by address through wallets/<id>/by-address/* and accounts/<name>/by-address/*, which the grants above
+# cover and which only sign for keys of the wallet or account in their path. The unscoped by-address/* reaches
+# every key in the mount and is not granted.
+
+# The JSON-RPC facades wallets/<id>/rpc and accounts/<name>/rpc fall under the grants above and only sign for
+# the wallet or account in their path.
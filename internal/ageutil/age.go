@@ -0,0 +1,373 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package ageutil
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ecdh"
+	"crypto/hkdf"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcutil/bech32"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// This file implements the age v1 file format (https://age-encryption.org/v1) for X25519 recipients only, so
+// backups can be decrypted with the standard age tools.
+
+const (
+	intro          = "age-encryption.org/v1\n"
+	stanzaPrefix   = "-> "
+	footerPrefix   = "---"
+	x25519Label    = "age-encryption.org/v1/X25519"
+	recipientHRP   = "age"
+	identityHRP    = "age-secret-key-"
+	fileKeySize    = 16
+	nonceSize      = 16
+	chunkSize      = 64 * 1024
+	columnsPerLine = 64
+)
+
+// ErrNoMatchingStanza is returned when a file has no X25519 stanza that the identity can open.
+var ErrNoMatchingStanza = errors.New("no age recipient stanza matches the identity")
+
+var b64 = base64.RawStdEncoding.Strict()
+
+// IsEncrypted reports whether ciphertext starts with the age v1 header.
+func IsEncrypted(ciphertext []byte) bool {
+	return bytes.HasPrefix(ciphertext, []byte(intro))
+}
+
+// ParseRecipient parses an age1... X25519 recipient.
+func ParseRecipient(s string) (*ecdh.PublicKey, error) {
+	key, err := decodeBech32(strings.TrimSpace(s), recipientHRP)
+	if err != nil {
+		return nil, fmt.Errorf("parse age recipient: %w", err)
+	}
+	pub, err := ecdh.X25519().NewPublicKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("parse age recipient: %w", err)
+	}
+	return pub, nil
+}
+
+// ParseIdentity parses an AGE-SECRET-KEY-1... X25519 identity.
+func ParseIdentity(s string) (*ecdh.PrivateKey, error) {
+	key, err := decodeBech32(strings.TrimSpace(s), identityHRP)
+	if err != nil {
+		return nil, fmt.Errorf("parse age identity: %w", err)
+	}
+	priv, err := ecdh.X25519().NewPrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("parse age identity: %w", err)
+	}
+	return priv, nil
+}
+
+// FormatRecipient returns the age1... encoding of an X25519 public key.
+func FormatRecipient(pub *ecdh.PublicKey) (string, error) {
+	return encodeBech32(recipientHRP, pub.Bytes())
+}
+
+// FormatIdentity returns the AGE-SECRET-KEY-1... encoding of an X25519 private key.
+func FormatIdentity(priv *ecdh.PrivateKey) (string, error) {
+	s, err := encodeBech32(identityHRP, priv.Bytes())
+	return strings.ToUpper(s), err
+}
+
+// Encrypt encrypts plaintext to recipient as a binary age file.
+func Encrypt(recipient *ecdh.PublicKey, plaintext []byte) ([]byte, error) {
+	if recipient == nil {
+		return nil, fmt.Errorf("age recipient is nil")
+	}
+	fileKey := make([]byte, fileKeySize)
+	if _, err := rand.Read(fileKey); err != nil {
+		return nil, fmt.Errorf("age file key: %w", err)
+	}
+
+	ephemeral, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("age ephemeral key: %w", err)
+	}
+	share := ephemeral.PublicKey().Bytes()
+	wrapKey, err := x25519WrapKey(ephemeral, recipient, share, recipient.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	body, err := aeadSeal(wrapKey, make([]byte, chacha20poly1305.NonceSize), fileKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var header bytes.Buffer
+	header.WriteString(intro)
+	header.WriteString(stanzaPrefix + "X25519 " + b64.EncodeToString(share) + "\n")
+	encoded := b64.EncodeToString(body)
+	for len(encoded) >= columnsPerLine {
+		header.WriteString(encoded[:columnsPerLine] + "\n")
+		encoded = encoded[columnsPerLine:]
+	}
+	header.WriteString(encoded + "\n")
+	header.WriteString(footerPrefix)
+	mac, err := headerMAC(fileKey, header.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	header.WriteString(" " + b64.EncodeToString(mac) + "\n")
+
+	nonce := make([]byte, nonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("age payload nonce: %w", err)
+	}
+	payloadKey, err := hkdf.Key(sha256.New, fileKey, nonce, "payload", chacha20poly1305.KeySize)
+	if err != nil {
+		return nil, fmt.Errorf("age payload key: %w", err)
+	}
+	out := append(header.Bytes(), nonce...)
+	for counter := uint64(0); ; counter++ {
+		n := min(chunkSize, len(plaintext))
+		last := n == len(plaintext)
+		chunk, err := aeadSeal(payloadKey, streamNonce(counter, last), plaintext[:n])
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, chunk...)
+		plaintext = plaintext[n:]
+		if last {
+			return out, nil
+		}
+	}
+}
+
+// Decrypt decrypts a binary age file with an X25519 identity.
+func Decrypt(identity *ecdh.PrivateKey, ciphertext []byte) ([]byte, error) {
+	if identity == nil {
+		return nil, fmt.Errorf("age identity is nil")
+	}
+	r := bufio.NewReader(bytes.NewReader(ciphertext))
+	line, err := readLine(r)
+	if err != nil || line+"\n" != intro {
+		return nil, fmt.Errorf("not an age v1 file")
+	}
+	headerLen := len(intro)
+
+	var fileKey []byte
+	for {
+		line, err := readLine(r)
+		if err != nil {
+			return nil, fmt.Errorf("read age header: %w", err)
+		}
+		if strings.HasPrefix(line, footerPrefix+" ") {
+			mac, err := b64.DecodeString(strings.TrimPrefix(line, footerPrefix+" "))
+			if err != nil {
+				return nil, fmt.Errorf("decode age header mac: %w", err)
+			}
+			if fileKey == nil {
+				return nil, ErrNoMatchingStanza
+			}
+			want, err := headerMAC(fileKey, ciphertext[:headerLen+len(footerPrefix)])
+			if err != nil {
+				return nil, err
+			}
+			if !hmac.Equal(mac, want) {
+				return nil, fmt.Errorf("age header mac mismatch")
+			}
+			headerLen += len(line) + 1
+			break
+		}
+		if !strings.HasPrefix(line, stanzaPrefix) {
+			return nil, fmt.Errorf("malformed age header line %q", line)
+		}
+		headerLen += len(line) + 1
+		args := strings.Split(strings.TrimPrefix(line, stanzaPrefix), " ")
+		var body []byte
+		for {
+			bodyLine, err := readLine(r)
+			if err != nil {
+				return nil, fmt.Errorf("read age stanza: %w", err)
+			}
+			headerLen += len(bodyLine) + 1
+			chunk, err := b64.DecodeString(bodyLine)
+			if err != nil || len(bodyLine) > columnsPerLine {
+				return nil, fmt.Errorf("malformed age stanza body")
+			}
+			body = append(body, chunk...)
+			if len(bodyLine) < columnsPerLine {
+				break
+			}
+		}
+		if fileKey == nil && len(args) == 2 && args[0] == "X25519" {
+			fileKey, err = unwrapX25519(identity, args[1], body)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	payload := ciphertext[headerLen:]
+	if len(payload) < nonceSize {
+		return nil, fmt.Errorf("age payload is truncated")
+	}
+	payloadKey, err := hkdf.Key(sha256.New, fileKey, payload[:nonceSize], "payload", chacha20poly1305.KeySize)
+	if err != nil {
+		return nil, fmt.Errorf("age payload key: %w", err)
+	}
+	payload = payload[nonceSize:]
+	var out []byte
+	for counter := uint64(0); ; counter++ {
+		n := min(chunkSize+chacha20poly1305.Overhead, len(payload))
+		last := n == len(payload)
+		plain, err := aeadOpen(payloadKey, streamNonce(counter, last), payload[:n])
+		if err != nil {
+			return nil, fmt.Errorf("decrypt age payload chunk %d: %w", counter, err)
+		}
+		if last && len(plain) == 0 && counter > 0 {
+			return nil, fmt.Errorf("age payload ends with an empty chunk")
+		}
+		out = append(out, plain...)
+		payload = payload[n:]
+		if last {
+			return out, nil
+		}
+	}
+}
+
+// unwrapX25519 opens the file key from an X25519 stanza with share arg and wrapped body.
+func unwrapX25519(identity *ecdh.PrivateKey, shareArg string, body []byte) ([]byte, error) {
+	share, err := b64.DecodeString(shareArg)
+	if err != nil {
+		return nil, fmt.Errorf("decode age X25519 share: %w", err)
+	}
+	ephemeral, err := ecdh.X25519().NewPublicKey(share)
+	if err != nil {
+		return nil, fmt.Errorf("parse age X25519 share: %w", err)
+	}
+	wrapKey, err := x25519WrapKey(identity, ephemeral, share, identity.PublicKey().Bytes())
+	if err != nil {
+		return nil, err
+	}
+	fileKey, err := aeadOpen(wrapKey, make([]byte, chacha20poly1305.NonceSize), body)
+	if err != nil || len(fileKey) != fileKeySize {
+		return nil, ErrNoMatchingStanza
+	}
+	return fileKey, nil
+}
+
+// x25519WrapKey derives the key that wraps the file key in an X25519 stanza.
+func x25519WrapKey(priv *ecdh.PrivateKey, pub *ecdh.PublicKey, share, recipient []byte) ([]byte, error) {
+	shared, err := priv.ECDH(pub)
+	if err != nil {
+		return nil, fmt.Errorf("age X25519: %w", err)
+	}
+	salt := append(append([]byte{}, share...), recipient...)
+	key, err := hkdf.Key(sha256.New, shared, salt, x25519Label, chacha20poly1305.KeySize)
+	if err != nil {
+		return nil, fmt.Errorf("age wrap key: %w", err)
+	}
+	return key, nil
+}
+
+// headerMAC returns the HMAC of the header up to and including the footer dashes.
+func headerMAC(fileKey, header []byte) ([]byte, error) {
+	key, err := hkdf.Key(sha256.New, fileKey, nil, "header", sha256.Size)
+	if err != nil {
+		return nil, fmt.Errorf("age header key: %w", err)
+	}
+	h := hmac.New(sha256.New, key)
+	h.Write(header)
+	return h.Sum(nil), nil
+}
+
+// streamNonce returns the STREAM nonce of a payload chunk: an 11-byte big-endian counter and a last-chunk flag.
+func streamNonce(counter uint64, last bool) []byte {
+	nonce := make([]byte, chacha20poly1305.NonceSize)
+	for i := 10; i >= 3; i-- {
+		nonce[i] = byte(counter)
+		counter >>= 8
+	}
+	if last {
+		nonce[11] = 1
+	}
+	return nonce
+}
+
+// aeadSeal encrypts plaintext with ChaCha20-Poly1305.
+func aeadSeal(key, nonce, plaintext []byte) ([]byte, error) {
+	aead, err := chacha20poly1305.New(key)
+	if err != nil {
+		return nil, err
+	}
+	return aead.Seal(nil, nonce, plaintext, nil), nil
+}
+
+// aeadOpen decrypts ciphertext with ChaCha20-Poly1305.
+func aeadOpen(key, nonce, ciphertext []byte) ([]byte, error) {
+	aead, err := chacha20poly1305.New(key)
+	if err != nil {
+		return nil, err
+	}
+	return aead.Open(nil, nonce, ciphertext, nil)
+}
+
+// readLine reads one header line without its trailing newline.
+func readLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err == io.EOF {
+		return "", io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(line, "\n"), nil
+}
+
+// decodeBech32 decodes a Bech32 (not Bech32m) string with hrp into 32 bytes.
+func decodeBech32(s, hrp string) ([]byte, error) {
+	gotHRP, data, version, err := bech32.DecodeGeneric(s)
+	if err != nil {
+		return nil, err
+	}
+	if gotHRP != hrp || version != bech32.Version0 {
+		return nil, fmt.Errorf("want a bech32 %q string", hrp)
+	}
+	key, err := bech32.ConvertBits(data, 5, 8, false)
+	if err != nil {
+		return nil, err
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("want 32 bytes, got %d", len(key))
+	}
+	return key, nil
+}
+
+// encodeBech32 encodes data as a Bech32 string with hrp.
+func encodeBech32(hrp string, data []byte) (string, error) {
+	converted, err := bech32.ConvertBits(data, 8, 5, true)
+	if err != nil {
+		return "", err
+	}
+	return bech32.Encode(hrp, converted)
+}
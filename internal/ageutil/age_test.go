@@ -0,0 +1,139 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package ageutil
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"crypto/rand"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// Key pair from the age test vectors: the X25519 scalar 0x42 repeated 32 times.
+const (
+	testIdentity  = "AGE-SECRET-KEY-1GFPYYSJZGFPYYSJZGFPYYSJZGFPYYSJZGFPYYSJZGFPYYSJZGFPQ4EGAEX"
+	testRecipient = "age1zvkyg2lqzraa2lnjvqej32nkuu0ues2s82hzrye869xeexvn73equnujwj"
+)
+
+// TestParseAndFormat checks the bech32 encodings of the test vector key pair.
+func TestParseAndFormat(t *testing.T) {
+	t.Parallel()
+
+	identity, err := ParseIdentity(strings.ToLower(testIdentity))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(identity.Bytes(), bytes.Repeat([]byte{0x42}, 32)) {
+		t.Fatalf("identity=%x want 0x42 repeated.", identity.Bytes())
+	}
+	if got, err := FormatIdentity(identity); err != nil || got != testIdentity {
+		t.Fatalf("identity=%s err=%v want %s.", got, err, testIdentity)
+	}
+	if got, err := FormatRecipient(identity.PublicKey()); err != nil || got != testRecipient {
+		t.Fatalf("recipient=%s err=%v want %s.", got, err, testRecipient)
+	}
+	recipient, err := ParseRecipient(" " + testRecipient + " ")
+	if err != nil || !recipient.Equal(identity.PublicKey()) {
+		t.Fatalf("recipient=%v err=%v want the identity's public key.", recipient, err)
+	}
+
+	for _, bad := range []string{testIdentity, "age1qqqq", testRecipient[:len(testRecipient)-1] + "q"} {
+		if _, err := ParseRecipient(bad); err == nil {
+			t.Fatalf("ParseRecipient(%q) want error.", bad)
+		}
+	}
+	if _, err := ParseIdentity(testRecipient); err == nil {
+		t.Fatal("ParseIdentity of a recipient want error.")
+	}
+}
+
+// TestEncryptDecrypt round-trips payloads around the 64 KiB chunk boundary and checks the header layout.
+func TestEncryptDecrypt(t *testing.T) {
+	t.Parallel()
+
+	identity, err := ParseIdentity(testIdentity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, size := range []int{0, 1, chunkSize - 1, chunkSize, chunkSize + 1, 2*chunkSize + 7} {
+		plaintext := make([]byte, size)
+		if _, err := rand.Read(plaintext); err != nil {
+			t.Fatal(err)
+		}
+		ciphertext, err := Encrypt(identity.PublicKey(), plaintext)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !IsEncrypted(ciphertext) || !bytes.Contains(ciphertext, []byte("\n-> X25519 ")) {
+			t.Fatalf("size %d: header %q want an age v1 X25519 header.", size, ciphertext[:min(len(ciphertext), 80)])
+		}
+		chunks := max(1, (size+chunkSize-1)/chunkSize)
+		headerLen := bytes.Index(ciphertext, []byte("\n--- ")) + len("\n--- ") + 43 + 1
+		if want := headerLen + nonceSize + size + chunks*16; len(ciphertext) != want {
+			t.Fatalf("size %d: len=%d want %d.", size, len(ciphertext), want)
+		}
+		got, err := Decrypt(identity, ciphertext)
+		if err != nil {
+			t.Fatalf("size %d: %v.", size, err)
+		}
+		if !bytes.Equal(got, plaintext) {
+			t.Fatalf("size %d: plaintext mismatch.", size)
+		}
+	}
+}
+
+// TestDecrypt_rejects checks the wrong identity, a tampered header and truncated payloads are refused.
+func TestDecrypt_rejects(t *testing.T) {
+	t.Parallel()
+
+	identity, err := ParseIdentity(testIdentity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ciphertext, err := Encrypt(identity.PublicKey(), bytes.Repeat([]byte{1}, chunkSize+10))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	other, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Decrypt(other, ciphertext); !errors.Is(err, ErrNoMatchingStanza) {
+		t.Fatalf("err=%v want ErrNoMatchingStanza.", err)
+	}
+
+	headerEnd := bytes.Index(ciphertext, []byte("\n--- "))
+	tampered := bytes.Clone(ciphertext)
+	tampered = append(tampered[:headerEnd], append([]byte("\n-> X25519 AAAA\nAAAA"), tampered[headerEnd:]...)...)
+	if _, err := Decrypt(identity, tampered); err == nil || !strings.Contains(err.Error(), "mac") {
+		t.Fatalf("err=%v want header mac mismatch.", err)
+	}
+
+	for name, c := range map[string][]byte{
+		"last chunk dropped": ciphertext[:len(ciphertext)-10-16],
+		"truncated":          ciphertext[:len(ciphertext)-1],
+		"not age":            []byte("age-encryption.org/v2\n"),
+	} {
+		if _, err := Decrypt(identity, c); err == nil {
+			t.Fatalf("%s: want error.", name)
+		}
+	}
+}
@@ -18,12 +18,19 @@
 package backend
 
 import (
+	"context"
+	"fmt"
+	"strconv"
 	"sync"
+	"time"
 
 	"github.com/hashicorp/vault/sdk/framework"
 	"github.com/hashicorp/vault/sdk/logical"
 
 	"github.com/bsostech/vault-blockchain/internal/path"
+	"github.com/bsostech/vault-blockchain/internal/path/account"
+	"github.com/bsostech/vault-blockchain/internal/path/addressindex"
+	"github.com/bsostech/vault-blockchain/internal/path/wallet"
 	"github.com/bsostech/vault-blockchain/internal/version"
 )
 
@@ -33,25 +40,96 @@ type ethereumBackend struct {
 	// walletMu provides per-wallet mutex locks for operations that require atomicity
 	// within a single Vault active node (counter read-increment-write).
 	walletMu sync.Map
+	// accountMu provides per-account mutex locks for single-key keyring updates (rotation).
+	accountMu sync.Map
+}
+
+// mountOptionDisableRawSign is the mount option (`vault secrets enable -options=...`) that removes the raw sign paths.
+const mountOptionDisableRawSign = "disable_raw_sign"
+
+// pathOptionsFromConfig reads mount-level path options from the backend config.
+func pathOptionsFromConfig(conf *logical.BackendConfig) (path.Options, error) {
+	var opts path.Options
+	if conf == nil {
+		return opts, nil
+	}
+	if raw, ok := conf.Config[mountOptionDisableRawSign]; ok && raw != "" {
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			return opts, fmt.Errorf("invalid %s mount option %q: %w", mountOptionDisableRawSign, raw, err)
+		}
+		opts.DisableRawSign = v
+	}
+	return opts, nil
 }
 
 // newBackend constructs the backend with paths and seal-wrap prefixes.
 func newBackend(conf *logical.BackendConfig) (*ethereumBackend, error) {
+	opts, err := pathOptionsFromConfig(conf)
+	if err != nil {
+		return nil, err
+	}
 	var b ethereumBackend
 	b.Backend = &framework.Backend{
 		Help:           "",
 		RunningVersion: "v" + version.Version,
 		Paths: framework.PathAppend(
-			path.GetPaths(&b.walletMu),
+			path.GetPaths(&b.walletMu, &b.accountMu, b.dispatch, opts),
 		),
 		PathsSpecial: &logical.Paths{
 			SealWrapStorage: []string{
 				"wallets/",
 				"accounts/",
+				"shamir_imports/",
 			},
 		},
-		Secrets:     []*framework.Secret{},
-		BackendType: logical.TypeLogical,
+		Secrets:        []*framework.Secret{},
+		BackendType:    logical.TypeLogical,
+		InitializeFunc: b.initialize,
 	}
 	return &b, nil
 }
+
+// dispatch routes a request synthesized by the JSON-RPC facade through the backend's own router.
+func (b *ethereumBackend) dispatch(ctx context.Context, req *logical.Request) (*logical.Response, error) {
+	return b.Backend.HandleRequest(ctx, req)
+}
+
+// initialize runs on the active node when the mount starts and migrates legacy unversioned single-key
+// accounts to the versioned keyring layout.
+func (b *ethereumBackend) initialize(ctx context.Context, req *logical.InitializationRequest) error {
+	migrated, err := account.MigrateSingleKeyAccounts(ctx, req.Storage)
+	if err != nil {
+		return fmt.Errorf("migrate single-key accounts: %w", err)
+	}
+	if migrated > 0 {
+		b.Logger().Info("migrated single-key accounts to versioned keys", "count", migrated)
+	}
+	indexed, err := backfillAddressIndex(ctx, req.Storage)
+	if err != nil {
+		return fmt.Errorf("backfill address index: %w", err)
+	}
+	if indexed > 0 {
+		b.Logger().Info("indexed addresses of existing accounts", "count", indexed)
+	}
+	return nil
+}
+
+// backfillAddressIndex indexes the addresses of wallets and single-key accounts stored before the address index
+// existed. It runs once per mount; the marker is written only after every entry is indexed, so a failed run is
+// retried on the next start.
+func backfillAddressIndex(ctx context.Context, s logical.Storage) (int, error) {
+	done, err := addressindex.BackfillDone(ctx, s)
+	if err != nil || done {
+		return 0, err
+	}
+	singleKey, err := account.IndexSingleKeyAccountAddresses(ctx, s)
+	if err != nil {
+		return singleKey, err
+	}
+	derived, err := wallet.IndexDerivedAccountAddresses(ctx, s)
+	if err != nil {
+		return singleKey + derived, err
+	}
+	return singleKey + derived, addressindex.MarkBackfillDone(ctx, s, time.Now())
+}
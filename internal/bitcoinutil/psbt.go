@@ -0,0 +1,178 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package bitcoinutil
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// KeySource returns the private key for a BIP-32 derivation record of a PSBT input, or nil when the record
+// does not belong to the signer. Returned keys are zeroed by SignPSBT.
+type KeySource func(d *psbt.Bip32Derivation) (*btcec.PrivateKey, error)
+
+// DecodePSBT parses a base64 (BIP-174) PSBT.
+func DecodePSBT(encoded string) (*psbt.Packet, error) {
+	p, err := psbt.NewFromRawBytes(strings.NewReader(strings.TrimSpace(encoded)), true)
+	if err != nil {
+		return nil, fmt.Errorf("decode psbt: %w", err)
+	}
+	return p, nil
+}
+
+// SignPSBT adds a partial signature to every unfinalized input of p whose BIP-32 derivation records resolve
+// to a key through keys, and returns the indices of the inputs it signed. Only native SegWit (P2WPKH) inputs
+// are signed; an input whose derivation resolves but whose UTXO is missing, of another script type or locked
+// to a different public key is an error. Inputs are signed with SIGHASH_ALL only; an input of the signer that
+// requests any other sighash type is an error.
+func SignPSBT(p *psbt.Packet, keys KeySource) ([]int, error) {
+	prevOuts := make(map[wire.OutPoint]*wire.TxOut, len(p.Inputs))
+	for i := range p.Inputs {
+		if utxo, err := inputUtxo(p, i); err == nil && utxo != nil {
+			prevOuts[p.UnsignedTx.TxIn[i].PreviousOutPoint] = utxo
+		}
+	}
+	sigHashes := txscript.NewTxSigHashes(p.UnsignedTx, txscript.NewMultiPrevOutFetcher(prevOuts))
+	updater, err := psbt.NewUpdater(p)
+	if err != nil {
+		return nil, fmt.Errorf("psbt updater: %w", err)
+	}
+
+	signed := make([]int, 0, len(p.Inputs))
+	for i := range p.Inputs {
+		in := &p.Inputs[i]
+		if in.FinalScriptSig != nil || in.FinalScriptWitness != nil {
+			continue
+		}
+		for _, d := range in.Bip32Derivation {
+			ok, err := signInput(p, updater, sigHashes, i, d, keys)
+			if err != nil {
+				return nil, fmt.Errorf("input %d: %w", i, err)
+			}
+			if ok {
+				signed = append(signed, i)
+				break
+			}
+		}
+	}
+	return signed, nil
+}
+
+// signInput signs input i with the key that d resolves to, if any, and reports whether it did.
+func signInput(p *psbt.Packet, updater *psbt.Updater, sigHashes *txscript.TxSigHashes, i int, d *psbt.Bip32Derivation, keys KeySource) (bool, error) {
+	key, err := keys(d)
+	if err != nil {
+		return false, err
+	}
+	if key == nil {
+		return false, nil
+	}
+	defer key.Zero()
+	pub := key.PubKey().SerializeCompressed()
+	if !bytes.Equal(pub, d.PubKey) {
+		return false, fmt.Errorf("derivation path does not match public key %x", d.PubKey)
+	}
+	for _, sig := range p.Inputs[i].PartialSigs {
+		if bytes.Equal(sig.PubKey, pub) {
+			return true, nil
+		}
+	}
+	utxo, err := inputUtxo(p, i)
+	if err != nil {
+		return false, err
+	}
+	if utxo == nil {
+		return false, errors.New("missing witness or non-witness UTXO")
+	}
+	if !isP2WPKHFor(utxo.PkScript, pub) {
+		return false, errors.New("only native SegWit (P2WPKH) outputs of the derived key can be signed")
+	}
+	switch p.Inputs[i].SighashType {
+	case txscript.SigHashDefault, txscript.SigHashAll:
+	default:
+		return false, fmt.Errorf("sighash type %#x is not supported; only SIGHASH_ALL is signed", uint32(p.Inputs[i].SighashType))
+	}
+	sig, err := txscript.RawTxInWitnessSignature(p.UnsignedTx, sigHashes, i, utxo.Value, utxo.PkScript, txscript.SigHashAll, key)
+	if err != nil {
+		return false, fmt.Errorf("witness signature: %w", err)
+	}
+	if _, err := updater.Sign(i, sig, pub, nil, nil); err != nil {
+		return false, fmt.Errorf("add partial signature: %w", err)
+	}
+	return true, nil
+}
+
+// inputUtxo returns the output spent by input i from its witness UTXO or, failing that, its non-witness UTXO,
+// which must be the transaction the input references.
+func inputUtxo(p *psbt.Packet, i int) (*wire.TxOut, error) {
+	in := p.Inputs[i]
+	if in.WitnessUtxo != nil {
+		return in.WitnessUtxo, nil
+	}
+	if in.NonWitnessUtxo == nil {
+		return nil, nil
+	}
+	prev := p.UnsignedTx.TxIn[i].PreviousOutPoint
+	if in.NonWitnessUtxo.TxHash() != prev.Hash {
+		return nil, errors.New("non-witness UTXO does not match the spent outpoint")
+	}
+	if int(prev.Index) >= len(in.NonWitnessUtxo.TxOut) {
+		return nil, errors.New("spent output index is out of range of the non-witness UTXO")
+	}
+	return in.NonWitnessUtxo.TxOut[prev.Index], nil
+}
+
+// isP2WPKHFor reports whether pkScript is the version 0 witness program of pub's hash160.
+func isP2WPKHFor(pkScript, pub []byte) bool {
+	if !txscript.IsPayToWitnessPubKeyHash(pkScript) {
+		return false
+	}
+	return bytes.Equal(pkScript[2:], btcutil.Hash160(pub))
+}
+
+// FinalizePSBT finalizes every input that has the signatures it needs. When all inputs are final it returns
+// the network-serialized transaction as hex and its txid; otherwise complete is false and the PSBT keeps its
+// partial signatures.
+func FinalizePSBT(p *psbt.Packet) (rawTx, txid string, complete bool, err error) {
+	for i := range p.Inputs {
+		if _, ferr := psbt.MaybeFinalize(p, i); ferr != nil && !errors.Is(ferr, psbt.ErrNotFinalizable) {
+			return "", "", false, fmt.Errorf("finalize input %d: %w", i, ferr)
+		}
+	}
+	if !p.IsComplete() {
+		return "", "", false, nil
+	}
+	tx, err := psbt.Extract(p)
+	if err != nil {
+		return "", "", false, fmt.Errorf("extract transaction: %w", err)
+	}
+	var buf bytes.Buffer
+	if err := tx.Serialize(&buf); err != nil {
+		return "", "", false, fmt.Errorf("serialize transaction: %w", err)
+	}
+	return hex.EncodeToString(buf.Bytes()), tx.TxHash().String(), true, nil
+}
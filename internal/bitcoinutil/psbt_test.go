@@ -0,0 +1,204 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package bitcoinutil
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// testKey returns a deterministic secp256k1 key whose scalar is b repeated.
+func testKey(b byte) *btcec.PrivateKey {
+	key, _ := btcec.PrivKeyFromBytes(bytes.Repeat([]byte{b}, 32))
+	return key
+}
+
+// testP2WPKHScript returns the P2WPKH output script of key.
+func testP2WPKHScript(t *testing.T, key *btcec.PrivateKey) []byte {
+	t.Helper()
+	addr, err := btcutil.NewAddressWitnessPubKeyHash(btcutil.Hash160(key.PubKey().SerializeCompressed()), &chaincfg.RegressionNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	script, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return script
+}
+
+// testPacket builds a PSBT spending one P2WPKH output per key, each with a derivation record at path [i].
+func testPacket(t *testing.T, fingerprint uint32, keys ...*btcec.PrivateKey) *psbt.Packet {
+	t.Helper()
+	tx := wire.NewMsgTx(2)
+	for i := range keys {
+		tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{byte(i + 1)}, uint32(i)), nil, nil))
+	}
+	tx.AddTxOut(wire.NewTxOut(90_000, testP2WPKHScript(t, testKey(9))))
+	p, err := psbt.NewFromUnsignedTx(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, key := range keys {
+		p.Inputs[i].WitnessUtxo = wire.NewTxOut(50_000, testP2WPKHScript(t, key))
+		p.Inputs[i].Bip32Derivation = []*psbt.Bip32Derivation{{
+			PubKey:               key.PubKey().SerializeCompressed(),
+			MasterKeyFingerprint: fingerprint,
+			Bip32Path:            []uint32{uint32(i)},
+		}}
+	}
+	return p
+}
+
+// TestSignPSBT_finalizesAndVerifies signs every input of the signer, then checks the extracted transaction
+// against the script engine.
+func TestSignPSBT_finalizesAndVerifies(t *testing.T) {
+	t.Parallel()
+	keys := []*btcec.PrivateKey{testKey(1), testKey(2)}
+	p := testPacket(t, 7, keys...)
+	encoded, err := p.B64Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err = DecodePSBT(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signed, err := SignPSBT(p, func(d *psbt.Bip32Derivation) (*btcec.PrivateKey, error) {
+		if d.MasterKeyFingerprint != 7 {
+			return nil, nil
+		}
+		return testKey(byte(d.Bip32Path[0] + 1)), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(signed) != 2 || signed[0] != 0 || signed[1] != 1 {
+		t.Fatalf("signed inputs: got %v", signed)
+	}
+	rawTx, txid, complete, err := FinalizePSBT(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !complete || txid == "" {
+		t.Fatalf("complete=%v txid=%q", complete, txid)
+	}
+
+	raw, err := hex.DecodeString(rawTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tx wire.MsgTx
+	if err := tx.Deserialize(bytes.NewReader(raw)); err != nil {
+		t.Fatal(err)
+	}
+	if tx.TxHash().String() != txid {
+		t.Fatalf("txid: got %s want %s", tx.TxHash(), txid)
+	}
+	prevOuts := txscript.NewMultiPrevOutFetcher(nil)
+	for i, key := range keys {
+		prevOuts.AddPrevOut(tx.TxIn[i].PreviousOutPoint, wire.NewTxOut(50_000, testP2WPKHScript(t, key)))
+	}
+	sigHashes := txscript.NewTxSigHashes(&tx, prevOuts)
+	for i, key := range keys {
+		vm, err := txscript.NewEngine(testP2WPKHScript(t, key), &tx, i, txscript.StandardVerifyFlags, nil, sigHashes, 50_000, prevOuts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := vm.Execute(); err != nil {
+			t.Fatalf("input %d: %v", i, err)
+		}
+	}
+}
+
+// TestSignPSBT_skipsForeignInputs leaves inputs of other signers unsigned and the PSBT incomplete.
+func TestSignPSBT_skipsForeignInputs(t *testing.T) {
+	t.Parallel()
+	p := testPacket(t, 7, testKey(1), testKey(2))
+	p.Inputs[1].Bip32Derivation[0].MasterKeyFingerprint = 8
+
+	signed, err := SignPSBT(p, func(d *psbt.Bip32Derivation) (*btcec.PrivateKey, error) {
+		if d.MasterKeyFingerprint != 7 {
+			return nil, nil
+		}
+		return testKey(byte(d.Bip32Path[0] + 1)), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(signed) != 1 || signed[0] != 0 {
+		t.Fatalf("signed inputs: got %v", signed)
+	}
+	if len(p.Inputs[1].PartialSigs) != 0 {
+		t.Fatal("foreign input was signed")
+	}
+	if _, _, complete, err := FinalizePSBT(p); err != nil || complete {
+		t.Fatalf("complete=%v err=%v", complete, err)
+	}
+	if p.Inputs[0].FinalScriptWitness == nil {
+		t.Fatal("own input was not finalized")
+	}
+}
+
+// TestSignPSBT_rejectsMismatchedKey fails when a derivation resolves to a key other than its public key.
+func TestSignPSBT_rejectsMismatchedKey(t *testing.T) {
+	t.Parallel()
+	p := testPacket(t, 7, testKey(1))
+	if _, err := SignPSBT(p, func(*psbt.Bip32Derivation) (*btcec.PrivateKey, error) {
+		return testKey(3), nil
+	}); err == nil {
+		t.Fatal("expected public key mismatch error")
+	}
+}
+
+// TestSignPSBT_rejectsNonAllSighash signs SIGHASH_ALL inputs and refuses any other requested sighash type.
+func TestSignPSBT_rejectsNonAllSighash(t *testing.T) {
+	t.Parallel()
+	keys := func(d *psbt.Bip32Derivation) (*btcec.PrivateKey, error) {
+		return testKey(byte(d.Bip32Path[0] + 1)), nil
+	}
+	p := testPacket(t, 7, testKey(1))
+	p.Inputs[0].SighashType = txscript.SigHashAll
+	if signed, err := SignPSBT(p, keys); err != nil || len(signed) != 1 {
+		t.Fatalf("signed=%v err=%v", signed, err)
+	}
+	for _, hashType := range []txscript.SigHashType{
+		txscript.SigHashNone,
+		txscript.SigHashSingle,
+		txscript.SigHashAll | txscript.SigHashAnyOneCanPay,
+	} {
+		p := testPacket(t, 7, testKey(1))
+		p.Inputs[0].SighashType = hashType
+		if _, err := SignPSBT(p, keys); err == nil {
+			t.Fatalf("sighash %#x: expected unsupported sighash error", uint32(hashType))
+		}
+		if len(p.Inputs[0].PartialSigs) != 0 {
+			t.Fatalf("sighash %#x: input was signed", uint32(hashType))
+		}
+	}
+}
@@ -0,0 +1,42 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package ethutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+// ParseAuthorizationListJSON parses an EIP-7702 authorization list from JSON.
+//
+// Entries use the JSON-RPC shape emitted by go-ethereum and the sign-authorization
+// endpoints: {"chainId","address","nonce","yParity","r","s"} with hex quantities.
+func ParseAuthorizationListJSON(raw string) ([]ethtypes.SetCodeAuthorization, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, nil
+	}
+	var auths []ethtypes.SetCodeAuthorization
+	if err := json.Unmarshal([]byte(raw), &auths); err != nil {
+		return nil, fmt.Errorf("authorization_list JSON: %w", err)
+	}
+	return auths, nil
+}
@@ -0,0 +1,79 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package ethutil
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// TestParseAuthorizationListJSON verifies empty input, a round trip through the response encoding, and invalid JSON.
+func TestParseAuthorizationListJSON(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty_string_returns_nil", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := ParseAuthorizationListJSON("  ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != nil {
+			t.Fatalf("got %v want nil.", got)
+		}
+	})
+
+	t.Run("round_trip", func(t *testing.T) {
+		t.Parallel()
+
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		delegate := common.HexToAddress("0x0000000000000000000000000000000000000007")
+		auth, err := SignSetCodeAuthorization(big.NewInt(1), delegate, 3, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := SetCodeAuthorizationResponseData(auth)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := ParseAuthorizationListJSON("[" + data["authorization"].(string) + "]")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 1 {
+			t.Fatalf("len(got)=%d want 1.", len(got))
+		}
+		if got[0] != auth {
+			t.Fatalf("got %+v want %+v.", got[0], auth)
+		}
+	})
+
+	t.Run("invalid_json_returns_error", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := ParseAuthorizationListJSON("{"); err == nil {
+			t.Fatal("expected error.")
+		}
+	})
+}
@@ -0,0 +1,117 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package ethutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+)
+
+// ParseBlobHashesJSON parses EIP-4844 blob versioned hashes from a JSON array of hex strings.
+func ParseBlobHashesJSON(raw string) ([]common.Hash, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, nil
+	}
+	var hexHashes []string
+	if err := json.Unmarshal([]byte(raw), &hexHashes); err != nil {
+		return nil, fmt.Errorf("blob_versioned_hashes JSON: %w", err)
+	}
+	hashes := make([]common.Hash, 0, len(hexHashes))
+	for i, h := range hexHashes {
+		b, err := hexutil.Decode(strings.TrimSpace(h))
+		if err != nil {
+			return nil, fmt.Errorf("blob_versioned_hashes[%d]: %w", i, err)
+		}
+		if !kzg4844.IsValidVersionedHash(b) {
+			return nil, fmt.Errorf("blob_versioned_hashes[%d]: not a KZG versioned hash", i)
+		}
+		hashes = append(hashes, common.BytesToHash(b))
+	}
+	return hashes, nil
+}
+
+// ParseBlobsJSON parses raw EIP-4844 blobs from a JSON array of hex strings.
+//
+// Each blob is right-padded with zeros to the fixed 131072-byte blob size; callers are
+// responsible for encoding data as valid field elements (the top byte of each 32-byte
+// chunk must keep the value below the BLS12-381 modulus).
+func ParseBlobsJSON(raw string) ([]kzg4844.Blob, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, nil
+	}
+	var hexBlobs []string
+	if err := json.Unmarshal([]byte(raw), &hexBlobs); err != nil {
+		return nil, fmt.Errorf("blobs JSON: %w", err)
+	}
+	blobs := make([]kzg4844.Blob, len(hexBlobs))
+	for i, h := range hexBlobs {
+		b, err := hexutil.Decode(strings.TrimSpace(h))
+		if err != nil {
+			return nil, fmt.Errorf("blobs[%d]: %w", i, err)
+		}
+		if len(b) > len(blobs[i]) {
+			return nil, fmt.Errorf("blobs[%d]: %d bytes exceeds blob size %d", i, len(b), len(blobs[i]))
+		}
+		copy(blobs[i][:], b)
+	}
+	return blobs, nil
+}
+
+// NewBlobSidecar computes KZG commitments and proofs for blobs and returns the sidecar.
+//
+// version selects the proof layout: ethtypes.BlobSidecarVersion0 carries one blob proof per
+// blob (Cancun/Prague pools), ethtypes.BlobSidecarVersion1 carries cell proofs (Osaka pools).
+func NewBlobSidecar(blobs []kzg4844.Blob, version byte) (*ethtypes.BlobTxSidecar, error) {
+	if len(blobs) == 0 {
+		return nil, fmt.Errorf("at least one blob is required")
+	}
+	if version != ethtypes.BlobSidecarVersion0 && version != ethtypes.BlobSidecarVersion1 {
+		return nil, fmt.Errorf("unsupported blob sidecar version %d", version)
+	}
+	commitments := make([]kzg4844.Commitment, 0, len(blobs))
+	var proofs []kzg4844.Proof
+	for i := range blobs {
+		commitment, err := kzg4844.BlobToCommitment(&blobs[i])
+		if err != nil {
+			return nil, fmt.Errorf("blob %d commitment: %w", i, err)
+		}
+		commitments = append(commitments, commitment)
+		if version == ethtypes.BlobSidecarVersion0 {
+			proof, err := kzg4844.ComputeBlobProof(&blobs[i], commitment)
+			if err != nil {
+				return nil, fmt.Errorf("blob %d proof: %w", i, err)
+			}
+			proofs = append(proofs, proof)
+			continue
+		}
+		cellProofs, err := kzg4844.ComputeCellProofs(&blobs[i])
+		if err != nil {
+			return nil, fmt.Errorf("blob %d cell proofs: %w", i, err)
+		}
+		proofs = append(proofs, cellProofs...)
+	}
+	return ethtypes.NewBlobTxSidecar(version, blobs, commitments, proofs), nil
+}
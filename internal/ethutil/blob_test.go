@@ -0,0 +1,126 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package ethutil
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+)
+
+// TestParseBlobHashesJSON verifies ParseBlobHashesJSON for empty input, valid hashes, and rejected inputs.
+func TestParseBlobHashesJSON(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty_string_returns_nil", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := ParseBlobHashesJSON("  ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != nil {
+			t.Fatalf("got %v want nil.", got)
+		}
+	})
+
+	t.Run("valid_versioned_hash", func(t *testing.T) {
+		t.Parallel()
+
+		raw := `["0x0100000000000000000000000000000000000000000000000000000000000001"]`
+		got, err := ParseBlobHashesJSON(raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 1 || got[0][0] != 0x01 || got[0][31] != 0x01 {
+			t.Fatalf("got %v want one versioned hash.", got)
+		}
+	})
+
+	t.Run("wrong_version_byte_returns_error", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ParseBlobHashesJSON(`["0x0200000000000000000000000000000000000000000000000000000000000001"]`)
+		if err == nil {
+			t.Fatal("expected error.")
+		}
+	})
+
+	t.Run("invalid_json_returns_error", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ParseBlobHashesJSON("[")
+		if err == nil {
+			t.Fatal("expected error.")
+		}
+	})
+}
+
+// TestParseBlobsJSON verifies blobs are zero-padded to the fixed blob size and oversize input is rejected.
+func TestParseBlobsJSON(t *testing.T) {
+	t.Parallel()
+
+	got, err := ParseBlobsJSON(`["0x00aa"]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0][1] != 0xaa || got[0][2] != 0 {
+		t.Fatalf("unexpected blob prefix %x.", got[0][:4])
+	}
+
+	oversize := make([]byte, len(kzg4844.Blob{})+1)
+	_, err = ParseBlobsJSON(`["` + hexutil.Encode(oversize) + `"]`)
+	if err == nil {
+		t.Fatal("expected error.")
+	}
+}
+
+// TestNewBlobSidecar_version0 verifies one commitment and one proof per blob, with hashes the KZG layer accepts.
+func TestNewBlobSidecar_version0(t *testing.T) {
+	t.Parallel()
+
+	blobs := []kzg4844.Blob{{}}
+	blobs[0][1] = 0x42
+	sc, err := NewBlobSidecar(blobs, ethtypes.BlobSidecarVersion0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sc.Commitments) != 1 || len(sc.Proofs) != 1 {
+		t.Fatalf("commitments=%d proofs=%d want 1/1.", len(sc.Commitments), len(sc.Proofs))
+	}
+	if err := kzg4844.VerifyBlobProof(&sc.Blobs[0], sc.Commitments[0], sc.Proofs[0]); err != nil {
+		t.Fatal(err)
+	}
+	if err := sc.ValidateBlobCommitmentHashes(sc.BlobHashes()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestNewBlobSidecar_rejectsEmptyAndUnknownVersion verifies NewBlobSidecar input validation.
+func TestNewBlobSidecar_rejectsEmptyAndUnknownVersion(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewBlobSidecar(nil, ethtypes.BlobSidecarVersion0); err == nil {
+		t.Fatal("expected error for no blobs.")
+	}
+	if _, err := NewBlobSidecar([]kzg4844.Blob{{}}, 7); err == nil {
+		t.Fatal("expected error for unknown version.")
+	}
+}
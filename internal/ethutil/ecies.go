@@ -20,7 +20,10 @@ package ethutil
 import (
 	"crypto/rand"
 	"fmt"
+	"strings"
 
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/crypto/ecies"
 )
 
@@ -47,3 +50,35 @@ func DecryptECIES(privateKey *ecies.PrivateKey, ciphertext []byte) ([]byte, erro
 	}
 	return plainText, nil
 }
+
+// ParseECIESPublicKey parses a hex secp256k1 public key (optional 0x prefix) as an ECIES public key. It accepts
+// the 33-byte compressed and 65-byte uncompressed encodings, and the 64-byte form without the 0x04 prefix that
+// single-key accounts report as public_key.
+func ParseECIESPublicKey(s string) (*ecies.PublicKey, error) {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, "0x") && !strings.HasPrefix(s, "0X") {
+		s = "0x" + s
+	}
+	raw, err := hexutil.Decode(s)
+	if err != nil {
+		return nil, fmt.Errorf("decode public key hex: %w", err)
+	}
+	switch len(raw) {
+	case 33:
+		pub, err := crypto.DecompressPubkey(raw)
+		if err != nil {
+			return nil, fmt.Errorf("parse compressed public key: %w", err)
+		}
+		return ecies.ImportECDSAPublic(pub), nil
+	case 64:
+		raw = append([]byte{0x04}, raw...)
+	case 65:
+	default:
+		return nil, fmt.Errorf("public key must be 33, 64 or 65 bytes, got %d", len(raw))
+	}
+	pub, err := crypto.UnmarshalPubkey(raw)
+	if err != nil {
+		return nil, fmt.Errorf("parse public key: %w", err)
+	}
+	return ecies.ImportECDSAPublic(pub), nil
+}
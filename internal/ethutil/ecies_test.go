@@ -21,6 +21,7 @@ import (
 	"bytes"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/crypto/ecies"
 )
@@ -102,3 +103,31 @@ func TestDecryptECIES_wrongKey(t *testing.T) {
 	}
 }
 
+
+// TestParseECIESPublicKey_encodings verifies compressed, uncompressed and prefix-less keys parse to the same key.
+func TestParseECIESPublicKey_encodings(t *testing.T) {
+	t.Parallel()
+
+	ecdsaKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	uncompressed := crypto.FromECDSAPub(&ecdsaKey.PublicKey)
+	inputs := []string{
+		hexutil.Encode(crypto.CompressPubkey(&ecdsaKey.PublicKey)),
+		hexutil.Encode(uncompressed),
+		hexutil.Encode(uncompressed)[4:],
+	}
+	for _, in := range inputs {
+		pub, err := ParseECIESPublicKey(in)
+		if err != nil {
+			t.Fatalf("ParseECIESPublicKey(%s): %v", in, err)
+		}
+		if pub.X.Cmp(ecdsaKey.PublicKey.X) != 0 || pub.Y.Cmp(ecdsaKey.PublicKey.Y) != 0 {
+			t.Fatalf("ParseECIESPublicKey(%s) returned a different key.", in)
+		}
+	}
+	if _, err := ParseECIESPublicKey("0x1234"); err == nil {
+		t.Fatal("expected error for short key.")
+	}
+}
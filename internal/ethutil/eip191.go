@@ -0,0 +1,84 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package ethutil
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// EIP-191 version bytes supported by the sign-message endpoints.
+const (
+	// EIP191VersionIntendedValidator is version 0x00: 0x19 0x00 <validator address> <data>.
+	EIP191VersionIntendedValidator byte = 0x00
+	// EIP191VersionPersonalSign is version 0x45 ('E'): "\x19Ethereum Signed Message:\n" <len> <data>.
+	EIP191VersionPersonalSign byte = 0x45
+)
+
+// ParseEIP191Version parses the request `version` value ("0x45"/"E"/"personal" or "0x00"/"intended_validator").
+//
+// An empty value selects personal_sign.
+func ParseEIP191Version(raw string) (byte, error) {
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case "", "0x45", "45", "e", "personal", "personal_sign":
+		return EIP191VersionPersonalSign, nil
+	case "0x00", "00", "0", "intended_validator":
+		return EIP191VersionIntendedValidator, nil
+	default:
+		return 0, fmt.Errorf("unsupported eip191 version %q (want 0x45 or 0x00)", raw)
+	}
+}
+
+// EIP191Hash returns the EIP-191 signing hash of msg for the given version.
+//
+// validator is only used (and required) for version 0x00.
+func EIP191Hash(version byte, validator *common.Address, msg []byte) (common.Hash, error) {
+	switch version {
+	case EIP191VersionPersonalSign:
+		return common.BytesToHash(accounts.TextHash(msg)), nil
+	case EIP191VersionIntendedValidator:
+		if validator == nil {
+			return common.Hash{}, fmt.Errorf("eip191 version 0x00 requires a validator address")
+		}
+		return crypto.Keccak256Hash([]byte{0x19, EIP191VersionIntendedValidator}, validator.Bytes(), msg), nil
+	default:
+		return common.Hash{}, fmt.Errorf("unsupported eip191 version 0x%02x", version)
+	}
+}
+
+// SignEIP191 signs msg under EIP-191 and returns the hash and a 65-byte signature with v in {27, 28}.
+func SignEIP191(version byte, validator *common.Address, msg []byte, pk *ecdsa.PrivateKey) (common.Hash, []byte, error) {
+	if pk == nil {
+		return common.Hash{}, nil, fmt.Errorf("signing key is nil")
+	}
+	hash, err := EIP191Hash(version, validator, msg)
+	if err != nil {
+		return common.Hash{}, nil, err
+	}
+	sig, err := crypto.Sign(hash.Bytes(), pk)
+	if err != nil {
+		return common.Hash{}, nil, fmt.Errorf("eip191 sign: %w", err)
+	}
+	sig[crypto.RecoveryIDOffset] += 27
+	return hash, sig, nil
+}
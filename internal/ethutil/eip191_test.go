@@ -0,0 +1,117 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package ethutil
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// TestSignEIP191_personal verifies the personal_sign prefix, v normalization, and recovery.
+func TestSignEIP191_personal(t *testing.T) {
+	t.Parallel()
+
+	pk, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg := []byte("hello")
+	hash, sig, err := SignEIP191(EIP191VersionPersonalSign, nil, msg, pk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hash != common.BytesToHash(accounts.TextHash(msg)) {
+		t.Fatalf("hash=%s want personal_sign hash.", hash)
+	}
+	if v := sig[crypto.RecoveryIDOffset]; v != 27 && v != 28 {
+		t.Fatalf("v=%d want 27 or 28.", v)
+	}
+
+	raw := append([]byte(nil), sig...)
+	raw[crypto.RecoveryIDOffset] -= 27
+	pub, err := crypto.SigToPub(hash.Bytes(), raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := crypto.PubkeyToAddress(*pub), crypto.PubkeyToAddress(pk.PublicKey); got != want {
+		t.Fatalf("recovered address=%s want %s.", got, want)
+	}
+}
+
+// TestSignEIP191_intendedValidator verifies version 0x00 binds the validator and requires it.
+func TestSignEIP191_intendedValidator(t *testing.T) {
+	t.Parallel()
+
+	pk, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	validator := common.HexToAddress("0x0000000000000000000000000000000000000009")
+	msg := []byte{0x01, 0x02}
+	hash, _, err := SignEIP191(EIP191VersionIntendedValidator, &validator, msg, pk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := crypto.Keccak256Hash([]byte{0x19, 0x00}, validator.Bytes(), msg)
+	if hash != want {
+		t.Fatalf("hash=%s want %s.", hash, want)
+	}
+
+	if _, _, err := SignEIP191(EIP191VersionIntendedValidator, nil, msg, pk); err == nil {
+		t.Fatal("expected error without validator.")
+	}
+}
+
+// TestSignEIP191_nilKey verifies SignEIP191 returns an error when the private key is nil.
+func TestSignEIP191_nilKey(t *testing.T) {
+	t.Parallel()
+
+	if _, _, err := SignEIP191(EIP191VersionPersonalSign, nil, []byte("x"), nil); err == nil {
+		t.Fatal("expected error.")
+	}
+}
+
+// TestParseEIP191Version verifies accepted spellings and rejection of unknown versions.
+func TestParseEIP191Version(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]byte{
+		"":                   EIP191VersionPersonalSign,
+		"0x45":               EIP191VersionPersonalSign,
+		"E":                  EIP191VersionPersonalSign,
+		"0x00":               EIP191VersionIntendedValidator,
+		"intended_validator": EIP191VersionIntendedValidator,
+	}
+	for raw, want := range cases {
+		got, err := ParseEIP191Version(raw)
+		if err != nil {
+			t.Fatalf("ParseEIP191Version(%q): %v", raw, err)
+		}
+		if got != want {
+			t.Fatalf("ParseEIP191Version(%q)=0x%02x want 0x%02x.", raw, got, want)
+		}
+	}
+	if _, err := ParseEIP191Version("0x01"); err == nil {
+		t.Fatal("expected error for version 0x01.")
+	}
+}
@@ -0,0 +1,98 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package ethutil
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// keystorePBKDF2Vector is the pbkdf2 test vector from the Web3 Secret Storage definition (password "testpassword").
+const keystorePBKDF2Vector = `{"crypto":{"cipher":"aes-128-ctr","cipherparams":{"iv":"6087dab2f9fdbbfaddc31a909735c1e6"},` +
+	`"ciphertext":"5318b4d5bcd28de64ee5559e671353e16f075ecae9f99c7a79a38af5f869aa46","kdf":"pbkdf2",` +
+	`"kdfparams":{"c":262144,"dklen":32,"prf":"hmac-sha256","salt":"ae3cd4e7013836a3df6bd7241b12db061dbe2c6785853cce422d148a624ce0bd"},` +
+	`"mac":"517ead924a9d0dc3124507e3393d175ce3ff7c1e96529c6c555ce9e51205e9b2"},` +
+	`"id":"3198bc9c-6672-5ab3-d995-4942343ae5b6","version":3}`
+
+// keystoreScryptVector is go-ethereum's very-light-scrypt keystore (empty password).
+const keystoreScryptVector = `{"address":"45dea0fb0bba44f4fcf290bba71fd57d7117cbb8","crypto":{"cipher":"aes-128-ctr",` +
+	`"ciphertext":"b87781948a1befd247bff51ef4063f716cf6c2d3481163e9a8f42e1f9bb74145",` +
+	`"cipherparams":{"iv":"dc4926b48a105133d2f16b96833abf1e"},"kdf":"scrypt",` +
+	`"kdfparams":{"dklen":32,"n":2,"p":1,"r":8,"salt":"004244bbdc51cadda545b1cfa43cff9ed2ae88e08c61f1479dbb45410722f8f0"},` +
+	`"mac":"39990c1684557447940d4c69e06b1b82b2aceacb43f284df65c956daf3046b85"},` +
+	`"id":"ce541d8d-c79b-40f8-9f8c-20f59616faba","version":3}`
+
+// TestDecryptKeystore_vectors verifies the pbkdf2 and scrypt KDFs decrypt to the expected accounts.
+func TestDecryptKeystore_vectors(t *testing.T) {
+	t.Parallel()
+
+	pk, err := DecryptKeystore([]byte(keystorePBKDF2Vector), "testpassword")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := hex.EncodeToString(crypto.FromECDSA(pk)), "7a28b5ba57c53603b0b07b56bba752f7784bf506fa95edc395f5cf6c7514fe9d"; got != want {
+		t.Fatalf("pbkdf2 key=%s want %s.", got, want)
+	}
+
+	pk, err = DecryptKeystore([]byte(keystoreScryptVector), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := common.HexToAddress("45dea0fb0bba44f4fcf290bba71fd57d7117cbb8")
+	if got := crypto.PubkeyToAddress(pk.PublicKey); got != want {
+		t.Fatalf("scrypt address=%s want %s.", got, want)
+	}
+	if _, err := DecryptKeystore([]byte(keystoreScryptVector), "wrong"); err == nil {
+		t.Fatal("expected error for wrong password.")
+	}
+}
+
+// TestEncryptKeystore_roundTrip verifies an exported keystore is v3 JSON that decrypts to the same key.
+func TestEncryptKeystore_roundTrip(t *testing.T) {
+	t.Parallel()
+
+	pk, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyJSON, err := EncryptKeystore(pk, "secret", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var parsed struct {
+		Address string `json:"address"`
+		Version int    `json:"version"`
+	}
+	if err := json.Unmarshal(keyJSON, &parsed); err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Version != 3 {
+		t.Fatalf("version=%d want 3.", parsed.Version)
+	}
+	got, err := DecryptKeystore(keyJSON, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.D.Cmp(pk.D) != 0 {
+		t.Fatal("decrypted key differs from the exported key.")
+	}
+}
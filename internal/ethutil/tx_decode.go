@@ -0,0 +1,54 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package ethutil
+
+import (
+	"fmt"
+
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// Signed transaction encodings recognised by DecodeSignedTx.
+const (
+	// TxEncodingBinary is the EIP-2718 wire format used by eth_sendRawTransaction: a type byte followed by the
+	// payload for typed transactions, a plain RLP list for legacy ones.
+	TxEncodingBinary = "binary"
+	// TxEncodingRLP is a typed transaction wrapped in an RLP byte string, as it appears inside block bodies.
+	TxEncodingRLP = "rlp"
+)
+
+// DecodeSignedTx decodes a signed transaction of any supported type in either encoding, including blob
+// transactions in canonical or network (with sidecar) form, and reports which encoding it was given.
+func DecodeSignedTx(raw []byte) (*ethtypes.Transaction, string, error) {
+	if len(raw) == 0 {
+		return nil, "", fmt.Errorf("signed transaction is empty")
+	}
+	var tx ethtypes.Transaction
+	binErr := tx.UnmarshalBinary(raw)
+	if binErr == nil {
+		return &tx, TxEncodingBinary, nil
+	}
+	if kind, _, _, err := rlp.Split(raw); err == nil && kind == rlp.String {
+		var wrapped ethtypes.Transaction
+		if err := rlp.DecodeBytes(raw, &wrapped); err == nil {
+			return &wrapped, TxEncodingRLP, nil
+		}
+	}
+	return nil, "", fmt.Errorf("decode signed transaction: %w", binErr)
+}
@@ -0,0 +1,86 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package ethutil
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// TestDecodeSignedTx verifies legacy and typed transactions decode from the binary form and typed transactions
+// also decode from the RLP-wrapped form, keeping the same hash.
+func TestDecodeSignedTx(t *testing.T) {
+	t.Parallel()
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	chainID := big.NewInt(1)
+	to := common.HexToAddress("0x0000000000000000000000000000000000000003")
+	legacy, err := SignType0EIP155(chainID, 1, 21_000, big.NewInt(1), nil, &to, big.NewInt(1_000_000_000), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dynamic, err := SignEIP1559(chainID, 2, 21_000, big.NewInt(1), nil, &to, big.NewInt(1), big.NewInt(2), nil, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, signed := range []*ethtypes.Transaction{legacy, dynamic} {
+		bin, err := signed.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, encoding, err := DecodeSignedTx(bin)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if encoding != TxEncodingBinary || got.Hash() != signed.Hash() {
+			t.Fatalf("type %d: encoding=%s hash=%s want %s %s.", signed.Type(), encoding, got.Hash(), TxEncodingBinary, signed.Hash())
+		}
+	}
+
+	wrapped, err := rlp.EncodeToBytes(dynamic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, encoding, err := DecodeSignedTx(wrapped)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if encoding != TxEncodingRLP || got.Hash() != dynamic.Hash() {
+		t.Fatalf("encoding=%s hash=%s want %s %s.", encoding, got.Hash(), TxEncodingRLP, dynamic.Hash())
+	}
+}
+
+// TestDecodeSignedTx_invalid verifies empty and malformed input is rejected.
+func TestDecodeSignedTx_invalid(t *testing.T) {
+	t.Parallel()
+
+	for _, raw := range [][]byte{nil, {0x02}, {0x83, 0x01, 0x02, 0x03}, {0xde, 0xad, 0xbe, 0xef}} {
+		if _, _, err := DecodeSignedTx(raw); err == nil {
+			t.Fatalf("raw=%x expected error.", raw)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package ethutil
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
+)
+
+// BumpFee returns fee raised by percent, rounded up and at least one wei above fee, so that the result
+// satisfies a transaction pool's price-bump rule for the same percentage.
+func BumpFee(fee *big.Int, percent uint64) *big.Int {
+	if fee == nil {
+		fee = new(big.Int)
+	}
+	bumped := new(big.Int).Mul(fee, new(big.Int).SetUint64(100+percent))
+	bumped.Add(bumped, big.NewInt(99))
+	bumped.Div(bumped, big.NewInt(100))
+	if bumped.Cmp(fee) <= 0 {
+		bumped.Add(fee, big.NewInt(1))
+	}
+	return bumped
+}
+
+// ReplacementTx builds the unsigned replacement of orig, a pending transaction sent by from: the same chain and
+// nonce with every fee (gas price, or tip and fee cap) raised by percent via BumpFee.
+//
+// A speed-up keeps the type, recipient, value, data, gas limit, access list and authorization list. A cancel is
+// a zero-value self-transfer with a 21000 gas limit; set-code transactions are cancelled with a type-2
+// transaction since a set-code transaction needs an authorization list. Blob transactions are rejected: their
+// pool requires the blob sidecar, which is not kept after signing.
+func ReplacementTx(orig *ethtypes.Transaction, from common.Address, cancel bool, percent uint64) (*ethtypes.Transaction, error) {
+	if orig == nil {
+		return nil, fmt.Errorf("transaction to replace is nil")
+	}
+	if !orig.Protected() {
+		return nil, fmt.Errorf("transaction to replace is not replay-protected (EIP-155)")
+	}
+	chainID := orig.ChainId()
+	to, value, data, gas, accessList := orig.To(), orig.Value(), orig.Data(), orig.Gas(), orig.AccessList()
+	if cancel {
+		self := from
+		to, value, data, gas, accessList = &self, new(big.Int), nil, params.TxGas, nil
+	}
+	switch orig.Type() {
+	case ethtypes.LegacyTxType:
+		return ethtypes.NewTx(&ethtypes.LegacyTx{
+			Nonce:    orig.Nonce(),
+			GasPrice: BumpFee(orig.GasPrice(), percent),
+			Gas:      gas,
+			To:       to,
+			Value:    value,
+			Data:     data,
+		}), nil
+	case ethtypes.AccessListTxType:
+		return ethtypes.NewTx(&ethtypes.AccessListTx{
+			ChainID:    chainID,
+			Nonce:      orig.Nonce(),
+			GasPrice:   BumpFee(orig.GasPrice(), percent),
+			Gas:        gas,
+			To:         to,
+			Value:      value,
+			Data:       data,
+			AccessList: accessList,
+		}), nil
+	case ethtypes.DynamicFeeTxType:
+		return replacementDynamicFeeTx(orig, chainID, gas, to, value, data, accessList, percent), nil
+	case ethtypes.SetCodeTxType:
+		if cancel {
+			return replacementDynamicFeeTx(orig, chainID, gas, to, value, data, accessList, percent), nil
+		}
+		chainID256, overflow := uint256.FromBig(chainID)
+		if overflow {
+			return nil, fmt.Errorf("chain id overflows 256 bits")
+		}
+		return ethtypes.NewTx(&ethtypes.SetCodeTx{
+			ChainID:    chainID256,
+			Nonce:      orig.Nonce(),
+			GasTipCap:  uint256.MustFromBig(BumpFee(orig.GasTipCap(), percent)),
+			GasFeeCap:  uint256.MustFromBig(BumpFee(orig.GasFeeCap(), percent)),
+			Gas:        gas,
+			To:         *to,
+			Value:      uint256.MustFromBig(value),
+			Data:       data,
+			AccessList: accessList,
+			AuthList:   orig.SetCodeAuthorizations(),
+		}), nil
+	case ethtypes.BlobTxType:
+		return nil, fmt.Errorf("blob transactions cannot be replaced: sign a new blob transaction with the same nonce")
+	}
+	return nil, fmt.Errorf("unsupported transaction type %d", orig.Type())
+}
+
+// replacementDynamicFeeTx builds a type-2 replacement of orig with the given payload and bumped fees.
+func replacementDynamicFeeTx(
+	orig *ethtypes.Transaction,
+	chainID *big.Int,
+	gas uint64,
+	to *common.Address,
+	value *big.Int,
+	data []byte,
+	accessList ethtypes.AccessList,
+	percent uint64,
+) *ethtypes.Transaction {
+	return ethtypes.NewTx(&ethtypes.DynamicFeeTx{
+		ChainID:    chainID,
+		Nonce:      orig.Nonce(),
+		GasTipCap:  BumpFee(orig.GasTipCap(), percent),
+		GasFeeCap:  BumpFee(orig.GasFeeCap(), percent),
+		Gas:        gas,
+		To:         to,
+		Value:      value,
+		Data:       data,
+		AccessList: accessList,
+	})
+}
+
+// SignReplacementTx signs an unsigned transaction built by ReplacementTx for chainID.
+func SignReplacementTx(chainID *big.Int, unsigned *ethtypes.Transaction, key *ecdsa.PrivateKey) (*ethtypes.Transaction, error) {
+	if chainID == nil {
+		return nil, fmt.Errorf("chain id is nil")
+	}
+	if key == nil {
+		return nil, fmt.Errorf("signing key is nil")
+	}
+	signedTx, err := ethtypes.SignTx(unsigned, ethtypes.LatestSignerForChainID(chainID), key)
+	if err != nil {
+		return nil, fmt.Errorf("sign replacement tx: %w", err)
+	}
+	return signedTx, nil
+}
@@ -0,0 +1,131 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package ethutil
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// TestBumpFee verifies fees are raised by the percentage rounded up, and always by at least one wei.
+func TestBumpFee(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		fee     int64
+		percent uint64
+		want    int64
+	}{
+		{fee: 100, percent: 10, want: 110},
+		{fee: 15, percent: 10, want: 17},
+		{fee: 5, percent: 10, want: 6},
+		{fee: 0, percent: 10, want: 1},
+		{fee: 1_000_000_000, percent: 25, want: 1_250_000_000},
+	} {
+		if got := BumpFee(big.NewInt(tc.fee), tc.percent); got.Int64() != tc.want {
+			t.Fatalf("BumpFee(%d, %d)=%s want %d.", tc.fee, tc.percent, got, tc.want)
+		}
+	}
+}
+
+// TestReplacementTx_speedUpAndCancel verifies a speed-up keeps the payload and nonce with bumped fees, and a
+// cancel is a zero-value self-transfer with the same nonce.
+func TestReplacementTx_speedUpAndCancel(t *testing.T) {
+	t.Parallel()
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	chainID := big.NewInt(1)
+	to := common.HexToAddress("0x0000000000000000000000000000000000000003")
+	orig, err := SignEIP1559(chainID, 4, 50_000, big.NewInt(9), []byte{0x01}, &to, big.NewInt(100), big.NewInt(1000), nil, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	unsigned, err := ReplacementTx(orig, from, false, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	speedUp, err := SignReplacementTx(chainID, unsigned, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if speedUp.Type() != ethtypes.DynamicFeeTxType || speedUp.Nonce() != 4 || *speedUp.To() != to ||
+		speedUp.Value().Int64() != 9 || speedUp.Gas() != 50_000 || len(speedUp.Data()) != 1 {
+		t.Fatalf("speed-up changed the payload: %+v.", speedUp)
+	}
+	if speedUp.GasTipCap().Int64() != 110 || speedUp.GasFeeCap().Int64() != 1100 {
+		t.Fatalf("tip=%s cap=%s want 110 1100.", speedUp.GasTipCap(), speedUp.GasFeeCap())
+	}
+	if sender, err := ethtypes.Sender(ethtypes.LatestSignerForChainID(chainID), speedUp); err != nil || sender != from {
+		t.Fatalf("sender=%s err=%v want %s.", sender, err, from)
+	}
+
+	unsigned, err = ReplacementTx(orig, from, true, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if unsigned.Nonce() != 4 || *unsigned.To() != from || unsigned.Value().Sign() != 0 || unsigned.Gas() != 21_000 ||
+		len(unsigned.Data()) != 0 || unsigned.GasTipCap().Int64() != 120 || unsigned.GasFeeCap().Int64() != 1200 {
+		t.Fatalf("cancel=%+v want zero-value self-transfer with nonce 4 and 20%% higher fees.", unsigned)
+	}
+}
+
+// TestReplacementTx_legacy verifies a type-0 speed-up bumps the gas price and signs with EIP-155.
+func TestReplacementTx_legacy(t *testing.T) {
+	t.Parallel()
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	chainID := big.NewInt(5)
+	to := common.HexToAddress("0x0000000000000000000000000000000000000003")
+	orig, err := SignType0EIP155(chainID, 1, 21_000, big.NewInt(1), nil, &to, big.NewInt(1_000), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unsigned, err := ReplacementTx(orig, crypto.PubkeyToAddress(key.PublicKey), false, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed, err := SignReplacementTx(chainID, unsigned, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signed.Type() != ethtypes.LegacyTxType || signed.GasPrice().Int64() != 1_100 || signed.ChainId().Int64() != 5 {
+		t.Fatalf("type=%d gas_price=%s chain_id=%s want legacy 1100 5.", signed.Type(), signed.GasPrice(), signed.ChainId())
+	}
+}
+
+// TestReplacementTx_rejectsBlob verifies blob transactions are not replaced.
+func TestReplacementTx_rejectsBlob(t *testing.T) {
+	t.Parallel()
+
+	blob := ethtypes.NewTx(&ethtypes.BlobTx{BlobHashes: []common.Hash{{0x01}}})
+	if _, err := ReplacementTx(blob, common.Address{}, false, 10); err == nil {
+		t.Fatal("ReplacementTx(blob) err=nil want error.")
+	}
+}
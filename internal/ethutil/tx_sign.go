@@ -19,12 +19,14 @@ package ethutil
 
 import (
 	"crypto/ecdsa"
+	"encoding/json"
 	"fmt"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/holiman/uint256"
 )
 
 // SignType0EIP155 builds and signs a type-0 transaction with EIP-155.
@@ -63,6 +65,48 @@ func SignType0EIP155(
 	return signedTx, nil
 }
 
+// SignEIP2930 builds and signs a type-1 (Berlin) access-list transaction with a fixed gas price.
+func SignEIP2930(
+	chainID *big.Int,
+	nonce, gasLimit uint64,
+	value *big.Int,
+	txData []byte,
+	toPtr *common.Address,
+	gasPrice *big.Int,
+	accessList ethtypes.AccessList,
+	key *ecdsa.PrivateKey,
+) (*ethtypes.Transaction, error) {
+	if chainID == nil {
+		return nil, fmt.Errorf("chain id is nil")
+	}
+	if key == nil {
+		return nil, fmt.Errorf("signing key is nil")
+	}
+	if value == nil {
+		value = big.NewInt(0)
+	}
+	if gasPrice == nil {
+		gasPrice = big.NewInt(0)
+	}
+	inner := &ethtypes.AccessListTx{
+		ChainID:    chainID,
+		Nonce:      nonce,
+		GasPrice:   gasPrice,
+		Gas:        gasLimit,
+		To:         toPtr,
+		Value:      value,
+		Data:       txData,
+		AccessList: accessList,
+	}
+	tx := ethtypes.NewTx(inner)
+	signer := ethtypes.NewEIP2930Signer(chainID)
+	signedTx, err := ethtypes.SignTx(tx, signer, key)
+	if err != nil {
+		return nil, fmt.Errorf("sign eip2930 tx: %w", err)
+	}
+	return signedTx, nil
+}
+
 // SignEIP1559 builds and signs a type-2 (London) transaction.
 func SignEIP1559(
 	chainID *big.Int,
@@ -112,6 +156,213 @@ func SignEIP1559(
 	return signedTx, nil
 }
 
+// SignEIP4844 builds and signs a type-3 (Cancun) blob transaction.
+//
+// Blob transactions cannot create contracts, so to is required. When sidecar is non-nil the
+// versioned hashes are taken from its commitments (and must match blobHashes if both are
+// given), and the returned tx carries the sidecar so MarshalBinary yields the network
+// wrapper form expected by eth_sendRawTransaction.
+func SignEIP4844(
+	chainID *big.Int,
+	nonce, gasLimit uint64,
+	value *big.Int,
+	txData []byte,
+	to common.Address,
+	tip, feeCap, blobFeeCap *big.Int,
+	blobHashes []common.Hash,
+	sidecar *ethtypes.BlobTxSidecar,
+	accessList ethtypes.AccessList,
+	key *ecdsa.PrivateKey,
+) (*ethtypes.Transaction, error) {
+	if chainID == nil {
+		return nil, fmt.Errorf("chain id is nil")
+	}
+	if key == nil {
+		return nil, fmt.Errorf("signing key is nil")
+	}
+	if tip == nil {
+		return nil, fmt.Errorf("max_priority_fee_per_gas is nil")
+	}
+	if feeCap == nil {
+		return nil, fmt.Errorf("max_fee_per_gas is nil")
+	}
+	if blobFeeCap == nil {
+		return nil, fmt.Errorf("max_fee_per_blob_gas is nil")
+	}
+	if value == nil {
+		value = big.NewInt(0)
+	}
+	if feeCap.Cmp(tip) < 0 {
+		return nil, fmt.Errorf("max_fee_per_gas must be >= max_priority_fee_per_gas")
+	}
+	if sidecar != nil {
+		if len(blobHashes) == 0 {
+			blobHashes = sidecar.BlobHashes()
+		} else if err := sidecar.ValidateBlobCommitmentHashes(blobHashes); err != nil {
+			return nil, fmt.Errorf("blob_versioned_hashes do not match blobs: %w", err)
+		}
+	}
+	if len(blobHashes) == 0 {
+		return nil, fmt.Errorf("blob transaction requires at least one blob hash")
+	}
+	chainID256, overflow := uint256.FromBig(chainID)
+	if overflow {
+		return nil, fmt.Errorf("chain id overflows 256 bits")
+	}
+	inner := &ethtypes.BlobTx{
+		ChainID:    chainID256,
+		Nonce:      nonce,
+		GasTipCap:  uint256.MustFromBig(tip),
+		GasFeeCap:  uint256.MustFromBig(feeCap),
+		Gas:        gasLimit,
+		To:         to,
+		Value:      uint256.MustFromBig(value),
+		Data:       txData,
+		AccessList: accessList,
+		BlobFeeCap: uint256.MustFromBig(blobFeeCap),
+		BlobHashes: blobHashes,
+		Sidecar:    sidecar,
+	}
+	tx := ethtypes.NewTx(inner)
+	signer := ethtypes.NewCancunSigner(chainID)
+	signedTx, err := ethtypes.SignTx(tx, signer, key)
+	if err != nil {
+		return nil, fmt.Errorf("sign eip4844 tx: %w", err)
+	}
+	return signedTx, nil
+}
+
+// SignSetCodeAuthorization signs an EIP-7702 authorization tuple (chain_id, address, nonce).
+//
+// A chain ID of zero authorizes the delegation on every chain. When the authority also sends
+// the carrying set-code tx, nonce must be the tx nonce + 1 because the sender nonce is bumped first.
+func SignSetCodeAuthorization(
+	chainID *big.Int,
+	address common.Address,
+	nonce uint64,
+	key *ecdsa.PrivateKey,
+) (ethtypes.SetCodeAuthorization, error) {
+	if chainID == nil {
+		return ethtypes.SetCodeAuthorization{}, fmt.Errorf("chain id is nil")
+	}
+	if key == nil {
+		return ethtypes.SetCodeAuthorization{}, fmt.Errorf("signing key is nil")
+	}
+	chainID256, overflow := uint256.FromBig(chainID)
+	if overflow {
+		return ethtypes.SetCodeAuthorization{}, fmt.Errorf("chain id overflows 256 bits")
+	}
+	auth, err := ethtypes.SignSetCode(key, ethtypes.SetCodeAuthorization{
+		ChainID: *chainID256,
+		Address: address,
+		Nonce:   nonce,
+	})
+	if err != nil {
+		return ethtypes.SetCodeAuthorization{}, fmt.Errorf("sign eip7702 authorization: %w", err)
+	}
+	return auth, nil
+}
+
+// SignEIP7702 builds and signs a type-4 (Prague) set-code transaction carrying authList.
+func SignEIP7702(
+	chainID *big.Int,
+	nonce, gasLimit uint64,
+	value *big.Int,
+	txData []byte,
+	to common.Address,
+	tip, feeCap *big.Int,
+	authList []ethtypes.SetCodeAuthorization,
+	accessList ethtypes.AccessList,
+	key *ecdsa.PrivateKey,
+) (*ethtypes.Transaction, error) {
+	if chainID == nil {
+		return nil, fmt.Errorf("chain id is nil")
+	}
+	if key == nil {
+		return nil, fmt.Errorf("signing key is nil")
+	}
+	if tip == nil {
+		return nil, fmt.Errorf("max_priority_fee_per_gas is nil")
+	}
+	if feeCap == nil {
+		return nil, fmt.Errorf("max_fee_per_gas is nil")
+	}
+	if value == nil {
+		value = big.NewInt(0)
+	}
+	if feeCap.Cmp(tip) < 0 {
+		return nil, fmt.Errorf("max_fee_per_gas must be >= max_priority_fee_per_gas")
+	}
+	if len(authList) == 0 {
+		return nil, fmt.Errorf("set-code transaction requires a non-empty authorization_list")
+	}
+	chainID256, overflow := uint256.FromBig(chainID)
+	if overflow {
+		return nil, fmt.Errorf("chain id overflows 256 bits")
+	}
+	inner := &ethtypes.SetCodeTx{
+		ChainID:    chainID256,
+		Nonce:      nonce,
+		GasTipCap:  uint256.MustFromBig(tip),
+		GasFeeCap:  uint256.MustFromBig(feeCap),
+		Gas:        gasLimit,
+		To:         to,
+		Value:      uint256.MustFromBig(value),
+		Data:       txData,
+		AccessList: accessList,
+		AuthList:   authList,
+	}
+	tx := ethtypes.NewTx(inner)
+	signer := ethtypes.NewPragueSigner(chainID)
+	signedTx, err := ethtypes.SignTx(tx, signer, key)
+	if err != nil {
+		return nil, fmt.Errorf("sign eip7702 tx: %w", err)
+	}
+	return signedTx, nil
+}
+
+// SetCodeAuthorizationResponseData builds the Vault response data map for a signed authorization.
+//
+// The "authorization" value is the JSON-RPC encoding accepted back by ParseAuthorizationListJSON.
+func SetCodeAuthorizationResponseData(auth ethtypes.SetCodeAuthorization) (map[string]interface{}, error) {
+	authority, err := auth.Authority()
+	if err != nil {
+		return nil, fmt.Errorf("recover authority: %w", err)
+	}
+	encoded, err := json.Marshal(auth)
+	if err != nil {
+		return nil, fmt.Errorf("encode authorization: %w", err)
+	}
+	return map[string]interface{}{
+		"chain_id":      auth.ChainID.Dec(),
+		"address":       auth.Address.Hex(),
+		"nonce":         auth.Nonce,
+		"y_parity":      auth.V,
+		"r":             hexutil.EncodeBig(auth.R.ToBig()),
+		"s":             hexutil.EncodeBig(auth.S.ToBig()),
+		"authority":     authority.Hex(),
+		"authorization": string(encoded),
+	}, nil
+}
+
+// TxTypeLabel returns the sign-tx path name of tx's type ("legacy", "eip2930", "eip1559", "blob" or "eip7702"),
+// or "unknown".
+func TxTypeLabel(tx *ethtypes.Transaction) string {
+	switch tx.Type() {
+	case ethtypes.LegacyTxType:
+		return "legacy"
+	case ethtypes.AccessListTxType:
+		return "eip2930"
+	case ethtypes.DynamicFeeTxType:
+		return "eip1559"
+	case ethtypes.BlobTxType:
+		return "blob"
+	case ethtypes.SetCodeTxType:
+		return "eip7702"
+	}
+	return "unknown"
+}
+
 // SignedTxResponseData builds the Vault response data map for a signed transaction.
 //
 // The response is derived from the signed transaction itself (to/value/gas/fee/type), and the
@@ -133,7 +384,9 @@ func SignedTxResponseData(signedTx *ethtypes.Transaction) (map[string]interface{
 
 	// MarshalBinary returns the wire format (EIP-2718 type byte + payload for type-1/2 txs).
 	// EncodeRLP alone omits the type prefix for typed txs, which breaks ethers/eth_sendRawTransaction.
-	raw, err := signedTx.MarshalBinary()
+	// Blob txs are reported in canonical (block) form here; the network wrapper with blobs,
+	// commitments and proofs is added below when the sidecar is present.
+	raw, err := signedTx.WithoutBlobTxSidecar().MarshalBinary()
 	if err != nil {
 		return nil, fmt.Errorf("marshal signed tx: %w", err)
 	}
@@ -143,20 +396,11 @@ func SignedTxResponseData(signedTx *ethtypes.Transaction) (map[string]interface{
 		toHex = toPtr.Hex()
 	}
 
-	txType := "unknown"
-	switch signedTx.Type() {
-	case ethtypes.LegacyTxType:
-		txType = "legacy"
-	case ethtypes.AccessListTxType:
-		txType = "eip2930"
-	case ethtypes.DynamicFeeTxType:
-		txType = "eip1559"
-	}
-
 	gasPriceOrFeeCap := ""
-	if signedTx.Type() == ethtypes.DynamicFeeTxType {
+	switch signedTx.Type() {
+	case ethtypes.DynamicFeeTxType, ethtypes.BlobTxType, ethtypes.SetCodeTxType:
 		gasPriceOrFeeCap = signedTx.GasFeeCap().String()
-	} else {
+	default:
 		gasPriceOrFeeCap = signedTx.GasPrice().String()
 	}
 
@@ -165,14 +409,47 @@ func SignedTxResponseData(signedTx *ethtypes.Transaction) (map[string]interface{
 		value = big.NewInt(0)
 	}
 
-	return map[string]interface{}{
-		"type":               txType,
+	out := map[string]interface{}{
+		"type":               TxTypeLabel(signedTx),
 		"transaction_hash":   signedTx.Hash().Hex(),
 		"signed_transaction": hexutil.Encode(raw),
 		"address_from":       from.Hex(),
 		"address_to":         toHex,
+		"nonce":              signedTx.Nonce(),
 		"value":              value.String(),
 		"gas_limit":          signedTx.Gas(),
 		"gas_price":          gasPriceOrFeeCap,
-	}, nil
+	}
+	if signedTx.Type() == ethtypes.BlobTxType {
+		hashes := make([]string, 0, len(signedTx.BlobHashes()))
+		for _, h := range signedTx.BlobHashes() {
+			hashes = append(hashes, h.Hex())
+		}
+		out["max_fee_per_blob_gas"] = signedTx.BlobGasFeeCap().String()
+		out["blob_versioned_hashes"] = hashes
+		if signedTx.BlobTxSidecar() != nil {
+			wrapped, err := signedTx.MarshalBinary()
+			if err != nil {
+				return nil, fmt.Errorf("marshal blob tx network form: %w", err)
+			}
+			out["signed_transaction_network"] = hexutil.Encode(wrapped)
+		}
+	}
+	if signedTx.Type() == ethtypes.SetCodeTxType {
+		auths := make([]interface{}, 0, len(signedTx.SetCodeAuthorizations()))
+		for _, auth := range signedTx.SetCodeAuthorizations() {
+			authority := ""
+			if addr, err := auth.Authority(); err == nil {
+				authority = addr.Hex()
+			}
+			auths = append(auths, map[string]interface{}{
+				"chain_id":  auth.ChainID.Dec(),
+				"address":   auth.Address.Hex(),
+				"nonce":     auth.Nonce,
+				"authority": authority,
+			})
+		}
+		out["authorization_list"] = auths
+	}
+	return out, nil
 }
@@ -22,8 +22,10 @@ import (
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	ethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
 )
 
 // TestSignType0EIP155 verifies a type-0 EIP-155 signed tx recovers to the signer address.
@@ -107,6 +109,75 @@ func TestSignType0EIP155_nilChainID(t *testing.T) {
 	}
 }
 
+// TestSignEIP2930 verifies an access-list tx carries its access list and recovers to the signer address.
+func TestSignEIP2930(t *testing.T) {
+	t.Parallel()
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chainID := big.NewInt(1)
+	gasPrice := big.NewInt(1_000_000_000)
+	to := common.HexToAddress("0x0000000000000000000000000000000000000003")
+	al := ethtypes.AccessList{
+		{
+			Address:     common.HexToAddress("0x0000000000000000000000000000000000000004"),
+			StorageKeys: []common.Hash{common.HexToHash("0x01")},
+		},
+	}
+
+	tx, err := SignEIP2930(chainID, 3, 30_000, big.NewInt(5), nil, &to, gasPrice, al, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.Type() != ethtypes.AccessListTxType {
+		t.Fatalf("tx.Type()=%d want %d.", tx.Type(), ethtypes.AccessListTxType)
+	}
+	if tx.GasPrice().Cmp(gasPrice) != 0 {
+		t.Fatalf("tx.GasPrice()=%s want %s.", tx.GasPrice(), gasPrice)
+	}
+	if len(tx.AccessList()) != 1 || tx.AccessList()[0].Address != al[0].Address {
+		t.Fatalf("tx.AccessList()=%v want %v.", tx.AccessList(), al)
+	}
+
+	signer := ethtypes.NewEIP2930Signer(chainID)
+	from, err := ethtypes.Sender(signer, tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantFrom := crypto.PubkeyToAddress(key.PublicKey)
+	if from != wantFrom {
+		t.Fatalf("from=%s want %s.", from, wantFrom)
+	}
+}
+
+// TestSignEIP2930_nilKey verifies SignEIP2930 returns an error when the signing key is nil.
+func TestSignEIP2930_nilKey(t *testing.T) {
+	t.Parallel()
+
+	_, err := SignEIP2930(big.NewInt(1), 0, 0, big.NewInt(0), nil, nil, big.NewInt(0), nil, nil)
+	if err == nil {
+		t.Fatal("expected error.")
+	}
+}
+
+// TestSignEIP2930_nilChainID verifies SignEIP2930 returns an error when chain ID is nil.
+func TestSignEIP2930_nilChainID(t *testing.T) {
+	t.Parallel()
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = SignEIP2930(nil, 0, 0, big.NewInt(0), nil, nil, big.NewInt(0), nil, key)
+	if err == nil {
+		t.Fatal("expected error.")
+	}
+}
+
 // TestSignEIP1559 verifies a dynamic-fee tx recovers to the signer address.
 func TestSignEIP1559(t *testing.T) {
 	t.Parallel()
@@ -227,6 +298,250 @@ func TestSignEIP1559_nilFeeCap(t *testing.T) {
 	}
 }
 
+// TestSignEIP4844 verifies a blob tx built from versioned hashes recovers to the signer address.
+func TestSignEIP4844(t *testing.T) {
+	t.Parallel()
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chainID := big.NewInt(1)
+	to := common.HexToAddress("0x0000000000000000000000000000000000000005")
+	hashes := []common.Hash{common.HexToHash("0x0100000000000000000000000000000000000000000000000000000000000001")}
+
+	tx, err := SignEIP4844(chainID, 1, 21_000, nil, nil, to, big.NewInt(1), big.NewInt(2), big.NewInt(3), hashes, nil, nil, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.Type() != ethtypes.BlobTxType {
+		t.Fatalf("tx.Type()=%d want %d.", tx.Type(), ethtypes.BlobTxType)
+	}
+	if tx.BlobGasFeeCap().Int64() != 3 {
+		t.Fatalf("tx.BlobGasFeeCap()=%s want 3.", tx.BlobGasFeeCap())
+	}
+
+	signer := ethtypes.NewCancunSigner(chainID)
+	from, err := ethtypes.Sender(signer, tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if from != crypto.PubkeyToAddress(key.PublicKey) {
+		t.Fatalf("from=%s want %s.", from, crypto.PubkeyToAddress(key.PublicKey))
+	}
+
+	got, err := SignedTxResponseData(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got["type"] != "blob" {
+		t.Fatalf("type=%v want %v.", got["type"], "blob")
+	}
+	if got["max_fee_per_blob_gas"] != "3" {
+		t.Fatalf("max_fee_per_blob_gas=%v want 3.", got["max_fee_per_blob_gas"])
+	}
+	if _, ok := got["signed_transaction_network"]; ok {
+		t.Fatal("signed_transaction_network should be absent without a sidecar.")
+	}
+}
+
+// TestSignEIP4844_withSidecar verifies hashes are derived from the sidecar and the network form is reported.
+func TestSignEIP4844_withSidecar(t *testing.T) {
+	t.Parallel()
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sc, err := NewBlobSidecar([]kzg4844.Blob{{}}, ethtypes.BlobSidecarVersion0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	to := common.HexToAddress("0x0000000000000000000000000000000000000005")
+
+	tx, err := SignEIP4844(big.NewInt(1), 0, 21_000, nil, nil, to, big.NewInt(1), big.NewInt(2), big.NewInt(3), nil, sc, nil, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tx.BlobHashes()) != 1 || tx.BlobHashes()[0] != sc.BlobHashes()[0] {
+		t.Fatalf("tx.BlobHashes()=%v want %v.", tx.BlobHashes(), sc.BlobHashes())
+	}
+
+	got, err := SignedTxResponseData(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	canonical, _ := got["signed_transaction"].(string)
+	network, _ := got["signed_transaction_network"].(string)
+	if network == "" || len(network) <= len(canonical) {
+		t.Fatalf("network form len=%d want > canonical len=%d.", len(network), len(canonical))
+	}
+	var decoded ethtypes.Transaction
+	if err := decoded.UnmarshalBinary(hexutil.MustDecode(network)); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.BlobTxSidecar() == nil || decoded.Hash() != tx.Hash() {
+		t.Fatal("network form must round-trip with sidecar and the same hash.")
+	}
+}
+
+// TestSignEIP4844_mismatchedHashes verifies caller hashes must match the sidecar commitments.
+func TestSignEIP4844_mismatchedHashes(t *testing.T) {
+	t.Parallel()
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sc, err := NewBlobSidecar([]kzg4844.Blob{{}}, ethtypes.BlobSidecarVersion0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wrong := []common.Hash{common.HexToHash("0x0100000000000000000000000000000000000000000000000000000000000009")}
+	_, err = SignEIP4844(big.NewInt(1), 0, 0, nil, nil, common.Address{}, big.NewInt(1), big.NewInt(1), big.NewInt(1), wrong, sc, nil, key)
+	if err == nil {
+		t.Fatal("expected error.")
+	}
+}
+
+// TestSignEIP4844_requiresBlobHashes verifies a blob tx with neither hashes nor sidecar is rejected.
+func TestSignEIP4844_requiresBlobHashes(t *testing.T) {
+	t.Parallel()
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = SignEIP4844(big.NewInt(1), 0, 0, nil, nil, common.Address{}, big.NewInt(1), big.NewInt(1), big.NewInt(1), nil, nil, nil, key)
+	if err == nil {
+		t.Fatal("expected error.")
+	}
+}
+
+// TestSignSetCodeAuthorization verifies the authorization recovers to the signer and allows chain ID 0.
+func TestSignSetCodeAuthorization(t *testing.T) {
+	t.Parallel()
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	delegate := common.HexToAddress("0x0000000000000000000000000000000000000007")
+
+	auth, err := SignSetCodeAuthorization(big.NewInt(0), delegate, 5, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	authority, err := auth.Authority()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if authority != crypto.PubkeyToAddress(key.PublicKey) {
+		t.Fatalf("authority=%s want %s.", authority, crypto.PubkeyToAddress(key.PublicKey))
+	}
+	if auth.Address != delegate || auth.Nonce != 5 || !auth.ChainID.IsZero() {
+		t.Fatalf("auth=%+v want address %s nonce 5 chain 0.", auth, delegate)
+	}
+
+	data, err := SetCodeAuthorizationResponseData(auth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data["authority"] != authority.Hex() {
+		t.Fatalf("authority=%v want %s.", data["authority"], authority.Hex())
+	}
+	if data["chain_id"] != "0" {
+		t.Fatalf("chain_id=%v want 0.", data["chain_id"])
+	}
+}
+
+// TestSignSetCodeAuthorization_nilKey verifies SignSetCodeAuthorization returns an error when the signing key is nil.
+func TestSignSetCodeAuthorization_nilKey(t *testing.T) {
+	t.Parallel()
+
+	_, err := SignSetCodeAuthorization(big.NewInt(1), common.Address{}, 0, nil)
+	if err == nil {
+		t.Fatal("expected error.")
+	}
+}
+
+// TestSignEIP7702 verifies a set-code tx recovers to the signer and reports its authorities.
+func TestSignEIP7702(t *testing.T) {
+	t.Parallel()
+
+	sender, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	authorityKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chainID := big.NewInt(1)
+	delegate := common.HexToAddress("0x0000000000000000000000000000000000000007")
+	to := crypto.PubkeyToAddress(authorityKey.PublicKey)
+	auth, err := SignSetCodeAuthorization(chainID, delegate, 0, authorityKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx, err := SignEIP7702(
+		chainID, 2, 100_000, nil, nil, to, big.NewInt(1), big.NewInt(2),
+		[]ethtypes.SetCodeAuthorization{auth}, nil, sender,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.Type() != ethtypes.SetCodeTxType {
+		t.Fatalf("tx.Type()=%d want %d.", tx.Type(), ethtypes.SetCodeTxType)
+	}
+
+	signer := ethtypes.NewPragueSigner(chainID)
+	from, err := ethtypes.Sender(signer, tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if from != crypto.PubkeyToAddress(sender.PublicKey) {
+		t.Fatalf("from=%s want %s.", from, crypto.PubkeyToAddress(sender.PublicKey))
+	}
+
+	got, err := SignedTxResponseData(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got["type"] != "eip7702" {
+		t.Fatalf("type=%v want %v.", got["type"], "eip7702")
+	}
+	if got["gas_price"] != "2" {
+		t.Fatalf("gas_price=%v want 2.", got["gas_price"])
+	}
+	auths, _ := got["authorization_list"].([]interface{})
+	if len(auths) != 1 {
+		t.Fatalf("len(authorization_list)=%d want 1.", len(auths))
+	}
+	entry, _ := auths[0].(map[string]interface{})
+	if entry["authority"] != to.Hex() {
+		t.Fatalf("authority=%v want %s.", entry["authority"], to.Hex())
+	}
+}
+
+// TestSignEIP7702_requiresAuthorizations verifies SignEIP7702 rejects an empty authorization list.
+func TestSignEIP7702_requiresAuthorizations(t *testing.T) {
+	t.Parallel()
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = SignEIP7702(big.NewInt(1), 0, 0, nil, nil, common.Address{}, big.NewInt(1), big.NewInt(2), nil, nil, key)
+	if err == nil {
+		t.Fatal("expected error.")
+	}
+}
+
 // TestSignedTxResponseData verifies SignedTxResponseData fields for a legacy signed transfer transaction.
 func TestSignedTxResponseData(t *testing.T) {
 	t.Parallel()
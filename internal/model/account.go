@@ -27,13 +27,15 @@ import (
 	"github.com/bsostech/vault-blockchain/pkg/utils"
 )
 
-// Account holds address and key material as hex strings. Loaded from single-key mode
-// accounts/<name>/address storage or built in-memory from a derived HD key to reuse
-// ECIES/ECDSA helpers.
+// Account holds address and key material as hex strings. Stored as one key version of a
+// single-key AccountKeyring (or the legacy accounts/<name>/address entry) or built in-memory
+// from a derived HD key to reuse ECIES/ECDSA helpers.
 type Account struct {
 	AddressStr    string `json:"address"` // Ethereum account address derived from the private key
 	PrivateKeyStr string `json:"private_key"`
 	PublicKeyStr  string `json:"public_key"` // Ethereum public key derived from the private key
+	// CreatedAt is the Unix time a rotated single-key version was generated; zero for original keys.
+	CreatedAt int64 `json:"created_at,omitempty"`
 }
 
 // NewAccount builds an Account from hex address and key strings.
@@ -0,0 +1,144 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package model
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto/ecies"
+
+	"github.com/bsostech/vault-blockchain/pkg/utils"
+)
+
+var (
+	// ErrKeyVersionOutOfRange is returned when a requested key version or version bound is outside its allowed range.
+	ErrKeyVersionOutOfRange = errors.New("key version out of range")
+	// ErrNoDecryptionVersion is returned when min_decryption_version leaves no key version to decrypt with.
+	ErrNoDecryptionVersion = errors.New("no key version is allowed to decrypt")
+)
+
+// AccountKeyring is the versioned key history of a single-key account, stored at accounts/<name>/keys.
+// Versions start at 1. Encryption and signing default to the latest version; signing may name any version from
+// MinSigningVersion up, and decryption tries every version from MinDecryptionVersion up, like the transit
+// engine's min_encryption_version/min_decryption_version model. MinDecryptionVersion never exceeds
+// MinSigningVersion, which never exceeds LatestVersion.
+type AccountKeyring struct {
+	LatestVersion        int              `json:"latest_version"`
+	MinSigningVersion    int              `json:"min_signing_version"`
+	MinDecryptionVersion int              `json:"min_decryption_version"`
+	Keys                 map[int]*Account `json:"keys"`
+}
+
+// NewAccountKeyring returns a keyring holding first as version 1.
+func NewAccountKeyring(first *Account) *AccountKeyring {
+	return &AccountKeyring{
+		LatestVersion:        1,
+		MinSigningVersion:    1,
+		MinDecryptionVersion: 1,
+		Keys:                 map[int]*Account{1: first},
+	}
+}
+
+// Rotate adds next as the new latest version and returns its number. The version bounds are left unchanged, so
+// earlier versions can still sign when named explicitly until MinSigningVersion is raised.
+func (k *AccountKeyring) Rotate(next *Account, now time.Time) int {
+	if k.Keys == nil {
+		k.Keys = make(map[int]*Account)
+	}
+	next.CreatedAt = now.Unix()
+	k.LatestVersion++
+	k.Keys[k.LatestVersion] = next
+	return k.LatestVersion
+}
+
+// SigningAccount returns the latest key version, the one used to encrypt and to sign by default.
+func (k *AccountKeyring) SigningAccount() (*Account, error) {
+	return k.SigningAccountVersion(0)
+}
+
+// SigningAccountVersion returns key version v for signing, or the latest version when v is 0. Versions outside
+// min_signing_version..latest_version yield an error wrapping ErrKeyVersionOutOfRange.
+func (k *AccountKeyring) SigningAccountVersion(v int) (*Account, error) {
+	if v == 0 {
+		v = k.LatestVersion
+	}
+	if v < 1 || v < k.MinSigningVersion || v > k.LatestVersion {
+		return nil, fmt.Errorf("%w: key_version must be between %d and %d", ErrKeyVersionOutOfRange,
+			k.MinSigningVersion, k.LatestVersion)
+	}
+	acct, ok := k.Keys[v]
+	if !ok || acct == nil || acct.PrivateKeyStr == "" {
+		return nil, fmt.Errorf("key version %d is missing", v)
+	}
+	return acct, nil
+}
+
+// DecryptionVersions returns the versions allowed to decrypt, newest first.
+func (k *AccountKeyring) DecryptionVersions() []int {
+	var out []int
+	for v := k.LatestVersion; v >= k.MinDecryptionVersion && v >= 1; v-- {
+		if acct, ok := k.Keys[v]; ok && acct != nil && acct.PrivateKeyStr != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// SetMinVersions sets the oldest versions allowed to sign and to decrypt. minSigning must be between 1 and the
+// latest version, and minDecryption between 1 and minSigning; nothing changes when either is out of range.
+func (k *AccountKeyring) SetMinVersions(minSigning, minDecryption int) error {
+	if minSigning < 1 || minSigning > k.LatestVersion {
+		return fmt.Errorf("%w: min_signing_version must be between 1 and %d", ErrKeyVersionOutOfRange, k.LatestVersion)
+	}
+	if minDecryption < 1 || minDecryption > minSigning {
+		return fmt.Errorf("%w: min_decryption_version must be between 1 and min_signing_version %d",
+			ErrKeyVersionOutOfRange, minSigning)
+	}
+	k.MinSigningVersion = minSigning
+	k.MinDecryptionVersion = minDecryption
+	return nil
+}
+
+// Decrypt opens an ECIES ciphertext with the allowed decryption versions, newest first, and returns the
+// plaintext together with the version that opened it.
+func (k *AccountKeyring) Decrypt(ciphertext []byte) ([]byte, int, error) {
+	versions := k.DecryptionVersions()
+	if len(versions) == 0 {
+		return nil, 0, ErrNoDecryptionVersion
+	}
+	for _, version := range versions {
+		plaintext, err := decryptWithAccount(k.Keys[version], ciphertext)
+		if err == nil {
+			return plaintext, version, nil
+		}
+	}
+	return nil, 0, fmt.Errorf("ecies decrypt: no key version from %d to %d opens the ciphertext",
+		k.LatestVersion, k.MinDecryptionVersion)
+}
+
+// decryptWithAccount decrypts ciphertext with one key version's ECIES private key.
+func decryptWithAccount(acct *Account, ciphertext []byte) ([]byte, error) {
+	ecdsaKey, err := acct.GetPrivateKeyECDSA()
+	if err != nil {
+		return nil, fmt.Errorf("ecdsa private key: %w", err)
+	}
+	defer utils.ZeroKey(ecdsaKey)
+	return ecies.ImportECDSA(ecdsaKey).Decrypt(ciphertext, nil, nil)
+}
@@ -0,0 +1,106 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package model
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestAccountKeyring_Rotate verifies rotation appends a version, signs with it by default and leaves the version
+// bounds unchanged.
+func TestAccountKeyring_Rotate(t *testing.T) {
+	t.Parallel()
+
+	k := NewAccountKeyring(NewAccount("0x1", "aa", ""))
+	if got := k.Rotate(NewAccount("0x2", "bb", ""), time.Unix(100, 0)); got != 2 {
+		t.Fatalf("Rotate=%d want 2", got)
+	}
+	if k.MinSigningVersion != 1 || k.MinDecryptionVersion != 1 {
+		t.Fatalf("min_signing=%d min_decryption=%d", k.MinSigningVersion, k.MinDecryptionVersion)
+	}
+	if k.Keys[2].CreatedAt != 100 {
+		t.Fatalf("CreatedAt=%d", k.Keys[2].CreatedAt)
+	}
+	signer, err := k.SigningAccount()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signer.AddressStr != "0x2" {
+		t.Fatalf("signing address=%q", signer.AddressStr)
+	}
+	if got := k.DecryptionVersions(); !reflect.DeepEqual(got, []int{2, 1}) {
+		t.Fatalf("DecryptionVersions=%v", got)
+	}
+}
+
+// TestAccountKeyring_SigningAccountVersion verifies explicit versions sign only within min_signing..latest.
+func TestAccountKeyring_SigningAccountVersion(t *testing.T) {
+	t.Parallel()
+
+	k := NewAccountKeyring(NewAccount("0x1", "aa", ""))
+	k.Rotate(NewAccount("0x2", "bb", ""), time.Unix(0, 0))
+	signer, err := k.SigningAccountVersion(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signer.AddressStr != "0x1" {
+		t.Fatalf("version 1 address=%q", signer.AddressStr)
+	}
+	if err := k.SetMinVersions(2, 1); err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []int{-1, 1, 3} {
+		if _, err := k.SigningAccountVersion(v); !errors.Is(err, ErrKeyVersionOutOfRange) {
+			t.Fatalf("SigningAccountVersion(%d) err=%v", v, err)
+		}
+	}
+	if signer, err := k.SigningAccountVersion(0); err != nil || signer.AddressStr != "0x2" {
+		t.Fatalf("SigningAccountVersion(0)=%v, %v", signer, err)
+	}
+}
+
+// TestAccountKeyring_SetMinVersions verifies min_decryption <= min_signing <= latest is enforced and the bounds
+// trim DecryptionVersions.
+func TestAccountKeyring_SetMinVersions(t *testing.T) {
+	t.Parallel()
+
+	k := NewAccountKeyring(NewAccount("0x1", "aa", ""))
+	k.Rotate(NewAccount("0x2", "bb", ""), time.Unix(0, 0))
+	for _, tc := range []struct{ signing, decryption int }{
+		{0, 1},
+		{3, 1},
+		{1, 0},
+		{1, 2},
+	} {
+		if err := k.SetMinVersions(tc.signing, tc.decryption); !errors.Is(err, ErrKeyVersionOutOfRange) {
+			t.Fatalf("SetMinVersions(%d, %d) err=%v", tc.signing, tc.decryption, err)
+		}
+	}
+	if k.MinSigningVersion != 1 || k.MinDecryptionVersion != 1 {
+		t.Fatalf("rejected bounds changed keyring: min_signing=%d min_decryption=%d", k.MinSigningVersion, k.MinDecryptionVersion)
+	}
+	if err := k.SetMinVersions(2, 2); err != nil {
+		t.Fatal(err)
+	}
+	if got := k.DecryptionVersions(); !reflect.DeepEqual(got, []int{2}) {
+		t.Fatalf("DecryptionVersions=%v", got)
+	}
+}
@@ -0,0 +1,84 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Address owner types recorded in the address index.
+const (
+	AddressOwnerWallet  = "wallet"
+	AddressOwnerAccount = "account"
+)
+
+// AddressOwner is the address index entry for one Ethereum address, stored at addresses/<address>. It names
+// the wallet-derived account (WalletID, Account segment, Index) or single-key account (Name, key Version)
+// whose key produced the address.
+type AddressOwner struct {
+	Type     string `json:"type"`
+	WalletID string `json:"wallet_id,omitempty"`
+	Account  string `json:"account,omitempty"`
+	Index    string `json:"index,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Version  int    `json:"version,omitempty"`
+}
+
+// NewWalletAddressOwner returns the owner entry of a derived account.
+func NewWalletAddressOwner(walletID, account, index string) *AddressOwner {
+	return &AddressOwner{Type: AddressOwnerWallet, WalletID: walletID, Account: account, Index: index}
+}
+
+// NewAccountAddressOwner returns the owner entry of a single-key account key version.
+func NewAccountAddressOwner(name string, version int) *AddressOwner {
+	return &AddressOwner{Type: AddressOwnerAccount, Name: name, Version: version}
+}
+
+// SameResource reports whether o and other belong to the same wallet or single-key account.
+func (o *AddressOwner) SameResource(other *AddressOwner) bool {
+	if o == nil || other == nil || o.Type != other.Type {
+		return false
+	}
+	if o.Type == AddressOwnerWallet {
+		return o.WalletID == other.WalletID
+	}
+	return o.Name == other.Name
+}
+
+// PathPrefix returns the path of the owning account, to which signing suffixes such as /sign-tx/eip1559 append.
+func (o *AddressOwner) PathPrefix() string {
+	if o.Type == AddressOwnerWallet {
+		if o.Account == "" || o.Account == "0" {
+			return "wallets/" + o.WalletID + "/accounts/" + o.Index
+		}
+		return "wallets/" + o.WalletID + "/accounts/" + o.Account + "/" + o.Index
+	}
+	return "accounts/" + o.Name
+}
+
+// NormalizeAddress returns the lowercase 0x form of a hex address, the form address index keys use.
+func NormalizeAddress(address string) (string, error) {
+	address = strings.TrimSpace(address)
+	if !common.IsHexAddress(address) {
+		return "", fmt.Errorf("invalid address %q", address)
+	}
+	return strings.ToLower(common.HexToAddress(address).Hex()), nil
+}
@@ -0,0 +1,73 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package model
+
+import "testing"
+
+// TestNormalizeAddress verifies mixed-case and unprefixed addresses map to one lowercase key and junk is rejected.
+func TestNormalizeAddress(t *testing.T) {
+	t.Parallel()
+	want := "0x9858effd232b4033e47d90003d41ec34ecaeda94"
+	for _, in := range []string{
+		"0x9858EfFD232B4033E47d90003D41EC34EcaEda94",
+		" 9858effd232b4033e47d90003d41ec34ecaeda94 ",
+		"0X9858EFFD232B4033E47D90003D41EC34ECAEDA94",
+	} {
+		got, err := NormalizeAddress(in)
+		if err != nil || got != want {
+			t.Fatalf("NormalizeAddress(%q)=%q, %v want %q", in, got, err, want)
+		}
+	}
+	if _, err := NormalizeAddress("0x1234"); err == nil {
+		t.Fatal("want error for short address")
+	}
+}
+
+// TestAddressOwner_PathPrefix verifies owner entries map to the signing path of their account.
+func TestAddressOwner_PathPrefix(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		owner *AddressOwner
+		want  string
+	}{
+		{NewWalletAddressOwner("w1", "0", "4"), "wallets/w1/accounts/4"},
+		{NewWalletAddressOwner("w1", "2", "4"), "wallets/w1/accounts/2/4"},
+		{NewAccountAddressOwner("alice", 3), "accounts/alice"},
+	} {
+		if got := tc.owner.PathPrefix(); got != tc.want {
+			t.Fatalf("PathPrefix=%q want %q", got, tc.want)
+		}
+	}
+}
+
+// TestAddressOwner_SameResource verifies ownership compares the wallet or account, not the index or version.
+func TestAddressOwner_SameResource(t *testing.T) {
+	t.Parallel()
+	if !NewWalletAddressOwner("w1", "0", "1").SameResource(NewWalletAddressOwner("w1", "3", "7")) {
+		t.Fatal("want same wallet")
+	}
+	if NewWalletAddressOwner("w1", "0", "1").SameResource(NewWalletAddressOwner("w2", "0", "1")) {
+		t.Fatal("want different wallets")
+	}
+	if !NewAccountAddressOwner("alice", 1).SameResource(NewAccountAddressOwner("alice", 2)) {
+		t.Fatal("want same account")
+	}
+	if NewAccountAddressOwner("w1", 1).SameResource(NewWalletAddressOwner("w1", "0", "1")) {
+		t.Fatal("want different owner types")
+	}
+}
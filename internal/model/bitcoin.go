@@ -0,0 +1,177 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package model
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+// Bitcoin networks accepted by the wallet bitcoin paths.
+const (
+	BitcoinNetworkMainnet = "mainnet"
+	BitcoinNetworkTestnet = "testnet"
+	BitcoinNetworkRegtest = "regtest"
+)
+
+// BitcoinNetworks lists every accepted Bitcoin network name.
+var BitcoinNetworks = []string{BitcoinNetworkMainnet, BitcoinNetworkTestnet, BitcoinNetworkRegtest}
+
+// bip84Purpose is the purpose' segment of BIP-84 native SegWit (P2WPKH) paths.
+const bip84Purpose = 84
+
+// BitcoinNetworkParams returns the chain parameters for network; empty means mainnet.
+func BitcoinNetworkParams(network string) (*chaincfg.Params, error) {
+	switch strings.ToLower(strings.TrimSpace(network)) {
+	case "", BitcoinNetworkMainnet:
+		return &chaincfg.MainNetParams, nil
+	case BitcoinNetworkTestnet:
+		return &chaincfg.TestNet3Params, nil
+	case BitcoinNetworkRegtest:
+		return &chaincfg.RegressionNetParams, nil
+	default:
+		return nil, fmt.Errorf("network %q is unknown (want one of %s)", network, strings.Join(BitcoinNetworks, ", "))
+	}
+}
+
+// bip84CoinType returns the coin_type' segment for params: 0 on mainnet and 1 on every test network (SLIP-44).
+func bip84CoinType(params *chaincfg.Params) uint32 {
+	if params.Net == chaincfg.MainNetParams.Net {
+		return 0
+	}
+	return 1
+}
+
+// BIP84Path returns the child indices of m/84'/<coin_type>'/0'/<change>/<index> for params.
+func BIP84Path(params *chaincfg.Params, change bool, index uint32) []uint32 {
+	var changeIdx uint32
+	if change {
+		changeIdx = 1
+	}
+	return []uint32{
+		hdkeychain.HardenedKeyStart + bip84Purpose,
+		hdkeychain.HardenedKeyStart + bip84CoinType(params),
+		hdkeychain.HardenedKeyStart,
+		changeIdx,
+		index,
+	}
+}
+
+// ParseBIP84Path reports whether path is a BIP-84 account 0 address path for params and returns its
+// change flag and address index.
+func ParseBIP84Path(params *chaincfg.Params, path []uint32) (change bool, index uint32, ok bool) {
+	if len(path) != 5 {
+		return false, 0, false
+	}
+	want := BIP84Path(params, false, 0)
+	for i := 0; i < 3; i++ {
+		if path[i] != want[i] {
+			return false, 0, false
+		}
+	}
+	if path[3] > 1 || path[4] > MaxBIP44AddressIndex {
+		return false, 0, false
+	}
+	return path[3] == 1, path[4], true
+}
+
+// FormatDerivationPath renders child indices as a BIP-32 path string such as m/84'/0'/0'/0/0.
+func FormatDerivationPath(path []uint32) string {
+	var b strings.Builder
+	b.WriteString("m")
+	for _, idx := range path {
+		if idx >= hdkeychain.HardenedKeyStart {
+			fmt.Fprintf(&b, "/%d'", idx-hdkeychain.HardenedKeyStart)
+		} else {
+			fmt.Fprintf(&b, "/%d", idx)
+		}
+	}
+	return b.String()
+}
+
+// BitcoinAccount is the public metadata of a BIP-84 native SegWit address; it is derived on demand and not stored.
+type BitcoinAccount struct {
+	Address        string
+	DerivationPath string
+	PublicKey      string
+}
+
+// BitcoinAccount derives the P2WPKH (bech32) address at m/84'/<coin_type>'/0'/<change>/<index> on network
+// from the wallet's mnemonic and passphrase. The wallet's Ethereum path template does not apply.
+func (s *WalletSeed) BitcoinAccount(network string, change bool, index uint32) (*BitcoinAccount, error) {
+	if err := ValidateAddressIndex(uint64(index)); err != nil {
+		return nil, fmt.Errorf("validate index: %w", err)
+	}
+	params, err := BitcoinNetworkParams(network)
+	if err != nil {
+		return nil, err
+	}
+	path := BIP84Path(params, change, index)
+	key, err := s.BitcoinPrivateKey(path)
+	if err != nil {
+		return nil, err
+	}
+	defer key.Zero()
+	pub := key.PubKey().SerializeCompressed()
+	addr, err := btcutil.NewAddressWitnessPubKeyHash(btcutil.Hash160(pub), params)
+	if err != nil {
+		return nil, fmt.Errorf("p2wpkh address: %w", err)
+	}
+	return &BitcoinAccount{
+		Address:        addr.EncodeAddress(),
+		DerivationPath: FormatDerivationPath(path),
+		PublicKey:      hex.EncodeToString(pub),
+	}, nil
+}
+
+// BitcoinPrivateKey derives the secp256k1 private key at the BIP-32 child indices path.
+// Callers must Zero the key when done.
+func (s *WalletSeed) BitcoinPrivateKey(path []uint32) (*btcec.PrivateKey, error) {
+	cur, err := deriveExtendedKey(s.Mnemonic, s.Passphrase, path)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Zero()
+	key, err := cur.ECPrivKey()
+	if err != nil {
+		return nil, fmt.Errorf("hd leaf secp256k1: %w", err)
+	}
+	return key, nil
+}
+
+// MasterKeyFingerprint returns the BIP-32 master key fingerprint (the first four bytes of the hash160 of the
+// master public key) as read from PSBT derivation records, i.e. little-endian.
+func (s *WalletSeed) MasterKeyFingerprint() (uint32, error) {
+	master, err := deriveExtendedKey(s.Mnemonic, s.Passphrase, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer master.Zero()
+	pub, err := master.ECPubKey()
+	if err != nil {
+		return 0, fmt.Errorf("hd master public key: %w", err)
+	}
+	return binary.LittleEndian.Uint32(btcutil.Hash160(pub.SerializeCompressed())[:4]), nil
+}
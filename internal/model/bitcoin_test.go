@@ -0,0 +1,108 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package model
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestWalletSeedBitcoinAccount checks the BIP-84 reference vectors and the test network variants.
+func TestWalletSeedBitcoinAccount(t *testing.T) {
+	t.Parallel()
+	seed := &WalletSeed{Mnemonic: testMnemonicHD}
+	cases := []struct {
+		network string
+		change  bool
+		index   uint32
+		address string
+		path    string
+	}{
+		{"", false, 0, "bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu", "m/84'/0'/0'/0/0"},
+		{BitcoinNetworkMainnet, false, 1, "bc1qnjg0jd8228aq7egyzacy8cys3knf9xvrerkf9g", "m/84'/0'/0'/0/1"},
+		{BitcoinNetworkMainnet, true, 0, "bc1q8c6fshw2dlwun7ekn9qwf37cu2rn755upcp6el", "m/84'/0'/0'/1/0"},
+		{BitcoinNetworkTestnet, false, 0, "tb1q6rz28mcfaxtmd6v789l9rrlrusdprr9pqcpvkl", "m/84'/1'/0'/0/0"},
+	}
+	for _, c := range cases {
+		got, err := seed.BitcoinAccount(c.network, c.change, c.index)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Address != c.address || got.DerivationPath != c.path {
+			t.Fatalf("%s %v %d: got %s %s want %s %s", c.network, c.change, c.index, got.Address, got.DerivationPath, c.address, c.path)
+		}
+	}
+	first, err := seed.BitcoinAccount("", false, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.PublicKey != "0330d54fd0dd420a6e5f8d3624f5f3482cae350f79d5f0753bf5beef9c2d91af3c" {
+		t.Fatalf("public key: got %s", first.PublicKey)
+	}
+	regtest, err := seed.BitcoinAccount(BitcoinNetworkRegtest, false, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(regtest.Address, "bcrt1q") || regtest.DerivationPath != "m/84'/1'/0'/0/0" {
+		t.Fatalf("regtest: got %s %s", regtest.Address, regtest.DerivationPath)
+	}
+	if _, err := seed.BitcoinAccount("signet", false, 0); err == nil {
+		t.Fatal("expected unknown network error")
+	}
+}
+
+// TestWalletSeedMasterKeyFingerprint checks the fingerprint against the BIP-84 reference mnemonic (73c5da0a).
+func TestWalletSeedMasterKeyFingerprint(t *testing.T) {
+	t.Parallel()
+	fp, err := (&WalletSeed{Mnemonic: testMnemonicHD}).MasterKeyFingerprint()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fp != 0x0adac573 {
+		t.Fatalf("fingerprint: got %08x", fp)
+	}
+}
+
+// TestParseBIP84Path accepts receive and change paths of account 0 and rejects other layouts.
+func TestParseBIP84Path(t *testing.T) {
+	t.Parallel()
+	params, err := BitcoinNetworkParams(BitcoinNetworkTestnet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	change, index, ok := ParseBIP84Path(params, BIP84Path(params, true, 7))
+	if !ok || !change || index != 7 {
+		t.Fatalf("got %v %d %v", change, index, ok)
+	}
+	mainnet, err := BitcoinNetworkParams("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{"m/84'/0'/0'/0/0", "m/84'/1'/1'/0/0", "m/84'/1'/0'/2/0", "m/44'/1'/0'/0/0", "m/84'/1'/0'/0"} {
+		indices, err := ParseDerivationPath(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, _, ok := ParseBIP84Path(params, indices); ok {
+			t.Fatalf("%s: accepted on testnet", path)
+		}
+	}
+	if _, _, ok := ParseBIP84Path(mainnet, BIP84Path(mainnet, false, 0)); !ok {
+		t.Fatal("mainnet path rejected")
+	}
+}
@@ -0,0 +1,107 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package model
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ErrChainPolicyViolation is wrapped by every ChainPolicy.Check rejection so handlers can map it to a client error.
+var ErrChainPolicyViolation = errors.New("chain policy violation")
+
+// ChainPolicy is the signing policy for one registered chain; stored at chains/<chain_id>.
+//
+// Empty MaxValue and MaxGasPrice mean no limit.
+type ChainPolicy struct {
+	Name                  string `json:"name"`
+	MaxValue              string `json:"max_value,omitempty"`
+	MaxGasPrice           string `json:"max_gas_price,omitempty"`
+	AllowContractCreation bool   `json:"allow_contract_creation"`
+}
+
+// TxPolicyInput carries the transaction fields that ChainPolicy and DestinationPolicy inspect.
+//
+// FeeCap is gas_price for legacy and EIP-2930 transactions and max_fee_per_gas for dynamic-fee types;
+// nil skips the max_gas_price rule (the signer rejects a missing fee on its own).
+type TxPolicyInput struct {
+	ChainID *big.Int
+	Value   *big.Int
+	FeeCap  *big.Int
+	To      *common.Address
+	Data    []byte
+	// Replaces is the hash of the pending transaction this one replaces; its spend is not counted twice.
+	Replaces string
+	// Delegates are the EIP-7702 delegation addresses of a set-code transaction's authorization list; each must
+	// pass the destination policy.
+	Delegates []common.Address
+}
+
+// Validate checks that the limits are non-negative decimal integers.
+func (p *ChainPolicy) Validate() error {
+	if strings.TrimSpace(p.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	if _, err := parseOptionalLimit("max_value", p.MaxValue); err != nil {
+		return err
+	}
+	if _, err := parseOptionalLimit("max_gas_price", p.MaxGasPrice); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Check returns an error wrapping ErrChainPolicyViolation and naming the rule that rejects in.
+func (p *ChainPolicy) Check(in *TxPolicyInput) error {
+	label := fmt.Sprintf("chain %s (%s)", in.ChainID.String(), p.Name)
+	if in.To == nil && !p.AllowContractCreation {
+		return fmt.Errorf("%w: %s allow_contract_creation: contract creation is not allowed", ErrChainPolicyViolation, label)
+	}
+	maxValue, err := parseOptionalLimit("max_value", p.MaxValue)
+	if err != nil {
+		return err
+	}
+	if maxValue != nil && in.Value != nil && in.Value.Cmp(maxValue) > 0 {
+		return fmt.Errorf("%w: %s max_value: value %s exceeds %s", ErrChainPolicyViolation, label, in.Value.String(), maxValue.String())
+	}
+	maxGasPrice, err := parseOptionalLimit("max_gas_price", p.MaxGasPrice)
+	if err != nil {
+		return err
+	}
+	if maxGasPrice != nil && in.FeeCap != nil && in.FeeCap.Cmp(maxGasPrice) > 0 {
+		return fmt.Errorf("%w: %s max_gas_price: fee %s exceeds %s", ErrChainPolicyViolation, label, in.FeeCap.String(), maxGasPrice.String())
+	}
+	return nil
+}
+
+// parseOptionalLimit parses a decimal wei limit; the empty string means no limit and returns nil.
+func parseOptionalLimit(field, raw string) (*big.Int, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, nil
+	}
+	v, ok := new(big.Int).SetString(raw, 10)
+	if !ok || v.Sign() < 0 {
+		return nil, fmt.Errorf("%s %q is not a non-negative decimal integer", field, raw)
+	}
+	return v, nil
+}
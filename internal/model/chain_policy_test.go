@@ -0,0 +1,79 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package model
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// TestChainPolicy_Validate rejects a missing name and non-decimal limits.
+func TestChainPolicy_Validate(t *testing.T) {
+	t.Parallel()
+	if err := (&ChainPolicy{Name: "mainnet", MaxValue: "100", MaxGasPrice: ""}).Validate(); err != nil {
+		t.Fatalf("valid policy: %v", err)
+	}
+	bad := []*ChainPolicy{
+		{},
+		{Name: "x", MaxValue: "-1"},
+		{Name: "x", MaxGasPrice: "0x10"},
+	}
+	for i, p := range bad {
+		if err := p.Validate(); err == nil {
+			t.Fatalf("case %d: expected error for %+v", i, p)
+		}
+	}
+}
+
+// TestChainPolicy_Check verifies each rule rejects with its own name and passing input is accepted.
+func TestChainPolicy_Check(t *testing.T) {
+	t.Parallel()
+	p := &ChainPolicy{Name: "mainnet", MaxValue: "1000", MaxGasPrice: "50"}
+	to := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	ok := &TxPolicyInput{ChainID: big.NewInt(1), Value: big.NewInt(1000), FeeCap: big.NewInt(50), To: &to}
+	if err := p.Check(ok); err != nil {
+		t.Fatalf("within limits: %v", err)
+	}
+
+	cases := []struct {
+		in   *TxPolicyInput
+		rule string
+	}{
+		{&TxPolicyInput{ChainID: big.NewInt(1), Value: big.NewInt(1)}, "allow_contract_creation"},
+		{&TxPolicyInput{ChainID: big.NewInt(1), Value: big.NewInt(1001), To: &to}, "max_value"},
+		{&TxPolicyInput{ChainID: big.NewInt(1), Value: big.NewInt(1), FeeCap: big.NewInt(51), To: &to}, "max_gas_price"},
+	}
+	for _, c := range cases {
+		err := p.Check(c.in)
+		if !errors.Is(err, ErrChainPolicyViolation) {
+			t.Fatalf("%s: got %v", c.rule, err)
+		}
+		if !strings.Contains(err.Error(), c.rule) {
+			t.Fatalf("error %q does not name rule %s", err, c.rule)
+		}
+	}
+
+	p.AllowContractCreation = true
+	if err := p.Check(&TxPolicyInput{ChainID: big.NewInt(1), Value: big.NewInt(0)}); err != nil {
+		t.Fatalf("contract creation allowed: %v", err)
+	}
+}
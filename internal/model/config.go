@@ -0,0 +1,196 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package model
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// Default mount configuration values, used when no config entry is stored or a field is unset.
+const (
+	// DefaultMaxBatchDerivedAccounts caps how many derived accounts one batch request may create.
+	DefaultMaxBatchDerivedAccounts = 10000
+	// DefaultMaxBulkReadDerivedSpan is the maximum inclusive range size (end - start + 1) for bulk metadata read.
+	DefaultMaxBulkReadDerivedSpan = 10000
+	// DefaultMnemonicStrength is the BIP-39 entropy size in bits for generated mnemonics (24 words).
+	DefaultMnemonicStrength = 256
+	// DefaultGasLimit is the gas limit used by sign-tx requests that omit gas_limit.
+	DefaultGasLimit uint64 = 21000
+	// MinReplacementFeeBumpPercent is the smallest fee increase geth's transaction pool accepts for a
+	// same-nonce replacement, and the default for sign-tx/replace and sign-tx/cancel.
+	MinReplacementFeeBumpPercent = 10
+)
+
+// Signing modes that can be restricted with Config.AllowedSigningModes.
+const (
+	SigningModeLegacy            = "legacy"
+	SigningModeEIP2930           = "eip2930"
+	SigningModeEIP1559           = "eip1559"
+	SigningModeBlob              = "blob"
+	SigningModeEIP7702           = "eip7702"
+	SigningModeSign              = "sign"
+	SigningModeSignMessage       = "sign-message"
+	SigningModeSignEIP712        = "sign-eip712"
+	SigningModeSignAuthorization = "sign-authorization"
+	SigningModeBitcoinPSBT       = "bitcoin-psbt"
+	SigningModeSolanaTx          = "solana-tx"
+)
+
+// SigningModes lists every signing mode accepted in Config.AllowedSigningModes.
+var SigningModes = []string{
+	SigningModeLegacy,
+	SigningModeEIP2930,
+	SigningModeEIP1559,
+	SigningModeBlob,
+	SigningModeEIP7702,
+	SigningModeSign,
+	SigningModeSignMessage,
+	SigningModeSignEIP712,
+	SigningModeSignAuthorization,
+	SigningModeBitcoinPSBT,
+	SigningModeSolanaTx,
+}
+
+// Config holds mount-level settings; stored at config.
+//
+// Zero values mean "use the default": the limits fall back to the Default* constants and empty
+// allow-lists permit every chain ID and signing mode.
+type Config struct {
+	MaxBatchDerivedAccounts int      `json:"max_batch_derived_accounts,omitempty"`
+	MaxBulkReadDerivedSpan  int      `json:"max_bulk_read_derived_span,omitempty"`
+	MnemonicStrength        int      `json:"mnemonic_strength,omitempty"`
+	DefaultGasLimit         uint64   `json:"default_gas_limit,omitempty"`
+	AllowedChainIDs         []string `json:"allowed_chain_ids,omitempty"`
+	AllowedSigningModes     []string `json:"allowed_signing_modes,omitempty"`
+	DefaultDerivationPath   string   `json:"default_derivation_path,omitempty"`
+	// DeletionRetentionSeconds is how long soft-deleted wallets and accounts stay restorable.
+	DeletionRetentionSeconds int64 `json:"deletion_retention_seconds,omitempty"`
+	// AllowKeyExport enables exporting single-key accounts as keystore v3 JSON; off by default.
+	AllowKeyExport bool `json:"allow_key_export,omitempty"`
+	// ReplacementFeeBumpPercent is how much sign-tx/replace and sign-tx/cancel raise the fees of the replaced
+	// transaction.
+	ReplacementFeeBumpPercent int `json:"replacement_fee_bump_percent,omitempty"`
+}
+
+// WithDefaults returns a copy of c with unset fields replaced by their defaults.
+func (c *Config) WithDefaults() *Config {
+	out := &Config{}
+	if c != nil {
+		*out = *c
+	}
+	if out.MaxBatchDerivedAccounts <= 0 {
+		out.MaxBatchDerivedAccounts = DefaultMaxBatchDerivedAccounts
+	}
+	if out.MaxBulkReadDerivedSpan <= 0 {
+		out.MaxBulkReadDerivedSpan = DefaultMaxBulkReadDerivedSpan
+	}
+	if out.MnemonicStrength == 0 {
+		out.MnemonicStrength = DefaultMnemonicStrength
+	}
+	if out.DefaultGasLimit == 0 {
+		out.DefaultGasLimit = DefaultGasLimit
+	}
+	if out.DefaultDerivationPath == "" {
+		out.DefaultDerivationPath = DefaultEthereumDerivationPath
+	}
+	if out.DeletionRetentionSeconds <= 0 {
+		out.DeletionRetentionSeconds = int64(DefaultDeletionRetention.Seconds())
+	}
+	if out.ReplacementFeeBumpPercent <= 0 {
+		out.ReplacementFeeBumpPercent = MinReplacementFeeBumpPercent
+	}
+	return out
+}
+
+// DeletionRetention returns the restore window for soft-deleted wallets and accounts.
+func (c *Config) DeletionRetention() time.Duration {
+	if c == nil || c.DeletionRetentionSeconds <= 0 {
+		return DefaultDeletionRetention
+	}
+	return time.Duration(c.DeletionRetentionSeconds) * time.Second
+}
+
+// Validate checks field ranges and allow-list values.
+func (c *Config) Validate() error {
+	if c.MaxBatchDerivedAccounts < 0 {
+		return fmt.Errorf("max_batch_derived_accounts must be positive")
+	}
+	if c.MaxBulkReadDerivedSpan < 0 {
+		return fmt.Errorf("max_bulk_read_derived_span must be positive")
+	}
+	switch c.MnemonicStrength {
+	case 0, 128, 160, 192, 224, 256:
+	default:
+		return fmt.Errorf("mnemonic_strength must be one of 128, 160, 192, 224, 256")
+	}
+	for _, id := range c.AllowedChainIDs {
+		if bi, ok := new(big.Int).SetString(id, 10); !ok || bi.Sign() <= 0 {
+			return fmt.Errorf("allowed_chain_ids entry %q is not a positive decimal integer", id)
+		}
+	}
+	for _, mode := range c.AllowedSigningModes {
+		if !isKnownSigningMode(mode) {
+			return fmt.Errorf("allowed_signing_modes entry %q is unknown (want one of %s)", mode, strings.Join(SigningModes, ", "))
+		}
+	}
+	if c.DeletionRetentionSeconds < 0 {
+		return fmt.Errorf("deletion_retention must not be negative")
+	}
+	if c.ReplacementFeeBumpPercent != 0 && c.ReplacementFeeBumpPercent < MinReplacementFeeBumpPercent {
+		return fmt.Errorf("replacement_fee_bump_percent must be at least %d", MinReplacementFeeBumpPercent)
+	}
+	if c.DefaultDerivationPath != "" {
+		if err := ValidateDerivationTemplate(c.DefaultDerivationPath); err != nil {
+			return fmt.Errorf("default_derivation_path: %w", err)
+		}
+	}
+	return nil
+}
+
+// CheckSigning returns an error naming the rule that rejects mode or chainID.
+// chainID may be nil for modes that are not bound to a chain.
+func (c *Config) CheckSigning(mode string, chainID *big.Int) error {
+	if len(c.AllowedSigningModes) > 0 && !containsString(c.AllowedSigningModes, mode) {
+		return fmt.Errorf("signing mode %q is not in allowed_signing_modes", mode)
+	}
+	if chainID == nil || len(c.AllowedChainIDs) == 0 {
+		return nil
+	}
+	if !containsString(c.AllowedChainIDs, chainID.String()) {
+		return fmt.Errorf("chain_id %s is not in allowed_chain_ids", chainID.String())
+	}
+	return nil
+}
+
+// isKnownSigningMode reports whether mode is listed in SigningModes.
+func isKnownSigningMode(mode string) bool {
+	return containsString(SigningModes, mode)
+}
+
+// containsString reports whether list contains s.
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
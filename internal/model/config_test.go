@@ -0,0 +1,104 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package model
+
+import (
+	"math/big"
+	"testing"
+)
+
+// TestConfig_WithDefaults verifies unset fields fall back to the compile-time defaults.
+func TestConfig_WithDefaults(t *testing.T) {
+	t.Parallel()
+	var nilCfg *Config
+	got := nilCfg.WithDefaults()
+	if got.MaxBatchDerivedAccounts != DefaultMaxBatchDerivedAccounts {
+		t.Fatalf("MaxBatchDerivedAccounts=%d", got.MaxBatchDerivedAccounts)
+	}
+	if got.MaxBulkReadDerivedSpan != DefaultMaxBulkReadDerivedSpan {
+		t.Fatalf("MaxBulkReadDerivedSpan=%d", got.MaxBulkReadDerivedSpan)
+	}
+	if got.MnemonicStrength != DefaultMnemonicStrength {
+		t.Fatalf("MnemonicStrength=%d", got.MnemonicStrength)
+	}
+	if got.DefaultGasLimit != DefaultGasLimit {
+		t.Fatalf("DefaultGasLimit=%d", got.DefaultGasLimit)
+	}
+	if got.DefaultDerivationPath != DefaultEthereumDerivationPath {
+		t.Fatalf("DefaultDerivationPath=%q", got.DefaultDerivationPath)
+	}
+	if got.DeletionRetention() != DefaultDeletionRetention {
+		t.Fatalf("DeletionRetention=%s", got.DeletionRetention())
+	}
+	if got.ReplacementFeeBumpPercent != MinReplacementFeeBumpPercent {
+		t.Fatalf("ReplacementFeeBumpPercent=%d", got.ReplacementFeeBumpPercent)
+	}
+
+	custom := (&Config{MaxBatchDerivedAccounts: 5, DefaultGasLimit: 50000}).WithDefaults()
+	if custom.MaxBatchDerivedAccounts != 5 || custom.DefaultGasLimit != 50000 {
+		t.Fatalf("custom values not kept: %+v", custom)
+	}
+}
+
+// TestConfig_Validate rejects unknown modes, bad chain IDs, strengths and derivation paths.
+func TestConfig_Validate(t *testing.T) {
+	t.Parallel()
+	if err := (&Config{}).Validate(); err != nil {
+		t.Fatalf("empty config: %v", err)
+	}
+	bad := []*Config{
+		{MnemonicStrength: 100},
+		{DeletionRetentionSeconds: -1},
+		{ReplacementFeeBumpPercent: 5},
+		{AllowedChainIDs: []string{"0"}},
+		{AllowedChainIDs: []string{"mainnet"}},
+		{AllowedSigningModes: []string{"eth_sign"}},
+		{DefaultDerivationPath: "44'/60'"},
+	}
+	for _, c := range bad {
+		if err := c.Validate(); err == nil {
+			t.Fatalf("%+v: expected error", c)
+		}
+	}
+}
+
+// TestConfig_CheckSigning verifies the allow-lists and that an empty list permits everything.
+func TestConfig_CheckSigning(t *testing.T) {
+	t.Parallel()
+	open := &Config{}
+	if err := open.CheckSigning(SigningModeLegacy, big.NewInt(5)); err != nil {
+		t.Fatalf("open config: %v", err)
+	}
+
+	c := &Config{
+		AllowedChainIDs:     []string{"1", "11155111"},
+		AllowedSigningModes: []string{SigningModeEIP1559, SigningModeSignMessage},
+	}
+	if err := c.CheckSigning(SigningModeEIP1559, big.NewInt(11155111)); err != nil {
+		t.Fatalf("allowed: %v", err)
+	}
+	if err := c.CheckSigning(SigningModeSignMessage, nil); err != nil {
+		t.Fatalf("allowed without chain: %v", err)
+	}
+	if err := c.CheckSigning(SigningModeLegacy, big.NewInt(1)); err == nil {
+		t.Fatal("legacy: expected error")
+	}
+	if err := c.CheckSigning(SigningModeEIP1559, big.NewInt(5)); err == nil {
+		t.Fatal("chain 5: expected error")
+	}
+}
@@ -0,0 +1,185 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// ErrDestinationPolicyViolation is wrapped by every DestinationPolicy.Check rejection.
+var ErrDestinationPolicyViolation = errors.New("destination policy violation")
+
+// DestinationRuleWildcard matches any address or any selector in a destination rule.
+const DestinationRuleWildcard = "*"
+
+// DestinationPolicy restricts which `to` addresses and contract selectors a wallet or single-key account may sign for;
+// stored at wallets/<wallet_id>/policy or accounts/<name>/policy.
+//
+// Each rule is "<address>:<selector>" where either part may be "*"; a bare "<address>" means "<address>:*".
+// The selector is the first 4 bytes of the calldata as 0x-prefixed hex. Deny rules win over allow rules,
+// and an empty Allow list permits every destination that is not denied.
+type DestinationPolicy struct {
+	Allow []string `json:"allow,omitempty"`
+	Deny  []string `json:"deny,omitempty"`
+}
+
+// destinationRule is a parsed DestinationPolicy rule; empty fields are wildcards.
+type destinationRule struct {
+	address  string
+	selector string
+}
+
+// NormalizeDestinationRule parses raw and returns its canonical "<address>:<selector>" form
+// (lower-case hex, wildcards as "*").
+func NormalizeDestinationRule(raw string) (string, error) {
+	r, err := parseDestinationRule(raw)
+	if err != nil {
+		return "", err
+	}
+	return r.String(), nil
+}
+
+// parseDestinationRule parses one "<address>[:<selector>]" rule.
+func parseDestinationRule(raw string) (destinationRule, error) {
+	addrPart, selPart, _ := strings.Cut(strings.TrimSpace(raw), ":")
+	addrPart = strings.TrimSpace(addrPart)
+	selPart = strings.TrimSpace(selPart)
+	var r destinationRule
+	switch {
+	case addrPart == DestinationRuleWildcard:
+	case common.IsHexAddress(addrPart):
+		r.address = strings.ToLower(common.HexToAddress(addrPart).Hex())
+	default:
+		return r, fmt.Errorf("destination rule %q: address must be a hex address or %q", raw, DestinationRuleWildcard)
+	}
+	switch {
+	case selPart == "" || selPart == DestinationRuleWildcard:
+	default:
+		b, err := hexutil.Decode(selPart)
+		if err != nil || len(b) != 4 {
+			return r, fmt.Errorf("destination rule %q: selector must be 4 bytes of 0x-prefixed hex or %q", raw, DestinationRuleWildcard)
+		}
+		r.selector = hexutil.Encode(b)
+	}
+	return r, nil
+}
+
+// String returns the canonical "<address>:<selector>" form of r.
+func (r destinationRule) String() string {
+	addr, sel := r.address, r.selector
+	if addr == "" {
+		addr = DestinationRuleWildcard
+	}
+	if sel == "" {
+		sel = DestinationRuleWildcard
+	}
+	return addr + ":" + sel
+}
+
+// matches reports whether r covers a call to `to` (lower-case hex, empty for contract creation) with selector.
+func (r destinationRule) matches(to, selector string) bool {
+	if r.address != "" && r.address != to {
+		return false
+	}
+	return r.selector == "" || r.selector == selector
+}
+
+// Normalize rewrites every allow and deny rule into its canonical form, dropping blank entries,
+// and returns an error naming the first malformed rule.
+func (p *DestinationPolicy) Normalize() error {
+	var err error
+	if p.Allow, err = normalizeDestinationRules(p.Allow); err != nil {
+		return err
+	}
+	p.Deny, err = normalizeDestinationRules(p.Deny)
+	return err
+}
+
+// normalizeDestinationRules canonicalizes rules, skipping blank entries.
+func normalizeDestinationRules(rules []string) ([]string, error) {
+	var out []string
+	for _, raw := range rules {
+		if strings.TrimSpace(raw) == "" {
+			continue
+		}
+		r, err := NormalizeDestinationRule(raw)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, nil
+}
+
+// Check returns an error wrapping ErrDestinationPolicyViolation when the policy rejects a transaction to `to`
+// carrying data; owner labels the wallet or account in the error. A nil `to` (contract creation) only
+// matches rules whose address is "*".
+func (p *DestinationPolicy) Check(owner string, to *common.Address, data []byte) error {
+	toStr := ""
+	toLabel := "contract creation"
+	if to != nil {
+		toStr = strings.ToLower(to.Hex())
+		toLabel = toStr
+	}
+	selector := ""
+	selLabel := "none"
+	if len(data) >= 4 {
+		selector = hexutil.Encode(data[:4])
+		selLabel = selector
+	}
+	return p.check(owner, toStr, selector, "to "+toLabel+" selector "+selLabel)
+}
+
+// CheckDelegate returns an error wrapping ErrDestinationPolicyViolation when the policy rejects an EIP-7702
+// delegation to delegate. Delegated code runs for every later call to the account, so only rules whose
+// selector is "*" match a delegate.
+func (p *DestinationPolicy) CheckDelegate(owner string, delegate common.Address) error {
+	addr := strings.ToLower(delegate.Hex())
+	return p.check(owner, addr, "", "delegate "+addr)
+}
+
+// check applies the deny then allow rules to a call to `to` with selector; label describes it in errors.
+func (p *DestinationPolicy) check(owner, to, selector, label string) error {
+	for _, raw := range p.Deny {
+		r, err := parseDestinationRule(raw)
+		if err != nil {
+			return err
+		}
+		if r.matches(to, selector) {
+			return fmt.Errorf("%w: %s deny rule %s matches %s", ErrDestinationPolicyViolation, owner, r.String(), label)
+		}
+	}
+	if len(p.Allow) == 0 {
+		return nil
+	}
+	for _, raw := range p.Allow {
+		r, err := parseDestinationRule(raw)
+		if err != nil {
+			return err
+		}
+		if r.matches(to, selector) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s allow list has no rule matching %s", ErrDestinationPolicyViolation, owner, label)
+}
@@ -0,0 +1,140 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package model
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// TestNormalizeDestinationRule verifies canonical forms and rejection of malformed rules.
+func TestNormalizeDestinationRule(t *testing.T) {
+	t.Parallel()
+	good := map[string]string{
+		"0x00000000000000000000000000000000000000AA":   "0x00000000000000000000000000000000000000aa:*",
+		"0x00000000000000000000000000000000000000aa:*": "0x00000000000000000000000000000000000000aa:*",
+		"*:0xA9059CBB": "*:0xa9059cbb",
+		" 0x00000000000000000000000000000000000000aa:0xa9059cbb": "0x00000000000000000000000000000000000000aa:0xa9059cbb",
+	}
+	for in, want := range good {
+		got, err := NormalizeDestinationRule(in)
+		if err != nil || got != want {
+			t.Fatalf("%q: got %q, %v want %q", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "0x1234", "*:0x12", "*:transfer"} {
+		if _, err := NormalizeDestinationRule(in); err == nil {
+			t.Fatalf("%q: expected error", in)
+		}
+	}
+}
+
+// TestDestinationPolicy_Check verifies deny precedence, allow-list matching and wildcard handling.
+func TestDestinationPolicy_Check(t *testing.T) {
+	t.Parallel()
+	token := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	other := common.HexToAddress("0x00000000000000000000000000000000000000bb")
+	transfer := hexutil.MustDecode("0xa9059cbb0000")
+	approve := hexutil.MustDecode("0x095ea7b30000")
+
+	p := &DestinationPolicy{
+		Allow: []string{token.Hex() + ":*", "*:0xa9059cbb"},
+		Deny:  []string{other.Hex() + ":0xa9059cbb"},
+	}
+	allowed := []struct {
+		to   *common.Address
+		data []byte
+	}{
+		{&token, approve},
+		{&token, nil},
+		{nil, transfer},
+	}
+	for i, c := range allowed {
+		if err := p.Check("wallet w", c.to, c.data); err != nil {
+			t.Fatalf("case %d: %v", i, err)
+		}
+	}
+
+	denied := []struct {
+		to   *common.Address
+		data []byte
+		want string
+	}{
+		{&other, transfer, "deny rule"},
+		{&other, approve, "allow list"},
+		{&other, nil, "allow list"},
+		{nil, nil, "contract creation"},
+	}
+	for i, c := range denied {
+		err := p.Check("wallet w", c.to, c.data)
+		if !errors.Is(err, ErrDestinationPolicyViolation) || !strings.Contains(err.Error(), c.want) {
+			t.Fatalf("case %d: got %v want %s", i, err, c.want)
+		}
+	}
+
+	if err := (&DestinationPolicy{}).Check("wallet w", nil, nil); err != nil {
+		t.Fatalf("empty policy: %v", err)
+	}
+}
+
+// TestDestinationPolicy_CheckDelegate verifies delegates match only wildcard-selector rules and deny wins.
+func TestDestinationPolicy_CheckDelegate(t *testing.T) {
+	t.Parallel()
+	trusted := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	drainer := common.HexToAddress("0x00000000000000000000000000000000000000bb")
+	token := common.HexToAddress("0x00000000000000000000000000000000000000cc")
+
+	p := &DestinationPolicy{Allow: []string{trusted.Hex(), token.Hex() + ":0xa9059cbb"}}
+	if err := p.CheckDelegate("wallet w", trusted); err != nil {
+		t.Fatalf("trusted delegate: %v", err)
+	}
+	for _, d := range []common.Address{drainer, token} {
+		if err := p.CheckDelegate("wallet w", d); !errors.Is(err, ErrDestinationPolicyViolation) || !strings.Contains(err.Error(), "delegate") {
+			t.Fatalf("delegate %s: got %v want violation", d.Hex(), err)
+		}
+	}
+	deny := &DestinationPolicy{Deny: []string{drainer.Hex()}}
+	if err := deny.CheckDelegate("wallet w", drainer); !errors.Is(err, ErrDestinationPolicyViolation) {
+		t.Fatalf("denied delegate: got %v want violation", err)
+	}
+	if err := deny.CheckDelegate("wallet w", trusted); err != nil {
+		t.Fatalf("delegate not denied: %v", err)
+	}
+}
+
+// TestDestinationPolicy_Normalize verifies rules are canonicalized, blanks dropped and bad rules rejected.
+func TestDestinationPolicy_Normalize(t *testing.T) {
+	t.Parallel()
+	p := &DestinationPolicy{Allow: []string{"*:0xA9059CBB", " "}, Deny: []string{"0x00000000000000000000000000000000000000AA"}}
+	if err := p.Normalize(); err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Allow) != 1 || p.Allow[0] != "*:0xa9059cbb" {
+		t.Fatalf("Allow=%v", p.Allow)
+	}
+	if len(p.Deny) != 1 || p.Deny[0] != "0x00000000000000000000000000000000000000aa:*" {
+		t.Fatalf("Deny=%v", p.Deny)
+	}
+	if err := (&DestinationPolicy{Deny: []string{"nope"}}).Normalize(); err == nil {
+		t.Fatal("expected error")
+	}
+}
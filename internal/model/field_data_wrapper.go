@@ -216,6 +216,15 @@ func (f *FieldDataWrapper) GetUint64(key string, defaultValue uint64) uint64 {
 	return bigInt.Uint64()
 }
 
+// GetUint64NonEmpty is GetUint64 but also returns defaultValue when the field is an empty string
+// (unset TypeString fields without a schema Default read as "").
+func (f *FieldDataWrapper) GetUint64NonEmpty(key string, defaultValue uint64) uint64 {
+	if f.GetString(key, "") == "" {
+		return defaultValue
+	}
+	return f.GetUint64(key, defaultValue)
+}
+
 // MustGetUint64 parses a required decimal string field into uint64.
 func (f *FieldDataWrapper) MustGetUint64(key string) (uint64, error) {
 	bigInt, err := f.MustGetBigInt(key)
@@ -135,3 +135,16 @@ func TestGetBigInt_invalidReturnsDefault(t *testing.T) {
 		t.Fatalf("got %v want %v", got, def)
 	}
 }
+
+// TestGetUint64NonEmpty verifies empty fields fall back to the default while set fields are parsed.
+func TestGetUint64NonEmpty(t *testing.T) {
+	t.Parallel()
+
+	w := fieldDataForTest(t, map[string]interface{}{"set": "50000"}, "set", "unset")
+	if got := w.GetUint64NonEmpty("unset", 21000); got != 21000 {
+		t.Fatalf("unset: got %d want 21000", got)
+	}
+	if got := w.GetUint64NonEmpty("set", 21000); got != 50000 {
+		t.Fatalf("set: got %d want 50000", got)
+	}
+}
@@ -0,0 +1,138 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Journal operations recorded for signing and decryption requests. Sign-tx records use
+// JournalOpSignTxPrefix followed by the transaction type label (legacy, eip1559, ...). Bitcoin and Solana
+// records are prefixed with their chain.
+const (
+	JournalOpSign              = "sign"
+	JournalOpSignMessage       = "sign-message"
+	JournalOpSignEIP712        = "sign-eip712"
+	JournalOpSignAuthorization = "sign-authorization"
+	JournalOpSignTxPrefix      = "sign-tx/"
+	JournalOpDecrypt           = "decrypt"
+	JournalOpBitcoinSignPSBT   = "bitcoin/sign-psbt"
+	JournalOpSolanaSignTx      = "solana/sign-tx"
+)
+
+// ErrJournalBroken is wrapped by every VerifyJournal failure.
+var ErrJournalBroken = errors.New("journal chain is broken")
+
+// JournalRecord is one entry of a key's signing journal; stored at <key prefix>/journal/records/<seq>. Hash
+// covers every other field, including PrevHash, so editing or removing a record breaks the chain after it.
+type JournalRecord struct {
+	Seq           uint64 `json:"seq"`
+	Time          int64  `json:"time"`
+	EntityID      string `json:"entity_id,omitempty"`
+	Operation     string `json:"operation"`
+	Address       string `json:"address,omitempty"`
+	PayloadDigest string `json:"payload_digest"`
+	TxHash        string `json:"tx_hash,omitempty"`
+	PrevHash      string `json:"prev_hash,omitempty"`
+	Hash          string `json:"hash"`
+}
+
+// JournalHead points at the last record of a journal; stored at <key prefix>/journal/head.
+type JournalHead struct {
+	Seq  uint64 `json:"seq"`
+	Hash string `json:"hash"`
+}
+
+// JournalPayloadDigest returns the hex SHA-256 digest recorded for a signed or decrypted payload.
+func JournalPayloadDigest(payload []byte) string {
+	digest := sha256.Sum256(payload)
+	return hex.EncodeToString(digest[:])
+}
+
+// Link sets the record's sequence number and previous hash to follow head (nil for an empty journal) and
+// computes its hash.
+func (r *JournalRecord) Link(head *JournalHead) error {
+	r.Seq, r.PrevHash = 1, ""
+	if head != nil {
+		r.Seq = head.Seq + 1
+		r.PrevHash = head.Hash
+	}
+	hash, err := r.ComputeHash()
+	if err != nil {
+		return err
+	}
+	r.Hash = hash
+	return nil
+}
+
+// ComputeHash returns the hex SHA-256 digest of the record's JSON encoding with Hash left empty.
+func (r *JournalRecord) ComputeHash() (string, error) {
+	unsealed := *r
+	unsealed.Hash = ""
+	b, err := json.Marshal(&unsealed)
+	if err != nil {
+		return "", fmt.Errorf("encode journal record %d: %w", r.Seq, err)
+	}
+	digest := sha256.Sum256(b)
+	return hex.EncodeToString(digest[:]), nil
+}
+
+// Head returns the journal head pointing at r.
+func (r *JournalRecord) Head() *JournalHead {
+	return &JournalHead{Seq: r.Seq, Hash: r.Hash}
+}
+
+// VerifyJournal checks that records, ordered by Seq, run contiguously from 1, that each hash matches its
+// record and links to the previous one, and that the last record is the one head points at. On failure it
+// returns the sequence number of the first record that does not verify and an error wrapping ErrJournalBroken.
+func VerifyJournal(records []JournalRecord, head *JournalHead) (uint64, error) {
+	prevHash := ""
+	for i := range records {
+		r := &records[i]
+		want := uint64(i) + 1
+		if r.Seq != want {
+			return want, fmt.Errorf("%w: record %d is missing", ErrJournalBroken, want)
+		}
+		if r.PrevHash != prevHash {
+			return r.Seq, fmt.Errorf("%w: record %d does not link to record %d", ErrJournalBroken, r.Seq, r.Seq-1)
+		}
+		hash, err := r.ComputeHash()
+		if err != nil {
+			return r.Seq, err
+		}
+		if hash != r.Hash {
+			return r.Seq, fmt.Errorf("%w: record %d hash mismatch", ErrJournalBroken, r.Seq)
+		}
+		prevHash = r.Hash
+	}
+	var last uint64
+	if len(records) > 0 {
+		last = records[len(records)-1].Seq
+	}
+	switch {
+	case head == nil && last != 0:
+		return last, fmt.Errorf("%w: journal head is missing", ErrJournalBroken)
+	case head != nil && (head.Seq != last || head.Hash != prevHash):
+		return last + 1, fmt.Errorf("%w: journal head points at record %d but the chain ends at %d", ErrJournalBroken, head.Seq, last)
+	}
+	return 0, nil
+}
@@ -0,0 +1,106 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package model
+
+import (
+	"errors"
+	"testing"
+)
+
+// buildJournal returns n chained records and the head pointing at the last one.
+func buildJournal(t *testing.T, n int) ([]JournalRecord, *JournalHead) {
+	t.Helper()
+	var head *JournalHead
+	records := make([]JournalRecord, 0, n)
+	for i := 0; i < n; i++ {
+		r := JournalRecord{
+			Time:          1_700_000_000 + int64(i),
+			EntityID:      "entity-1",
+			Operation:     JournalOpSign,
+			Address:       "0xabc",
+			PayloadDigest: JournalPayloadDigest([]byte{byte(i)}),
+		}
+		if err := r.Link(head); err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, r)
+		head = r.Head()
+	}
+	return records, head
+}
+
+// TestJournalRecord_Link verifies sequence numbers start at 1 and each record links to the previous hash.
+func TestJournalRecord_Link(t *testing.T) {
+	t.Parallel()
+	records, head := buildJournal(t, 3)
+	if records[0].Seq != 1 || records[0].PrevHash != "" {
+		t.Fatalf("first record=%+v", records[0])
+	}
+	if records[2].Seq != 3 || records[2].PrevHash != records[1].Hash {
+		t.Fatalf("third record=%+v", records[2])
+	}
+	if head.Seq != 3 || head.Hash != records[2].Hash {
+		t.Fatalf("head=%+v", head)
+	}
+	if seq, err := VerifyJournal(records, head); err != nil {
+		t.Fatalf("VerifyJournal seq=%d err=%v", seq, err)
+	}
+	if seq, err := VerifyJournal(nil, nil); err != nil {
+		t.Fatalf("empty journal seq=%d err=%v", seq, err)
+	}
+}
+
+// TestVerifyJournal_detectsTampering verifies edits, removals and truncation are reported at the first bad record.
+func TestVerifyJournal_detectsTampering(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name   string
+		mutate func([]JournalRecord, *JournalHead) ([]JournalRecord, *JournalHead)
+		want   uint64
+	}{
+		{"edited", func(r []JournalRecord, h *JournalHead) ([]JournalRecord, *JournalHead) {
+			r[1].TxHash = "0xdead"
+			return r, h
+		}, 2},
+		{"rehashed", func(r []JournalRecord, h *JournalHead) ([]JournalRecord, *JournalHead) {
+			r[1].EntityID = "entity-2"
+			r[1].Hash, _ = r[1].ComputeHash()
+			return r, h
+		}, 3},
+		{"removed", func(r []JournalRecord, h *JournalHead) ([]JournalRecord, *JournalHead) {
+			return append(r[:1:1], r[2:]...), h
+		}, 2},
+		{"truncated", func(r []JournalRecord, h *JournalHead) ([]JournalRecord, *JournalHead) {
+			return r[:2], h
+		}, 3},
+		{"headless", func(r []JournalRecord, _ *JournalHead) ([]JournalRecord, *JournalHead) {
+			return r, nil
+		}, 3},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			records, head := buildJournal(t, 3)
+			records, head = tc.mutate(records, head)
+			seq, err := VerifyJournal(records, head)
+			if !errors.Is(err, ErrJournalBroken) || seq != tc.want {
+				t.Fatalf("seq=%d err=%v want seq %d.", seq, err, tc.want)
+			}
+		})
+	}
+}
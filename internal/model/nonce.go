@@ -0,0 +1,89 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package model
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// NonceAuto is the sign-tx `nonce` value that takes the next nonce from the tracker.
+const NonceAuto = "auto"
+
+// ErrNonceNotIssued is returned when releasing a nonce the tracker has not handed out yet.
+var ErrNonceNotIssued = errors.New("nonce has not been issued")
+
+// NonceTracker holds the nonces issued for one address on one chain; stored at nonces/<chain_id>/<address>.
+// Next is the lowest nonce never issued; Released lists lower nonces given back for dropped transactions,
+// which are issued again, lowest first, before Next.
+type NonceTracker struct {
+	Next     uint64   `json:"next"`
+	Released []uint64 `json:"released,omitempty"`
+}
+
+// Peek returns the nonce Take would issue.
+func (t *NonceTracker) Peek() uint64 {
+	if len(t.Released) > 0 {
+		return t.Released[0]
+	}
+	return t.Next
+}
+
+// Take issues the lowest released nonce, or Next.
+func (t *NonceTracker) Take() uint64 {
+	n := t.Peek()
+	t.MarkUsed(n)
+	return n
+}
+
+// MarkUsed records that nonce n has been signed or reserved: it leaves the released list, and Next moves past it.
+func (t *NonceTracker) MarkUsed(n uint64) {
+	if i, ok := slices.BinarySearch(t.Released, n); ok {
+		t.Released = slices.Delete(t.Released, i, i+1)
+	}
+	if n >= t.Next {
+		t.Next = n + 1
+	}
+}
+
+// Release gives back nonce n, e.g. for a dropped transaction, so it is issued again. Releasing the highest
+// issued nonce lowers Next instead of leaving a gap.
+func (t *NonceTracker) Release(n uint64) error {
+	if n >= t.Next {
+		return fmt.Errorf("%w: %d (next is %d)", ErrNonceNotIssued, n, t.Next)
+	}
+	if i, ok := slices.BinarySearch(t.Released, n); !ok {
+		t.Released = slices.Insert(t.Released, i, n)
+	}
+	for len(t.Released) > 0 && t.Released[len(t.Released)-1] == t.Next-1 {
+		t.Released = t.Released[:len(t.Released)-1]
+		t.Next--
+	}
+	return nil
+}
+
+// Set moves Next to next, e.g. to the chain's pending transaction count, and drops released nonces at or above it.
+func (t *NonceTracker) Set(next uint64) {
+	t.Next = next
+	i, _ := slices.BinarySearch(t.Released, next)
+	t.Released = t.Released[:i]
+	if len(t.Released) == 0 {
+		t.Released = nil
+	}
+}
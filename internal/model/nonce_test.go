@@ -0,0 +1,83 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package model
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// TestNonceTracker_gapFill verifies released nonces are issued again lowest first before Next, and releasing
+// the highest issued nonce lowers Next.
+func TestNonceTracker_gapFill(t *testing.T) {
+	t.Parallel()
+	tr := &NonceTracker{Next: 5}
+	if got := tr.Take(); got != 5 || tr.Next != 6 {
+		t.Fatalf("Take=%d next=%d want 5, 6.", got, tr.Next)
+	}
+	tr.MarkUsed(6)
+	tr.MarkUsed(7)
+	if err := tr.Release(6); err != nil {
+		t.Fatal(err)
+	}
+	if err := tr.Release(5); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(tr.Released, []uint64{5, 6}) || tr.Next != 8 {
+		t.Fatalf("tracker=%+v want released [5 6], next 8.", tr)
+	}
+	if got := tr.Take(); got != 5 {
+		t.Fatalf("Take=%d want 5.", got)
+	}
+	if err := tr.Release(7); err != nil {
+		t.Fatal(err)
+	}
+	if len(tr.Released) != 0 || tr.Next != 6 {
+		t.Fatalf("tracker=%+v want released [], next 6.", tr)
+	}
+	if got := tr.Take(); got != 6 || tr.Next != 7 {
+		t.Fatalf("Take=%d next=%d want 6, 7.", got, tr.Next)
+	}
+}
+
+// TestNonceTracker_releaseAndSet verifies unissued nonces cannot be released and Set drops released nonces at
+// or above the new Next.
+func TestNonceTracker_releaseAndSet(t *testing.T) {
+	t.Parallel()
+	tr := &NonceTracker{Next: 10}
+	if err := tr.Release(10); !errors.Is(err, ErrNonceNotIssued) {
+		t.Fatalf("err=%v want ErrNonceNotIssued.", err)
+	}
+	for _, n := range []uint64{2, 4, 6} {
+		if err := tr.Release(n); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tr.Release(4); err != nil {
+		t.Fatal(err)
+	}
+	tr.Set(5)
+	if !slices.Equal(tr.Released, []uint64{2, 4}) || tr.Next != 5 || tr.Peek() != 2 {
+		t.Fatalf("tracker=%+v want released [2 4], next 5.", tr)
+	}
+	tr.Set(1)
+	if tr.Released != nil || tr.Peek() != 1 {
+		t.Fatalf("tracker=%+v want no released, next 1.", tr)
+	}
+}
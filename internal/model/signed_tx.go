@@ -0,0 +1,27 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package model
+
+// SignedTxRecord keeps a transaction signed by this mount so it can later be replaced by hash;
+// stored at wallets/<wallet_id>/signed_txs/<tx_hash> or accounts/<name>/signed_txs/<tx_hash>.
+type SignedTxRecord struct {
+	// Raw is the 0x hex of the signed transaction in canonical form (blob transactions without sidecar).
+	Raw  string `json:"raw"`
+	From string `json:"from"`
+	Time int64  `json:"time"`
+}
@@ -0,0 +1,117 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package model
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcutil/base58"
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+
+	"github.com/bsostech/vault-blockchain/pkg/utils"
+)
+
+// solanaCoinType is the SLIP-44 coin type of Solana.
+const solanaCoinType = 501
+
+// slip10Ed25519Key is the HMAC key of the SLIP-0010 ed25519 master key.
+const slip10Ed25519Key = "ed25519 seed"
+
+// SolanaPath returns the child indices of m/44'/501'/<index>'/0', the layout used by Phantom and Solflare.
+// Every segment is hardened, as SLIP-0010 ed25519 derivation requires.
+func SolanaPath(index uint32) []uint32 {
+	return []uint32{
+		hdkeychain.HardenedKeyStart + 44,
+		hdkeychain.HardenedKeyStart + solanaCoinType,
+		hdkeychain.HardenedKeyStart + index,
+		hdkeychain.HardenedKeyStart,
+	}
+}
+
+// SolanaAccount is the public metadata of a Solana account; it is derived on demand and not stored.
+type SolanaAccount struct {
+	Address        string
+	DerivationPath string
+}
+
+// SolanaAccount derives the base58 address (the ed25519 public key) at m/44'/501'/<index>'/0' from the
+// wallet's mnemonic and passphrase. The wallet's Ethereum path template does not apply.
+func (s *WalletSeed) SolanaAccount(index uint32) (*SolanaAccount, error) {
+	key, err := s.SolanaPrivateKey(index)
+	if err != nil {
+		return nil, err
+	}
+	defer utils.ZeroBytes(key)
+	return &SolanaAccount{
+		Address:        base58.Encode(key.Public().(ed25519.PublicKey)),
+		DerivationPath: FormatDerivationPath(SolanaPath(index)),
+	}, nil
+}
+
+// SolanaPrivateKey derives the ed25519 private key at m/44'/501'/<index>'/0' via SLIP-0010.
+// Callers must zero the key when done.
+func (s *WalletSeed) SolanaPrivateKey(index uint32) (ed25519.PrivateKey, error) {
+	if err := ValidateAddressIndex(uint64(index)); err != nil {
+		return nil, fmt.Errorf("validate index: %w", err)
+	}
+	seed, err := bip39Seed(s.Mnemonic, s.Passphrase)
+	if err != nil {
+		return nil, err
+	}
+	defer utils.ZeroBytes(seed)
+	key, chainCode, err := deriveSLIP10Ed25519(seed, SolanaPath(index))
+	if err != nil {
+		return nil, err
+	}
+	defer utils.ZeroBytes(chainCode)
+	defer utils.ZeroBytes(key)
+	return ed25519.NewKeyFromSeed(key), nil
+}
+
+// deriveSLIP10Ed25519 walks the SLIP-0010 ed25519 tree from seed along path and returns the 32-byte private
+// key and chain code. ed25519 has no public derivation, so every index must be hardened. The caller must zero
+// both slices.
+func deriveSLIP10Ed25519(seed []byte, path []uint32) (key, chainCode []byte, err error) {
+	mac := hmac.New(sha512.New, []byte(slip10Ed25519Key))
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+	key, chainCode = sum[:32], sum[32:]
+
+	data := make([]byte, 37)
+	defer utils.ZeroBytes(data)
+	for _, idx := range path {
+		if idx < hdkeychain.HardenedKeyStart {
+			utils.ZeroBytes(sum)
+			return nil, nil, fmt.Errorf("slip-0010 ed25519 derivation needs hardened indices, got %d", idx)
+		}
+		data[0] = 0
+		copy(data[1:33], key)
+		binary.BigEndian.PutUint32(data[33:], idx)
+		mac = hmac.New(sha512.New, chainCode)
+		mac.Write(data)
+		next := mac.Sum(nil)
+		utils.ZeroBytes(sum)
+		sum = next
+		key, chainCode = sum[:32], sum[32:]
+	}
+	return key, chainCode, nil
+}
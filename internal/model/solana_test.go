@@ -0,0 +1,77 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package model
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+)
+
+// TestDeriveSLIP10Ed25519 checks SLIP-0010 ed25519 test vector 1 and rejects non-hardened indices.
+func TestDeriveSLIP10Ed25519(t *testing.T) {
+	t.Parallel()
+	seed, err := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		path      []uint32
+		key       string
+		chainCode string
+	}{
+		{nil, "2b4be7f19ee27bbf30c667b642d5f4aa69fd169872f8fc3059c08ebae2eb19e7", "90046a93de5380a72b5e45010748567d5ea02bbf6522f979e05c0d8d8ca9fffb"},
+		{[]uint32{hdkeychain.HardenedKeyStart}, "68e0fe46dfb67e368c75379acec591dad19df3cde26e63b93a8e704f1dade7a3", "8b59aa11380b624e81507a27fedda59fea6d0b779a778918a2fd3590e16e9c69"},
+	}
+	for _, c := range cases {
+		key, chainCode, err := deriveSLIP10Ed25519(seed, c.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(key) != c.key || hex.EncodeToString(chainCode) != c.chainCode {
+			t.Fatalf("%v: got %x %x", c.path, key, chainCode)
+		}
+	}
+	if _, _, err := deriveSLIP10Ed25519(seed, []uint32{0}); err == nil {
+		t.Fatal("expected error for non-hardened index")
+	}
+}
+
+// TestWalletSeedSolanaAccount checks the first Phantom address of the reference mnemonic and that the
+// passphrase changes the key.
+func TestWalletSeedSolanaAccount(t *testing.T) {
+	t.Parallel()
+	got, err := (&WalletSeed{Mnemonic: testMnemonicHD}).SolanaAccount(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Address != "HAgk14JpMQLgt6rVgv7cBQFJWFto5Dqxi472uT3DKpqk" || got.DerivationPath != "m/44'/501'/0'/0'" {
+		t.Fatalf("got %s %s", got.Address, got.DerivationPath)
+	}
+	other, err := (&WalletSeed{Mnemonic: testMnemonicHD, Passphrase: "x"}).SolanaAccount(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other.Address == got.Address {
+		t.Fatal("passphrase did not change the address")
+	}
+	if _, err := (&WalletSeed{Mnemonic: testMnemonicHD}).SolanaAccount(MaxBIP44AddressIndex + 1); err == nil {
+		t.Fatal("expected index out of range error")
+	}
+}
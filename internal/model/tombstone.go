@@ -0,0 +1,58 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package model
+
+import "time"
+
+// DefaultDeletionRetention is how long a soft-deleted wallet or account stays restorable when
+// Config.DeletionRetentionSeconds is unset.
+const DefaultDeletionRetention = 30 * 24 * time.Hour
+
+// Tombstone marks a soft-deleted wallet or single-key account; stored at wallets/<wallet_id>/tombstone or
+// accounts/<name>/tombstone. The entry keeps its key material but is refused for signing until restored.
+type Tombstone struct {
+	DeletedAt int64 `json:"deleted_at"`
+}
+
+// NewTombstone returns a tombstone recording now as the deletion time.
+func NewTombstone(now time.Time) *Tombstone {
+	return &Tombstone{DeletedAt: now.Unix()}
+}
+
+// RestoreDeadline returns the last moment the entry may be restored given retention.
+func (t *Tombstone) RestoreDeadline(retention time.Duration) time.Time {
+	return time.Unix(t.DeletedAt, 0).Add(retention)
+}
+
+// Restorable reports whether the entry may still be restored at now.
+func (t *Tombstone) Restorable(retention time.Duration, now time.Time) bool {
+	return !now.After(t.RestoreDeadline(retention))
+}
+
+// TombstoneResponseData builds the deletion fields shared by wallet and account status responses.
+// A nil tombstone yields deleted=false.
+func TombstoneResponseData(t *Tombstone, retention time.Duration) map[string]interface{} {
+	if t == nil {
+		return map[string]interface{}{"deleted": false}
+	}
+	return map[string]interface{}{
+		"deleted":       true,
+		"deleted_at":    time.Unix(t.DeletedAt, 0).UTC().Format(time.RFC3339),
+		"restore_until": t.RestoreDeadline(retention).UTC().Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,55 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+// TestTombstone_Restorable verifies the restore window is inclusive of its deadline and closed after it.
+func TestTombstone_Restorable(t *testing.T) {
+	t.Parallel()
+	deletedAt := time.Unix(1_700_000_000, 0)
+	tomb := NewTombstone(deletedAt)
+	retention := time.Hour
+	if got := tomb.RestoreDeadline(retention); !got.Equal(deletedAt.Add(time.Hour)) {
+		t.Fatalf("RestoreDeadline=%s", got)
+	}
+	if !tomb.Restorable(retention, deletedAt.Add(time.Hour)) {
+		t.Fatal("want restorable at deadline")
+	}
+	if tomb.Restorable(retention, deletedAt.Add(time.Hour+time.Second)) {
+		t.Fatal("want not restorable after deadline")
+	}
+}
+
+// TestTombstoneResponseData verifies live entries report deleted=false and tombstoned ones carry RFC3339 times.
+func TestTombstoneResponseData(t *testing.T) {
+	t.Parallel()
+	if got := TombstoneResponseData(nil, time.Hour); got["deleted"] != false || len(got) != 1 {
+		t.Fatalf("nil tombstone data=%v", got)
+	}
+	got := TombstoneResponseData(NewTombstone(time.Unix(0, 0)), time.Hour)
+	if got["deleted"] != true {
+		t.Fatalf("deleted=%v", got["deleted"])
+	}
+	if got["deleted_at"] != "1970-01-01T00:00:00Z" || got["restore_until"] != "1970-01-01T01:00:00Z" {
+		t.Fatalf("data=%v", got)
+	}
+}
@@ -0,0 +1,197 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package model
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// ErrVelocityLimitExceeded is wrapped by every VelocityLimits.Check rejection.
+var ErrVelocityLimitExceeded = errors.New("velocity limit exceeded")
+
+// DefaultVelocityWindow is the rolling window used when VelocityLimits.WindowSeconds is unset.
+const DefaultVelocityWindow = 24 * time.Hour
+
+// VelocityLimits caps the native value a wallet may sign for within a rolling window;
+// stored at wallets/<wallet_id>/limits. Empty caps mean no limit.
+type VelocityLimits struct {
+	WindowSeconds   int64  `json:"window_seconds,omitempty"`
+	WalletMaxValue  string `json:"wallet_max_value,omitempty"`
+	AccountMaxValue string `json:"account_max_value,omitempty"`
+}
+
+// SpendEntry records the value of one signed transaction. For a replacement, Replaces is the hash of the
+// transaction it replaces and Value only what it adds above the value already counted for that transaction.
+type SpendEntry struct {
+	Index    string `json:"index"`
+	Value    string `json:"value"`
+	TxHash   string `json:"tx_hash,omitempty"`
+	Replaces string `json:"replaces,omitempty"`
+	Time     int64  `json:"time"`
+}
+
+// SpendLedger is the list of recent signed-transaction values for a wallet; stored at wallets/<wallet_id>/spend.
+type SpendLedger struct {
+	Entries []SpendEntry `json:"entries,omitempty"`
+}
+
+// Window returns the rolling window length; a nil receiver or unset window yields DefaultVelocityWindow.
+func (l *VelocityLimits) Window() time.Duration {
+	if l == nil || l.WindowSeconds <= 0 {
+		return DefaultVelocityWindow
+	}
+	return time.Duration(l.WindowSeconds) * time.Second
+}
+
+// Validate checks the window and that the caps are non-negative decimal integers.
+func (l *VelocityLimits) Validate() error {
+	if l.WindowSeconds < 0 {
+		return fmt.Errorf("window must be positive")
+	}
+	if _, err := parseOptionalLimit("wallet_max_value", l.WalletMaxValue); err != nil {
+		return err
+	}
+	if _, err := parseOptionalLimit("account_max_value", l.AccountMaxValue); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Check returns an error wrapping ErrVelocityLimitExceeded when signing value for index would take the
+// wallet or the account over its cap within the window ending at now. A nil receiver allows everything.
+func (l *VelocityLimits) Check(ledger *SpendLedger, index string, value *big.Int, now time.Time) error {
+	if l == nil || value == nil || value.Sign() == 0 {
+		return nil
+	}
+	walletSpent, accountSpent := ledger.Totals(index, now.Add(-l.Window()))
+	window := l.Window().String()
+	walletMax, err := parseOptionalLimit("wallet_max_value", l.WalletMaxValue)
+	if err != nil {
+		return err
+	}
+	if walletMax != nil && new(big.Int).Add(walletSpent, value).Cmp(walletMax) > 0 {
+		return fmt.Errorf("%w: wallet_max_value: %s already spent in %s, %s more exceeds %s",
+			ErrVelocityLimitExceeded, walletSpent.String(), window, value.String(), walletMax.String())
+	}
+	accountMax, err := parseOptionalLimit("account_max_value", l.AccountMaxValue)
+	if err != nil {
+		return err
+	}
+	if accountMax != nil && new(big.Int).Add(accountSpent, value).Cmp(accountMax) > 0 {
+		return fmt.Errorf("%w: account_max_value: index %s already spent %s in %s, %s more exceeds %s",
+			ErrVelocityLimitExceeded, index, accountSpent.String(), window, value.String(), accountMax.String())
+	}
+	return nil
+}
+
+// Totals sums the entries recorded after since, for the whole wallet and for index.
+// A nil receiver yields zero totals.
+func (s *SpendLedger) Totals(index string, since time.Time) (wallet, account *big.Int) {
+	wallet, account = new(big.Int), new(big.Int)
+	for _, e := range s.entries() {
+		if e.Time <= since.Unix() {
+			continue
+		}
+		v, ok := new(big.Int).SetString(e.Value, 10)
+		if !ok {
+			continue
+		}
+		wallet.Add(wallet, v)
+		if e.Index == index {
+			account.Add(account, v)
+		}
+	}
+	return wallet, account
+}
+
+// AccountTotals sums the entries recorded after since per derived account index.
+func (s *SpendLedger) AccountTotals(since time.Time) map[string]*big.Int {
+	out := make(map[string]*big.Int)
+	for _, e := range s.entries() {
+		if e.Time <= since.Unix() {
+			continue
+		}
+		v, ok := new(big.Int).SetString(e.Value, 10)
+		if !ok {
+			continue
+		}
+		if out[e.Index] == nil {
+			out[e.Index] = new(big.Int)
+		}
+		out[e.Index].Add(out[e.Index], v)
+	}
+	return out
+}
+
+// Record appends an entry and drops entries recorded at or before since.
+func (s *SpendLedger) Record(entry SpendEntry, since time.Time) {
+	kept := s.Entries[:0]
+	for _, e := range s.Entries {
+		if e.Time > since.Unix() {
+			kept = append(kept, e)
+		}
+	}
+	s.Entries = append(kept, entry)
+}
+
+// Counted sums the entries recorded after since for txHash and, through Replaces, every transaction it
+// replaced: the value already counted for one nonce. An empty txHash yields zero.
+func (s *SpendLedger) Counted(txHash string, since time.Time) *big.Int {
+	total := new(big.Int)
+	entries := s.entries()
+	for hops := 0; txHash != "" && hops < len(entries); hops++ {
+		next := ""
+		for _, e := range entries {
+			if e.Time <= since.Unix() || !strings.EqualFold(e.TxHash, txHash) {
+				continue
+			}
+			if v, ok := new(big.Int).SetString(e.Value, 10); ok {
+				total.Add(total, v)
+			}
+			next = e.Replaces
+		}
+		txHash = next
+	}
+	return total
+}
+
+// ReplacementValue returns the value a transaction replacing replaces adds to the window starting at since:
+// value less what is already counted for replaces, never negative. Only one of the two can be mined, so the
+// pair counts max(original, replacement) and a cancellation frees nothing.
+func (s *SpendLedger) ReplacementValue(replaces string, value *big.Int, since time.Time) *big.Int {
+	if value == nil {
+		value = new(big.Int)
+	}
+	extra := new(big.Int).Sub(value, s.Counted(replaces, since))
+	if extra.Sign() < 0 {
+		return new(big.Int)
+	}
+	return extra
+}
+
+// entries returns the ledger entries, or nil for a nil receiver.
+func (s *SpendLedger) entries() []SpendEntry {
+	if s == nil {
+		return nil
+	}
+	return s.Entries
+}
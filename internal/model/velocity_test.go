@@ -0,0 +1,129 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package model
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestVelocityLimits_Window verifies the default and configured window lengths.
+func TestVelocityLimits_Window(t *testing.T) {
+	t.Parallel()
+	var nilLimits *VelocityLimits
+	if got := nilLimits.Window(); got != DefaultVelocityWindow {
+		t.Fatalf("nil window=%v", got)
+	}
+	if got := (&VelocityLimits{WindowSeconds: 3600}).Window(); got != time.Hour {
+		t.Fatalf("window=%v", got)
+	}
+}
+
+// TestVelocityLimits_Check verifies wallet and account caps over the rolling window.
+func TestVelocityLimits_Check(t *testing.T) {
+	t.Parallel()
+	now := time.Unix(1_700_000_000, 0)
+	ledger := &SpendLedger{Entries: []SpendEntry{
+		{Index: "0", Value: "60", Time: now.Add(-time.Hour).Unix()},
+		{Index: "1", Value: "10", Time: now.Add(-2 * time.Hour).Unix()},
+		{Index: "0", Value: "1000", Time: now.Add(-25 * time.Hour).Unix()},
+	}}
+	limits := &VelocityLimits{WalletMaxValue: "100", AccountMaxValue: "65"}
+
+	if err := limits.Check(ledger, "0", big.NewInt(5), now); err != nil {
+		t.Fatalf("within caps: %v", err)
+	}
+	cases := []struct {
+		index string
+		value int64
+		rule  string
+	}{
+		{"0", 6, "account_max_value"},
+		{"1", 31, "wallet_max_value"},
+	}
+	for _, c := range cases {
+		err := limits.Check(ledger, c.index, big.NewInt(c.value), now)
+		if !errors.Is(err, ErrVelocityLimitExceeded) || !strings.Contains(err.Error(), c.rule) {
+			t.Fatalf("index %s value %d: got %v want %s", c.index, c.value, err, c.rule)
+		}
+	}
+
+	var nilLimits *VelocityLimits
+	if err := nilLimits.Check(ledger, "0", big.NewInt(1_000_000), now); err != nil {
+		t.Fatalf("nil limits: %v", err)
+	}
+}
+
+// TestSpendLedger_RecordPrunes verifies Record drops entries outside the window and totals follow.
+func TestSpendLedger_RecordPrunes(t *testing.T) {
+	t.Parallel()
+	now := time.Unix(1_700_000_000, 0)
+	since := now.Add(-DefaultVelocityWindow)
+	ledger := &SpendLedger{Entries: []SpendEntry{
+		{Index: "0", Value: "5", Time: since.Add(-time.Second).Unix()},
+		{Index: "1", Value: "7", Time: now.Add(-time.Minute).Unix()},
+	}}
+	ledger.Record(SpendEntry{Index: "0", Value: "3", Time: now.Unix()}, since)
+	if len(ledger.Entries) != 2 {
+		t.Fatalf("entries=%v", ledger.Entries)
+	}
+	wallet, account := ledger.Totals("0", since)
+	if wallet.Int64() != 10 || account.Int64() != 3 {
+		t.Fatalf("wallet=%v account=%v", wallet, account)
+	}
+	per := ledger.AccountTotals(since)
+	if per["0"].Int64() != 3 || per["1"].Int64() != 7 {
+		t.Fatalf("per-account=%v", per)
+	}
+}
+
+// TestSpendLedger_ReplacementValue verifies a replacement chain counts the largest value once and that entries
+// outside the window no longer cover a replacement.
+func TestSpendLedger_ReplacementValue(t *testing.T) {
+	t.Parallel()
+	now := time.Unix(1_700_000_000, 0)
+	since := now.Add(-DefaultVelocityWindow)
+	ledger := &SpendLedger{Entries: []SpendEntry{
+		{Index: "0", Value: "5", TxHash: "0xaa", Time: now.Add(-time.Hour).Unix()},
+		{Index: "0", Value: "3", TxHash: "0xbb", Replaces: "0xAA", Time: now.Add(-time.Minute).Unix()},
+		{Index: "0", Value: "9", TxHash: "0xcc", Time: since.Unix()},
+	}}
+	cases := []struct {
+		replaces string
+		value    int64
+		want     int64
+	}{
+		{"", 4, 4},
+		{"0xaa", 4, 0},
+		{"0xaa", 6, 1},
+		{"0xbb", 0, 0},
+		{"0xbb", 10, 2},
+		{"0xcc", 9, 9},
+	}
+	for _, tc := range cases {
+		if got := ledger.ReplacementValue(tc.replaces, big.NewInt(tc.value), since); got.Int64() != tc.want {
+			t.Fatalf("replaces=%s value=%d: got %v want %d", tc.replaces, tc.value, got, tc.want)
+		}
+	}
+	if got := ledger.Counted("0xBB", since); got.Int64() != 8 {
+		t.Fatalf("counted=%v want 8", got)
+	}
+}
@@ -21,26 +21,77 @@ import (
 	"crypto/ecdsa"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/btcsuite/btcd/btcutil/hdkeychain"
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/tyler-smith/go-bip39"
+	"golang.org/x/text/unicode/norm"
 
 	"github.com/bsostech/vault-blockchain/pkg/utils"
 )
 
 // WalletSeed holds the BIP-39 mnemonic for a wallet; stored at wallets/<wallet_id>/seed.
+//
+// DerivationPath is the derivation path template fixed at creation time (see DerivationPathForIndex).
+// Empty means DefaultEthereumDerivationPath (wallets created before the field existed).
+// Passphrase is the optional BIP-39 passphrase ("25th word"); it is stored in the same seal-wrapped
+// entry as the mnemonic and never returned.
 type WalletSeed struct {
-	Mnemonic string `json:"mnemonic"`
+	Mnemonic       string `json:"mnemonic"`
+	Passphrase     string `json:"passphrase,omitempty"`
+	DerivationPath string `json:"derivation_path,omitempty"`
 }
 
-// WalletCounter tracks the next auto-increment index for derived accounts; stored at wallets/<wallet_id>/counter.
+// PathTemplate returns the wallet's derivation path template, defaulting to DefaultEthereumDerivationPath.
+func (s *WalletSeed) PathTemplate() string {
+	if s == nil || s.DerivationPath == "" {
+		return DefaultEthereumDerivationPath
+	}
+	return s.DerivationPath
+}
+
+// DeriveAccount returns the checksummed hex address and path string for index using the wallet's
+// mnemonic, passphrase and path template.
+func (s *WalletSeed) DeriveAccount(index uint32) (address string, derivationPath string, err error) {
+	return DeriveEthereumAccountAtPath(s.Mnemonic, s.Passphrase, s.PathTemplate(), index)
+}
+
+// DeriveAccountAt is DeriveAccount under BIP-44 account segment account (see AccountPathTemplate).
+func (s *WalletSeed) DeriveAccountAt(account, index uint32) (address string, derivationPath string, err error) {
+	tmpl, err := AccountPathTemplate(s.PathTemplate(), account)
+	if err != nil {
+		return "", "", err
+	}
+	return DeriveEthereumAccountAtPath(s.Mnemonic, s.Passphrase, tmpl, index)
+}
+
+// PrivateKeyECDSA derives the secp256k1 private key for index using the wallet's mnemonic, passphrase and
+// path template. Callers must clear sensitive material when done.
+func (s *WalletSeed) PrivateKeyECDSA(index uint32) (*ecdsa.PrivateKey, error) {
+	return PrivateKeyECDSAAtPath(s.Mnemonic, s.Passphrase, s.PathTemplate(), index)
+}
+
+// PrivateKeyECDSAAt is PrivateKeyECDSA under BIP-44 account segment account (see AccountPathTemplate).
+// Callers must clear sensitive material when done.
+func (s *WalletSeed) PrivateKeyECDSAAt(account, index uint32) (*ecdsa.PrivateKey, error) {
+	tmpl, err := AccountPathTemplate(s.PathTemplate(), account)
+	if err != nil {
+		return nil, err
+	}
+	return PrivateKeyECDSAAtPath(s.Mnemonic, s.Passphrase, tmpl, index)
+}
+
+// WalletCounter tracks the next auto-increment index for derived accounts; stored at wallets/<wallet_id>/counter
+// for account segment 0 and at wallets/<wallet_id>/segments/<account>/counter for the others.
 type WalletCounter struct {
 	NextIndex uint32 `json:"next_index"`
 }
 
-// DerivedAccount holds public metadata for a derived address; stored at wallets/<wallet_id>/accounts/<index>.
+// DerivedAccount holds public metadata for a derived address; stored at wallets/<wallet_id>/accounts/<index>
+// (account segment 0) or wallets/<wallet_id>/segments/<account>/accounts/<index>.
 type DerivedAccount struct {
 	Address        string `json:"address"`
 	DerivationPath string `json:"derivation_path"`
@@ -52,6 +103,9 @@ const MaxBIP44AddressIndex uint32 = 0x7FFFFFFF
 var (
 	// ErrIndexOutOfRange indicates the address index is outside BIP-44 limits.
 	ErrIndexOutOfRange = errors.New("address index out of BIP-44 range")
+	// ErrAccountSegmentUnsupported indicates the wallet's path template has no hardened account' segment
+	// that a non-zero account can replace.
+	ErrAccountSegmentUnsupported = errors.New("derivation path template has no hardened account' segment")
 )
 
 // ValidateAddressIndex returns ErrIndexOutOfRange when index exceeds MaxBIP44AddressIndex.
@@ -62,44 +116,108 @@ func ValidateAddressIndex(index uint64) error {
 	return nil
 }
 
-// ethereumBIP44ChildIndices is m/44'/60'/0'/0/<index> as successive BIP-32 child indices.
-func ethereumBIP44ChildIndices(index uint32) []uint32 {
-	return []uint32{
-		44 + hdkeychain.HardenedKeyStart,
-		60 + hdkeychain.HardenedKeyStart,
-		0 + hdkeychain.HardenedKeyStart,
-		0,
-		index,
+// DefaultEthereumDerivationPath is the BIP-44 prefix m/44'/60'/0'/0 under which address indices are derived.
+const DefaultEthereumDerivationPath = "m/44'/60'/0'/0"
+
+// DerivationPathIndexPlaceholder marks where the address index goes in a derivation path template,
+// e.g. m/44'/60'/{index}'/0/0 (Ledger Live). A template without it is a prefix and the index is appended.
+const DerivationPathIndexPlaceholder = "{index}"
+
+// DerivationPathForIndex expands template for index: the placeholder is replaced, or the index is appended
+// as a final non-hardened segment when the template has none.
+func DerivationPathForIndex(template string, index uint32) string {
+	template = strings.TrimSpace(template)
+	if strings.Contains(template, DerivationPathIndexPlaceholder) {
+		return strings.Replace(template, DerivationPathIndexPlaceholder, strconv.FormatUint(uint64(index), 10), 1)
 	}
+	return fmt.Sprintf("%s/%d", template, index)
 }
 
-// derivePrivateKeyAtEthereumPath uses BIP-39 seed + BIP-32 (via btcsuite hdkeychain) to reach
-// m/44'/60'/0'/0/<index>, then returns the secp256k1 key as *ecdsa.PrivateKey for go-ethereum.
-func derivePrivateKeyAtEthereumPath(mnemonic string, index uint32) (*ecdsa.PrivateKey, error) {
-	if !bip39.IsMnemonicValid(mnemonic) {
-		return nil, fmt.Errorf("invalid mnemonic")
+// ValidateDerivationTemplate checks that template is a BIP-32 path with at most one placeholder,
+// used as a whole segment (optionally hardened).
+func ValidateDerivationTemplate(template string) error {
+	if n := strings.Count(template, DerivationPathIndexPlaceholder); n > 1 {
+		return fmt.Errorf("derivation path %q has %d %s placeholders; at most one is allowed", template, n, DerivationPathIndexPlaceholder)
 	}
-	seed := bip39.NewSeed(mnemonic, "")
-	defer func() {
-		for i := range seed {
-			seed[i] = 0
+	for _, seg := range strings.Split(strings.TrimSpace(template), "/") {
+		if strings.Contains(seg, DerivationPathIndexPlaceholder) &&
+			strings.TrimRight(seg, "'h") != DerivationPathIndexPlaceholder {
+			return fmt.Errorf("derivation path %q: %s must be a whole segment", template, DerivationPathIndexPlaceholder)
 		}
-	}()
+	}
+	_, err := ParseDerivationPath(DerivationPathForIndex(template, 0))
+	return err
+}
 
-	master, err := hdkeychain.NewMaster(seed, &chaincfg.MainNetParams)
-	if err != nil {
-		return nil, fmt.Errorf("hd master: %w", err)
+// bip44AccountSegment is the position of the account' segment in m/purpose'/coin_type'/account'/...
+const bip44AccountSegment = 3
+
+// AccountPathTemplate returns template with its BIP-44 account' segment set to account. Account 0 returns
+// template unchanged so existing wallets keep their paths. Otherwise the segment must be a fixed hardened
+// number; a template whose account' is the {index} placeholder (e.g. Ledger Live) yields
+// ErrAccountSegmentUnsupported.
+func AccountPathTemplate(template string, account uint32) (string, error) {
+	if err := ValidateAddressIndex(uint64(account)); err != nil {
+		return "", fmt.Errorf("validate account: %w", err)
+	}
+	if account == 0 {
+		return template, nil
 	}
+	segments := strings.Split(strings.TrimSpace(template), "/")
+	if len(segments) <= bip44AccountSegment {
+		return "", fmt.Errorf("%w: %q", ErrAccountSegmentUnsupported, template)
+	}
+	seg := segments[bip44AccountSegment]
+	hardened := strings.HasSuffix(seg, "'") || strings.HasSuffix(seg, "h")
+	if !hardened || strings.Contains(seg, DerivationPathIndexPlaceholder) {
+		return "", fmt.Errorf("%w: %q", ErrAccountSegmentUnsupported, template)
+	}
+	segments[bip44AccountSegment] = fmt.Sprintf("%d'", account)
+	return strings.Join(segments, "/"), nil
+}
 
-	cur := master
-	for _, childIdx := range ethereumBIP44ChildIndices(index) {
-		next, derr := cur.Derive(childIdx)
-		if derr != nil {
-			cur.Zero()
-			return nil, fmt.Errorf("hd derive: %w", derr)
+// ParseDerivationPath parses a BIP-32 path such as m/44'/60'/0'/0 into child indices.
+// Hardened segments use a trailing ' (or h).
+func ParseDerivationPath(path string) ([]uint32, error) {
+	segments := strings.Split(strings.TrimSpace(path), "/")
+	if len(segments) < 2 || segments[0] != "m" {
+		return nil, fmt.Errorf("derivation path %q must start with m/ and have at least one segment", path)
+	}
+	out := make([]uint32, 0, len(segments)-1)
+	for _, seg := range segments[1:] {
+		hardened := strings.HasSuffix(seg, "'") || strings.HasSuffix(seg, "h")
+		if hardened {
+			seg = seg[:len(seg)-1]
 		}
-		cur.Zero()
-		cur = next
+		v, err := strconv.ParseUint(seg, 10, 32)
+		if err != nil || v >= uint64(hdkeychain.HardenedKeyStart) {
+			return nil, fmt.Errorf("derivation path %q has invalid segment %q", path, seg)
+		}
+		idx := uint32(v)
+		if hardened {
+			idx += hdkeychain.HardenedKeyStart
+		}
+		out = append(out, idx)
+	}
+	return out, nil
+}
+
+// childIndicesAtPath expands pathTemplate for index into successive BIP-32 child indices.
+func childIndicesAtPath(pathTemplate string, index uint32) ([]uint32, error) {
+	return ParseDerivationPath(DerivationPathForIndex(pathTemplate, index))
+}
+
+// derivePrivateKeyAtPath uses BIP-39 seed (with the NFKD-normalised passphrase) + BIP-32 (via btcsuite
+// hdkeychain) to reach pathTemplate expanded for index, then returns the secp256k1 key as *ecdsa.PrivateKey
+// for go-ethereum.
+func derivePrivateKeyAtPath(mnemonic, passphrase, pathTemplate string, index uint32) (*ecdsa.PrivateKey, error) {
+	childIndices, err := childIndicesAtPath(pathTemplate, index)
+	if err != nil {
+		return nil, err
+	}
+	cur, err := deriveExtendedKey(mnemonic, passphrase, childIndices)
+	if err != nil {
+		return nil, err
 	}
 
 	btcecPriv, err := cur.ECPrivKey()
@@ -123,25 +241,73 @@ func derivePrivateKeyAtEthereumPath(mnemonic string, index uint32) (*ecdsa.Priva
 	return ecdsaPriv, nil
 }
 
-// DeriveEthereumAccount returns the checksummed hex address and path string m/44'/60'/0'/0/<index>.
+// bip39Seed returns the BIP-39 seed of mnemonic with the NFKD-normalised passphrase. The caller must zero it.
+func bip39Seed(mnemonic, passphrase string) ([]byte, error) {
+	if !bip39.IsMnemonicValid(mnemonic) {
+		return nil, fmt.Errorf("invalid mnemonic")
+	}
+	return bip39.NewSeed(mnemonic, norm.NFKD.String(passphrase)), nil
+}
+
+// deriveExtendedKey builds the BIP-32 master key from the mnemonic and the NFKD-normalised passphrase and
+// walks childIndices. The caller must Zero the returned private extended key.
+func deriveExtendedKey(mnemonic, passphrase string, childIndices []uint32) (*hdkeychain.ExtendedKey, error) {
+	seed, err := bip39Seed(mnemonic, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	defer utils.ZeroBytes(seed)
+
+	master, err := hdkeychain.NewMaster(seed, &chaincfg.MainNetParams)
+	if err != nil {
+		return nil, fmt.Errorf("hd master: %w", err)
+	}
+
+	cur := master
+	for _, childIdx := range childIndices {
+		next, derr := cur.Derive(childIdx)
+		if derr != nil {
+			cur.Zero()
+			return nil, fmt.Errorf("hd derive: %w", derr)
+		}
+		cur.Zero()
+		cur = next
+	}
+	return cur, nil
+}
+
+// DeriveEthereumAccount returns the checksummed hex address and path string m/44'/60'/0'/0/<index>
+// for a mnemonic without a passphrase.
 func DeriveEthereumAccount(mnemonic string, index uint32) (address string, derivationPath string, err error) {
+	return DeriveEthereumAccountAtPath(mnemonic, "", DefaultEthereumDerivationPath, index)
+}
+
+// DeriveEthereumAccountAtPath returns the checksummed hex address and the path pathTemplate expanded for index.
+// passphrase is the optional BIP-39 passphrase; empty means none.
+func DeriveEthereumAccountAtPath(mnemonic, passphrase, pathTemplate string, index uint32) (address string, derivationPath string, err error) {
 	if err := ValidateAddressIndex(uint64(index)); err != nil {
 		return "", "", fmt.Errorf("validate index: %w", err)
 	}
-	pk, err := derivePrivateKeyAtEthereumPath(mnemonic, index)
+	pk, err := derivePrivateKeyAtPath(mnemonic, passphrase, pathTemplate, index)
 	if err != nil {
 		return "", "", err
 	}
 	defer utils.ZeroKey(pk)
-	derivationPath = fmt.Sprintf("m/44'/60'/0'/0/%d", index)
+	derivationPath = DerivationPathForIndex(pathTemplate, index)
 	return crypto.PubkeyToAddress(pk.PublicKey).Hex(), derivationPath, nil
 }
 
-// PrivateKeyECDSA derives the secp256k1 private key at m/44'/60'/0'/0/<index>.
+// PrivateKeyECDSA derives the secp256k1 private key at m/44'/60'/0'/0/<index> for a mnemonic without a passphrase.
 // Callers must clear sensitive material when done.
 func PrivateKeyECDSA(mnemonic string, index uint32) (*ecdsa.PrivateKey, error) {
+	return PrivateKeyECDSAAtPath(mnemonic, "", DefaultEthereumDerivationPath, index)
+}
+
+// PrivateKeyECDSAAtPath derives the secp256k1 private key at pathTemplate expanded for index.
+// passphrase is the optional BIP-39 passphrase; empty means none. Callers must clear sensitive material when done.
+func PrivateKeyECDSAAtPath(mnemonic, passphrase, pathTemplate string, index uint32) (*ecdsa.PrivateKey, error) {
 	if err := ValidateAddressIndex(uint64(index)); err != nil {
 		return nil, fmt.Errorf("validate index: %w", err)
 	}
-	return derivePrivateKeyAtEthereumPath(mnemonic, index)
+	return derivePrivateKeyAtPath(mnemonic, passphrase, pathTemplate, index)
 }
@@ -0,0 +1,78 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tyler-smith/go-bip39"
+)
+
+// WalletBackupFormatVersion is the payload version written by wallet backups.
+const WalletBackupFormatVersion = 1
+
+// WalletBackupFormatSLIP39Share marks a backup record for one encrypted SLIP-39 share of a Shamir split.
+const WalletBackupFormatSLIP39Share = "slip39_share"
+
+// WalletBackup is the plaintext of an encrypted wallet backup: everything needed to re-import the wallet and
+// re-derive its accounts on another cluster.
+type WalletBackup struct {
+	Version        int    `json:"version"`
+	WalletID       string `json:"wallet_id"`
+	Mnemonic       string `json:"mnemonic"`
+	Passphrase     string `json:"passphrase,omitempty"`
+	DerivationPath string `json:"derivation_path,omitempty"`
+	// Counters maps each account segment to its next auto-increment index at backup time.
+	Counters  map[string]uint32 `json:"counters,omitempty"`
+	CreatedAt int64             `json:"created_at"`
+}
+
+// Validate checks the payload version, mnemonic and derivation path template.
+func (b *WalletBackup) Validate() error {
+	if b.Version != WalletBackupFormatVersion {
+		return fmt.Errorf("unsupported backup version %d", b.Version)
+	}
+	if !bip39.IsMnemonicValid(b.Mnemonic) {
+		return fmt.Errorf("backup mnemonic is invalid")
+	}
+	if b.DerivationPath != "" {
+		if err := ValidateDerivationTemplate(b.DerivationPath); err != nil {
+			return fmt.Errorf("backup derivation_path: %w", err)
+		}
+	}
+	return nil
+}
+
+// WalletBackupRecord is the audit entry kept in wallet metadata for each backup taken; stored as a list at
+// wallets/<wallet_id>/backups. It never contains key material.
+type WalletBackupRecord struct {
+	CreatedAt        int64  `json:"created_at"`
+	Recipient        string `json:"recipient"`
+	CiphertextSHA256 string `json:"ciphertext_sha256"`
+	EntityID         string `json:"entity_id,omitempty"`
+	// Format is empty for a full ECIES seed backup, or WalletBackupFormatSLIP39Share for one custodian share.
+	Format string `json:"format,omitempty"`
+}
+
+// IsAgeRecipient reports whether recipient looks like an age recipient rather than a hex public key. Only X25519
+// recipients can be parsed; plugin recipients are rejected when parsed.
+func IsAgeRecipient(recipient string) bool {
+	r := strings.ToLower(strings.TrimSpace(recipient))
+	return strings.HasPrefix(r, "age1")
+}
@@ -0,0 +1,50 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package model
+
+import "testing"
+
+// TestWalletBackup_Validate rejects unknown versions, invalid mnemonics and bad templates.
+func TestWalletBackup_Validate(t *testing.T) {
+	t.Parallel()
+	ok := &WalletBackup{Version: WalletBackupFormatVersion, Mnemonic: testMnemonicHD, DerivationPath: "m/44'/60'/0'/0"}
+	if err := ok.Validate(); err != nil {
+		t.Fatalf("valid backup: %v", err)
+	}
+	bad := []*WalletBackup{
+		{Version: 2, Mnemonic: testMnemonicHD},
+		{Version: WalletBackupFormatVersion, Mnemonic: "not a mnemonic"},
+		{Version: WalletBackupFormatVersion, Mnemonic: testMnemonicHD, DerivationPath: "m/x"},
+	}
+	for i, b := range bad {
+		if err := b.Validate(); err == nil {
+			t.Fatalf("bad[%d]: expected error", i)
+		}
+	}
+}
+
+// TestIsAgeRecipient distinguishes age recipients from hex public keys.
+func TestIsAgeRecipient(t *testing.T) {
+	t.Parallel()
+	if !IsAgeRecipient(" AGE1qyqszqgpqyqszqgp ") {
+		t.Fatal("want age recipient")
+	}
+	if IsAgeRecipient("0x02a1b2") {
+		t.Fatal("hex key is not an age recipient")
+	}
+}
@@ -0,0 +1,74 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/tyler-smith/go-bip39"
+
+	"github.com/bsostech/vault-blockchain/internal/slip39"
+	"github.com/bsostech/vault-blockchain/pkg/utils"
+)
+
+// ShamirImportTTL is how long submitted SLIP-39 shares wait for the threshold before the pending import is
+// discarded.
+const ShamirImportTTL = 7 * 24 * time.Hour
+
+// ShamirImport holds the SLIP-39 shares submitted toward reconstructing a wallet until the threshold is met;
+// stored at shamir_imports/<wallet_id>.
+type ShamirImport struct {
+	Shares    []string `json:"shares"`
+	CreatedAt int64    `json:"created_at"`
+}
+
+// ExpiresAt is when the pending import is discarded: ShamirImportTTL after its first share was submitted.
+func (i *ShamirImport) ExpiresAt() time.Time {
+	return time.Unix(i.CreatedAt, 0).Add(ShamirImportTTL)
+}
+
+// Expired reports whether the pending import has outlived ShamirImportTTL at now.
+func (i *ShamirImport) Expired(now time.Time) bool {
+	return !now.Before(i.ExpiresAt())
+}
+
+// SplitMnemonicShares splits the BIP-39 entropy of mnemonic into count SLIP-39 share mnemonics, any threshold of
+// which recover it. The shares carry no SLIP-39 passphrase; the wallet's BIP-39 passphrase is not included.
+func SplitMnemonicShares(mnemonic string, threshold, count int, iterationExponent uint8) ([]string, error) {
+	entropy, err := bip39.EntropyFromMnemonic(mnemonic)
+	if err != nil {
+		return nil, fmt.Errorf("mnemonic entropy: %w", err)
+	}
+	defer utils.ZeroBytes(entropy)
+	return slip39.Split(entropy, nil, threshold, count, iterationExponent)
+}
+
+// CombineMnemonicShares recovers the BIP-39 mnemonic from SLIP-39 shares made by SplitMnemonicShares.
+func CombineMnemonicShares(shares []string) (string, error) {
+	entropy, err := slip39.Combine(shares, nil)
+	if err != nil {
+		return "", err
+	}
+	defer utils.ZeroBytes(entropy)
+	mnemonic, err := bip39.NewMnemonic(entropy)
+	if err != nil {
+		return "", fmt.Errorf("recovered secret is not BIP-39 entropy: %w", err)
+	}
+	return mnemonic, nil
+}
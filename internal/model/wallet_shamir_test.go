@@ -0,0 +1,66 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+// TestMnemonicShares_roundTrip splits a mnemonic 2-of-3 and recovers it from each pair of shares.
+func TestMnemonicShares_roundTrip(t *testing.T) {
+	t.Parallel()
+	shares, err := SplitMnemonicShares(testMnemonicHD, 2, 3, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pairs := [][2]int{{0, 1}, {0, 2}, {2, 1}}
+	for _, p := range pairs {
+		got, err := CombineMnemonicShares([]string{shares[p[0]], shares[p[1]]})
+		if err != nil {
+			t.Fatalf("pair %v: %v", p, err)
+		}
+		if got != testMnemonicHD {
+			t.Fatalf("pair %v: mnemonic=%q", p, got)
+		}
+	}
+	if _, err := CombineMnemonicShares(shares[:1]); err == nil {
+		t.Fatal("expected error below threshold")
+	}
+}
+
+// TestSplitMnemonicShares_invalidMnemonic rejects a phrase that is not BIP-39.
+func TestSplitMnemonicShares_invalidMnemonic(t *testing.T) {
+	t.Parallel()
+	if _, err := SplitMnemonicShares("not a mnemonic", 2, 3, 0); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+// TestShamirImport_expired expires a pending import ShamirImportTTL after it was started.
+func TestShamirImport_expired(t *testing.T) {
+	t.Parallel()
+	started := time.Unix(1700000000, 0)
+	pending := &ShamirImport{CreatedAt: started.Unix()}
+	if pending.Expired(started.Add(ShamirImportTTL - time.Second)) {
+		t.Fatalf("expired before ShamirImportTTL.")
+	}
+	if !pending.Expired(started.Add(ShamirImportTTL)) {
+		t.Fatalf("not expired at ShamirImportTTL.")
+	}
+}
@@ -110,3 +110,225 @@ func TestPrivateKeyECDSA_indexOutOfRange(t *testing.T) {
 		t.Fatalf("expected %v, got %v", ErrIndexOutOfRange, err)
 	}
 }
+
+// TestParseDerivationPath verifies hardened markers and rejection of malformed paths.
+func TestParseDerivationPath(t *testing.T) {
+	t.Parallel()
+	got, err := ParseDerivationPath("m/44'/1h/2/3'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []uint32{44 + 0x80000000, 1 + 0x80000000, 2, 3 + 0x80000000}
+	if len(got) != len(want) {
+		t.Fatalf("len: got %d want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("segment %d: got %d want %d", i, got[i], want[i])
+		}
+	}
+	for _, bad := range []string{"", "m", "44'/60'", "m/x", "m/2147483648"} {
+		if _, err := ParseDerivationPath(bad); err == nil {
+			t.Fatalf("%q: expected error", bad)
+		}
+	}
+}
+
+// TestDeriveEthereumAccountAtPath_customBase verifies a non-default base path changes the address and path string.
+func TestDeriveEthereumAccountAtPath_customBase(t *testing.T) {
+	t.Parallel()
+	addrDefault, _, err := DeriveEthereumAccountAtPath(testMnemonicHD, "", DefaultEthereumDerivationPath, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addrLegacy, _, err := DeriveEthereumAccount(testMnemonicHD, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addrDefault != addrLegacy {
+		t.Fatalf("default base path: got %s want %s", addrDefault, addrLegacy)
+	}
+
+	addr, path, err := DeriveEthereumAccountAtPath(testMnemonicHD, "", "m/44'/1'/0'/0", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != "m/44'/1'/0'/0/0" {
+		t.Fatalf("path: got %q", path)
+	}
+	if addr == addrDefault {
+		t.Fatal("coin type 1 should differ from coin type 60")
+	}
+	pk, err := PrivateKeyECDSAAtPath(testMnemonicHD, "", "m/44'/1'/0'/0", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { utils.ZeroKey(pk) })
+	if got := crypto.PubkeyToAddress(pk.PublicKey).Hex(); got != addr {
+		t.Fatalf("address mismatch: derived %s pubkey %s", addr, got)
+	}
+}
+
+// TestWalletSeed_passphrase verifies the passphrase changes derivation, is NFKD-normalised, and that the
+// WalletSeed helpers match the package-level functions.
+func TestWalletSeed_passphrase(t *testing.T) {
+	t.Parallel()
+	seed := &WalletSeed{Mnemonic: testMnemonicHD, Passphrase: "caf\u00e9"}
+	addr, path, err := seed.DeriveAccount(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != DefaultEthereumDerivationPath+"/0" {
+		t.Fatalf("path: got %q", path)
+	}
+	noPass, _, err := DeriveEthereumAccount(testMnemonicHD, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addr == noPass {
+		t.Fatal("passphrase should change the derived address")
+	}
+	decomposed, _, err := DeriveEthereumAccountAtPath(testMnemonicHD, "cafe\u0301", DefaultEthereumDerivationPath, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decomposed != addr {
+		t.Fatalf("NFKD: composed %s decomposed %s", addr, decomposed)
+	}
+	pk, err := seed.PrivateKeyECDSA(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { utils.ZeroKey(pk) })
+	if got := crypto.PubkeyToAddress(pk.PublicKey).Hex(); got != addr {
+		t.Fatalf("address mismatch: derived %s pubkey %s", addr, got)
+	}
+}
+
+// TestWalletSeed_PathTemplate verifies an empty DerivationPath falls back to the default prefix.
+func TestWalletSeed_PathTemplate(t *testing.T) {
+	t.Parallel()
+	if got := (&WalletSeed{}).PathTemplate(); got != DefaultEthereumDerivationPath {
+		t.Fatalf("empty: got %q", got)
+	}
+	if got := (&WalletSeed{DerivationPath: "m/44'/1'/0'/0"}).PathTemplate(); got != "m/44'/1'/0'/0" {
+		t.Fatalf("custom: got %q", got)
+	}
+}
+
+// TestDerivationPathForIndex verifies placeholder substitution and the append fallback.
+func TestDerivationPathForIndex(t *testing.T) {
+	t.Parallel()
+	cases := map[string]string{
+		"m/44'/60'/0'/0":         "m/44'/60'/0'/0/7",
+		"m/44'/60'/{index}'/0/0": "m/44'/60'/7'/0/0",
+		"m/44'/60'/0'/{index}":   "m/44'/60'/0'/7",
+	}
+	for tmpl, want := range cases {
+		if got := DerivationPathForIndex(tmpl, 7); got != want {
+			t.Fatalf("%q: got %q want %q", tmpl, got, want)
+		}
+	}
+}
+
+// TestValidateDerivationTemplate checks accepted templates and rejects misplaced or repeated placeholders.
+func TestValidateDerivationTemplate(t *testing.T) {
+	t.Parallel()
+	for _, ok := range []string{DefaultEthereumDerivationPath, "m/44'/60'/{index}'/0/0", "m/44'/60'/0'/0/{index}", "m/44h/60h/{index}h"} {
+		if err := ValidateDerivationTemplate(ok); err != nil {
+			t.Fatalf("%q: %v", ok, err)
+		}
+	}
+	for _, bad := range []string{"", "m/{index}/{index}", "m/44'/60'/1{index}'/0", "m/44'/60'/{index}x", "44'/60'/{index}'"} {
+		if err := ValidateDerivationTemplate(bad); err == nil {
+			t.Fatalf("%q: expected error", bad)
+		}
+	}
+}
+
+// TestDeriveEthereumAccountAtPath_ledgerLiveTemplate verifies the account-level placeholder layout
+// derives at m/44'/60'/<index>'/0/0 and agrees with the default layout at index 0.
+func TestDeriveEthereumAccountAtPath_ledgerLiveTemplate(t *testing.T) {
+	t.Parallel()
+	const tmpl = "m/44'/60'/{index}'/0/0"
+	addr0, path0, err := DeriveEthereumAccountAtPath(testMnemonicHD, "", tmpl, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	def0, _, err := DeriveEthereumAccount(testMnemonicHD, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path0 != "m/44'/60'/0'/0/0" || addr0 != def0 {
+		t.Fatalf("index 0: path %q addr %s want default %s", path0, addr0, def0)
+	}
+
+	addr3, path3, err := DeriveEthereumAccountAtPath(testMnemonicHD, "", tmpl, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path3 != "m/44'/60'/3'/0/0" {
+		t.Fatalf("index 3: path %q", path3)
+	}
+	def3, _, err := DeriveEthereumAccount(testMnemonicHD, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addr3 == def3 {
+		t.Fatal("ledger live layout should differ from the default layout at index 3")
+	}
+	pk, err := PrivateKeyECDSAAtPath(testMnemonicHD, "", tmpl, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { utils.ZeroKey(pk) })
+	if got := crypto.PubkeyToAddress(pk.PublicKey).Hex(); got != addr3 {
+		t.Fatalf("address mismatch: derived %s pubkey %s", addr3, got)
+	}
+}
+
+// TestAccountPathTemplate verifies the account' segment is replaced for non-zero accounts and rejected
+// when the template has no fixed hardened account segment.
+func TestAccountPathTemplate(t *testing.T) {
+	t.Parallel()
+	got, err := AccountPathTemplate(DefaultEthereumDerivationPath, 0)
+	if err != nil || got != DefaultEthereumDerivationPath {
+		t.Fatalf("account 0: got %q err %v", got, err)
+	}
+	got, err = AccountPathTemplate("m/44'/60'/0'/{index}", 5)
+	if err != nil || got != "m/44'/60'/5'/{index}" {
+		t.Fatalf("account 5: got %q err %v", got, err)
+	}
+	for _, bad := range []string{"m/44'/60'/{index}'/0/0", "m/44'/60'", "m/44'/60'/0/0"} {
+		if _, err := AccountPathTemplate(bad, 1); !errors.Is(err, ErrAccountSegmentUnsupported) {
+			t.Fatalf("%q: err %v want ErrAccountSegmentUnsupported", bad, err)
+		}
+	}
+	if _, err := AccountPathTemplate(DefaultEthereumDerivationPath, MaxBIP44AddressIndex+1); err == nil {
+		t.Fatal("expected out-of-range error")
+	}
+
+	seed := &WalletSeed{Mnemonic: testMnemonicHD}
+	addr, path, err := seed.DeriveAccountAt(1, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != "m/44'/60'/1'/0/0" {
+		t.Fatalf("path: got %q", path)
+	}
+	pk, err := seed.PrivateKeyECDSAAt(1, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { utils.ZeroKey(pk) })
+	if got := crypto.PubkeyToAddress(pk.PublicKey).Hex(); got != addr {
+		t.Fatalf("address mismatch: derived %s pubkey %s", addr, got)
+	}
+	def0, _, err := seed.DeriveAccount(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if def0 == addr {
+		t.Fatal("account 1 should differ from account 0")
+	}
+}
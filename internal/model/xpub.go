@@ -0,0 +1,129 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ErrTemplateNotWatchOnly indicates the address index sits at or above a hardened segment of the template,
+// so addresses cannot be derived from an extended public key.
+var ErrTemplateNotWatchOnly = errors.New("derivation path template is not derivable from an extended public key")
+
+// WalletXpub is the account-level extended public key of a wallet account segment and the non-hardened child
+// template below it; stored at wallets/<wallet_id>/xpub/<account>. It holds public data only.
+type WalletXpub struct {
+	Xpub      string `json:"xpub"`
+	Path      string `json:"path"`
+	ChildPath string `json:"child_path"`
+}
+
+// hardenedSegment reports whether a derivation path segment ends with ' or h.
+func hardenedSegment(seg string) bool {
+	return strings.HasSuffix(seg, "'") || strings.HasSuffix(seg, "h")
+}
+
+// SplitWatchOnlyTemplate splits template at its last hardened segment into the account-level path and the
+// non-hardened child template holding the index placeholder, e.g. m/44'/60'/0'/0 becomes m/44'/60'/0' and
+// 0/{index}. Templates whose index is hardened or followed by a hardened segment yield ErrTemplateNotWatchOnly.
+func SplitWatchOnlyTemplate(template string) (accountPath, childTemplate string, err error) {
+	if err := ValidateDerivationTemplate(template); err != nil {
+		return "", "", err
+	}
+	tmpl := strings.TrimSpace(template)
+	if !strings.Contains(tmpl, DerivationPathIndexPlaceholder) {
+		tmpl += "/" + DerivationPathIndexPlaceholder
+	}
+	segments := strings.Split(tmpl, "/")
+	split := 1
+	for i := 1; i < len(segments); i++ {
+		if hardenedSegment(segments[i]) {
+			split = i + 1
+		}
+	}
+	accountPath = strings.Join(segments[:split], "/")
+	if strings.Contains(accountPath, DerivationPathIndexPlaceholder) {
+		return "", "", fmt.Errorf("%w: %q", ErrTemplateNotWatchOnly, template)
+	}
+	return accountPath, strings.Join(segments[split:], "/"), nil
+}
+
+// ExtendedPublicKeyAtPath returns the BIP-32 extended public key (xpub) at path, which may be "m".
+// passphrase is the optional BIP-39 passphrase; empty means none.
+func ExtendedPublicKeyAtPath(mnemonic, passphrase, path string) (string, error) {
+	var childIndices []uint32
+	if strings.TrimSpace(path) != "m" {
+		var err error
+		if childIndices, err = ParseDerivationPath(path); err != nil {
+			return "", err
+		}
+	}
+	key, err := deriveExtendedKey(mnemonic, passphrase, childIndices)
+	if err != nil {
+		return "", err
+	}
+	defer key.Zero()
+	pub, err := key.Neuter()
+	if err != nil {
+		return "", fmt.Errorf("hd neuter: %w", err)
+	}
+	return pub.String(), nil
+}
+
+// AddressFromExtendedPublicKey returns the checksummed hex address at childTemplate (non-hardened, relative
+// to xpub) expanded for index. It uses public data only.
+func AddressFromExtendedPublicKey(xpub, childTemplate string, index uint32) (string, error) {
+	if err := ValidateAddressIndex(uint64(index)); err != nil {
+		return "", fmt.Errorf("validate index: %w", err)
+	}
+	key, err := hdkeychain.NewKeyFromString(strings.TrimSpace(xpub))
+	if err != nil {
+		return "", fmt.Errorf("parse extended public key: %w", err)
+	}
+	if key.IsPrivate() {
+		return "", fmt.Errorf("expected an extended public key, got a private one")
+	}
+	rel := strings.Replace(childTemplate, DerivationPathIndexPlaceholder, strconv.FormatUint(uint64(index), 10), 1)
+	for _, seg := range strings.Split(rel, "/") {
+		if hardenedSegment(seg) {
+			return "", fmt.Errorf("%w: child path %q has hardened segment %q", ErrTemplateNotWatchOnly, childTemplate, seg)
+		}
+		v, err := strconv.ParseUint(seg, 10, 32)
+		if err != nil || v >= uint64(hdkeychain.HardenedKeyStart) {
+			return "", fmt.Errorf("child path %q has invalid segment %q", childTemplate, seg)
+		}
+		if key, err = key.Derive(uint32(v)); err != nil {
+			return "", fmt.Errorf("hd derive: %w", err)
+		}
+	}
+	ecPub, err := key.ECPubKey()
+	if err != nil {
+		return "", fmt.Errorf("hd leaf public key: %w", err)
+	}
+	pub, err := crypto.UnmarshalPubkey(ecPub.SerializeUncompressed())
+	if err != nil {
+		return "", fmt.Errorf("secp256k1 public key: %w", err)
+	}
+	return crypto.PubkeyToAddress(*pub).Hex(), nil
+}
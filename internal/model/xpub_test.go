@@ -0,0 +1,83 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package model
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestSplitWatchOnlyTemplate checks the account-level split and rejects hardened index layouts.
+func TestSplitWatchOnlyTemplate(t *testing.T) {
+	t.Parallel()
+	cases := map[string][2]string{
+		DefaultEthereumDerivationPath: {"m/44'/60'/0'", "0/{index}"},
+		"m/44'/60'/2'/0/{index}":      {"m/44'/60'/2'", "0/{index}"},
+		"m/44'/60'/0'/{index}/0":      {"m/44'/60'/0'", "{index}/0"},
+		"m/0":                         {"m", "0/{index}"},
+	}
+	for tmpl, want := range cases {
+		acct, child, err := SplitWatchOnlyTemplate(tmpl)
+		if err != nil {
+			t.Fatalf("%q: %v", tmpl, err)
+		}
+		if acct != want[0] || child != want[1] {
+			t.Fatalf("%q: got %q %q want %q %q", tmpl, acct, child, want[0], want[1])
+		}
+	}
+	for _, bad := range []string{"m/44'/60'/{index}'/0/0", "m/44'/60'/0'/{index}/0'"} {
+		if _, _, err := SplitWatchOnlyTemplate(bad); !errors.Is(err, ErrTemplateNotWatchOnly) {
+			t.Fatalf("%q: err %v want ErrTemplateNotWatchOnly", bad, err)
+		}
+	}
+}
+
+// TestAddressFromExtendedPublicKey verifies watch-only derivation matches mnemonic derivation.
+func TestAddressFromExtendedPublicKey(t *testing.T) {
+	t.Parallel()
+	xpub, err := ExtendedPublicKeyAtPath(testMnemonicHD, "", "m/44'/60'/0'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(xpub, "xpub") {
+		t.Fatalf("xpub: got %q", xpub)
+	}
+	for _, index := range []uint32{0, 1, 17} {
+		got, err := AddressFromExtendedPublicKey(xpub, "0/{index}", index)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, _, err := DeriveEthereumAccount(testMnemonicHD, index)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Fatalf("index %d: got %s want %s", index, got, want)
+		}
+	}
+	if _, err := AddressFromExtendedPublicKey(xpub, "0'/{index}", 0); !errors.Is(err, ErrTemplateNotWatchOnly) {
+		t.Fatalf("hardened child: err %v", err)
+	}
+	if _, err := AddressFromExtendedPublicKey("not-an-xpub", "0/{index}", 0); err == nil {
+		t.Fatal("expected parse error")
+	}
+	if _, err := ExtendedPublicKeyAtPath(testMnemonicHD, "", "m/x"); err == nil {
+		t.Fatal("expected path error")
+	}
+}
@@ -24,14 +24,21 @@ import (
 	"fmt"
 	"net/http"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/hashicorp/vault/sdk/framework"
 	"github.com/hashicorp/vault/sdk/logical"
 
+	"github.com/bsostech/vault-blockchain/internal/ethutil"
 	"github.com/bsostech/vault-blockchain/internal/model"
+	"github.com/bsostech/vault-blockchain/internal/path/addressindex"
+	"github.com/bsostech/vault-blockchain/internal/path/config"
+	"github.com/bsostech/vault-blockchain/internal/path/nonces"
 	"github.com/bsostech/vault-blockchain/internal/path/storagekey"
 	"github.com/bsostech/vault-blockchain/pkg/utils"
 )
@@ -48,42 +55,32 @@ func respondSingleKeyAccountConflict(req *logical.Request) (*logical.Response, e
 	)
 }
 
-// putSingleKeyAccountIfAbsent writes the account JSON under accounts/<name>/address if missing.
+// putSingleKeyAccountIfAbsent writes a keyring holding account as version 1 under accounts/<name>/keys if no
+// account exists for name.
 func putSingleKeyAccountIfAbsent(
 	ctx context.Context,
 	req *logical.Request,
 	name string,
 	account *model.Account,
 ) error {
-	key := storagekey.SingleKeyAccountKey(name)
-	existing, err := req.Storage.Get(ctx, key)
+	exists, err := singleKeyAccountExists(ctx, req.Storage, name)
 	if err != nil {
 		return fmt.Errorf("get single-key account %s: %w", name, err)
 	}
-	if existing != nil {
+	if exists {
 		return errSingleKeyAccountAlreadyExists
 	}
-	entry, err := logical.StorageEntryJSON(key, account)
-	if err != nil {
-		return fmt.Errorf("encode single-key account %s: %w", name, err)
-	}
-	if err := req.Storage.Put(ctx, entry); err != nil {
-		return fmt.Errorf("put single-key account %s: %w", name, err)
+	account.CreatedAt = time.Now().Unix()
+	keyring := model.NewAccountKeyring(account)
+	if err := WriteSingleKeyAccountKeyring(ctx, req.Storage, name, keyring); err != nil {
+		return err
 	}
-	return nil
+	_, err = addressindex.IndexAddress(ctx, req.Storage, account.AddressStr, model.NewAccountAddressOwner(name, keyring.LatestVersion))
+	return err
 }
 
-// handleSingleKeyAccountCreate generates a new keypair and stores it under the given account name.
-func handleSingleKeyAccountCreate(
-	ctx context.Context,
-	req *logical.Request,
-	data *framework.FieldData,
-) (*logical.Response, error) {
-	name, err := model.NewFieldDataWrapper(data).MustGetString("name")
-	if err != nil || name == "" {
-		return logical.ErrorResponse("name is required"), nil
-	}
-
+// generateSingleKeyAccount creates a fresh ECDSA key pair in the stored account format.
+func generateSingleKeyAccount() (*model.Account, error) {
 	privateKey, err := crypto.GenerateKey()
 	if err != nil {
 		return nil, fmt.Errorf("generate key: %w", err)
@@ -102,8 +99,24 @@ func handleSingleKeyAccountCreate(
 	publicKeyString := hexutil.Encode(publicKeyBytes)[4:]
 
 	address := crypto.PubkeyToAddress(privateKey.PublicKey).Hex()
+	return model.NewAccount(address, privateKeyString, publicKeyString), nil
+}
+
+// handleSingleKeyAccountCreate generates a new keypair and stores it under the given account name.
+func handleSingleKeyAccountCreate(
+	ctx context.Context,
+	req *logical.Request,
+	data *framework.FieldData,
+) (*logical.Response, error) {
+	name, err := model.NewFieldDataWrapper(data).MustGetString("name")
+	if err != nil || name == "" {
+		return logical.ErrorResponse("name is required"), nil
+	}
 
-	account := model.NewAccount(address, privateKeyString, publicKeyString)
+	account, err := generateSingleKeyAccount()
+	if err != nil {
+		return nil, err
+	}
 	if err := putSingleKeyAccountIfAbsent(ctx, req, name, account); err != nil {
 		if errors.Is(err, errSingleKeyAccountAlreadyExists) {
 			return respondSingleKeyAccountConflict(req)
@@ -118,7 +131,8 @@ func handleSingleKeyAccountCreate(
 	}, nil
 }
 
-// handleSingleKeyAccountImport stores an existing private key under the given account name.
+// handleSingleKeyAccountImport stores an existing private key under the given account name. The key is given
+// either as raw hex private_key or as a keystore v3 JSON (scrypt or pbkdf2) with its password.
 func handleSingleKeyAccountImport(
 	ctx context.Context,
 	req *logical.Request,
@@ -129,18 +143,26 @@ func handleSingleKeyAccountImport(
 	if err != nil || name == "" {
 		return logical.ErrorResponse("name is required"), nil
 	}
-	privHex, err := wrapper.MustGetString("private_key")
-	if err != nil {
-		return logical.ErrorResponse("%s", err.Error()), nil
-	}
-	privHex = normalizeHexNo0x(privHex)
-	if privHex == "" {
-		return logical.ErrorResponse("private_key is required"), nil
-	}
+	privHex := normalizeHexNo0x(wrapper.GetString("private_key", ""))
+	keystoreJSON := strings.TrimSpace(wrapper.GetString("keystore", ""))
 
-	privateKey, err := crypto.HexToECDSA(privHex)
-	if err != nil {
-		return logical.ErrorResponse("invalid private_key"), nil
+	var privateKey *ecdsa.PrivateKey
+	switch {
+	case privHex != "" && keystoreJSON != "":
+		return logical.ErrorResponse("provide either private_key or keystore, not both"), nil
+	case keystoreJSON != "":
+		privateKey, err = ethutil.DecryptKeystore([]byte(keystoreJSON), wrapper.GetString("password", ""))
+		if err != nil {
+			return logical.ErrorResponse("invalid keystore: %s", err.Error()), nil
+		}
+		privHex = hexutil.Encode(crypto.FromECDSA(privateKey))[2:]
+	case privHex != "":
+		privateKey, err = crypto.HexToECDSA(privHex)
+		if err != nil {
+			return logical.ErrorResponse("invalid private_key"), nil
+		}
+	default:
+		return logical.ErrorResponse("private_key or keystore is required"), nil
 	}
 	defer utils.ZeroKey(privateKey)
 
@@ -162,6 +184,57 @@ func handleSingleKeyAccountImport(
 	}, nil
 }
 
+// handleSingleKeyAccountExport returns the account's signing key as keystore v3 JSON encrypted with the caller's
+// password. It is refused unless config allow_key_export is set.
+func handleSingleKeyAccountExport(
+	ctx context.Context,
+	req *logical.Request,
+	data *framework.FieldData,
+) (*logical.Response, error) {
+	wrapper := model.NewFieldDataWrapper(data)
+	name, err := wrapper.MustGetString("name")
+	if err != nil || name == "" {
+		return logical.ErrorResponse("name is required"), nil
+	}
+	password := wrapper.GetString("password", "")
+	if password == "" {
+		return logical.ErrorResponse("password is required"), nil
+	}
+	cfg, err := config.Read(ctx, req.Storage)
+	if err != nil {
+		return nil, err
+	}
+	if !cfg.AllowKeyExport {
+		return logical.ErrorResponse("key export is disabled; set allow_key_export in config"), nil
+	}
+	keyring, err := ReadSingleKeyAccountKeyring(ctx, req.Storage, name)
+	if err != nil {
+		return RespondLoadSingleKeyAccountError(err)
+	}
+	account, err := keyring.SigningAccount()
+	if err != nil {
+		return nil, err
+	}
+	privateKey, err := crypto.HexToECDSA(account.PrivateKeyStr)
+	if err != nil {
+		return nil, fmt.Errorf("parse private key %s: %w", name, err)
+	}
+	defer utils.ZeroKey(privateKey)
+
+	lightKDF, _ := data.Get("light_kdf").(bool)
+	keyJSON, err := ethutil.EncryptKeystore(privateKey, password, lightKDF)
+	if err != nil {
+		return nil, err
+	}
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"address":  account.AddressStr,
+			"version":  keyring.LatestVersion,
+			"keystore": string(keyJSON),
+		},
+	}, nil
+}
+
 func normalizeHexNo0x(s string) string {
 	s = strings.TrimSpace(s)
 	s = strings.TrimPrefix(s, "0x")
@@ -192,12 +265,17 @@ func handleSingleKeyAccountRead(
 	if err != nil || name == "" {
 		return logical.ErrorResponse("name is required"), nil
 	}
-	account, err := ReadSingleKeyAccount(ctx, req.Storage, name)
+	keyring, err := ReadSingleKeyAccountKeyring(ctx, req.Storage, name)
 	if err != nil {
 		return RespondLoadSingleKeyAccountError(err)
 	}
+	account, err := keyring.SigningAccount()
+	if err != nil {
+		return nil, err
+	}
 	out := map[string]interface{}{
 		"address": account.AddressStr,
+		"version": keyring.LatestVersion,
 	}
 	if account.PublicKeyStr != "" {
 		out["public_key"] = account.PublicKeyStr
@@ -205,7 +283,8 @@ func handleSingleKeyAccountRead(
 	return &logical.Response{Data: out}, nil
 }
 
-// handleSingleKeyAccountsList returns sorted account names that have a stored key record.
+// handleSingleKeyAccountsList returns sorted account names that have a stored key record. Key info carries a
+// deleted flag so soft-deleted accounts stay listable until purged.
 func handleSingleKeyAccountsList(
 	ctx context.Context,
 	req *logical.Request,
@@ -229,5 +308,374 @@ func handleSingleKeyAccountsList(
 		names = append(names, id)
 	}
 	sort.Strings(names)
-	return logical.ListResponse(names), nil
+	info := make(map[string]interface{}, len(names))
+	for _, name := range names {
+		tomb, err := ReadSingleKeyAccountTombstone(ctx, req.Storage, name)
+		if err != nil {
+			return nil, err
+		}
+		info[name] = map[string]interface{}{"deleted": tomb != nil}
+	}
+	return logical.ListResponseWithInfo(names, info), nil
+}
+
+// handleSingleKeyAccountStatus returns the account's address and deletion status.
+func handleSingleKeyAccountStatus(
+	ctx context.Context,
+	req *logical.Request,
+	data *framework.FieldData,
+) (*logical.Response, error) {
+	name, err := model.NewFieldDataWrapper(data).MustGetString("name")
+	if err != nil || name == "" {
+		return logical.ErrorResponse("name is required"), nil
+	}
+	keyring, err := readSingleKeyAccountKeyring(ctx, req.Storage, name)
+	if err != nil {
+		return nil, err
+	}
+	if keyring == nil {
+		return nil, nil
+	}
+	tomb, err := ReadSingleKeyAccountTombstone(ctx, req.Storage, name)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := config.Read(ctx, req.Storage)
+	if err != nil {
+		return nil, err
+	}
+	out := model.TombstoneResponseData(tomb, cfg.DeletionRetention())
+	out["name"] = name
+	if account := keyring.Keys[keyring.LatestVersion]; account != nil {
+		out["address"] = account.AddressStr
+	}
+	out["latest_version"] = keyring.LatestVersion
+	return &logical.Response{Data: out}, nil
+}
+
+// handleSingleKeyAccountDelete soft-deletes the account: a tombstone disables signing until the account is
+// restored. Deleting an already deleted account keeps the original deletion time.
+func handleSingleKeyAccountDelete(
+	ctx context.Context,
+	req *logical.Request,
+	data *framework.FieldData,
+) (*logical.Response, error) {
+	name, err := model.NewFieldDataWrapper(data).MustGetString("name")
+	if err != nil || name == "" {
+		return logical.ErrorResponse("name is required"), nil
+	}
+	exists, err := singleKeyAccountExists(ctx, req.Storage, name)
+	if err != nil {
+		return nil, fmt.Errorf("get single-key account %s: %w", name, err)
+	}
+	if !exists {
+		return logical.ErrorResponse("account not found"), nil
+	}
+	tomb, err := ReadSingleKeyAccountTombstone(ctx, req.Storage, name)
+	if err != nil {
+		return nil, err
+	}
+	if tomb == nil {
+		if err := WriteSingleKeyAccountTombstone(ctx, req.Storage, name, model.NewTombstone(time.Now())); err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+// handleSingleKeyAccountRestore removes the tombstone of a soft-deleted account while the config
+// deletion_retention window is open.
+func handleSingleKeyAccountRestore(
+	ctx context.Context,
+	req *logical.Request,
+	data *framework.FieldData,
+) (*logical.Response, error) {
+	name, err := model.NewFieldDataWrapper(data).MustGetString("name")
+	if err != nil || name == "" {
+		return logical.ErrorResponse("name is required"), nil
+	}
+	tomb, err := ReadSingleKeyAccountTombstone(ctx, req.Storage, name)
+	if err != nil {
+		return nil, err
+	}
+	if tomb == nil {
+		return logical.ErrorResponse("account is not deleted"), nil
+	}
+	cfg, err := config.Read(ctx, req.Storage)
+	if err != nil {
+		return nil, err
+	}
+	if !tomb.Restorable(cfg.DeletionRetention(), time.Now()) {
+		return logical.ErrorResponse("restore window ended at %s; purge the account instead",
+			tomb.RestoreDeadline(cfg.DeletionRetention()).UTC().Format(time.RFC3339)), nil
+	}
+	if err := req.Storage.Delete(ctx, storagekey.SingleKeyAccountTombstoneKey(name)); err != nil {
+		return nil, fmt.Errorf("delete single-key account tombstone %s: %w", name, err)
+	}
+	return &logical.Response{Data: map[string]interface{}{"name": name, "deleted": false}}, nil
+}
+
+// handleSingleKeyAccountPurge permanently removes every storage entry of a soft-deleted account: the key
+// record, the account's policies and signed-transaction records, and the address index entries and nonce trackers
+// of its key versions. Trackers of an address indexed to a wallet or account holding the same key are kept.
+func handleSingleKeyAccountPurge(
+	ctx context.Context,
+	req *logical.Request,
+	data *framework.FieldData,
+) (*logical.Response, error) {
+	name, err := model.NewFieldDataWrapper(data).MustGetString("name")
+	if err != nil || name == "" {
+		return logical.ErrorResponse("name is required"), nil
+	}
+	tomb, err := ReadSingleKeyAccountTombstone(ctx, req.Storage, name)
+	if err != nil {
+		return nil, err
+	}
+	if tomb == nil {
+		return logical.ErrorResponse("account must be deleted before it is purged"), nil
+	}
+	view := logical.NewStorageView(req.Storage, storagekey.SingleKeyAccountPrefix(name))
+	keys, err := logical.CollectKeys(ctx, view)
+	if err != nil {
+		return nil, fmt.Errorf("collect single-key account keys %s: %w", name, err)
+	}
+	keyring, err := readSingleKeyAccountKeyring(ctx, req.Storage, name)
+	if err != nil {
+		return nil, err
+	}
+	if keyring != nil {
+		chainIDs, err := nonces.ListChainIDs(ctx, req.Storage)
+		if err != nil {
+			return nil, err
+		}
+		for version, acct := range keyring.Keys {
+			self := model.NewAccountAddressOwner(name, version)
+			owner, err := addressindex.ReadAddressOwner(ctx, req.Storage, acct.AddressStr)
+			if err != nil {
+				return nil, err
+			}
+			if err := addressindex.UnindexAddress(ctx, req.Storage, acct.AddressStr, self); err != nil {
+				return nil, err
+			}
+			if owner != nil && !owner.SameResource(self) {
+				continue
+			}
+			if err := nonces.DeleteAll(ctx, req.Storage, chainIDs, acct.AddressStr); err != nil {
+				return nil, err
+			}
+		}
+	}
+	// Remove the tombstone last so an interrupted purge can be retried.
+	for _, key := range keys {
+		if key == "tombstone" {
+			continue
+		}
+		if err := view.Delete(ctx, key); err != nil {
+			return nil, fmt.Errorf("purge single-key account %s key %s: %w", name, key, err)
+		}
+	}
+	if err := req.Storage.Delete(ctx, storagekey.SingleKeyAccountTombstoneKey(name)); err != nil {
+		return nil, fmt.Errorf("delete single-key account tombstone %s: %w", name, err)
+	}
+	return &logical.Response{Data: map[string]interface{}{"name": name, "purged_keys": len(keys)}}, nil
+}
+
+// existenceSingleKeyPolicy reports whether a destination policy is stored for name.
+func existenceSingleKeyPolicy(ctx context.Context, req *logical.Request, data *framework.FieldData) (bool, error) {
+	name := model.NewFieldDataWrapper(data).GetString("name", "")
+	if name == "" {
+		return false, nil
+	}
+	p, err := ReadSingleKeyDestinationPolicy(ctx, req.Storage, name)
+	if err != nil {
+		return false, err
+	}
+	return p != nil, nil
+}
+
+// handleSingleKeyPolicyRead returns the account's destination policy.
+func handleSingleKeyPolicyRead(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	name, err := model.NewFieldDataWrapper(data).MustGetString("name")
+	if err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	p, err := ReadSingleKeyDestinationPolicy(ctx, req.Storage, name)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return nil, nil
+	}
+	return &logical.Response{Data: destinationPolicyResponseData(p)}, nil
+}
+
+// handleSingleKeyPolicyWrite merges allow/deny into the account's destination policy; omitted lists are kept.
+func handleSingleKeyPolicyWrite(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	name, err := model.NewFieldDataWrapper(data).MustGetString("name")
+	if err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	if _, err := ReadSingleKeyAccount(ctx, req.Storage, name); err != nil {
+		return RespondLoadSingleKeyAccountError(err)
+	}
+	p, err := ReadSingleKeyDestinationPolicy(ctx, req.Storage, name)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		p = &model.DestinationPolicy{}
+	}
+	if raw, ok := data.GetOk("allow"); ok {
+		p.Allow = raw.([]string)
+	}
+	if raw, ok := data.GetOk("deny"); ok {
+		p.Deny = raw.([]string)
+	}
+	if err := p.Normalize(); err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	if err := WriteSingleKeyDestinationPolicy(ctx, req.Storage, name, p); err != nil {
+		return nil, err
+	}
+	return &logical.Response{Data: destinationPolicyResponseData(p)}, nil
+}
+
+// handleSingleKeyPolicyDelete removes the account's destination policy.
+func handleSingleKeyPolicyDelete(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	name, err := model.NewFieldDataWrapper(data).MustGetString("name")
+	if err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	if err := req.Storage.Delete(ctx, storagekey.SingleKeyAccountPolicyKey(name)); err != nil {
+		return nil, fmt.Errorf("delete single-key account policy %s: %w", name, err)
+	}
+	return nil, nil
+}
+
+// destinationPolicyResponseData builds the Vault response map for a destination policy.
+func destinationPolicyResponseData(p *model.DestinationPolicy) map[string]interface{} {
+	allow, deny := p.Allow, p.Deny
+	if allow == nil {
+		allow = []string{}
+	}
+	if deny == nil {
+		deny = []string{}
+	}
+	return map[string]interface{}{
+		"allow": allow,
+		"deny":  deny,
+	}
+}
+
+// withAccountLock serializes h per account name so keyring read-modify-write cycles do not interleave.
+func withAccountLock(accountMu *sync.Map, h framework.OperationFunc) framework.OperationFunc {
+	return func(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+		name := model.NewFieldDataWrapper(data).GetString("name", "")
+		mu, _ := accountMu.LoadOrStore(name, &sync.Mutex{})
+		mu.(*sync.Mutex).Lock()
+		defer mu.(*sync.Mutex).Unlock()
+		return h(ctx, req, data)
+	}
+}
+
+// handleSingleKeyAccountRotate generates a new key version for the account. The new version becomes the default
+// for signing and encryption; earlier versions keep signing down to min_signing_version and decrypting down to
+// min_decryption_version.
+// Caller must hold the per-account mutex from accountMu.
+func handleSingleKeyAccountRotate(
+	ctx context.Context,
+	req *logical.Request,
+	data *framework.FieldData,
+) (*logical.Response, error) {
+	name, err := model.NewFieldDataWrapper(data).MustGetString("name")
+	if err != nil || name == "" {
+		return logical.ErrorResponse("name is required"), nil
+	}
+	keyring, err := ReadSingleKeyAccountKeyring(ctx, req.Storage, name)
+	if err != nil {
+		return RespondLoadSingleKeyAccountError(err)
+	}
+	next, err := generateSingleKeyAccount()
+	if err != nil {
+		return nil, err
+	}
+	version := keyring.Rotate(next, time.Now())
+	if err := WriteSingleKeyAccountKeyring(ctx, req.Storage, name, keyring); err != nil {
+		return nil, err
+	}
+	owner := model.NewAccountAddressOwner(name, version)
+	if _, err := addressindex.IndexAddress(ctx, req.Storage, next.AddressStr, owner); err != nil {
+		return nil, err
+	}
+	return &logical.Response{Data: singleKeyKeyringResponseData(keyring)}, nil
+}
+
+// handleSingleKeyAccountKeysRead returns the account's key versions with their public data and version bounds.
+func handleSingleKeyAccountKeysRead(
+	ctx context.Context,
+	req *logical.Request,
+	data *framework.FieldData,
+) (*logical.Response, error) {
+	name, err := model.NewFieldDataWrapper(data).MustGetString("name")
+	if err != nil || name == "" {
+		return logical.ErrorResponse("name is required"), nil
+	}
+	keyring, err := ReadSingleKeyAccountKeyring(ctx, req.Storage, name)
+	if err != nil {
+		return RespondLoadSingleKeyAccountError(err)
+	}
+	return &logical.Response{Data: singleKeyKeyringResponseData(keyring)}, nil
+}
+
+// handleSingleKeyAccountKeysWrite updates min_signing_version and min_decryption_version, retiring (or
+// re-enabling) older key versions for signing and decryption. Caller must hold the per-account mutex from accountMu.
+func handleSingleKeyAccountKeysWrite(
+	ctx context.Context,
+	req *logical.Request,
+	data *framework.FieldData,
+) (*logical.Response, error) {
+	name, err := model.NewFieldDataWrapper(data).MustGetString("name")
+	if err != nil || name == "" {
+		return logical.ErrorResponse("name is required"), nil
+	}
+	keyring, err := ReadSingleKeyAccountKeyring(ctx, req.Storage, name)
+	if err != nil {
+		return RespondLoadSingleKeyAccountError(err)
+	}
+	minSigning, minDecryption := keyring.MinSigningVersion, keyring.MinDecryptionVersion
+	if raw, ok := data.GetOk("min_signing_version"); ok {
+		minSigning = raw.(int)
+	}
+	if raw, ok := data.GetOk("min_decryption_version"); ok {
+		minDecryption = raw.(int)
+	}
+	if err := keyring.SetMinVersions(minSigning, minDecryption); err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	if err := WriteSingleKeyAccountKeyring(ctx, req.Storage, name, keyring); err != nil {
+		return nil, err
+	}
+	return &logical.Response{Data: singleKeyKeyringResponseData(keyring)}, nil
+}
+
+// singleKeyKeyringResponseData renders the keyring's public fields; private keys are never returned.
+func singleKeyKeyringResponseData(keyring *model.AccountKeyring) map[string]interface{} {
+	keys := make(map[string]interface{}, len(keyring.Keys))
+	for version, acct := range keyring.Keys {
+		keys[strconv.Itoa(version)] = map[string]interface{}{
+			"address":    acct.AddressStr,
+			"public_key": acct.PublicKeyStr,
+			"created_at": acct.CreatedAt,
+		}
+	}
+	out := map[string]interface{}{
+		"latest_version":         keyring.LatestVersion,
+		"min_signing_version":    keyring.MinSigningVersion,
+		"min_decryption_version": keyring.MinDecryptionVersion,
+		"keys":                   keys,
+	}
+	if latest := keyring.Keys[keyring.LatestVersion]; latest != nil {
+		out["address"] = latest.AddressStr
+	}
+	return out
 }
@@ -21,7 +21,10 @@ import (
 	"context"
 	"math/big"
 	"net/http"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
@@ -31,7 +34,14 @@ import (
 	"github.com/hashicorp/vault/sdk/framework"
 	"github.com/hashicorp/vault/sdk/logical"
 
+	"github.com/bsostech/vault-blockchain/internal/ethutil"
 	"github.com/bsostech/vault-blockchain/internal/model"
+	"github.com/bsostech/vault-blockchain/internal/path/addressindex"
+	"github.com/bsostech/vault-blockchain/internal/path/chain"
+	"github.com/bsostech/vault-blockchain/internal/path/config"
+	"github.com/bsostech/vault-blockchain/internal/path/journal"
+	"github.com/bsostech/vault-blockchain/internal/path/nonces"
+	"github.com/bsostech/vault-blockchain/internal/path/signedtxs"
 	"github.com/bsostech/vault-blockchain/internal/path/storagekey"
 	"github.com/bsostech/vault-blockchain/pkg/utils"
 )
@@ -68,6 +78,8 @@ func fieldData(raw map[string]interface{}) *framework.FieldData {
 	baseKeys := []string{
 		"name",
 		"private_key",
+		"keystore",
+		"password",
 		"data",
 		"to",
 		"address_to",
@@ -83,6 +95,15 @@ func fieldData(raw map[string]interface{}) *framework.FieldData {
 		"max_priority_fee_per_gas",
 		"maxPriorityFeePerGas",
 		"access_list",
+		"max_fee_per_blob_gas",
+		"blob_versioned_hashes",
+		"blobs",
+		"blob_sidecar_version",
+		"authorization_list",
+		"address",
+		"message",
+		"version",
+		"validator",
 		"payload",
 	}
 
@@ -214,6 +235,75 @@ func TestHandleSingleKeySign_roundTripRecover(t *testing.T) {
 	}
 }
 
+// TestHandleSingleKeySignMessage_intendedValidator verifies version 0x00 signs 0x19 0x00 <validator> <data>.
+func TestHandleSingleKeySignMessage_intendedValidator(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	acct, cleanup := mustPutSingleKeyAccount(ctx, t, s, "a1m")
+	t.Cleanup(cleanup)
+
+	req := &logical.Request{Storage: s}
+	validator := common.HexToAddress("0x0000000000000000000000000000000000000009")
+	msg := []byte{0x01, 0x02}
+	resp, err := handleSingleKeySignMessage(ctx, req, fieldData(map[string]interface{}{
+		"name":      "a1m",
+		"data":      hexutil.Encode(msg),
+		"version":   "0x00",
+		"validator": validator.Hex(),
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || resp.IsError() {
+		t.Fatalf("resp=%v want success.", resp)
+	}
+
+	hash := crypto.Keccak256Hash([]byte{0x19, 0x00}, validator.Bytes(), msg)
+	if resp.Data["message_hash"] != hash.Hex() {
+		t.Fatalf("message_hash=%v want %s.", resp.Data["message_hash"], hash.Hex())
+	}
+	sigHex, _ := resp.Data["signature"].(string)
+	sig, err := hexutil.Decode(sigHex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig[crypto.RecoveryIDOffset] -= 27
+	pub, err := crypto.SigToPub(hash.Bytes(), sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gotAddr := crypto.PubkeyToAddress(*pub)
+	wantAddr := common.HexToAddress(acct.AddressStr)
+	if gotAddr != wantAddr {
+		t.Fatalf("recovered address=%s want %s.", gotAddr, wantAddr)
+	}
+}
+
+// TestHandleSingleKeySignMessage_rejectsMessageAndData verifies setting both message and data is a logical error.
+func TestHandleSingleKeySignMessage_rejectsMessageAndData(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	_, cleanup := mustPutSingleKeyAccount(ctx, t, s, "a1n")
+	t.Cleanup(cleanup)
+
+	req := &logical.Request{Storage: s}
+	resp, err := handleSingleKeySignMessage(ctx, req, fieldData(map[string]interface{}{
+		"name":    "a1n",
+		"message": "hello",
+		"data":    "0x01",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || !resp.IsError() {
+		t.Fatalf("resp=%v want logical error response.", resp)
+	}
+}
+
 // TestHandleSingleKeyEncryptDecrypt_roundTrip verifies ECIES encrypt then decrypt round-trip for a stored account.
 func TestHandleSingleKeyEncryptDecrypt_roundTrip(t *testing.T) {
 	t.Parallel()
@@ -458,151 +548,1241 @@ func TestHandleSingleKeySignTxEIP1559_smoke(t *testing.T) {
 	}
 }
 
-func TestTypedDataFromPayloadSingleKey_rejectsInvalidJSON(t *testing.T) {
+// TestHandleSingleKeySignTxEIP2930_smoke verifies EIP-2930 sign-tx returns a type-1 tx carrying the access list.
+func TestHandleSingleKeySignTxEIP2930_smoke(t *testing.T) {
 	t.Parallel()
 
-	_, err := typedDataFromPayloadSingleKey("{")
-	if err == nil {
-		t.Fatal("expected error.")
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	acct, cleanup := mustPutSingleKeyAccount(ctx, t, s, "a5b")
+	t.Cleanup(cleanup)
+
+	req := &logical.Request{Storage: s}
+	to := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	resp, err := handleSingleKeySignTxEIP2930(ctx, req, fieldData(map[string]interface{}{
+		"name":        "a5b",
+		"chain_id":    "1",
+		"gas_limit":   "30000",
+		"gas_price":   "3",
+		"nonce":       "0",
+		"to":          to.Hex(),
+		"access_list": `[{"address":"0x0000000000000000000000000000000000000002","storageKeys":[]}]`,
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || resp.Data == nil {
+		t.Fatal("expected response data.")
+	}
+	if resp.Data["type"] != "eip2930" {
+		t.Fatalf("type=%v want %v.", resp.Data["type"], "eip2930")
+	}
+
+	signedHex, _ := resp.Data["signed_transaction"].(string)
+	raw, err := hexutil.Decode(signedHex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tx ethtypes.Transaction
+	if err := tx.UnmarshalBinary(raw); err != nil {
+		t.Fatal(err)
+	}
+	if tx.Type() != ethtypes.AccessListTxType {
+		t.Fatalf("tx.Type()=%d want %d.", tx.Type(), ethtypes.AccessListTxType)
+	}
+	if len(tx.AccessList()) != 1 {
+		t.Fatalf("len(tx.AccessList())=%d want 1.", len(tx.AccessList()))
+	}
+	signer := ethtypes.LatestSignerForChainID(tx.ChainId())
+	from, err := ethtypes.Sender(signer, &tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if from != common.HexToAddress(acct.AddressStr) {
+		t.Fatalf("recovered from=%s want %s.", from.Hex(), acct.AddressStr)
 	}
 }
 
-func TestTypedDataFromPayloadSingleKey_requiresPrimaryType(t *testing.T) {
+// TestHandleSingleKeySignTxEIP1559_configChainAndGasDefault verifies allowed_chain_ids and default_gas_limit from config.
+func TestHandleSingleKeySignTxEIP1559_configChainAndGasDefault(t *testing.T) {
 	t.Parallel()
 
-	_, err := typedDataFromPayloadSingleKey(`{"types":{},"primaryType":"   ","domain":{},"message":{}}`)
-	if err == nil {
-		t.Fatal("expected error.")
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	_, cleanup := mustPutSingleKeyAccount(ctx, t, s, "a5cfg")
+	t.Cleanup(cleanup)
+	if err := config.Write(ctx, s, &model.Config{
+		AllowedChainIDs: []string{"11155111"},
+		DefaultGasLimit: 50000,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	req := &logical.Request{Storage: s}
+	raw := map[string]interface{}{
+		"name":                     "a5cfg",
+		"chain_id":                 "1",
+		"max_fee_per_gas":          "2",
+		"max_priority_fee_per_gas": "1",
+	}
+	resp, err := handleSingleKeySignTxEIP1559(ctx, req, fieldData(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || !resp.IsError() {
+		t.Fatalf("resp=%v want logical error response for chain 1.", resp)
+	}
+
+	raw["chain_id"] = "11155111"
+	resp, err = handleSingleKeySignTxEIP1559(ctx, req, fieldData(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || resp.IsError() {
+		t.Fatalf("resp=%v want success.", resp)
+	}
+	if resp.Data["gas_limit"] != uint64(50000) {
+		t.Fatalf("gas_limit=%v want 50000.", resp.Data["gas_limit"])
 	}
 }
 
-// TestSignEIP1559TxSingleKey_feeCapLtTip_returnsLogicalError verifies fee cap below tip yields a logical error response.
-func TestSignEIP1559TxSingleKey_feeCapLtTip_returnsLogicalError(t *testing.T) {
+// TestHandleSingleKeySignTxType0_rejectsContractCreation verifies a chain policy without allow_contract_creation
+// rejects a transaction that has no recipient.
+func TestHandleSingleKeySignTxType0_rejectsContractCreation(t *testing.T) {
 	t.Parallel()
 
-	wrapper := model.NewFieldDataWrapper(fieldData(map[string]interface{}{
-		"max_priority_fee_per_gas": "2",
-		"max_fee_per_gas":          "1",
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	_, cleanup := mustPutSingleKeyAccount(ctx, t, s, "a5chain")
+	t.Cleanup(cleanup)
+	if err := chain.WritePolicy(ctx, s, "1", &model.ChainPolicy{Name: "mainnet", MaxGasPrice: "10"}); err != nil {
+		t.Fatal(err)
+	}
+
+	req := &logical.Request{Storage: s}
+	resp, err := handleSingleKeySignTxType0(ctx, req, fieldData(map[string]interface{}{
+		"name":      "a5chain",
+		"chain_id":  "1",
+		"gas_price": "1",
+		"data":      "0x6000",
 	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || !resp.IsError() || !strings.Contains(resp.Error().Error(), "allow_contract_creation") {
+		t.Fatalf("resp=%v want allow_contract_creation error.", resp)
+	}
 
-	resp, err := signEIP1559TxSingleKey(
-		wrapper,
-		big.NewInt(1),
-		0,
-		21_000,
-		big.NewInt(0),
-		nil,
-		nil,
-		nil,
-		&model.Account{AddressStr: "0xabc"},
-	)
+	resp, err = handleSingleKeySignTxType0(ctx, req, fieldData(map[string]interface{}{
+		"name":      "a5chain",
+		"chain_id":  "1",
+		"gas_price": "11",
+		"to":        "0x0000000000000000000000000000000000000001",
+	}))
 	if err != nil {
 		t.Fatal(err)
 	}
-	if resp == nil {
-		t.Fatal("expected logical error response.")
+	if resp == nil || !resp.IsError() || !strings.Contains(resp.Error().Error(), "max_gas_price") {
+		t.Fatalf("resp=%v want max_gas_price error.", resp)
 	}
 }
 
-// TestSignType0TxSingleKey_invalidGasPrice_returnsLogicalError verifies non-decimal gas_price yields a logical error.
-func TestSignType0TxSingleKey_invalidGasPrice_returnsLogicalError(t *testing.T) {
+// TestHandleSingleKeyPolicy_denyWinsOnSignTx verifies a deny rule for a selector rejects EIP-1559 signing
+// even when the contract is allowed, and that deleting the policy lifts the restriction.
+func TestHandleSingleKeyPolicy_denyWinsOnSignTx(t *testing.T) {
 	t.Parallel()
 
-	wrapper := model.NewFieldDataWrapper(fieldData(map[string]interface{}{
-		"gas_price": "not-a-number",
-	}))
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	_, cleanup := mustPutSingleKeyAccount(ctx, t, s, "a5policy")
+	t.Cleanup(cleanup)
+	req := &logical.Request{Storage: s}
 
-	resp, err := signType0TxSingleKey(
-		wrapper,
-		big.NewInt(1),
-		0,
-		21_000,
-		big.NewInt(0),
-		nil,
-		nil,
-		nil,
-		&model.Account{AddressStr: "0xabc"},
-	)
+	token := "0x00000000000000000000000000000000000000aa"
+	policyData := &framework.FieldData{
+		Raw:    map[string]interface{}{"name": "a5policy", "allow": token, "deny": "*:0x095ea7b3"},
+		Schema: pathSingleKeyPolicy().Fields,
+	}
+	resp, err := handleSingleKeyPolicyWrite(ctx, req, policyData)
 	if err != nil {
 		t.Fatal(err)
 	}
-	if resp == nil {
-		t.Fatal("expected logical error response.")
+	if resp == nil || resp.IsError() {
+		t.Fatalf("resp=%v want success.", resp)
 	}
-}
 
-func TestTypedDataFromPayloadSingleKey_validJSON(t *testing.T) {
-	t.Parallel()
+	raw := map[string]interface{}{
+		"name":                     "a5policy",
+		"chain_id":                 "1",
+		"to":                       token,
+		"data":                     "0x095ea7b3",
+		"max_fee_per_gas":          "2",
+		"max_priority_fee_per_gas": "1",
+	}
+	resp, err = handleSingleKeySignTxEIP1559(ctx, req, fieldData(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || !resp.IsError() || !strings.Contains(resp.Error().Error(), "deny rule *:0x095ea7b3") {
+		t.Fatalf("resp=%v want deny rule error.", resp)
+	}
 
-	_, err := typedDataFromPayloadSingleKey(`{"types":{"EIP712Domain":[],"Mail":[]},"primaryType":"Mail","domain":{},"message":{}}`)
+	if _, err := handleSingleKeyPolicyDelete(ctx, req, policyData); err != nil {
+		t.Fatal(err)
+	}
+	resp, err = handleSingleKeySignTxEIP1559(ctx, req, fieldData(raw))
 	if err != nil {
 		t.Fatal(err)
 	}
+	if resp == nil || resp.IsError() {
+		t.Fatalf("resp=%v want success after policy delete.", resp)
+	}
 }
 
-func TestTypedDataFromPayloadSingleKey_acceptsSnakeCasePrimaryType(t *testing.T) {
+// TestHandleSingleKeySignTxBlob_smoke verifies blob sign-tx from versioned hashes returns a signed type-3 transaction.
+func TestHandleSingleKeySignTxBlob_smoke(t *testing.T) {
 	t.Parallel()
 
-	_, err := typedDataFromPayloadSingleKey(`{"types":{"EIP712Domain":[],"Mail":[]},"primary_type":"Mail","domain":{},"message":{}}`)
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	acct, cleanup := mustPutSingleKeyAccount(ctx, t, s, "a5d")
+	t.Cleanup(cleanup)
+
+	req := &logical.Request{Storage: s}
+	to := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	resp, err := handleSingleKeySignTxBlob(ctx, req, fieldData(map[string]interface{}{
+		"name":                     "a5d",
+		"chain_id":                 "1",
+		"gas_limit":                "21000",
+		"max_fee_per_gas":          "2",
+		"max_priority_fee_per_gas": "1",
+		"max_fee_per_blob_gas":     "3",
+		"to":                       to.Hex(),
+		"blob_versioned_hashes":    `["0x0100000000000000000000000000000000000000000000000000000000000001"]`,
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || resp.Data == nil || resp.IsError() {
+		t.Fatalf("resp=%v want response data.", resp)
+	}
+	if resp.Data["type"] != "blob" {
+		t.Fatalf("type=%v want %v.", resp.Data["type"], "blob")
+	}
+
+	signedHex, _ := resp.Data["signed_transaction"].(string)
+	var tx ethtypes.Transaction
+	if err := tx.UnmarshalBinary(hexutil.MustDecode(signedHex)); err != nil {
+		t.Fatal(err)
+	}
+	if tx.Type() != ethtypes.BlobTxType || len(tx.BlobHashes()) != 1 {
+		t.Fatalf("tx.Type()=%d blob hashes=%d want %d/1.", tx.Type(), len(tx.BlobHashes()), ethtypes.BlobTxType)
+	}
+	signer := ethtypes.LatestSignerForChainID(tx.ChainId())
+	from, err := ethtypes.Sender(signer, &tx)
 	if err != nil {
 		t.Fatal(err)
 	}
+	if from != common.HexToAddress(acct.AddressStr) {
+		t.Fatalf("recovered from=%s want %s.", from.Hex(), acct.AddressStr)
+	}
 }
 
-// TestHandleSingleKeyAccountCreate_conflictReturns409 verifies duplicate create returns HTTP 409.
-func TestHandleSingleKeyAccountCreate_conflictReturns409(t *testing.T) {
+// TestHandleSingleKeySignTxEIP7702_smoke verifies a signed authorization feeds a type-4 set-code tx.
+func TestHandleSingleKeySignTxEIP7702_smoke(t *testing.T) {
 	t.Parallel()
 
 	ctx := context.Background()
 	s := new(logical.InmemStorage)
-	req := &logical.Request{Storage: s}
+	acct, cleanup := mustPutSingleKeyAccount(ctx, t, s, "a5f")
+	t.Cleanup(cleanup)
+	// Chain 0 authorizations need chains/0; registering it makes chain 1 need registering too.
+	for id, name := range map[string]string{"0": "every chain", "1": "mainnet"} {
+		if err := chain.WritePolicy(ctx, s, id, &model.ChainPolicy{Name: name}); err != nil {
+			t.Fatal(err)
+		}
+	}
 
-	resp, err := handleSingleKeyAccountCreate(ctx, req, fieldData(map[string]interface{}{"name": "dup"}))
+	req := &logical.Request{Storage: s}
+	authResp, err := handleSingleKeySignAuthorization(ctx, req, fieldData(map[string]interface{}{
+		"name":     "a5f",
+		"chain_id": "0",
+		"address":  "0x0000000000000000000000000000000000000007",
+		"nonce":    "1",
+	}))
 	if err != nil {
 		t.Fatal(err)
 	}
-	if resp == nil || resp.Data == nil {
-		t.Fatal("expected response.")
+	if authResp == nil || authResp.IsError() {
+		t.Fatalf("authResp=%v want success.", authResp)
+	}
+	if authResp.Data["authority"] != common.HexToAddress(acct.AddressStr).Hex() {
+		t.Fatalf("authority=%v want %s.", authResp.Data["authority"], acct.AddressStr)
 	}
 
-	resp2, err := handleSingleKeyAccountCreate(ctx, req, fieldData(map[string]interface{}{"name": "dup"}))
+	resp, err := handleSingleKeySignTxEIP7702(ctx, req, fieldData(map[string]interface{}{
+		"name":                     "a5f",
+		"chain_id":                 "1",
+		"gas_limit":                "100000",
+		"max_fee_per_gas":          "2",
+		"max_priority_fee_per_gas": "1",
+		"to":                       acct.AddressStr,
+		"authorization_list":       "[" + authResp.Data["authorization"].(string) + "]",
+	}))
 	if err != nil {
 		t.Fatal(err)
 	}
-	if resp2 == nil {
-		t.Fatal("expected response.")
+	if resp == nil || resp.IsError() {
+		t.Fatalf("resp=%v want success.", resp)
 	}
-	code, _ := resp2.Data[logical.HTTPStatusCode].(int)
-	if code != http.StatusConflict {
-		t.Fatalf("status=%d want %d.", code, http.StatusConflict)
+	if resp.Data["type"] != "eip7702" {
+		t.Fatalf("type=%v want %v.", resp.Data["type"], "eip7702")
 	}
 }
 
-// TestHandleSingleKeyAccountsList_sorted verifies listed account names are sorted lexicographically.
-func TestHandleSingleKeyAccountsList_sorted(t *testing.T) {
+// TestHandleSingleKeySignTxEIP7702_deniedDelegate verifies the destination policy applies to EIP-7702 delegates
+// on both sign-authorization and type-4 transactions.
+func TestHandleSingleKeySignTxEIP7702_deniedDelegate(t *testing.T) {
 	t.Parallel()
 
 	ctx := context.Background()
 	s := new(logical.InmemStorage)
-	_, c1 := mustPutSingleKeyAccount(ctx, t, s, "b")
-	_, c2 := mustPutSingleKeyAccount(ctx, t, s, "a")
-	t.Cleanup(c1)
-	t.Cleanup(c2)
-
+	acct, cleanup := mustPutSingleKeyAccount(ctx, t, s, "a5d")
+	t.Cleanup(cleanup)
 	req := &logical.Request{Storage: s}
-	resp, err := handleSingleKeyAccountsList(ctx, req, fieldData(map[string]interface{}{}))
+	const delegate = "0x0000000000000000000000000000000000000007"
+	authRaw := map[string]interface{}{
+		"name":     "a5d",
+		"chain_id": "1",
+		"address":  delegate,
+		"nonce":    "1",
+	}
+	authResp, err := handleSingleKeySignAuthorization(ctx, req, fieldData(authRaw))
 	if err != nil {
 		t.Fatal(err)
 	}
-	if resp == nil || len(resp.Data) == 0 {
-		t.Fatal("expected list response data.")
+	if authResp == nil || authResp.IsError() {
+		t.Fatalf("authResp=%v want success before the policy.", authResp)
 	}
 
-	keys, ok := resp.Data["keys"].([]string)
-	if !ok {
-		t.Fatalf("keys type=%T want []string.", resp.Data["keys"])
+	policy := &model.DestinationPolicy{Allow: []string{"0x00000000000000000000000000000000000000aa"}}
+	if err := WriteSingleKeyDestinationPolicy(ctx, s, "a5d", policy); err != nil {
+		t.Fatal(err)
 	}
-	if len(keys) != 2 || keys[0] != "a" || keys[1] != "b" {
-		t.Fatalf("keys=%v want [a b].", keys)
+	resp, err := handleSingleKeySignAuthorization(ctx, req, fieldData(authRaw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || !resp.IsError() || !strings.Contains(resp.Error().Error(), "delegate") {
+		t.Fatalf("resp=%v want delegate policy error.", resp)
+	}
+	policy.Allow = append(policy.Allow, acct.AddressStr)
+	if err := WriteSingleKeyDestinationPolicy(ctx, s, "a5d", policy); err != nil {
+		t.Fatal(err)
+	}
+	resp, err = handleSingleKeySignTxEIP7702(ctx, req, fieldData(map[string]interface{}{
+		"name":                     "a5d",
+		"chain_id":                 "1",
+		"nonce":                    "0",
+		"gas_limit":                "100000",
+		"max_fee_per_gas":          "2",
+		"max_priority_fee_per_gas": "1",
+		"to":                       acct.AddressStr,
+		"authorization_list":       "[" + authResp.Data["authorization"].(string) + "]",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || !resp.IsError() || !strings.Contains(resp.Error().Error(), "delegate") {
+		t.Fatalf("resp=%v want delegate policy error.", resp)
+	}
+}
+
+// TestHandleSingleKeySignAuthorization_chainRegistry verifies chain 0 needs chains/0 and that unregistered chains
+// are refused once any chain is registered.
+func TestHandleSingleKeySignAuthorization_chainRegistry(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	_, cleanup := mustPutSingleKeyAccount(ctx, t, s, "a5r")
+	t.Cleanup(cleanup)
+	req := &logical.Request{Storage: s}
+	sign := func(chainID string) *logical.Response {
+		t.Helper()
+		resp, err := handleSingleKeySignAuthorization(ctx, req, fieldData(map[string]interface{}{
+			"name":     "a5r",
+			"chain_id": chainID,
+			"address":  "0x0000000000000000000000000000000000000007",
+			"nonce":    "0",
+		}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+	if resp := sign("0"); resp == nil || !resp.IsError() {
+		t.Fatalf("chain 0: resp=%v want error.", resp)
+	}
+	if resp := sign("5"); resp == nil || resp.IsError() {
+		t.Fatalf("chain 5 with no chain registered: resp=%v want success.", resp)
+	}
+	if err := chain.WritePolicy(ctx, s, "1", &model.ChainPolicy{Name: "mainnet"}); err != nil {
+		t.Fatal(err)
+	}
+	if resp := sign("5"); resp == nil || !resp.IsError() {
+		t.Fatalf("unregistered chain 5: resp=%v want error.", resp)
+	}
+	if resp := sign("1"); resp == nil || resp.IsError() {
+		t.Fatalf("chain 1: resp=%v want success.", resp)
+	}
+}
+
+// TestHandleSingleKeySignAuthorization_invalidAddress verifies a non-hex delegate address is a logical error.
+func TestHandleSingleKeySignAuthorization_invalidAddress(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	_, cleanup := mustPutSingleKeyAccount(ctx, t, s, "a5g")
+	t.Cleanup(cleanup)
+
+	req := &logical.Request{Storage: s}
+	resp, err := handleSingleKeySignAuthorization(ctx, req, fieldData(map[string]interface{}{
+		"name":     "a5g",
+		"chain_id": "1",
+		"address":  "not-an-address",
+		"nonce":    "0",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || !resp.IsError() {
+		t.Fatalf("resp=%v want logical error response.", resp)
+	}
+}
+
+// TestHandleSingleKeySignTxBlob_requiresTo verifies blob txs without a recipient are rejected as a client error.
+func TestHandleSingleKeySignTxBlob_requiresTo(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	_, cleanup := mustPutSingleKeyAccount(ctx, t, s, "a5e")
+	t.Cleanup(cleanup)
+
+	req := &logical.Request{Storage: s}
+	resp, err := handleSingleKeySignTxBlob(ctx, req, fieldData(map[string]interface{}{
+		"name":                     "a5e",
+		"chain_id":                 "1",
+		"gas_limit":                "21000",
+		"max_fee_per_gas":          "2",
+		"max_priority_fee_per_gas": "1",
+		"max_fee_per_blob_gas":     "3",
+		"blob_versioned_hashes":    `["0x0100000000000000000000000000000000000000000000000000000000000001"]`,
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || !resp.IsError() {
+		t.Fatalf("resp=%v want logical error response.", resp)
+	}
+}
+
+func TestTypedDataFromPayloadSingleKey_rejectsInvalidJSON(t *testing.T) {
+	t.Parallel()
+
+	_, err := typedDataFromPayloadSingleKey("{")
+	if err == nil {
+		t.Fatal("expected error.")
+	}
+}
+
+func TestTypedDataFromPayloadSingleKey_requiresPrimaryType(t *testing.T) {
+	t.Parallel()
+
+	_, err := typedDataFromPayloadSingleKey(`{"types":{},"primaryType":"   ","domain":{},"message":{}}`)
+	if err == nil {
+		t.Fatal("expected error.")
+	}
+}
+
+// TestSignEIP1559TxSingleKey_feeCapLtTip_returnsLogicalError verifies fee cap below tip yields a logical error response.
+func TestSignEIP1559TxSingleKey_feeCapLtTip_returnsLogicalError(t *testing.T) {
+	t.Parallel()
+
+	wrapper := model.NewFieldDataWrapper(fieldData(map[string]interface{}{
+		"max_priority_fee_per_gas": "2",
+		"max_fee_per_gas":          "1",
+	}))
+
+	resp, err := signEIP1559TxSingleKey(
+		wrapper,
+		big.NewInt(1),
+		0,
+		21_000,
+		big.NewInt(0),
+		nil,
+		nil,
+		nil,
+		&model.Account{AddressStr: "0xabc"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil {
+		t.Fatal("expected logical error response.")
+	}
+}
+
+// TestSignType0TxSingleKey_invalidGasPrice_returnsLogicalError verifies non-decimal gas_price yields a logical error.
+func TestSignType0TxSingleKey_invalidGasPrice_returnsLogicalError(t *testing.T) {
+	t.Parallel()
+
+	wrapper := model.NewFieldDataWrapper(fieldData(map[string]interface{}{
+		"gas_price": "not-a-number",
+	}))
+
+	resp, err := signType0TxSingleKey(
+		wrapper,
+		big.NewInt(1),
+		0,
+		21_000,
+		big.NewInt(0),
+		nil,
+		nil,
+		nil,
+		&model.Account{AddressStr: "0xabc"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil {
+		t.Fatal("expected logical error response.")
+	}
+}
+
+func TestTypedDataFromPayloadSingleKey_validJSON(t *testing.T) {
+	t.Parallel()
+
+	_, err := typedDataFromPayloadSingleKey(`{"types":{"EIP712Domain":[],"Mail":[]},"primaryType":"Mail","domain":{},"message":{}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTypedDataFromPayloadSingleKey_acceptsSnakeCasePrimaryType(t *testing.T) {
+	t.Parallel()
+
+	_, err := typedDataFromPayloadSingleKey(`{"types":{"EIP712Domain":[],"Mail":[]},"primary_type":"Mail","domain":{},"message":{}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestHandleSingleKeyAccountCreate_conflictReturns409 verifies duplicate create returns HTTP 409.
+func TestHandleSingleKeyAccountCreate_conflictReturns409(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	req := &logical.Request{Storage: s}
+
+	resp, err := handleSingleKeyAccountCreate(ctx, req, fieldData(map[string]interface{}{"name": "dup"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || resp.Data == nil {
+		t.Fatal("expected response.")
+	}
+
+	resp2, err := handleSingleKeyAccountCreate(ctx, req, fieldData(map[string]interface{}{"name": "dup"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp2 == nil {
+		t.Fatal("expected response.")
+	}
+	code, _ := resp2.Data[logical.HTTPStatusCode].(int)
+	if code != http.StatusConflict {
+		t.Fatalf("status=%d want %d.", code, http.StatusConflict)
+	}
+}
+
+// TestHandleSingleKeyAccountsList_sorted verifies listed account names are sorted lexicographically.
+func TestHandleSingleKeyAccountsList_sorted(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	_, c1 := mustPutSingleKeyAccount(ctx, t, s, "b")
+	_, c2 := mustPutSingleKeyAccount(ctx, t, s, "a")
+	t.Cleanup(c1)
+	t.Cleanup(c2)
+
+	req := &logical.Request{Storage: s}
+	resp, err := handleSingleKeyAccountsList(ctx, req, fieldData(map[string]interface{}{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || len(resp.Data) == 0 {
+		t.Fatal("expected list response data.")
+	}
+
+	keys, ok := resp.Data["keys"].([]string)
+	if !ok {
+		t.Fatalf("keys type=%T want []string.", resp.Data["keys"])
+	}
+	if len(keys) != 2 || keys[0] != "a" || keys[1] != "b" {
+		t.Fatalf("keys=%v want [a b].", keys)
+	}
+}
+
+// TestHandleSingleKeyAccountDelete_blocksSigningUntilRestored verifies a soft-deleted account refuses signing,
+// is flagged in list and status reads, and signs again after restore.
+func TestHandleSingleKeyAccountDelete_blocksSigningUntilRestored(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	_, cleanup := mustPutSingleKeyAccount(ctx, t, s, "adel")
+	t.Cleanup(cleanup)
+	req := &logical.Request{Storage: s}
+	signRaw := map[string]interface{}{
+		"name": "adel",
+		"data": hexutil.Encode([]byte("hello")),
+	}
+
+	resp, err := handleSingleKeyAccountDelete(ctx, req, fieldData(map[string]interface{}{"name": "adel"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp != nil && resp.IsError() {
+		t.Fatalf("resp=%v want success.", resp)
+	}
+
+	resp, err = handleSingleKeySign(ctx, req, fieldData(signRaw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || !resp.IsError() || !strings.Contains(resp.Error().Error(), "account is deleted") {
+		t.Fatalf("resp=%v want account is deleted error.", resp)
+	}
+
+	resp, err = handleSingleKeyAccountsList(ctx, req, fieldData(map[string]interface{}{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, _ := resp.Data["key_info"].(map[string]interface{})
+	if entry, _ := info["adel"].(map[string]interface{}); entry["deleted"] != true {
+		t.Fatalf("key_info=%v want deleted=true.", info)
+	}
+
+	resp, err = handleSingleKeyAccountStatus(ctx, req, fieldData(map[string]interface{}{"name": "adel"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || resp.Data["deleted"] != true || resp.Data["restore_until"] == nil {
+		t.Fatalf("resp=%v want deleted status with restore_until.", resp)
+	}
+
+	resp, err = handleSingleKeyAccountRestore(ctx, req, fieldData(map[string]interface{}{"name": "adel"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || resp.IsError() {
+		t.Fatalf("resp=%v want success.", resp)
+	}
+	resp, err = handleSingleKeySign(ctx, req, fieldData(signRaw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || resp.IsError() {
+		t.Fatalf("resp=%v want signature after restore.", resp)
+	}
+}
+
+// TestHandleSingleKeyAccountRestore_windowEnded verifies restore is refused once config deletion_retention has elapsed.
+func TestHandleSingleKeyAccountRestore_windowEnded(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	if err := config.Write(ctx, s, &model.Config{DeletionRetentionSeconds: 3600}); err != nil {
+		t.Fatal(err)
+	}
+	_, cleanup := mustPutSingleKeyAccount(ctx, t, s, "aold")
+	t.Cleanup(cleanup)
+	if err := WriteSingleKeyAccountTombstone(ctx, s, "aold", model.NewTombstone(time.Now().Add(-2*time.Hour))); err != nil {
+		t.Fatal(err)
+	}
+	req := &logical.Request{Storage: s}
+
+	resp, err := handleSingleKeyAccountRestore(ctx, req, fieldData(map[string]interface{}{"name": "aold"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || !resp.IsError() || !strings.Contains(resp.Error().Error(), "restore window ended") {
+		t.Fatalf("resp=%v want restore window error.", resp)
+	}
+}
+
+// TestHandleSingleKeyAccountPurge_removesAllEntries verifies purge requires a prior delete, removes the key
+// record and policy, and lets the name be reused.
+func TestHandleSingleKeyAccountPurge_removesAllEntries(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	acct, cleanup := mustPutSingleKeyAccount(ctx, t, s, "apurge")
+	t.Cleanup(cleanup)
+	if err := WriteSingleKeyDestinationPolicy(ctx, s, "apurge", &model.DestinationPolicy{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := nonces.Write(ctx, s, "1", acct.AddressStr, &model.NonceTracker{Next: 3}); err != nil {
+		t.Fatal(err)
+	}
+	if err := signedtxs.Write(ctx, s, storagekey.SingleKeyAccountSignedTxsPrefix("apurge"), "0xabc", &model.SignedTxRecord{Raw: "0x01"}); err != nil {
+		t.Fatal(err)
+	}
+	req := &logical.Request{Storage: s}
+
+	resp, err := handleSingleKeyAccountPurge(ctx, req, fieldData(map[string]interface{}{"name": "apurge"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || !resp.IsError() || !strings.Contains(resp.Error().Error(), "must be deleted") {
+		t.Fatalf("resp=%v want must be deleted error.", resp)
+	}
+
+	if _, err := handleSingleKeyAccountDelete(ctx, req, fieldData(map[string]interface{}{"name": "apurge"})); err != nil {
+		t.Fatal(err)
+	}
+	resp, err = handleSingleKeyAccountPurge(ctx, req, fieldData(map[string]interface{}{"name": "apurge"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || resp.IsError() || resp.Data["purged_keys"] != 4 {
+		t.Fatalf("resp=%v want purged_keys=4.", resp)
+	}
+	left, err := logical.CollectKeys(ctx, logical.NewStorageView(s, storagekey.SingleKeyAccountPrefix("apurge")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(left) != 0 {
+		t.Fatalf("remaining keys=%v want none.", left)
+	}
+	if tracker, err := nonces.Read(ctx, s, "1", acct.AddressStr); err != nil || tracker != nil {
+		t.Fatalf("tracker=%+v err=%v want purged.", tracker, err)
+	}
+
+	resp, err = handleSingleKeyAccountCreate(ctx, req, fieldData(map[string]interface{}{"name": "apurge"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || resp.IsError() {
+		t.Fatalf("resp=%v want name reusable after purge.", resp)
+	}
+}
+
+// TestHandleSingleKeyAccountRotate_versionBounds verifies rotation moves default signing to a new key while the
+// previous version still signs with an explicit key_version and decrypts, until min_signing_version and
+// min_decryption_version retire it.
+func TestHandleSingleKeyAccountRotate_versionBounds(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	original, cleanup := mustPutSingleKeyAccount(ctx, t, s, "arot")
+	t.Cleanup(cleanup)
+	req := &logical.Request{Storage: s}
+
+	resp, err := handleSingleKeyEncrypt(ctx, req, fieldData(map[string]interface{}{
+		"name": "arot",
+		"data": hexutil.Encode([]byte("secret")),
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || resp.Data["key_version"] != 1 {
+		t.Fatalf("resp=%v want key_version 1.", resp)
+	}
+	oldCiphertext := resp.Data["ciphertext"].(string)
+
+	resp, err = handleSingleKeyAccountRotate(ctx, req, fieldData(map[string]interface{}{"name": "arot"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || resp.IsError() || resp.Data["latest_version"] != 2 || resp.Data["min_signing_version"] != 1 {
+		t.Fatalf("resp=%v want latest_version=2 and min_signing_version=1.", resp)
+	}
+	rotatedAddr, _ := resp.Data["address"].(string)
+	if rotatedAddr == "" || strings.EqualFold(rotatedAddr, original.AddressStr) {
+		t.Fatalf("address=%q want a new address.", rotatedAddr)
+	}
+
+	var accountMu sync.Map
+	signFields := pathSingleKeySign(&accountMu).Fields
+	dataBytes := []byte("hello")
+	sign := func(keyVersion int) *logical.Response {
+		t.Helper()
+		resp, err := handleSingleKeySign(ctx, req, &framework.FieldData{
+			Raw:    map[string]interface{}{"name": "arot", "data": hexutil.Encode(dataBytes), "key_version": keyVersion},
+			Schema: signFields,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+	signer := func(resp *logical.Response) common.Address {
+		t.Helper()
+		sig, err := hexutil.Decode(resp.Data["signature"].(string))
+		if err != nil {
+			t.Fatal(err)
+		}
+		pub, err := crypto.SigToPub(crypto.Keccak256(dataBytes), sig)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return crypto.PubkeyToAddress(*pub)
+	}
+	if got := signer(sign(0)); got != common.HexToAddress(rotatedAddr) {
+		t.Fatalf("signer=%s want rotated %s.", got, rotatedAddr)
+	}
+	if got := signer(sign(1)); got != common.HexToAddress(original.AddressStr) {
+		t.Fatalf("signer=%s want version 1 %s.", got, original.AddressStr)
+	}
+	if resp := sign(3); resp == nil || !resp.IsError() {
+		t.Fatalf("resp=%v want key_version out of range error.", resp)
+	}
+
+	decrypt := map[string]interface{}{"name": "arot", "data": oldCiphertext}
+	resp, err = handleSingleKeyDecrypt(ctx, req, fieldData(decrypt))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || resp.Data["plaintext"] != hexutil.Encode([]byte("secret")) || resp.Data["key_version"] != 1 {
+		t.Fatalf("resp=%v want plaintext from key_version 1.", resp)
+	}
+
+	keysFields := pathSingleKeyAccountKeys(&accountMu).Fields
+	for _, bounds := range []map[string]interface{}{
+		{"min_signing_version": 3},
+		{"min_decryption_version": 2},
+		{"min_signing_version": 2, "min_decryption_version": 3},
+	} {
+		bounds["name"] = "arot"
+		resp, err = handleSingleKeyAccountKeysWrite(ctx, req, &framework.FieldData{Raw: bounds, Schema: keysFields})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp == nil || !resp.IsError() {
+			t.Fatalf("%v: resp=%v want out of range error.", bounds, resp)
+		}
+	}
+	resp, err = handleSingleKeyAccountKeysWrite(ctx, req, &framework.FieldData{
+		Raw:    map[string]interface{}{"name": "arot", "min_signing_version": 2},
+		Schema: keysFields,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || resp.IsError() || resp.Data["min_signing_version"] != 2 || resp.Data["min_decryption_version"] != 1 {
+		t.Fatalf("resp=%v want min_signing_version=2 and min_decryption_version=1.", resp)
+	}
+	if resp := sign(1); resp == nil || !resp.IsError() {
+		t.Fatalf("resp=%v want version 1 refused below min_signing_version.", resp)
+	}
+	resp, err = handleSingleKeyAccountKeysWrite(ctx, req, &framework.FieldData{
+		Raw:    map[string]interface{}{"name": "arot", "min_decryption_version": 2},
+		Schema: keysFields,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || resp.IsError() || resp.Data["min_decryption_version"] != 2 {
+		t.Fatalf("resp=%v want min_decryption_version=2.", resp)
+	}
+	if _, err := handleSingleKeyDecrypt(ctx, req, fieldData(decrypt)); err == nil {
+		t.Fatal("expected decrypt with retired version to fail.")
+	}
+
+	resp, err = handleSingleKeyAccountKeysRead(ctx, req, &framework.FieldData{
+		Raw:    map[string]interface{}{"name": "arot"},
+		Schema: keysFields,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys, _ := resp.Data["keys"].(map[string]interface{})
+	if len(keys) != 2 {
+		t.Fatalf("keys=%v want 2 versions.", keys)
+	}
+	for version, raw := range keys {
+		if _, leaked := raw.(map[string]interface{})["private_key"]; leaked {
+			t.Fatalf("version %s exposes private_key.", version)
+		}
+	}
+}
+
+// TestHandleSingleKeyAccountKeystore_importAndExport imports a keystore v3 JSON, then verifies export is refused
+// until config allow_key_export is set and that the exported keystore decrypts to the same key.
+func TestHandleSingleKeyAccountKeystore_importAndExport(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	req := &logical.Request{Storage: s}
+
+	pk, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { utils.ZeroKey(pk) })
+	keyJSON, err := ethutil.EncryptKeystore(pk, "geth-pass", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	importFields := pathSingleKeyAccountImport().Fields
+	resp, err := handleSingleKeyAccountImport(ctx, req, &framework.FieldData{
+		Raw:    map[string]interface{}{"name": "ks", "keystore": string(keyJSON), "password": "wrong"},
+		Schema: importFields,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || !resp.IsError() {
+		t.Fatalf("resp=%v want error for wrong password.", resp)
+	}
+	resp, err = handleSingleKeyAccountImport(ctx, req, &framework.FieldData{
+		Raw:    map[string]interface{}{"name": "ks", "keystore": string(keyJSON), "private_key": "01"},
+		Schema: importFields,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || !resp.IsError() {
+		t.Fatalf("resp=%v want error for both private_key and keystore.", resp)
+	}
+	resp, err = handleSingleKeyAccountImport(ctx, req, &framework.FieldData{
+		Raw:    map[string]interface{}{"name": "ks", "keystore": string(keyJSON), "password": "geth-pass"},
+		Schema: importFields,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantAddr := crypto.PubkeyToAddress(pk.PublicKey).Hex()
+	if resp == nil || resp.IsError() || resp.Data["address"] != wantAddr {
+		t.Fatalf("resp=%v want address %s.", resp, wantAddr)
+	}
+
+	exportFields := pathSingleKeyAccountExport().Fields
+	exportRaw := map[string]interface{}{"name": "ks", "password": "vault-pass", "light_kdf": true}
+	resp, err = handleSingleKeyAccountExport(ctx, req, &framework.FieldData{Raw: exportRaw, Schema: exportFields})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || !resp.IsError() || !strings.Contains(resp.Error().Error(), "allow_key_export") {
+		t.Fatalf("resp=%v want export disabled error.", resp)
+	}
+
+	if err := config.Write(ctx, s, &model.Config{AllowKeyExport: true}); err != nil {
+		t.Fatal(err)
+	}
+	resp, err = handleSingleKeyAccountExport(ctx, req, &framework.FieldData{Raw: exportRaw, Schema: exportFields})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || resp.IsError() || resp.Data["address"] != wantAddr || resp.Data["version"] != 1 {
+		t.Fatalf("resp=%v want export of version 1.", resp)
+	}
+	exported, err := ethutil.DecryptKeystore([]byte(resp.Data["keystore"].(string)), "vault-pass")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exported.D.Cmp(pk.D) != 0 {
+		t.Fatal("exported key differs from the imported key.")
+	}
+}
+
+// TestSingleKeyAccountAddressIndex_lifecycle verifies create, import and rotation index each new key version,
+// the backfill indexes accounts stored without an entry, and purge removes every version's entry.
+func TestSingleKeyAccountAddressIndex_lifecycle(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	req := &logical.Request{Storage: s}
+	wantOwner := func(addr, name string, version int) {
+		t.Helper()
+		owner, err := addressindex.ReadAddressOwner(ctx, s, addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if owner == nil || owner.Type != model.AddressOwnerAccount || owner.Name != name || owner.Version != version {
+			t.Fatalf("owner of %s=%+v want account %s version %d.", addr, owner, name, version)
+		}
+	}
+
+	resp, err := handleSingleKeyAccountCreate(ctx, req, fieldData(map[string]interface{}{"name": "aidx"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	created := resp.Data["address"].(string)
+	wantOwner(created, "aidx", 1)
+
+	pk, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { utils.ZeroKey(pk) })
+	resp, err = handleSingleKeyAccountImport(ctx, req, fieldData(map[string]interface{}{
+		"name":        "aimp",
+		"private_key": hexutil.Encode(crypto.FromECDSA(pk)),
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantOwner(resp.Data["address"].(string), "aimp", 1)
+
+	resp, err = handleSingleKeyAccountRotate(ctx, req, fieldData(map[string]interface{}{"name": "aidx"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rotated := resp.Data["address"].(string)
+	wantOwner(rotated, "aidx", 2)
+	wantOwner(created, "aidx", 1)
+
+	legacy, cleanup := mustPutSingleKeyAccount(ctx, t, s, "alegacy")
+	t.Cleanup(cleanup)
+	indexed, err := IndexSingleKeyAccountAddresses(ctx, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if indexed != 4 {
+		t.Fatalf("indexed=%d want 4 (one new, three rewritten).", indexed)
+	}
+	wantOwner(legacy.AddressStr, "alegacy", 1)
+
+	if _, err := handleSingleKeyAccountDelete(ctx, req, fieldData(map[string]interface{}{"name": "aidx"})); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := handleSingleKeyAccountPurge(ctx, req, fieldData(map[string]interface{}{"name": "aidx"})); err != nil {
+		t.Fatal(err)
+	}
+	for _, addr := range []string{created, rotated} {
+		if owner, err := addressindex.ReadAddressOwner(ctx, s, addr); err != nil || owner != nil {
+			t.Fatalf("owner of %s=%+v err=%v want removed by purge.", addr, owner, err)
+		}
+	}
+}
+
+// TestSingleKeyJournal_recordsSigningOperations verifies sign-tx, sign and decrypt each append a chained journal
+// record that can be listed, read and verified, while encrypt is not journaled.
+func TestSingleKeyJournal_recordsSigningOperations(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	acct, cleanup := mustPutSingleKeyAccount(ctx, t, s, "ajournal")
+	t.Cleanup(cleanup)
+	req := &logical.Request{Storage: s, EntityID: "entity-1"}
+
+	txResp, err := handleSingleKeySignTxEIP1559(ctx, req, fieldData(map[string]interface{}{
+		"name":                     "ajournal",
+		"chain_id":                 "1",
+		"to":                       "0x0000000000000000000000000000000000000001",
+		"max_fee_per_gas":          "2",
+		"max_priority_fee_per_gas": "1",
+	}))
+	if err != nil || txResp == nil || txResp.IsError() {
+		t.Fatalf("resp=%v err=%v want signed tx.", txResp, err)
+	}
+	if _, err := handleSingleKeySign(ctx, req, fieldData(map[string]interface{}{"name": "ajournal", "data": "0x01"})); err != nil {
+		t.Fatal(err)
+	}
+	encResp, err := handleSingleKeyEncrypt(ctx, req, fieldData(map[string]interface{}{"name": "ajournal", "data": "0x02"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := handleSingleKeyDecrypt(ctx, req, fieldData(map[string]interface{}{
+		"name": "ajournal",
+		"data": encResp.Data["ciphertext"],
+	})); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := pathSingleKeyJournal().Callbacks[logical.ListOperation](ctx, req, &framework.FieldData{
+		Raw:    map[string]interface{}{"name": "ajournal"},
+		Schema: pathSingleKeyJournal().Fields,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keys, _ := resp.Data["keys"].([]string); len(keys) != 3 {
+		t.Fatalf("keys=%v want 3 records.", resp.Data["keys"])
+	}
+
+	resp, err = pathSingleKeyJournalRecord().Callbacks[logical.ReadOperation](ctx, req, &framework.FieldData{
+		Raw:    map[string]interface{}{"name": "ajournal", "seq": "1"},
+		Schema: pathSingleKeyJournalRecord().Fields,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Data["operation"] != "sign-tx/eip1559" || resp.Data["tx_hash"] != txResp.Data["transaction_hash"] ||
+		common.HexToAddress(resp.Data["address"].(string)) != common.HexToAddress(acct.AddressStr) || resp.Data["entity_id"] != "entity-1" {
+		t.Fatalf("record=%v want sign-tx/eip1559 by entity-1 with the tx hash.", resp.Data)
+	}
+
+	resp, err = pathSingleKeyJournalVerify().Callbacks[logical.ReadOperation](ctx, req, &framework.FieldData{
+		Raw:    map[string]interface{}{"name": "ajournal"},
+		Schema: pathSingleKeyJournalVerify().Fields,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Data["valid"] != true || resp.Data["records"] != 3 {
+		t.Fatalf("verify=%v want 3 valid records.", resp.Data)
+	}
+}
+
+// TestSingleKeyJournal_recordsMessageAndAuthorization verifies sign-message and sign-authorization each append a
+// record with their own operation to the account's journal.
+func TestSingleKeyJournal_recordsMessageAndAuthorization(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	_, cleanup := mustPutSingleKeyAccount(ctx, t, s, "ajmsg")
+	t.Cleanup(cleanup)
+	req := &logical.Request{Storage: s}
+	var accountMu sync.Map
+
+	p := pathSingleKeySignMessage(&accountMu)
+	if resp, err := p.Callbacks[logical.UpdateOperation](ctx, req, &framework.FieldData{
+		Raw:    map[string]interface{}{"name": "ajmsg", "data": "0x0102"},
+		Schema: p.Fields,
+	}); err != nil || resp == nil || resp.IsError() {
+		t.Fatalf("resp=%v err=%v want signature.", resp, err)
+	}
+	p = pathSingleKeySignAuthorization(&accountMu)
+	authResp, err := p.Callbacks[logical.UpdateOperation](ctx, req, &framework.FieldData{
+		Raw: map[string]interface{}{
+			"name":     "ajmsg",
+			"chain_id": "1",
+			"address":  "0x0000000000000000000000000000000000000007",
+			"nonce":    "0",
+		},
+		Schema: p.Fields,
+	})
+	if err != nil || authResp == nil || authResp.IsError() {
+		t.Fatalf("resp=%v err=%v want authorization.", authResp, err)
+	}
+
+	records, _, _, err := journal.Verify(ctx, s, storagekey.SingleKeyAccountJournalPrefix("ajmsg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("records=%v want 2.", records)
+	}
+	if records[0].Operation != model.JournalOpSignMessage || records[0].PayloadDigest != model.JournalPayloadDigest([]byte{1, 2}) {
+		t.Fatalf("record=%+v want sign-message of the data.", records[0])
+	}
+	encoded, _ := authResp.Data["authorization"].(string)
+	if records[1].Operation != model.JournalOpSignAuthorization || records[1].PrevHash != records[0].Hash ||
+		records[1].PayloadDigest != model.JournalPayloadDigest([]byte(encoded)) {
+		t.Fatalf("record=%+v want sign-authorization linked to record 1.", records[1])
+	}
+}
+
+func TestSingleKeyNonce_explicitNonceAdvancesTracker(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	_, cleanup := mustPutSingleKeyAccount(ctx, t, s, "anonce")
+	t.Cleanup(cleanup)
+	req := &logical.Request{Storage: s}
+
+	sign := func(nonce string) (*logical.Response, error) {
+		return handleSingleKeySignTxEIP1559(ctx, req, fieldData(map[string]interface{}{
+			"name":                     "anonce",
+			"chain_id":                 "1",
+			"nonce":                    nonce,
+			"to":                       "0x0000000000000000000000000000000000000001",
+			"max_fee_per_gas":          "2",
+			"max_priority_fee_per_gas": "1",
+		}))
+	}
+	if resp, err := sign("9"); err != nil || resp.IsError() || resp.Data["nonce"] != uint64(9) {
+		t.Fatalf("resp=%v err=%v want nonce 9.", resp, err)
+	}
+	if resp, err := sign(model.NonceAuto); err != nil || resp.IsError() || resp.Data["nonce"] != uint64(10) {
+		t.Fatalf("resp=%v err=%v want auto nonce 10 after explicit 9.", resp, err)
+	}
+
+	p := pathSingleKeyNonce(new(sync.Map))
+	resp, err := p.Callbacks[logical.ReadOperation](ctx, req, &framework.FieldData{
+		Raw:    map[string]interface{}{"name": "anonce", "chain_id": "1"},
+		Schema: p.Fields,
+	})
+	if err != nil || resp == nil || resp.Data["next"] != uint64(11) {
+		t.Fatalf("resp=%v err=%v want next 11.", resp, err)
+	}
+	resp, err = p.Callbacks[logical.ReadOperation](ctx, req, &framework.FieldData{
+		Raw:    map[string]interface{}{"name": "anonce", "chain_id": "5"},
+		Schema: p.Fields,
+	})
+	if err != nil || resp != nil {
+		t.Fatalf("resp=%v err=%v want no tracker on chain 5.", resp, err)
+	}
+}
+
+func TestSingleKeySignTxReplace_legacy(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	_, cleanup := mustPutSingleKeyAccount(ctx, t, s, "areplace")
+	t.Cleanup(cleanup)
+	req := &logical.Request{Storage: s}
+
+	orig, err := handleSingleKeySignTxType0(ctx, req, fieldData(map[string]interface{}{
+		"name":      "areplace",
+		"chain_id":  "5",
+		"nonce":     "2",
+		"to":        "0x0000000000000000000000000000000000000001",
+		"gas_price": "1000",
+	}))
+	if err != nil || orig.IsError() {
+		t.Fatalf("resp=%v err=%v want signed tx.", orig, err)
+	}
+	p := pathSingleKeySignTxReplace(new(sync.Map))
+	replace := func(raw map[string]interface{}) *logical.Response {
+		t.Helper()
+		raw["name"] = "areplace"
+		resp, err := p.Callbacks[logical.UpdateOperation](ctx, req, &framework.FieldData{Raw: raw, Schema: p.Fields})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+	if resp := replace(map[string]interface{}{"transaction_hash": orig.Data["transaction_hash"], "fee_bump_percent": "5"}); resp == nil || !resp.IsError() {
+		t.Fatalf("resp=%v want error for a bump below 10%%.", resp)
+	}
+	if resp := replace(map[string]interface{}{}); resp == nil || !resp.IsError() {
+		t.Fatalf("resp=%v want error without transaction_hash or signed_transaction.", resp)
+	}
+	resp := replace(map[string]interface{}{"transaction_hash": orig.Data["transaction_hash"], "fee_bump_percent": "50"})
+	if resp == nil || resp.IsError() {
+		t.Fatalf("resp=%v want replacement.", resp)
+	}
+	if resp.Data["type"] != "legacy" || resp.Data["nonce"] != uint64(2) || resp.Data["gas_price"] != "1500" ||
+		resp.Data["address_to"] != orig.Data["address_to"] {
+		t.Fatalf("data=%v want legacy nonce 2 with gas price 1500.", resp.Data)
 	}
 }
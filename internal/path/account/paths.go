@@ -5,34 +5,165 @@ import (
 	"context"
 	"crypto/ecdsa"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math/big"
 	"strings"
+	"sync"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
-	"github.com/ethereum/go-ethereum/crypto/ecies"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/signer/core/apitypes"
 	"github.com/hashicorp/vault/sdk/framework"
 	"github.com/hashicorp/vault/sdk/logical"
 
 	"github.com/bsostech/vault-blockchain/internal/ethutil"
 	"github.com/bsostech/vault-blockchain/internal/model"
+	"github.com/bsostech/vault-blockchain/internal/path/chain"
+	"github.com/bsostech/vault-blockchain/internal/path/config"
+	"github.com/bsostech/vault-blockchain/internal/path/journal"
+	"github.com/bsostech/vault-blockchain/internal/path/nonces"
+	"github.com/bsostech/vault-blockchain/internal/path/signedtxs"
+	"github.com/bsostech/vault-blockchain/internal/path/storagekey"
 	"github.com/bsostech/vault-blockchain/pkg/utils"
 )
 
-// Paths returns all single-key account paths.
-func Paths() []*framework.Path {
+// Paths returns all single-key account paths. accountMu holds the per-account locks for keyring updates.
+func Paths(accountMu *sync.Map) []*framework.Path {
 	return []*framework.Path{
 		pathListSingleKeyAccounts(),
+		pathSingleKeyAccount(accountMu),
+		pathSingleKeyAccountRestore(accountMu),
+		pathSingleKeyAccountPurge(accountMu),
+		pathSingleKeyAccountRotate(accountMu),
+		pathSingleKeyAccountKeys(accountMu),
 		pathSingleKeyAccountAddress(),
 		pathSingleKeyAccountImport(),
-		pathSingleKeySign(),
-		pathSingleKeySignTxLegacy(),
-		pathSingleKeySignTxEIP1559(),
-		pathSingleKeySignEIP712(),
+		pathSingleKeyAccountExport(),
+		pathSingleKeyPolicy(),
+		pathSingleKeySign(accountMu),
+		pathSingleKeySignMessage(accountMu),
+		pathSingleKeySignTxLegacy(accountMu),
+		pathSingleKeySignTxEIP2930(accountMu),
+		pathSingleKeySignTxEIP1559(accountMu),
+		pathSingleKeySignTxBlob(accountMu),
+		pathSingleKeySignTxEIP7702(accountMu),
+		pathSingleKeySignTxReplace(accountMu),
+		pathSingleKeySignTxCancel(accountMu),
+		pathSingleKeySignEIP712(accountMu),
+		pathSingleKeySignAuthorization(accountMu),
 		pathSingleKeyEncrypt(),
-		pathSingleKeyDecrypt(),
+		pathSingleKeyDecrypt(accountMu),
+		pathSingleKeyJournal(),
+		pathSingleKeyJournalVerify(),
+		pathSingleKeyJournalRecord(),
+		pathSingleKeyNonce(accountMu),
+		pathSingleKeyNonceReserve(accountMu),
+		pathSingleKeyNonceRelease(accountMu),
+	}
+}
+
+// pathSingleKeyAccount registers read (status) and soft-delete on accounts/:name.
+func pathSingleKeyAccount(accountMu *sync.Map) *framework.Path {
+	return &framework.Path{
+		Pattern:      "accounts/" + framework.GenericNameRegex("name"),
+		HelpSynopsis: "Read a single-key account's status, or soft-delete it (restorable within config deletion_retention).",
+		Fields: map[string]*framework.FieldSchema{
+			"name": {
+				Type:        framework.TypeString,
+				Description: "Logical account name in the path.",
+			},
+		},
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.ReadOperation:   handleSingleKeyAccountStatus,
+			logical.DeleteOperation: withAccountLock(accountMu, handleSingleKeyAccountDelete),
+		},
+	}
+}
+
+// pathSingleKeyAccountRestore registers POST on accounts/:name/restore to undo a soft delete.
+func pathSingleKeyAccountRestore(accountMu *sync.Map) *framework.Path {
+	return &framework.Path{
+		Pattern:      "accounts/" + framework.GenericNameRegex("name") + "/restore",
+		HelpSynopsis: "Restore a soft-deleted single-key account within the deletion retention window.",
+		Fields: map[string]*framework.FieldSchema{
+			"name": {
+				Type:        framework.TypeString,
+				Description: "Logical account name in the path.",
+			},
+		},
+		ExistenceCheck: ExistenceSingleKeyAccount(),
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.CreateOperation: withAccountLock(accountMu, handleSingleKeyAccountRestore),
+			logical.UpdateOperation: withAccountLock(accountMu, handleSingleKeyAccountRestore),
+		},
+	}
+}
+
+// pathSingleKeyAccountPurge registers POST on accounts/:name/purge to permanently remove a soft-deleted account.
+func pathSingleKeyAccountPurge(accountMu *sync.Map) *framework.Path {
+	return &framework.Path{
+		Pattern:      "accounts/" + framework.GenericNameRegex("name") + "/purge",
+		HelpSynopsis: "Permanently remove a soft-deleted single-key account and its policies.",
+		Fields: map[string]*framework.FieldSchema{
+			"name": {
+				Type:        framework.TypeString,
+				Description: "Logical account name in the path.",
+			},
+		},
+		ExistenceCheck: ExistenceSingleKeyAccount(),
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.CreateOperation: withAccountLock(accountMu, handleSingleKeyAccountPurge),
+			logical.UpdateOperation: withAccountLock(accountMu, handleSingleKeyAccountPurge),
+		},
+	}
+}
+
+// pathSingleKeyAccountRotate registers POST on accounts/:name/rotate to generate a new key version.
+func pathSingleKeyAccountRotate(accountMu *sync.Map) *framework.Path {
+	return &framework.Path{
+		Pattern:      "accounts/" + framework.GenericNameRegex("name") + "/rotate",
+		HelpSynopsis: "Rotate a single-key account to a new key version; older versions keep their signing and decryption bounds.",
+		Fields: map[string]*framework.FieldSchema{
+			"name": {
+				Type:        framework.TypeString,
+				Description: "Logical account name in the path.",
+			},
+		},
+		ExistenceCheck: ExistenceSingleKeyAccount(),
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.CreateOperation: withAccountLock(accountMu, handleSingleKeyAccountRotate),
+			logical.UpdateOperation: withAccountLock(accountMu, handleSingleKeyAccountRotate),
+		},
+	}
+}
+
+// pathSingleKeyAccountKeys registers read/update on accounts/:name/keys for the key version history.
+func pathSingleKeyAccountKeys(accountMu *sync.Map) *framework.Path {
+	return &framework.Path{
+		Pattern:      "accounts/" + framework.GenericNameRegex("name") + "/keys",
+		HelpSynopsis: "Read a single-key account's key versions, or set the minimum versions allowed to sign and decrypt.",
+		Fields: map[string]*framework.FieldSchema{
+			"name": {
+				Type:        framework.TypeString,
+				Description: "Logical account name in the path.",
+			},
+			"min_signing_version": {
+				Type:        framework.TypeInt,
+				Description: "Oldest key version allowed to sign, between min_decryption_version and latest_version.",
+			},
+			"min_decryption_version": {
+				Type:        framework.TypeInt,
+				Description: "Oldest key version allowed to decrypt, between 1 and min_signing_version.",
+			},
+		},
+		ExistenceCheck: ExistenceSingleKeyAccount(),
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.ReadOperation:   handleSingleKeyAccountKeysRead,
+			logical.CreateOperation: withAccountLock(accountMu, handleSingleKeyAccountKeysWrite),
+			logical.UpdateOperation: withAccountLock(accountMu, handleSingleKeyAccountKeysWrite),
+		},
 	}
 }
 
@@ -60,7 +191,7 @@ func pathSingleKeyAccountAddress() *framework.Path {
 func pathSingleKeyAccountImport() *framework.Path {
 	return &framework.Path{
 		Pattern:      "accounts/" + framework.GenericNameRegex("name") + "/import",
-		HelpSynopsis: "Import an existing single-key Ethereum account private key.",
+		HelpSynopsis: "Import an existing single-key Ethereum account from a private key or keystore v3 JSON.",
 		Fields: map[string]*framework.FieldSchema{
 			"name": {
 				Type:        framework.TypeString,
@@ -68,8 +199,15 @@ func pathSingleKeyAccountImport() *framework.Path {
 			},
 			"private_key": {
 				Type:        framework.TypeString,
-				Required:    true,
-				Description: "ECDSA private key as hex string. Accepts optional 0x prefix.",
+				Description: "ECDSA private key as hex string. Accepts optional 0x prefix. Mutually exclusive with keystore.",
+			},
+			"keystore": {
+				Type:        framework.TypeString,
+				Description: "Web3 Secret Storage (keystore v3) JSON, scrypt or pbkdf2, as written by Geth and Clef.",
+			},
+			"password": {
+				Type:        framework.TypeString,
+				Description: "Password decrypting keystore.",
 			},
 		},
 		ExistenceCheck: ExistenceSingleKeyAccountSeed(),
@@ -80,6 +218,62 @@ func pathSingleKeyAccountImport() *framework.Path {
 	}
 }
 
+// pathSingleKeyAccountExport registers POST on accounts/:name/export to export the signing key as keystore v3 JSON.
+func pathSingleKeyAccountExport() *framework.Path {
+	return &framework.Path{
+		Pattern:      "accounts/" + framework.GenericNameRegex("name") + "/export",
+		HelpSynopsis: "Export a single-key account's signing key as keystore v3 JSON (requires config allow_key_export).",
+		Fields: map[string]*framework.FieldSchema{
+			"name": {
+				Type:        framework.TypeString,
+				Description: "Logical account name in the path.",
+			},
+			"password": {
+				Type:        framework.TypeString,
+				Description: "Password to encrypt the keystore with.",
+			},
+			"light_kdf": {
+				Type:        framework.TypeBool,
+				Description: "Use Geth's --lightkdf scrypt parameters (faster, weaker) instead of the standard ones.",
+			},
+		},
+		ExistenceCheck: ExistenceSingleKeyAccount(),
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.CreateOperation: handleSingleKeyAccountExport,
+			logical.UpdateOperation: handleSingleKeyAccountExport,
+		},
+	}
+}
+
+// pathSingleKeyPolicy registers read/write/delete on accounts/:name/policy.
+func pathSingleKeyPolicy() *framework.Path {
+	return &framework.Path{
+		Pattern:      "accounts/" + framework.GenericNameRegex("name") + "/policy",
+		HelpSynopsis: "Restrict which to addresses and contract selectors a single-key account may sign transactions for.",
+		Fields: map[string]*framework.FieldSchema{
+			"name": {
+				Type:        framework.TypeString,
+				Description: "Logical account name in the path.",
+			},
+			"allow": {
+				Type:        framework.TypeCommaStringSlice,
+				Description: "Allowed destinations as <address>:<selector> rules; either part may be *. Empty allows any destination not denied.",
+			},
+			"deny": {
+				Type:        framework.TypeCommaStringSlice,
+				Description: "Denied destinations as <address>:<selector> rules; deny wins over allow.",
+			},
+		},
+		ExistenceCheck: existenceSingleKeyPolicy,
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.ReadOperation:   handleSingleKeyPolicyRead,
+			logical.CreateOperation: handleSingleKeyPolicyWrite,
+			logical.UpdateOperation: handleSingleKeyPolicyWrite,
+			logical.DeleteOperation: handleSingleKeyPolicyDelete,
+		},
+	}
+}
+
 // pathListSingleKeyAccounts registers LIST on accounts/ for stored account names.
 func pathListSingleKeyAccounts() *framework.Path {
 	return &framework.Path{
@@ -94,12 +288,13 @@ func pathListSingleKeyAccounts() *framework.Path {
 }
 
 // pathSingleKeySign registers Keccak256-then-ECDSA sign on accounts/:name/sign.
-func pathSingleKeySign() *framework.Path {
+func pathSingleKeySign(accountMu *sync.Map) *framework.Path {
 	return &framework.Path{
 		Pattern:      "accounts/" + framework.GenericNameRegex("name") + "/sign",
 		HelpSynopsis: "Sign data (Keccak-256 hash then ECDSA) for a single-key account.",
 		Fields: map[string]*framework.FieldSchema{
-			"name": {Type: framework.TypeString},
+			"name":        {Type: framework.TypeString},
+			"key_version": keyVersionField(),
 			"data": {
 				Type:        framework.TypeString,
 				Description: "The data to hash (keccak) and sign.",
@@ -107,20 +302,43 @@ func pathSingleKeySign() *framework.Path {
 		},
 		ExistenceCheck: ExistenceSingleKeyAccount(),
 		Callbacks: map[logical.Operation]framework.OperationFunc{
-			logical.CreateOperation: handleSingleKeySign,
-			logical.UpdateOperation: handleSingleKeySign,
+			logical.CreateOperation: withAccountLock(accountMu, handleSingleKeySign),
+			logical.UpdateOperation: withAccountLock(accountMu, handleSingleKeySign),
 		},
 	}
 }
 
+// keyVersionField returns the schema of key_version, which selects the key version a single-key signing path
+// signs with.
+func keyVersionField() *framework.FieldSchema {
+	return &framework.FieldSchema{
+		Type:        framework.TypeInt,
+		Description: "Key version to sign with, between min_signing_version and latest_version. Defaults to the latest.",
+	}
+}
+
+// keyVersionFromRequest returns the requested key_version, or 0 (the latest version) when unset.
+func keyVersionFromRequest(wrapper *model.FieldDataWrapper) int {
+	raw, _ := wrapper.GetOk("key_version")
+	version, _ := raw.(int)
+	return version
+}
+
 // handleSingleKeySign signs hex-encoded payload data for the named single-key account.
 func handleSingleKeySign(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
 	wrapper := model.NewFieldDataWrapper(data)
+	cfg, err := config.Read(ctx, req.Storage)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.CheckSigning(model.SigningModeSign, nil); err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
 	name, err := wrapper.MustGetString("name")
 	if err != nil {
 		return nil, err
 	}
-	acct, err := ReadSingleKeyAccount(ctx, req.Storage, name)
+	acct, err := ReadSingleKeySigningAccount(ctx, req.Storage, name, keyVersionFromRequest(wrapper))
 	if err != nil {
 		return RespondLoadSingleKeyAccountError(err)
 	}
@@ -142,12 +360,121 @@ func handleSingleKeySign(ctx context.Context, req *logical.Request, data *framew
 	if err != nil {
 		return nil, fmt.Errorf("sign hash: %w", err)
 	}
-	return &logical.Response{
+	resp := &logical.Response{
 		Data: map[string]interface{}{
 			"signature": hexutil.Encode(signature),
 			"address":   acct.AddressStr,
 		},
-	}, nil
+	}
+	prefix := storagekey.SingleKeyAccountJournalPrefix(name)
+	return journal.Record(ctx, req, prefix, model.JournalOpSign, acct.AddressStr, dataBytes, resp, nil)
+}
+
+// pathSingleKeySignMessage registers EIP-191 message signing on accounts/:name/sign-message.
+func pathSingleKeySignMessage(accountMu *sync.Map) *framework.Path {
+	return &framework.Path{
+		Pattern:      "accounts/" + framework.GenericNameRegex("name") + "/sign-message",
+		HelpSynopsis: "Sign a message under EIP-191 (personal_sign or intended validator) for a single-key account.",
+		Fields: map[string]*framework.FieldSchema{
+			"name":        {Type: framework.TypeString},
+			"key_version": keyVersionField(),
+			"message": {
+				Type:        framework.TypeString,
+				Description: "UTF-8 message to sign. Mutually exclusive with data.",
+			},
+			"data": {
+				Type:        framework.TypeString,
+				Description: "Hex-encoded message bytes to sign. Mutually exclusive with message.",
+			},
+			"version": {
+				Type:        framework.TypeString,
+				Description: "EIP-191 version: 0x45 (personal_sign) or 0x00 (intended validator).",
+				Default:     "0x45",
+			},
+			"validator": {
+				Type:        framework.TypeString,
+				Description: "Intended validator hex address. Required for version 0x00.",
+			},
+		},
+		ExistenceCheck: ExistenceSingleKeyAccount(),
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.CreateOperation: withAccountLock(accountMu, handleSingleKeySignMessage),
+			logical.UpdateOperation: withAccountLock(accountMu, handleSingleKeySignMessage),
+		},
+	}
+}
+
+// handleSingleKeySignMessage signs an EIP-191 message with the account key; v is returned as 27/28.
+func handleSingleKeySignMessage(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	wrapper := model.NewFieldDataWrapper(data)
+	cfg, err := config.Read(ctx, req.Storage)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.CheckSigning(model.SigningModeSignMessage, nil); err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	name, err := wrapper.MustGetString("name")
+	if err != nil {
+		return nil, err
+	}
+	version, validator, msg, err := eip191MessageFromRequestSingleKey(wrapper)
+	if err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	acct, err := ReadSingleKeySigningAccount(ctx, req.Storage, name, keyVersionFromRequest(wrapper))
+	if err != nil {
+		return RespondLoadSingleKeyAccountError(err)
+	}
+	pk, err := acct.GetPrivateKeyECDSA()
+	if err != nil {
+		return nil, fmt.Errorf("single-key sign-message: %w", err)
+	}
+	defer utils.ZeroKey(pk)
+	hash, sig, err := ethutil.SignEIP191(version, validator, msg, pk)
+	if err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	resp := &logical.Response{
+		Data: map[string]interface{}{
+			"signature":    hexutil.Encode(sig),
+			"address":      acct.AddressStr,
+			"message_hash": hash.Hex(),
+			"version":      fmt.Sprintf("0x%02x", version),
+		},
+	}
+	prefix := storagekey.SingleKeyAccountJournalPrefix(name)
+	return journal.Record(ctx, req, prefix, model.JournalOpSignMessage, acct.AddressStr, msg, resp, nil)
+}
+
+// eip191MessageFromRequestSingleKey resolves the EIP-191 version, validator and message bytes from request fields.
+func eip191MessageFromRequestSingleKey(wrapper *model.FieldDataWrapper) (byte, *common.Address, []byte, error) {
+	version, err := ethutil.ParseEIP191Version(wrapper.GetString("version", ""))
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	text := wrapper.GetString("message", "")
+	hexStr := strings.TrimSpace(wrapper.GetString("data", ""))
+	if text != "" && hexStr != "" {
+		return 0, nil, nil, fmt.Errorf("set only one of message or data")
+	}
+	msg := []byte(text)
+	if hexStr != "" {
+		msg, err = hexutil.Decode(hexStr)
+		if err != nil {
+			return 0, nil, nil, fmt.Errorf("invalid data hex: %w", err)
+		}
+	}
+	var validator *common.Address
+	if version == ethutil.EIP191VersionIntendedValidator {
+		validatorStr := strings.TrimSpace(wrapper.GetString("validator", ""))
+		if !common.IsHexAddress(validatorStr) {
+			return 0, nil, nil, fmt.Errorf("version 0x00 requires validator to be a hex address")
+		}
+		addr := common.HexToAddress(validatorStr)
+		validator = &addr
+	}
+	return version, validator, msg, nil
 }
 
 // pathSingleKeyEncrypt registers ECIES encrypt on accounts/:name/encrypt.
@@ -170,17 +497,21 @@ func pathSingleKeyEncrypt() *framework.Path {
 	}
 }
 
-// handleSingleKeyEncrypt encrypts hex plaintext to the account's ECIES public key.
+// handleSingleKeyEncrypt encrypts hex plaintext to the ECIES public key of the account's latest key version.
 func handleSingleKeyEncrypt(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
 	dataWrapper := model.NewFieldDataWrapper(data)
 	name, err := dataWrapper.MustGetString("name")
 	if err != nil {
 		return nil, err
 	}
-	acct, err := ReadSingleKeyAccount(ctx, req.Storage, name)
+	keyring, err := ReadSingleKeyAccountKeyring(ctx, req.Storage, name)
 	if err != nil {
 		return RespondLoadSingleKeyAccountError(err)
 	}
+	acct, err := keyring.SigningAccount()
+	if err != nil {
+		return nil, err
+	}
 
 	dataToEncrypt, err := dataWrapper.MustGetString("data")
 	if err != nil {
@@ -200,13 +531,14 @@ func handleSingleKeyEncrypt(ctx context.Context, req *logical.Request, data *fra
 	}
 	return &logical.Response{
 		Data: map[string]interface{}{
-			"ciphertext": hexutil.Encode(cipherText),
+			"ciphertext":  hexutil.Encode(cipherText),
+			"key_version": keyring.LatestVersion,
 		},
 	}, nil
 }
 
 // pathSingleKeyDecrypt registers ECIES decrypt on accounts/:name/decrypt.
-func pathSingleKeyDecrypt() *framework.Path {
+func pathSingleKeyDecrypt(accountMu *sync.Map) *framework.Path {
 	return &framework.Path{
 		Pattern:      "accounts/" + framework.GenericNameRegex("name") + "/decrypt",
 		HelpSynopsis: "Decrypt data with the account private key (ECIES).",
@@ -219,20 +551,21 @@ func pathSingleKeyDecrypt() *framework.Path {
 		},
 		ExistenceCheck: ExistenceSingleKeyAccount(),
 		Callbacks: map[logical.Operation]framework.OperationFunc{
-			logical.CreateOperation: handleSingleKeyDecrypt,
-			logical.UpdateOperation: handleSingleKeyDecrypt,
+			logical.CreateOperation: withAccountLock(accountMu, handleSingleKeyDecrypt),
+			logical.UpdateOperation: withAccountLock(accountMu, handleSingleKeyDecrypt),
 		},
 	}
 }
 
-// handleSingleKeyDecrypt decrypts hex ciphertext with the account's ECIES private key.
+// handleSingleKeyDecrypt decrypts hex ciphertext with the account's ECIES private keys, trying every version
+// from the latest down to min_decryption_version so ciphertexts made before a rotation still open.
 func handleSingleKeyDecrypt(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
 	dataWrapper := model.NewFieldDataWrapper(data)
 	name, err := dataWrapper.MustGetString("name")
 	if err != nil {
 		return nil, err
 	}
-	acct, err := ReadSingleKeyAccount(ctx, req.Storage, name)
+	keyring, err := ReadSingleKeyAccountKeyring(ctx, req.Storage, name)
 	if err != nil {
 		return RespondLoadSingleKeyAccountError(err)
 	}
@@ -245,30 +578,31 @@ func handleSingleKeyDecrypt(ctx context.Context, req *logical.Request, data *fra
 	if err != nil {
 		return nil, fmt.Errorf("decode ciphertext hex: %w", err)
 	}
-	ecdsaKey, err := acct.GetPrivateKeyECDSA()
-	if err != nil {
-		return nil, fmt.Errorf("ecdsa private key: %w", err)
-	}
-	defer utils.ZeroKey(ecdsaKey)
-	privateKeyECIES := ecies.ImportECDSA(ecdsaKey)
-	plainText, err := ethutil.DecryptECIES(privateKeyECIES, dataBytes)
+	plainText, version, err := keyring.Decrypt(dataBytes)
 	if err != nil {
-		return nil, fmt.Errorf("ecies decrypt: %w", err)
+		if errors.Is(err, model.ErrNoDecryptionVersion) {
+			return logical.ErrorResponse("%s", err.Error()), nil
+		}
+		return nil, err
 	}
-	return &logical.Response{
+	resp := &logical.Response{
 		Data: map[string]interface{}{
-			"plaintext": hexutil.Encode(plainText),
+			"plaintext":   hexutil.Encode(plainText),
+			"key_version": version,
 		},
-	}, nil
+	}
+	prefix := storagekey.SingleKeyAccountJournalPrefix(name)
+	return journal.Record(ctx, req, prefix, model.JournalOpDecrypt, keyring.Keys[version].AddressStr, dataBytes, resp, nil)
 }
 
 // pathSingleKeySignEIP712 registers EIP-712 typed-data signing on accounts/:name/sign-eip712.
-func pathSingleKeySignEIP712() *framework.Path {
+func pathSingleKeySignEIP712(accountMu *sync.Map) *framework.Path {
 	return &framework.Path{
 		Pattern:      "accounts/" + framework.GenericNameRegex("name") + "/sign-eip712",
 		HelpSynopsis: "Sign EIP-712 typed data for a single-key account.",
 		Fields: map[string]*framework.FieldSchema{
-			"name": {Type: framework.TypeString},
+			"name":        {Type: framework.TypeString},
+			"key_version": keyVersionField(),
 			"payload": {
 				Type:        framework.TypeString,
 				Description: "The complete EIP-712 JSON payload (contains domain, types, primaryType, message).",
@@ -276,8 +610,8 @@ func pathSingleKeySignEIP712() *framework.Path {
 		},
 		ExistenceCheck: ExistenceSingleKeyAccount(),
 		Callbacks: map[logical.Operation]framework.OperationFunc{
-			logical.CreateOperation: handleSingleKeySignEIP712,
-			logical.UpdateOperation: handleSingleKeySignEIP712,
+			logical.CreateOperation: withAccountLock(accountMu, handleSingleKeySignEIP712),
+			logical.UpdateOperation: withAccountLock(accountMu, handleSingleKeySignEIP712),
 		},
 	}
 }
@@ -285,6 +619,13 @@ func pathSingleKeySignEIP712() *framework.Path {
 // handleSingleKeySignEIP712 parses a TypedData payload and returns an EIP-712 signature.
 func handleSingleKeySignEIP712(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
 	wrapper := model.NewFieldDataWrapper(data)
+	cfg, err := config.Read(ctx, req.Storage)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.CheckSigning(model.SigningModeSignEIP712, nil); err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
 	payload, err := wrapper.MustGetString("payload")
 	if err != nil {
 		return nil, err
@@ -297,7 +638,7 @@ func handleSingleKeySignEIP712(ctx context.Context, req *logical.Request, data *
 	if err != nil {
 		return nil, err
 	}
-	acct, err := ReadSingleKeyAccount(ctx, req.Storage, name)
+	acct, err := ReadSingleKeySigningAccount(ctx, req.Storage, name, keyVersionFromRequest(wrapper))
 	if err != nil {
 		return RespondLoadSingleKeyAccountError(err)
 	}
@@ -310,12 +651,108 @@ func handleSingleKeySignEIP712(ctx context.Context, req *logical.Request, data *
 	if err != nil {
 		return logical.ErrorResponse("%s", err.Error()), nil
 	}
-	return &logical.Response{
+	resp := &logical.Response{
 		Data: map[string]interface{}{
 			"signature": hexutil.Encode(sig),
 			"address":   acct.AddressStr,
 		},
-	}, nil
+	}
+	prefix := storagekey.SingleKeyAccountJournalPrefix(name)
+	return journal.Record(ctx, req, prefix, model.JournalOpSignEIP712, acct.AddressStr, []byte(payload), resp, nil)
+}
+
+// pathSingleKeySignAuthorization registers EIP-7702 authorization signing on accounts/:name/sign-authorization.
+func pathSingleKeySignAuthorization(accountMu *sync.Map) *framework.Path {
+	return &framework.Path{
+		Pattern:      "accounts/" + framework.GenericNameRegex("name") + "/sign-authorization",
+		HelpSynopsis: "Sign an EIP-7702 authorization tuple (chain_id, address, nonce) for a single-key account.",
+		Fields: map[string]*framework.FieldSchema{
+			"name":        {Type: framework.TypeString},
+			"key_version": keyVersionField(),
+			"chain_id": {
+				Type:        framework.TypeString,
+				Description: "Chain ID (decimal); 0 authorizes on every chain. Alias: chainID.",
+			},
+			"chainID": {
+				Type:        framework.TypeString,
+				Description: "Alias for chain_id.",
+			},
+			"address": {
+				Type:        framework.TypeString,
+				Description: "Hex address of the contract whose code is delegated to.",
+			},
+			"nonce": {
+				Type:        framework.TypeString,
+				Description: "Authority account nonce (decimal) at the time the authorization is applied.",
+			},
+		},
+		ExistenceCheck: ExistenceSingleKeyAccount(),
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.CreateOperation: withAccountLock(accountMu, handleSingleKeySignAuthorization),
+			logical.UpdateOperation: withAccountLock(accountMu, handleSingleKeySignAuthorization),
+		},
+	}
+}
+
+// handleSingleKeySignAuthorization signs an EIP-7702 authorization tuple with the account key.
+func handleSingleKeySignAuthorization(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	wrapper := model.NewFieldDataWrapper(data)
+	name, err := wrapper.MustGetString("name")
+	if err != nil {
+		return nil, err
+	}
+	chainID, err := wrapper.MustGetBigIntAny("chain_id", "chainID")
+	if err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	cfg, err := config.Read(ctx, req.Storage)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.CheckSigning(model.SigningModeSignAuthorization, chainID); err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	addrStr := strings.TrimSpace(wrapper.GetString("address", ""))
+	if !common.IsHexAddress(addrStr) {
+		return logical.ErrorResponse("address must be a hex address"), nil
+	}
+	if err := chain.CheckAuthorization(ctx, req.Storage, chainID, common.HexToAddress(addrStr)); err != nil {
+		return RespondLoadSingleKeyAccountError(err)
+	}
+	dest, err := ReadSingleKeyDestinationPolicy(ctx, req.Storage, name)
+	if err != nil {
+		return nil, err
+	}
+	if dest != nil {
+		if err := dest.CheckDelegate("account "+name, common.HexToAddress(addrStr)); err != nil {
+			return RespondLoadSingleKeyAccountError(err)
+		}
+	}
+	nonce, err := wrapper.MustGetUint64("nonce")
+	if err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	acct, err := ReadSingleKeySigningAccount(ctx, req.Storage, name, keyVersionFromRequest(wrapper))
+	if err != nil {
+		return RespondLoadSingleKeyAccountError(err)
+	}
+	pk, err := acct.GetPrivateKeyECDSA()
+	if err != nil {
+		return nil, fmt.Errorf("single-key authorization ecdsa key: %w", err)
+	}
+	defer utils.ZeroKey(pk)
+	auth, err := ethutil.SignSetCodeAuthorization(chainID, common.HexToAddress(addrStr), nonce, pk)
+	if err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	respData, err := ethutil.SetCodeAuthorizationResponseData(auth)
+	if err != nil {
+		return nil, err
+	}
+	encoded, _ := respData["authorization"].(string)
+	prefix := storagekey.SingleKeyAccountJournalPrefix(name)
+	return journal.Record(ctx, req, prefix, model.JournalOpSignAuthorization, acct.AddressStr, []byte(encoded),
+		&logical.Response{Data: respData}, nil)
 }
 
 // typedDataFromPayloadSingleKey parses a single JSON payload into go-ethereum TypedData.
@@ -352,21 +789,144 @@ func typedDataFromPayloadSingleKey(payload string) (*apitypes.TypedData, error)
 	return &td, nil
 }
 
-// patternSingleKeyAccountSignTxBase returns the path prefix for single-key sign-tx endpoints.
-func patternSingleKeyAccountSignTxBase() string {
-	return "accounts/" + framework.GenericNameRegex("name") + "/sign-tx"
+// pathSingleKeyJournal registers LIST on accounts/:name/journal for record sequence numbers.
+func pathSingleKeyJournal() *framework.Path {
+	return &framework.Path{
+		Pattern:      "accounts/" + framework.GenericNameRegex("name") + "/journal/?",
+		HelpSynopsis: "List the sequence numbers of a single-key account's signing journal.",
+		Fields: map[string]*framework.FieldSchema{
+			"name": {Type: framework.TypeString},
+		},
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.ListOperation: journal.HandleList(singleKeyJournalPrefix),
+		},
+	}
+}
+
+// pathSingleKeyJournalVerify registers read on accounts/:name/journal/verify.
+func pathSingleKeyJournalVerify() *framework.Path {
+	return &framework.Path{
+		Pattern:      "accounts/" + framework.GenericNameRegex("name") + "/journal/verify",
+		HelpSynopsis: "Check the hash chain of a single-key account's signing journal.",
+		Fields: map[string]*framework.FieldSchema{
+			"name": {Type: framework.TypeString},
+		},
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.ReadOperation: journal.HandleVerify(singleKeyJournalPrefix),
+		},
+	}
+}
+
+// pathSingleKeyJournalRecord registers read on accounts/:name/journal/:seq.
+func pathSingleKeyJournalRecord() *framework.Path {
+	return &framework.Path{
+		Pattern:      "accounts/" + framework.GenericNameRegex("name") + "/journal/" + journal.PatternSeq,
+		HelpSynopsis: "Read one record of a single-key account's signing journal.",
+		Fields: map[string]*framework.FieldSchema{
+			"name": {Type: framework.TypeString},
+			"seq":  journal.SeqField(),
+		},
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.ReadOperation: journal.HandleRead(singleKeyJournalPrefix),
+		},
+	}
+}
+
+// singleKeyJournalPrefix resolves the journal prefix of the account named in the request path.
+func singleKeyJournalPrefix(wrapper *model.FieldDataWrapper) (string, *logical.Response) {
+	name := wrapper.GetString("name", "")
+	if name == "" {
+		return "", logical.ErrorResponse("name is required")
+	}
+	return storagekey.SingleKeyAccountJournalPrefix(name), nil
+}
+
+// patternSingleKeyNonceBase returns the path prefix for a single-key account's nonce tracker on one chain.
+func patternSingleKeyNonceBase() string {
+	return "accounts/" + framework.GenericNameRegex("name") + "/nonces/" + nonces.PatternChainID
+}
+
+// singleKeyNonceFields returns field schemas shared by the single-key nonce paths.
+func singleKeyNonceFields() map[string]*framework.FieldSchema {
+	fields := nonces.Fields()
+	fields["name"] = &framework.FieldSchema{Type: framework.TypeString}
+	fields["key_version"] = keyVersionField()
+	return fields
+}
+
+// pathSingleKeyNonce registers read and set on accounts/:name/nonces/:chain_id.
+func pathSingleKeyNonce(accountMu *sync.Map) *framework.Path {
+	return &framework.Path{
+		Pattern:        patternSingleKeyNonceBase(),
+		HelpSynopsis:   "Read or set the nonce tracker used by sign-tx nonce \"auto\" for a single-key account on a chain.",
+		Fields:         singleKeyNonceFields(),
+		ExistenceCheck: ExistenceSingleKeyAccount(),
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.ReadOperation:   withAccountLock(accountMu, nonces.HandleRead(singleKeyNonceAddress)),
+			logical.CreateOperation: withAccountLock(accountMu, nonces.HandleWrite(singleKeyNonceAddress)),
+			logical.UpdateOperation: withAccountLock(accountMu, nonces.HandleWrite(singleKeyNonceAddress)),
+		},
+	}
+}
+
+// pathSingleKeyNonceReserve registers POST on accounts/:name/nonces/:chain_id/reserve.
+func pathSingleKeyNonceReserve(accountMu *sync.Map) *framework.Path {
+	return &framework.Path{
+		Pattern:        patternSingleKeyNonceBase() + "/reserve",
+		HelpSynopsis:   "Issue the next nonce of a single-key account on a chain without signing.",
+		Fields:         singleKeyNonceFields(),
+		ExistenceCheck: ExistenceSingleKeyAccount(),
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.CreateOperation: withAccountLock(accountMu, nonces.HandleReserve(singleKeyNonceAddress)),
+			logical.UpdateOperation: withAccountLock(accountMu, nonces.HandleReserve(singleKeyNonceAddress)),
+		},
+	}
+}
+
+// pathSingleKeyNonceRelease registers POST on accounts/:name/nonces/:chain_id/release.
+func pathSingleKeyNonceRelease(accountMu *sync.Map) *framework.Path {
+	return &framework.Path{
+		Pattern:        patternSingleKeyNonceBase() + "/release",
+		HelpSynopsis:   "Give an issued nonce of a single-key account back for reuse, e.g. after its transaction was dropped.",
+		Fields:         singleKeyNonceFields(),
+		ExistenceCheck: ExistenceSingleKeyAccount(),
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.CreateOperation: withAccountLock(accountMu, nonces.HandleRelease(singleKeyNonceAddress)),
+			logical.UpdateOperation: withAccountLock(accountMu, nonces.HandleRelease(singleKeyNonceAddress)),
+		},
+	}
+}
+
+// singleKeyNonceAddress resolves the signing address of the account named in the request path, for the key
+// version named by key_version or the latest.
+func singleKeyNonceAddress(ctx context.Context, s logical.Storage, wrapper *model.FieldDataWrapper) (string, *logical.Response, error) {
+	name := wrapper.GetString("name", "")
+	if name == "" {
+		return "", logical.ErrorResponse("name is required"), nil
+	}
+	acct, err := ReadSingleKeySigningAccount(ctx, s, name, keyVersionFromRequest(wrapper))
+	if err != nil {
+		resp, err := RespondLoadSingleKeyAccountError(err)
+		return "", resp, err
+	}
+	return acct.AddressStr, nil, nil
+}
+
+// patternSingleKeyAccountSignTxBase returns the path prefix for single-key sign-tx endpoints.
+func patternSingleKeyAccountSignTxBase() string {
+	return "accounts/" + framework.GenericNameRegex("name") + "/sign-tx"
 }
 
 // pathSingleKeySignTxLegacy registers EIP-155 legacy transaction signing on .../sign-tx/legacy.
-func pathSingleKeySignTxLegacy() *framework.Path {
+func pathSingleKeySignTxLegacy(accountMu *sync.Map) *framework.Path {
 	return &framework.Path{
 		Pattern:        patternSingleKeyAccountSignTxBase() + "/legacy",
 		HelpSynopsis:   "Sign an EIP-155 type-0 EVM transaction for a single-key account.",
 		Fields:         singleKeySignTxType0Fields(),
 		ExistenceCheck: ExistenceSingleKeyAccount(),
 		Callbacks: map[logical.Operation]framework.OperationFunc{
-			logical.CreateOperation: handleSingleKeySignTxType0,
-			logical.UpdateOperation: handleSingleKeySignTxType0,
+			logical.CreateOperation: withAccountLock(accountMu, handleSingleKeySignTxType0),
+			logical.UpdateOperation: withAccountLock(accountMu, handleSingleKeySignTxType0),
 		},
 	}
 }
@@ -374,14 +934,15 @@ func pathSingleKeySignTxLegacy() *framework.Path {
 // singleKeySignTxType0Fields returns field schemas for type-0 transaction requests.
 func singleKeySignTxType0Fields() map[string]*framework.FieldSchema {
 	return map[string]*framework.FieldSchema{
-		"name": {Type: framework.TypeString},
+		"name":        {Type: framework.TypeString},
+		"key_version": keyVersionField(),
 		"chain_id": {
 			Type:        framework.TypeString,
 			Description: "Chain ID (decimal). Alias: chainID.",
 		},
 		"nonce": {
 			Type:        framework.TypeString,
-			Description: "Transaction nonce (decimal).",
+			Description: "Transaction nonce (decimal), or \"auto\" for the next nonce from the nonce tracker.",
 		},
 		"to": {
 			Type:        framework.TypeString,
@@ -401,8 +962,7 @@ func singleKeySignTxType0Fields() map[string]*framework.FieldSchema {
 		},
 		"gas_limit": {
 			Type:        framework.TypeString,
-			Description: "Gas limit (decimal).",
-			Default:     "21000",
+			Description: "Gas limit (decimal). Default: config default_gas_limit (21000).",
 		},
 		"data": {
 			Type:        framework.TypeString,
@@ -427,21 +987,92 @@ func handleSingleKeySignTxType0(ctx context.Context, req *logical.Request, data
 	if err != nil {
 		return nil, err
 	}
-	signingKey, acct, cleanup, err := loadSingleKeySigningKeyForTx(ctx, req.Storage, name)
+	chainID, err := wrapper.MustGetBigIntAny("chain_id", "chainID")
+	if err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	cfg, err := config.Read(ctx, req.Storage)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.CheckSigning(model.SigningModeLegacy, chainID); err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	gasLimit := wrapper.GetUint64NonEmpty("gas_limit", cfg.DefaultGasLimit)
+	value := wrapper.BigIntWithAliases("value", "amount", big.NewInt(0))
+	inputStr := wrapper.GetString("data", "")
+	var txData []byte
+	if strings.TrimSpace(inputStr) != "" {
+		txData, err = hexutil.Decode(inputStr)
+		if err != nil {
+			return logical.ErrorResponse("invalid data hex: %s", err.Error()), nil
+		}
+	}
+	toStr := wrapper.GetStringFirstNonEmpty("to", "address_to")
+	var toPtr *common.Address
+	if toStr != "" {
+		addr := common.HexToAddress(toStr)
+		toPtr = &addr
+	}
+	policyIn := chain.TxPolicyInputFromRequest(wrapper, chainID, value, toPtr, txData, "gas_price")
+	signingKey, acct, cleanup, err := loadSingleKeySigningKeyForTx(ctx, req.Storage, name, keyVersionFromRequest(wrapper), policyIn)
 	if err != nil {
 		return RespondLoadSingleKeyAccountError(err)
 	}
 	defer cleanup()
+	nonce, errResp, err := nonces.FromRequest(ctx, req.Storage, wrapper, chainID, acct.AddressStr)
+	if errResp != nil || err != nil {
+		return errResp, err
+	}
+	resp, err := signType0TxSingleKey(wrapper, chainID, nonce, gasLimit, value, txData, toPtr, signingKey, acct)
+	return recordSingleKeySignedTx(ctx, req, name, chainID, resp, err)
+}
+
+const txTypeLabelEthereumType0 = "legacy"
+
+// pathSingleKeySignTxEIP2930 registers EIP-2930 transaction signing on .../sign-tx/eip2930.
+func pathSingleKeySignTxEIP2930(accountMu *sync.Map) *framework.Path {
+	return &framework.Path{
+		Pattern:        patternSingleKeyAccountSignTxBase() + "/eip2930",
+		HelpSynopsis:   "Sign an EIP-2930 (type-1) EVM transaction with an access list for a single-key account.",
+		Fields:         singleKeySignTxEIP2930Fields(),
+		ExistenceCheck: ExistenceSingleKeyAccount(),
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.CreateOperation: withAccountLock(accountMu, handleSingleKeySignTxEIP2930),
+			logical.UpdateOperation: withAccountLock(accountMu, handleSingleKeySignTxEIP2930),
+		},
+	}
+}
 
+// singleKeySignTxEIP2930Fields returns field schemas for EIP-2930 transaction requests.
+func singleKeySignTxEIP2930Fields() map[string]*framework.FieldSchema {
+	fields := singleKeySignTxType0Fields()
+	fields["access_list"] = &framework.FieldSchema{
+		Type:        framework.TypeString,
+		Description: "Optional EIP-2930 access list as JSON array.",
+	}
+	return fields
+}
+
+// handleSingleKeySignTxEIP2930 parses access-list tx fields and returns a signed type-1 transaction.
+func handleSingleKeySignTxEIP2930(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	wrapper := model.NewFieldDataWrapper(data)
+	name, err := wrapper.MustGetString("name")
+	if err != nil {
+		return nil, err
+	}
 	chainID, err := wrapper.MustGetBigIntAny("chain_id", "chainID")
 	if err != nil {
 		return logical.ErrorResponse("%s", err.Error()), nil
 	}
-	gasLimit, err := wrapper.MustGetUint64("gas_limit")
+	cfg, err := config.Read(ctx, req.Storage)
 	if err != nil {
 		return nil, err
 	}
-	nonce := wrapper.GetUint64("nonce", 0)
+	if err := cfg.CheckSigning(model.SigningModeEIP2930, chainID); err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	gasLimit := wrapper.GetUint64NonEmpty("gas_limit", cfg.DefaultGasLimit)
 	value := wrapper.BigIntWithAliases("value", "amount", big.NewInt(0))
 	inputStr := wrapper.GetString("data", "")
 	var txData []byte
@@ -457,21 +1088,30 @@ func handleSingleKeySignTxType0(ctx context.Context, req *logical.Request, data
 		addr := common.HexToAddress(toStr)
 		toPtr = &addr
 	}
-	return signType0TxSingleKey(wrapper, chainID, nonce, gasLimit, value, txData, toPtr, signingKey, acct)
+	policyIn := chain.TxPolicyInputFromRequest(wrapper, chainID, value, toPtr, txData, "gas_price")
+	signingKey, acct, cleanup, err := loadSingleKeySigningKeyForTx(ctx, req.Storage, name, keyVersionFromRequest(wrapper), policyIn)
+	if err != nil {
+		return RespondLoadSingleKeyAccountError(err)
+	}
+	defer cleanup()
+	nonce, errResp, err := nonces.FromRequest(ctx, req.Storage, wrapper, chainID, acct.AddressStr)
+	if errResp != nil || err != nil {
+		return errResp, err
+	}
+	resp, err := signEIP2930TxSingleKey(wrapper, chainID, nonce, gasLimit, value, txData, toPtr, signingKey, acct)
+	return recordSingleKeySignedTx(ctx, req, name, chainID, resp, err)
 }
 
-const txTypeLabelEthereumType0 = "legacy"
-
 // pathSingleKeySignTxEIP1559 registers EIP-1559 transaction signing on .../sign-tx/eip1559.
-func pathSingleKeySignTxEIP1559() *framework.Path {
+func pathSingleKeySignTxEIP1559(accountMu *sync.Map) *framework.Path {
 	return &framework.Path{
 		Pattern:        patternSingleKeyAccountSignTxBase() + "/eip1559",
 		HelpSynopsis:   "Sign an EIP-1559 (type-2) EVM transaction for a single-key account.",
 		Fields:         singleKeySignTxEIP1559Fields(),
 		ExistenceCheck: ExistenceSingleKeyAccount(),
 		Callbacks: map[logical.Operation]framework.OperationFunc{
-			logical.CreateOperation: handleSingleKeySignTxEIP1559,
-			logical.UpdateOperation: handleSingleKeySignTxEIP1559,
+			logical.CreateOperation: withAccountLock(accountMu, handleSingleKeySignTxEIP1559),
+			logical.UpdateOperation: withAccountLock(accountMu, handleSingleKeySignTxEIP1559),
 		},
 	}
 }
@@ -479,14 +1119,15 @@ func pathSingleKeySignTxEIP1559() *framework.Path {
 // singleKeySignTxEIP1559Fields returns field schemas for EIP-1559 transaction requests.
 func singleKeySignTxEIP1559Fields() map[string]*framework.FieldSchema {
 	return map[string]*framework.FieldSchema{
-		"name": {Type: framework.TypeString},
+		"name":        {Type: framework.TypeString},
+		"key_version": keyVersionField(),
 		"chain_id": {
 			Type:        framework.TypeString,
 			Description: "Chain ID (decimal). Alias: chainID.",
 		},
 		"nonce": {
 			Type:        framework.TypeString,
-			Description: "Transaction nonce (decimal).",
+			Description: "Transaction nonce (decimal), or \"auto\" for the next nonce from the nonce tracker.",
 		},
 		"to": {
 			Type:        framework.TypeString,
@@ -506,8 +1147,7 @@ func singleKeySignTxEIP1559Fields() map[string]*framework.FieldSchema {
 		},
 		"gas_limit": {
 			Type:        framework.TypeString,
-			Description: "Gas limit (decimal).",
-			Default:     "21000",
+			Description: "Gas limit (decimal). Default: config default_gas_limit (21000).",
 		},
 		"data": {
 			Type:        framework.TypeString,
@@ -547,21 +1187,179 @@ func handleSingleKeySignTxEIP1559(ctx context.Context, req *logical.Request, dat
 	if err != nil {
 		return nil, err
 	}
-	signingKey, acct, cleanup, err := loadSingleKeySigningKeyForTx(ctx, req.Storage, name)
+	chainID, err := wrapper.MustGetBigIntAny("chain_id", "chainID")
+	if err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	cfg, err := config.Read(ctx, req.Storage)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.CheckSigning(model.SigningModeEIP1559, chainID); err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	gasLimit := wrapper.GetUint64NonEmpty("gas_limit", cfg.DefaultGasLimit)
+	value := wrapper.BigIntWithAliases("value", "amount", big.NewInt(0))
+	inputStr := wrapper.GetString("data", "")
+	var txData []byte
+	if strings.TrimSpace(inputStr) != "" {
+		txData, err = hexutil.Decode(inputStr)
+		if err != nil {
+			return logical.ErrorResponse("invalid data hex: %s", err.Error()), nil
+		}
+	}
+	toStr := wrapper.GetStringFirstNonEmpty("to", "address_to")
+	var toPtr *common.Address
+	if toStr != "" {
+		addr := common.HexToAddress(toStr)
+		toPtr = &addr
+	}
+	policyIn := chain.TxPolicyInputFromRequest(wrapper, chainID, value, toPtr, txData, "max_fee_per_gas", "maxFeePerGas")
+	signingKey, acct, cleanup, err := loadSingleKeySigningKeyForTx(ctx, req.Storage, name, keyVersionFromRequest(wrapper), policyIn)
 	if err != nil {
 		return RespondLoadSingleKeyAccountError(err)
 	}
 	defer cleanup()
+	nonce, errResp, err := nonces.FromRequest(ctx, req.Storage, wrapper, chainID, acct.AddressStr)
+	if errResp != nil || err != nil {
+		return errResp, err
+	}
+	resp, err := signEIP1559TxSingleKey(wrapper, chainID, nonce, gasLimit, value, txData, toPtr, signingKey, acct)
+	return recordSingleKeySignedTx(ctx, req, name, chainID, resp, err)
+}
+
+// pathSingleKeySignTxBlob registers EIP-4844 blob transaction signing on .../sign-tx/blob.
+func pathSingleKeySignTxBlob(accountMu *sync.Map) *framework.Path {
+	return &framework.Path{
+		Pattern:        patternSingleKeyAccountSignTxBase() + "/blob",
+		HelpSynopsis:   "Sign an EIP-4844 (type-3) blob transaction for a single-key account.",
+		Fields:         singleKeySignTxBlobFields(),
+		ExistenceCheck: ExistenceSingleKeyAccount(),
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.CreateOperation: withAccountLock(accountMu, handleSingleKeySignTxBlob),
+			logical.UpdateOperation: withAccountLock(accountMu, handleSingleKeySignTxBlob),
+		},
+	}
+}
+
+// singleKeySignTxBlobFields returns field schemas for EIP-4844 blob transaction requests.
+func singleKeySignTxBlobFields() map[string]*framework.FieldSchema {
+	fields := singleKeySignTxEIP1559Fields()
+	fields["max_fee_per_blob_gas"] = &framework.FieldSchema{
+		Type:        framework.TypeString,
+		Description: "Max fee per blob gas (wei, decimal). Alias: maxFeePerBlobGas.",
+	}
+	fields["maxFeePerBlobGas"] = &framework.FieldSchema{
+		Type:        framework.TypeString,
+		Description: "CamelCase alias for max_fee_per_blob_gas.",
+	}
+	fields["blob_versioned_hashes"] = &framework.FieldSchema{
+		Type:        framework.TypeString,
+		Description: "Blob versioned hashes as JSON array of hex strings. Required unless blobs is set.",
+	}
+	fields["blobs"] = &framework.FieldSchema{
+		Type:        framework.TypeString,
+		Description: "Raw blobs as JSON array of hex strings; commitments and proofs are computed by the plugin.",
+	}
+	fields["blob_sidecar_version"] = &framework.FieldSchema{
+		Type:        framework.TypeString,
+		Description: "Sidecar proof layout when blobs is set: 0 (one proof per blob) or 1 (cell proofs, Osaka).",
+		Default:     "1",
+	}
+	return fields
+}
+
+// handleSingleKeySignTxBlob parses blob tx fields and returns a signed type-3 transaction.
+func handleSingleKeySignTxBlob(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	wrapper := model.NewFieldDataWrapper(data)
+	name, err := wrapper.MustGetString("name")
+	if err != nil {
+		return nil, err
+	}
+	chainID, err := wrapper.MustGetBigIntAny("chain_id", "chainID")
+	if err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	cfg, err := config.Read(ctx, req.Storage)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.CheckSigning(model.SigningModeBlob, chainID); err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	gasLimit := wrapper.GetUint64NonEmpty("gas_limit", cfg.DefaultGasLimit)
+	value := wrapper.BigIntWithAliases("value", "amount", big.NewInt(0))
+	inputStr := wrapper.GetString("data", "")
+	var txData []byte
+	if strings.TrimSpace(inputStr) != "" {
+		txData, err = hexutil.Decode(inputStr)
+		if err != nil {
+			return logical.ErrorResponse("invalid data hex: %s", err.Error()), nil
+		}
+	}
+	toStr := wrapper.GetStringFirstNonEmpty("to", "address_to")
+	var toPtr *common.Address
+	if toStr != "" {
+		addr := common.HexToAddress(toStr)
+		toPtr = &addr
+	}
+	policyIn := chain.TxPolicyInputFromRequest(wrapper, chainID, value, toPtr, txData, "max_fee_per_gas", "maxFeePerGas")
+	signingKey, acct, cleanup, err := loadSingleKeySigningKeyForTx(ctx, req.Storage, name, keyVersionFromRequest(wrapper), policyIn)
+	if err != nil {
+		return RespondLoadSingleKeyAccountError(err)
+	}
+	defer cleanup()
+	nonce, errResp, err := nonces.FromRequest(ctx, req.Storage, wrapper, chainID, acct.AddressStr)
+	if errResp != nil || err != nil {
+		return errResp, err
+	}
+	resp, err := signBlobTxSingleKey(wrapper, chainID, nonce, gasLimit, value, txData, toPtr, signingKey, acct)
+	return recordSingleKeySignedTx(ctx, req, name, chainID, resp, err)
+}
+
+// pathSingleKeySignTxEIP7702 registers EIP-7702 set-code transaction signing on .../sign-tx/eip7702.
+func pathSingleKeySignTxEIP7702(accountMu *sync.Map) *framework.Path {
+	return &framework.Path{
+		Pattern:        patternSingleKeyAccountSignTxBase() + "/eip7702",
+		HelpSynopsis:   "Sign an EIP-7702 (type-4) set-code transaction for a single-key account.",
+		Fields:         singleKeySignTxEIP7702Fields(),
+		ExistenceCheck: ExistenceSingleKeyAccount(),
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.CreateOperation: withAccountLock(accountMu, handleSingleKeySignTxEIP7702),
+			logical.UpdateOperation: withAccountLock(accountMu, handleSingleKeySignTxEIP7702),
+		},
+	}
+}
+
+// singleKeySignTxEIP7702Fields returns field schemas for EIP-7702 set-code transaction requests.
+func singleKeySignTxEIP7702Fields() map[string]*framework.FieldSchema {
+	fields := singleKeySignTxEIP1559Fields()
+	fields["authorization_list"] = &framework.FieldSchema{
+		Type:        framework.TypeString,
+		Description: "EIP-7702 authorization list as JSON array of signed tuples (see sign-authorization). Required.",
+	}
+	return fields
+}
 
+// handleSingleKeySignTxEIP7702 parses set-code tx fields and returns a signed type-4 transaction.
+func handleSingleKeySignTxEIP7702(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	wrapper := model.NewFieldDataWrapper(data)
+	name, err := wrapper.MustGetString("name")
+	if err != nil {
+		return nil, err
+	}
 	chainID, err := wrapper.MustGetBigIntAny("chain_id", "chainID")
 	if err != nil {
 		return logical.ErrorResponse("%s", err.Error()), nil
 	}
-	gasLimit, err := wrapper.MustGetUint64("gas_limit")
+	cfg, err := config.Read(ctx, req.Storage)
 	if err != nil {
 		return nil, err
 	}
-	nonce := wrapper.GetUint64("nonce", 0)
+	if err := cfg.CheckSigning(model.SigningModeEIP7702, chainID); err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	gasLimit := wrapper.GetUint64NonEmpty("gas_limit", cfg.DefaultGasLimit)
 	value := wrapper.BigIntWithAliases("value", "amount", big.NewInt(0))
 	inputStr := wrapper.GetString("data", "")
 	var txData []byte
@@ -577,16 +1375,112 @@ func handleSingleKeySignTxEIP1559(ctx context.Context, req *logical.Request, dat
 		addr := common.HexToAddress(toStr)
 		toPtr = &addr
 	}
-	return signEIP1559TxSingleKey(wrapper, chainID, nonce, gasLimit, value, txData, toPtr, signingKey, acct)
+	policyIn := chain.TxPolicyInputFromRequest(wrapper, chainID, value, toPtr, txData, "max_fee_per_gas", "maxFeePerGas")
+	policyIn.Delegates = chain.DelegatesFromRequest(wrapper)
+	signingKey, acct, cleanup, err := loadSingleKeySigningKeyForTx(ctx, req.Storage, name, keyVersionFromRequest(wrapper), policyIn)
+	if err != nil {
+		return RespondLoadSingleKeyAccountError(err)
+	}
+	defer cleanup()
+	nonce, errResp, err := nonces.FromRequest(ctx, req.Storage, wrapper, chainID, acct.AddressStr)
+	if errResp != nil || err != nil {
+		return errResp, err
+	}
+	resp, err := signEIP7702TxSingleKey(wrapper, chainID, nonce, gasLimit, value, txData, toPtr, signingKey, acct)
+	return recordSingleKeySignedTx(ctx, req, name, chainID, resp, err)
+}
+
+// singleKeySignTxReplacementFields returns field schemas for single-key replace and cancel requests.
+func singleKeySignTxReplacementFields() map[string]*framework.FieldSchema {
+	fields := signedtxs.Fields()
+	fields["name"] = &framework.FieldSchema{Type: framework.TypeString}
+	fields["key_version"] = keyVersionField()
+	return fields
 }
 
-// loadSingleKeySigningKeyForTx loads the account and returns an ECDSA key plus a zeroing cleanup.
+// pathSingleKeySignTxReplace registers speed-up of a pending transaction on .../sign-tx/replace.
+func pathSingleKeySignTxReplace(accountMu *sync.Map) *framework.Path {
+	return &framework.Path{
+		Pattern:        patternSingleKeyAccountSignTxBase() + "/replace",
+		HelpSynopsis:   "Re-sign a pending transaction of a single-key account with the same nonce and higher fees.",
+		Fields:         singleKeySignTxReplacementFields(),
+		ExistenceCheck: ExistenceSingleKeyAccount(),
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.CreateOperation: withAccountLock(accountMu, makeHandleSingleKeySignTxReplacement(false)),
+			logical.UpdateOperation: withAccountLock(accountMu, makeHandleSingleKeySignTxReplacement(false)),
+		},
+	}
+}
+
+// pathSingleKeySignTxCancel registers cancellation of a pending transaction on .../sign-tx/cancel.
+func pathSingleKeySignTxCancel(accountMu *sync.Map) *framework.Path {
+	return &framework.Path{
+		Pattern:        patternSingleKeyAccountSignTxBase() + "/cancel",
+		HelpSynopsis:   "Sign a zero-value self-transfer with the nonce and higher fees of a pending transaction of a single-key account.",
+		Fields:         singleKeySignTxReplacementFields(),
+		ExistenceCheck: ExistenceSingleKeyAccount(),
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.CreateOperation: withAccountLock(accountMu, makeHandleSingleKeySignTxReplacement(true)),
+			logical.UpdateOperation: withAccountLock(accountMu, makeHandleSingleKeySignTxReplacement(true)),
+		},
+	}
+}
+
+// makeHandleSingleKeySignTxReplacement returns the replace handler, or the cancel handler when cancel is set.
+// The replacement goes through the same policies as sign-tx and must be signed by the key version that sent the
+// original, named by key_version when it is not the latest.
+func makeHandleSingleKeySignTxReplacement(cancel bool) framework.OperationFunc {
+	return func(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+		wrapper := model.NewFieldDataWrapper(data)
+		name, err := wrapper.MustGetString("name")
+		if err != nil {
+			return nil, err
+		}
+		replacement, errResp, err := signedtxs.Prepare(ctx, req.Storage, storagekey.SingleKeyAccountSignedTxsPrefix(name), wrapper, cancel)
+		if errResp != nil || err != nil {
+			return errResp, err
+		}
+		signingKey, acct, cleanup, err := loadSingleKeySigningKeyForTx(ctx, req.Storage, name, keyVersionFromRequest(wrapper), replacement.PolicyInput())
+		if err != nil {
+			return RespondLoadSingleKeyAccountError(err)
+		}
+		defer cleanup()
+		resp, err := replacement.Sign(acct.AddressStr, signingKey)
+		return recordSingleKeySignedTx(ctx, req, name, replacement.ChainID, resp, err)
+	}
+}
+
+// loadSingleKeySigningKeyForTx enforces the registered chain policy and the account's destination policy on
+// policyIn, then loads key version version of the account (0 for the latest) and returns an ECDSA key plus a
+// zeroing cleanup.
+// Policy rejections wrap model.ErrChainPolicyViolation or model.ErrDestinationPolicyViolation.
 func loadSingleKeySigningKeyForTx(
 	ctx context.Context,
 	storage logical.Storage,
 	name string,
+	version int,
+	policyIn *model.TxPolicyInput,
 ) (signingKey *ecdsa.PrivateKey, acct *model.Account, cleanup func(), err error) {
-	acct, err = ReadSingleKeyAccount(ctx, storage, name)
+	if err := chain.CheckTx(ctx, storage, policyIn); err != nil {
+		return nil, nil, nil, err
+	}
+	if policyIn != nil {
+		dest, err := ReadSingleKeyDestinationPolicy(ctx, storage, name)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if dest != nil {
+			if err := dest.Check("account "+name, policyIn.To, policyIn.Data); err != nil {
+				return nil, nil, nil, err
+			}
+			for _, delegate := range policyIn.Delegates {
+				if err := dest.CheckDelegate("account "+name, delegate); err != nil {
+					return nil, nil, nil, err
+				}
+			}
+		}
+	}
+	acct, err = ReadSingleKeySigningAccount(ctx, storage, name, version)
 	if err != nil {
 		return nil, nil, nil, err
 	}
@@ -600,6 +1494,21 @@ func loadSingleKeySigningKeyForTx(
 	return signingKey, acct, cleanup, nil
 }
 
+// recordSingleKeySignedTx records a successfully signed transaction in the sender's nonce tracker, the
+// signed-transaction records and the account's signing journal.
+func recordSingleKeySignedTx(
+	ctx context.Context,
+	req *logical.Request,
+	name string,
+	chainID *big.Int,
+	resp *logical.Response,
+	err error,
+) (*logical.Response, error) {
+	resp, err = nonces.RecordSignedTx(ctx, req.Storage, chainID, resp, err)
+	resp, err = signedtxs.RecordSignedTx(ctx, req.Storage, storagekey.SingleKeyAccountSignedTxsPrefix(name), resp, err)
+	return journal.RecordSignedTx(ctx, req, storagekey.SingleKeyAccountJournalPrefix(name), resp, err)
+}
+
 // signType0TxSingleKey signs a type-0 tx and builds the Vault response map for handlers.
 func signType0TxSingleKey(
 	wrapper *model.FieldDataWrapper,
@@ -630,6 +1539,40 @@ func signType0TxSingleKey(
 	return &logical.Response{Data: data}, nil
 }
 
+// signEIP2930TxSingleKey signs a type-1 tx with access list and builds the Vault response map.
+func signEIP2930TxSingleKey(
+	wrapper *model.FieldDataWrapper,
+	chainID *big.Int,
+	nonce, gasLimit uint64,
+	value *big.Int,
+	txData []byte,
+	toPtr *common.Address,
+	signingKey *ecdsa.PrivateKey,
+	account *model.Account,
+) (*logical.Response, error) {
+	gasPrice, err := wrapper.MustGetBigIntAny("gas_price")
+	if err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	al, err := ethutil.ParseAccessListJSON(wrapper.GetString("access_list", ""))
+	if err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	signedTx, err := ethutil.SignEIP2930(
+		chainID, nonce, gasLimit, value, txData, toPtr, gasPrice, al, signingKey,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("sign eip2930 tx: %w", err)
+	}
+	data, err := ethutil.SignedTxResponseData(
+		signedTx,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &logical.Response{Data: data}, nil
+}
+
 // signEIP1559TxSingleKey signs a type-2 tx with access list and builds the Vault response map.
 func signEIP1559TxSingleKey(
 	wrapper *model.FieldDataWrapper,
@@ -672,3 +1615,129 @@ func signEIP1559TxSingleKey(
 	}
 	return &logical.Response{Data: data}, nil
 }
+
+// signBlobTxSingleKey signs a type-3 blob tx and builds the Vault response map.
+// Raw blobs are turned into a sidecar (commitments and proofs) before signing.
+func signBlobTxSingleKey(
+	wrapper *model.FieldDataWrapper,
+	chainID *big.Int,
+	nonce, gasLimit uint64,
+	value *big.Int,
+	txData []byte,
+	toPtr *common.Address,
+	signingKey *ecdsa.PrivateKey,
+	account *model.Account,
+) (*logical.Response, error) {
+	if toPtr == nil {
+		return logical.ErrorResponse("blob transactions require to (contract creation is not allowed)"), nil
+	}
+	tip, err := wrapper.MustGetBigIntAny(
+		"max_priority_fee_per_gas", "maxPriorityFeePerGas",
+	)
+	if err != nil {
+		return logical.ErrorResponse("blob tx requires max_priority_fee_per_gas: %s", err.Error()), nil
+	}
+	feeCap, err := wrapper.MustGetBigIntAny("max_fee_per_gas", "maxFeePerGas")
+	if err != nil {
+		return logical.ErrorResponse("blob tx requires max_fee_per_gas: %s", err.Error()), nil
+	}
+	if feeCap.Cmp(tip) < 0 {
+		return logical.ErrorResponse("max_fee_per_gas must be >= max_priority_fee_per_gas"), nil
+	}
+	blobFeeCap, err := wrapper.MustGetBigIntAny("max_fee_per_blob_gas", "maxFeePerBlobGas")
+	if err != nil {
+		return logical.ErrorResponse("blob tx requires max_fee_per_blob_gas: %s", err.Error()), nil
+	}
+	al, err := ethutil.ParseAccessListJSON(wrapper.GetString("access_list", ""))
+	if err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	blobHashes, err := ethutil.ParseBlobHashesJSON(wrapper.GetString("blob_versioned_hashes", ""))
+	if err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	blobs, err := ethutil.ParseBlobsJSON(wrapper.GetString("blobs", ""))
+	if err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	if len(blobHashes) == 0 && len(blobs) == 0 {
+		return logical.ErrorResponse("blob tx requires blob_versioned_hashes or blobs"), nil
+	}
+	var sidecar *ethtypes.BlobTxSidecar
+	if len(blobs) > 0 {
+		version, err := wrapper.MustGetUint64("blob_sidecar_version")
+		if err != nil || version > uint64(ethtypes.BlobSidecarVersion1) {
+			return logical.ErrorResponse("blob_sidecar_version must be 0 or 1"), nil
+		}
+		sidecar, err = ethutil.NewBlobSidecar(blobs, byte(version))
+		if err != nil {
+			return logical.ErrorResponse("%s", err.Error()), nil
+		}
+	}
+	signedTx, err := ethutil.SignEIP4844(
+		chainID, nonce, gasLimit, value, txData, *toPtr, tip, feeCap, blobFeeCap, blobHashes, sidecar, al, signingKey,
+	)
+	if err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	data, err := ethutil.SignedTxResponseData(
+		signedTx,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &logical.Response{Data: data}, nil
+}
+
+// signEIP7702TxSingleKey signs a type-4 set-code tx and builds the Vault response map.
+func signEIP7702TxSingleKey(
+	wrapper *model.FieldDataWrapper,
+	chainID *big.Int,
+	nonce, gasLimit uint64,
+	value *big.Int,
+	txData []byte,
+	toPtr *common.Address,
+	signingKey *ecdsa.PrivateKey,
+	account *model.Account,
+) (*logical.Response, error) {
+	if toPtr == nil {
+		return logical.ErrorResponse("set-code transactions require to (contract creation is not allowed)"), nil
+	}
+	tip, err := wrapper.MustGetBigIntAny(
+		"max_priority_fee_per_gas", "maxPriorityFeePerGas",
+	)
+	if err != nil {
+		return logical.ErrorResponse("eip7702 requires max_priority_fee_per_gas: %s", err.Error()), nil
+	}
+	feeCap, err := wrapper.MustGetBigIntAny("max_fee_per_gas", "maxFeePerGas")
+	if err != nil {
+		return logical.ErrorResponse("eip7702 requires max_fee_per_gas: %s", err.Error()), nil
+	}
+	if feeCap.Cmp(tip) < 0 {
+		return logical.ErrorResponse("max_fee_per_gas must be >= max_priority_fee_per_gas"), nil
+	}
+	al, err := ethutil.ParseAccessListJSON(wrapper.GetString("access_list", ""))
+	if err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	authList, err := ethutil.ParseAuthorizationListJSON(wrapper.GetString("authorization_list", ""))
+	if err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	if len(authList) == 0 {
+		return logical.ErrorResponse("eip7702 requires a non-empty authorization_list"), nil
+	}
+	signedTx, err := ethutil.SignEIP7702(
+		chainID, nonce, gasLimit, value, txData, *toPtr, tip, feeCap, authList, al, signingKey,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("sign eip7702 tx: %w", err)
+	}
+	data, err := ethutil.SignedTxResponseData(
+		signedTx,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &logical.Response{Data: data}, nil
+}
@@ -19,6 +19,7 @@ package account
 
 import (
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/hashicorp/vault/sdk/framework"
@@ -45,7 +46,8 @@ func pathRegisteredOps(p *framework.Path) map[logical.Operation]bool {
 func TestPaths_registerUpdateOnWriteEndpoints(t *testing.T) {
 	t.Parallel()
 
-	paths := Paths()
+	var accountMu sync.Map
+	paths := Paths(&accountMu)
 	if len(paths) == 0 {
 		t.Fatal("expected non-empty paths.")
 	}
@@ -63,10 +65,20 @@ func TestPaths_registerUpdateOnWriteEndpoints(t *testing.T) {
 	wantSuffixes := []string{
 		"/sign",
 		"/sign-eip712",
+		"/sign-message",
 		"/encrypt",
 		"/decrypt",
 		"/sign-tx/legacy",
+		"/sign-tx/eip2930",
 		"/sign-tx/eip1559",
+		"/sign-tx/blob",
+		"/sign-tx/eip7702",
+		"/sign-authorization",
+		"/restore",
+		"/purge",
+		"/rotate",
+		"/keys",
+		"/export",
 	}
 
 	for _, suffix := range wantSuffixes {
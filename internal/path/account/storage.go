@@ -21,26 +21,82 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/hashicorp/vault/sdk/framework"
 	"github.com/hashicorp/vault/sdk/logical"
 
 	"github.com/bsostech/vault-blockchain/internal/model"
+	"github.com/bsostech/vault-blockchain/internal/path/addressindex"
 	"github.com/bsostech/vault-blockchain/internal/path/storagekey"
 )
 
 // ErrSingleKeyAccountMissing is returned when no accounts/<name>/address entry exists.
 var ErrSingleKeyAccountMissing = errors.New("single-key account not found")
 
-// ReadSingleKeyAccount loads and decodes a stored single-key account for name.
+// ErrSingleKeyAccountDeleted is returned when the account is soft-deleted and must be restored before use.
+var ErrSingleKeyAccountDeleted = errors.New("account is deleted")
+
+// ReadSingleKeyAccount loads the latest key version of the single-key account for name, the version used to
+// encrypt and to sign by default. Soft-deleted accounts yield ErrSingleKeyAccountDeleted.
 func ReadSingleKeyAccount(ctx context.Context, s logical.Storage, name string) (*model.Account, error) {
-	key := storagekey.SingleKeyAccountKey(name)
-	entry, err := s.Get(ctx, key)
+	return ReadSingleKeySigningAccount(ctx, s, name, 0)
+}
+
+// ReadSingleKeySigningAccount loads key version version of the single-key account for name, or the latest when
+// version is 0. Versions below min_signing_version or above latest_version yield model.ErrKeyVersionOutOfRange.
+func ReadSingleKeySigningAccount(ctx context.Context, s logical.Storage, name string, version int) (*model.Account, error) {
+	keyring, err := ReadSingleKeyAccountKeyring(ctx, s, name)
+	if err != nil {
+		return nil, err
+	}
+	account, err := keyring.SigningAccountVersion(version)
+	if err != nil {
+		return nil, fmt.Errorf("single-key account %s: %w", name, err)
+	}
+	return account, nil
+}
+
+// ReadSingleKeyAccountKeyring loads the versioned keyring of the single-key account for name.
+// Missing accounts yield ErrSingleKeyAccountMissing and soft-deleted ones ErrSingleKeyAccountDeleted.
+func ReadSingleKeyAccountKeyring(ctx context.Context, s logical.Storage, name string) (*model.AccountKeyring, error) {
+	keyring, err := readSingleKeyAccountKeyring(ctx, s, name)
+	if err != nil {
+		return nil, err
+	}
+	if keyring == nil {
+		return nil, ErrSingleKeyAccountMissing
+	}
+	tomb, err := ReadSingleKeyAccountTombstone(ctx, s, name)
+	if err != nil {
+		return nil, err
+	}
+	if tomb != nil {
+		return nil, ErrSingleKeyAccountDeleted
+	}
+	return keyring, nil
+}
+
+// readSingleKeyAccountKeyring decodes the keyring for name regardless of its deletion state, returning nil when
+// no account exists. A legacy unversioned accounts/<name>/address entry is returned as version 1.
+func readSingleKeyAccountKeyring(ctx context.Context, s logical.Storage, name string) (*model.AccountKeyring, error) {
+	entry, err := s.Get(ctx, storagekey.SingleKeyAccountKeysKey(name))
+	if err != nil {
+		return nil, fmt.Errorf("get single-key account keys %s: %w", name, err)
+	}
+	if entry != nil {
+		var keyring model.AccountKeyring
+		if err := entry.DecodeJSON(&keyring); err != nil {
+			return nil, fmt.Errorf("decode single-key account keys %s: %w", name, err)
+		}
+		return &keyring, nil
+	}
+	entry, err = s.Get(ctx, storagekey.SingleKeyAccountKey(name))
 	if err != nil {
 		return nil, fmt.Errorf("get single-key account %s: %w", name, err)
 	}
 	if entry == nil {
-		return nil, ErrSingleKeyAccountMissing
+		return nil, nil
 	}
 	var account model.Account
 	if err := entry.DecodeJSON(&account); err != nil {
@@ -49,7 +105,124 @@ func ReadSingleKeyAccount(ctx context.Context, s logical.Storage, name string) (
 	if account.PrivateKeyStr == "" {
 		return nil, fmt.Errorf("single-key account %s: empty private key", name)
 	}
-	return &account, nil
+	return model.NewAccountKeyring(&account), nil
+}
+
+// WriteSingleKeyAccountKeyring persists the keyring for name and removes any legacy unversioned entry.
+func WriteSingleKeyAccountKeyring(ctx context.Context, s logical.Storage, name string, keyring *model.AccountKeyring) error {
+	entry, err := logical.StorageEntryJSON(storagekey.SingleKeyAccountKeysKey(name), keyring)
+	if err != nil {
+		return fmt.Errorf("encode single-key account keys %s: %w", name, err)
+	}
+	if err := s.Put(ctx, entry); err != nil {
+		return fmt.Errorf("put single-key account keys %s: %w", name, err)
+	}
+	if err := s.Delete(ctx, storagekey.SingleKeyAccountKey(name)); err != nil {
+		return fmt.Errorf("delete legacy single-key account %s: %w", name, err)
+	}
+	return nil
+}
+
+// singleKeyAccountExists reports whether a keyring or legacy entry is stored for name.
+func singleKeyAccountExists(ctx context.Context, s logical.Storage, name string) (bool, error) {
+	for _, key := range []string{storagekey.SingleKeyAccountKeysKey(name), storagekey.SingleKeyAccountKey(name)} {
+		entry, err := s.Get(ctx, key)
+		if err != nil {
+			return false, err
+		}
+		if entry != nil {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// MigrateSingleKeyAccounts rewrites every legacy unversioned single-key account as a keyring holding its key as
+// version 1, returning the number of accounts migrated. Already migrated accounts are skipped.
+func MigrateSingleKeyAccounts(ctx context.Context, s logical.Storage) (int, error) {
+	children, err := s.List(ctx, storagekey.SingleKeyAccountsRootPrefix())
+	if err != nil {
+		return 0, fmt.Errorf("list accounts: %w", err)
+	}
+	migrated := 0
+	for _, child := range children {
+		name := strings.TrimSuffix(child, "/")
+		if name == "" {
+			continue
+		}
+		legacy, err := s.Get(ctx, storagekey.SingleKeyAccountKey(name))
+		if err != nil {
+			return migrated, fmt.Errorf("get single-key account %s: %w", name, err)
+		}
+		if legacy == nil {
+			continue
+		}
+		keyring, err := readSingleKeyAccountKeyring(ctx, s, name)
+		if err != nil {
+			return migrated, err
+		}
+		if err := WriteSingleKeyAccountKeyring(ctx, s, name, keyring); err != nil {
+			return migrated, err
+		}
+		migrated++
+	}
+	return migrated, nil
+}
+
+// ReadSingleKeyAccountTombstone loads the account's tombstone, or returns nil when the account is not soft-deleted.
+func ReadSingleKeyAccountTombstone(ctx context.Context, s logical.Storage, name string) (*model.Tombstone, error) {
+	entry, err := s.Get(ctx, storagekey.SingleKeyAccountTombstoneKey(name))
+	if err != nil {
+		return nil, fmt.Errorf("get single-key account tombstone %s: %w", name, err)
+	}
+	if entry == nil {
+		return nil, nil
+	}
+	var t model.Tombstone
+	if err := entry.DecodeJSON(&t); err != nil {
+		return nil, fmt.Errorf("decode single-key account tombstone %s: %w", name, err)
+	}
+	return &t, nil
+}
+
+// WriteSingleKeyAccountTombstone persists the account's tombstone.
+func WriteSingleKeyAccountTombstone(ctx context.Context, s logical.Storage, name string, t *model.Tombstone) error {
+	entry, err := logical.StorageEntryJSON(storagekey.SingleKeyAccountTombstoneKey(name), t)
+	if err != nil {
+		return fmt.Errorf("encode single-key account tombstone %s: %w", name, err)
+	}
+	if err := s.Put(ctx, entry); err != nil {
+		return fmt.Errorf("put single-key account tombstone %s: %w", name, err)
+	}
+	return nil
+}
+
+// ReadSingleKeyDestinationPolicy loads the account's destination policy, or returns nil when none is set.
+func ReadSingleKeyDestinationPolicy(ctx context.Context, s logical.Storage, name string) (*model.DestinationPolicy, error) {
+	entry, err := s.Get(ctx, storagekey.SingleKeyAccountPolicyKey(name))
+	if err != nil {
+		return nil, fmt.Errorf("get single-key account policy %s: %w", name, err)
+	}
+	if entry == nil {
+		return nil, nil
+	}
+	var p model.DestinationPolicy
+	if err := entry.DecodeJSON(&p); err != nil {
+		return nil, fmt.Errorf("decode single-key account policy %s: %w", name, err)
+	}
+	return &p, nil
+}
+
+// WriteSingleKeyDestinationPolicy persists the account's destination policy. Callers validate it first.
+func WriteSingleKeyDestinationPolicy(ctx context.Context, s logical.Storage, name string, p *model.DestinationPolicy) error {
+	entry, err := logical.StorageEntryJSON(storagekey.SingleKeyAccountPolicyKey(name), p)
+	if err != nil {
+		return fmt.Errorf("encode single-key account policy %s: %w", name, err)
+	}
+	if err := s.Put(ctx, entry); err != nil {
+		return fmt.Errorf("put single-key account policy %s: %w", name, err)
+	}
+	return nil
 }
 
 // ExistenceSingleKeyAccount returns true when the single-key account key exists for name.
@@ -59,11 +232,11 @@ func ExistenceSingleKeyAccount() framework.ExistenceFunc {
 		if name == "" {
 			return false, nil
 		}
-		entry, err := req.Storage.Get(ctx, storagekey.SingleKeyAccountKey(name))
+		exists, err := singleKeyAccountExists(ctx, req.Storage, name)
 		if err != nil {
 			return false, fmt.Errorf("existence check single-key account %s: %w", name, err)
 		}
-		return entry != nil, nil
+		return exists, nil
 	}
 }
 
@@ -76,19 +249,58 @@ func ExistenceSingleKeyAccountSeed() framework.ExistenceFunc {
 		if name == "" {
 			return false, nil
 		}
-		entry, err := req.Storage.Get(ctx, storagekey.SingleKeyAccountKey(name))
+		exists, err := singleKeyAccountExists(ctx, req.Storage, name)
 		if err != nil {
 			return false, fmt.Errorf("single-key account existence check for %s: %w", name, err)
 		}
-		return entry != nil, nil
+		return exists, nil
 	}
 }
 
 // RespondLoadSingleKeyAccountError maps loader errors to logical responses for Vault handlers.
 func RespondLoadSingleKeyAccountError(err error) (*logical.Response, error) {
-	if errors.Is(err, ErrSingleKeyAccountMissing) {
+	switch {
+	case errors.Is(err, ErrSingleKeyAccountMissing):
 		return logical.ErrorResponse("account not found"), nil
+	case errors.Is(err, ErrSingleKeyAccountDeleted), errors.Is(err, model.ErrKeyVersionOutOfRange),
+		errors.Is(err, model.ErrChainPolicyViolation), errors.Is(err, model.ErrDestinationPolicyViolation):
+		return logical.ErrorResponse("%s", err.Error()), nil
+	default:
+		return nil, err
+	}
+}
+
+// IndexSingleKeyAccountAddresses writes address index entries for every key version of every stored single-key
+// account, including soft-deleted ones, and returns how many entries were written. It backfills accounts created
+// before the index existed; addresses already owned by another wallet or account are left unchanged.
+func IndexSingleKeyAccountAddresses(ctx context.Context, s logical.Storage) (int, error) {
+	children, err := s.List(ctx, storagekey.SingleKeyAccountsRootPrefix())
+	if err != nil {
+		return 0, fmt.Errorf("list accounts: %w", err)
+	}
+	indexed := 0
+	for _, child := range children {
+		name := strings.TrimSuffix(child, "/")
+		if name == "" {
+			continue
+		}
+		keyring, err := readSingleKeyAccountKeyring(ctx, s, name)
+		if err != nil {
+			return indexed, err
+		}
+		if keyring == nil {
+			continue
+		}
+		for version, acct := range keyring.Keys {
+			written, err := addressindex.IndexAddress(ctx, s, acct.AddressStr, model.NewAccountAddressOwner(name, version))
+			if err != nil {
+				return indexed, err
+			}
+			if written {
+				indexed++
+			}
+		}
 	}
-	return nil, err
+	return indexed, nil
 }
 
@@ -211,3 +211,46 @@ func TestRespondLoadSingleKeyAccountError(t *testing.T) {
 	}
 }
 
+// TestMigrateSingleKeyAccounts verifies legacy entries move to the versioned keyring as version 1 and reruns are no-ops.
+func TestMigrateSingleKeyAccounts(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	entry, err := logical.StorageEntryJSON(storagekey.SingleKeyAccountKey("legacy"), &model.Account{
+		AddressStr:    "0xabc",
+		PrivateKeyStr: "deadbeef",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Put(ctx, entry); err != nil {
+		t.Fatal(err)
+	}
+
+	migrated, err := account.MigrateSingleKeyAccounts(ctx, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if migrated != 1 {
+		t.Fatalf("migrated=%d want 1.", migrated)
+	}
+	if old, err := s.Get(ctx, storagekey.SingleKeyAccountKey("legacy")); err != nil || old != nil {
+		t.Fatalf("legacy entry=%v err=%v want removed.", old, err)
+	}
+	keyring, err := account.ReadSingleKeyAccountKeyring(ctx, s, "legacy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keyring.LatestVersion != 1 || keyring.Keys[1].AddressStr != "0xabc" {
+		t.Fatalf("keyring=%+v want version 1 with the legacy key.", keyring)
+	}
+
+	migrated, err = account.MigrateSingleKeyAccounts(ctx, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if migrated != 0 {
+		t.Fatalf("second run migrated=%d want 0.", migrated)
+	}
+}
@@ -0,0 +1,91 @@
+// Package addressindex implements the addresses/ reverse index from Ethereum address to the wallet-derived or
+// single-key account that owns its key.
+package addressindex
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/logical"
+
+	"github.com/bsostech/vault-blockchain/internal/model"
+	"github.com/bsostech/vault-blockchain/internal/path/storagekey"
+)
+
+// PatternAddress matches a 0x hex address path segment in any letter case.
+const PatternAddress = "(?P<address>0[xX][0-9a-fA-F]{40})"
+
+// Paths returns the address index paths.
+func Paths() []*framework.Path {
+	return []*framework.Path{
+		pathAddress(),
+	}
+}
+
+// pathAddress registers read on addresses/:address.
+func pathAddress() *framework.Path {
+	return &framework.Path{
+		Pattern:      "addresses/" + PatternAddress,
+		HelpSynopsis: "Look up the wallet-derived or single-key account that owns an address.",
+		Fields: map[string]*framework.FieldSchema{
+			"address": {
+				Type:        framework.TypeString,
+				Description: "Hex address in the path (any letter case).",
+			},
+		},
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.ReadOperation: handleAddressRead,
+		},
+	}
+}
+
+// handleAddressRead returns the owner of an indexed address, the path of its signing endpoints and whether
+// the owner is soft-deleted.
+func handleAddressRead(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	addr, err := model.NewFieldDataWrapper(data).MustGetString("address")
+	if err != nil {
+		return logical.ErrorResponse("address is required"), nil
+	}
+	owner, err := ReadAddressOwner(ctx, req.Storage, addr)
+	if err != nil {
+		return nil, err
+	}
+	if owner == nil {
+		return nil, nil
+	}
+	out, err := OwnerResponseData(ctx, req.Storage, owner)
+	if err != nil {
+		return nil, err
+	}
+	out["address"] = common.HexToAddress(addr).Hex()
+	return &logical.Response{Data: out}, nil
+}
+
+// OwnerResponseData returns the response fields that describe an address owner: its type, the path of its
+// signing endpoints, whether it is soft-deleted and the fields that identify it.
+func OwnerResponseData(ctx context.Context, s logical.Storage, owner *model.AddressOwner) (map[string]interface{}, error) {
+	tombKey := storagekey.SingleKeyAccountTombstoneKey(owner.Name)
+	if owner.Type == model.AddressOwnerWallet {
+		tombKey = storagekey.WalletTombstoneKey(owner.WalletID)
+	}
+	tomb, err := s.Get(ctx, tombKey)
+	if err != nil {
+		return nil, fmt.Errorf("get owner tombstone %s: %w", tombKey, err)
+	}
+	out := map[string]interface{}{
+		"type":    owner.Type,
+		"path":    owner.PathPrefix(),
+		"deleted": tomb != nil,
+	}
+	if owner.Type == model.AddressOwnerWallet {
+		out["wallet_id"] = owner.WalletID
+		out["account"] = owner.Account
+		out["account_index"] = owner.Index
+	} else {
+		out["name"] = owner.Name
+		out["version"] = owner.Version
+	}
+	return out, nil
+}
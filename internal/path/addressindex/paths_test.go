@@ -0,0 +1,135 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package addressindex
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/logical"
+
+	"github.com/bsostech/vault-blockchain/internal/model"
+	"github.com/bsostech/vault-blockchain/internal/path/storagekey"
+)
+
+const testAddress = "0x9858EfFD232B4033E47d90003D41EC34EcaEda94"
+
+// TestIndexAddress_firstOwnerWins verifies lookups ignore address case, a second owner of the same key does not
+// take the entry over, and only the owning resource can remove it.
+func TestIndexAddress_firstOwnerWins(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	wallet := model.NewWalletAddressOwner("w1", "0", "0")
+	written, err := IndexAddress(ctx, s, testAddress, wallet)
+	if err != nil || !written {
+		t.Fatalf("written=%v err=%v want first owner written.", written, err)
+	}
+	written, err = IndexAddress(ctx, s, testAddress, model.NewAccountAddressOwner("alice", 1))
+	if err != nil || written {
+		t.Fatalf("written=%v err=%v want second owner ignored.", written, err)
+	}
+	got, err := ReadAddressOwner(ctx, s, "0x9858effd232b4033e47d90003d41ec34ecaeda94")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got == nil || got.Type != model.AddressOwnerWallet || got.WalletID != "w1" || got.Index != "0" {
+		t.Fatalf("owner=%+v want wallet w1 index 0.", got)
+	}
+
+	if err := UnindexAddress(ctx, s, testAddress, model.NewAccountAddressOwner("alice", 1)); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := ReadAddressOwner(ctx, s, testAddress); got == nil {
+		t.Fatal("entry removed by a resource that does not own it.")
+	}
+	if err := UnindexAddress(ctx, s, testAddress, wallet); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := ReadAddressOwner(ctx, s, testAddress); got != nil {
+		t.Fatalf("owner=%+v want removed.", got)
+	}
+	if _, err := ReadAddressOwner(ctx, s, "0x1234"); err == nil {
+		t.Fatal("expected error for invalid address.")
+	}
+}
+
+// TestHandleAddressRead verifies lookups report the owner, its signing path and deletion state, and that
+// unindexed addresses read as not found.
+func TestHandleAddressRead(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	req := &logical.Request{Storage: new(logical.InmemStorage)}
+	fd := &framework.FieldData{
+		Raw:    map[string]interface{}{"address": "0x9858effd232b4033e47d90003d41ec34ecaeda94"},
+		Schema: pathAddress().Fields,
+	}
+	resp, err := handleAddressRead(ctx, req, fd)
+	if err != nil || resp != nil {
+		t.Fatalf("resp=%v err=%v want not found.", resp, err)
+	}
+
+	if _, err := IndexAddress(ctx, req.Storage, testAddress, model.NewWalletAddressOwner("w1", "2", "5")); err != nil {
+		t.Fatal(err)
+	}
+	resp, err = handleAddressRead(ctx, req, fd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"address": testAddress, "type": "wallet", "path": "wallets/w1/accounts/2/5", "deleted": false,
+		"wallet_id": "w1", "account": "2", "account_index": "5",
+	}
+	for k, v := range want {
+		if resp.Data[k] != v {
+			t.Fatalf("%s=%v want %v.", k, resp.Data[k], v)
+		}
+	}
+
+	if err := req.Storage.Put(ctx, &logical.StorageEntry{Key: storagekey.WalletTombstoneKey("w1"), Value: []byte("{}")}); err != nil {
+		t.Fatal(err)
+	}
+	resp, err = handleAddressRead(ctx, req, fd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Data["deleted"] != true {
+		t.Fatalf("deleted=%v want true.", resp.Data["deleted"])
+	}
+}
+
+// TestBackfillMarker verifies the backfill marker reads false until written.
+func TestBackfillMarker(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	if done, err := BackfillDone(ctx, s); err != nil || done {
+		t.Fatalf("done=%v err=%v want false.", done, err)
+	}
+	if err := MarkBackfillDone(ctx, s, time.Unix(1_700_000_000, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if done, err := BackfillDone(ctx, s); err != nil || !done {
+		t.Fatalf("done=%v err=%v want true.", done, err)
+	}
+}
@@ -0,0 +1,118 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package addressindex
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/vault/sdk/logical"
+
+	"github.com/bsostech/vault-blockchain/internal/model"
+	"github.com/bsostech/vault-blockchain/internal/path/storagekey"
+)
+
+// backfillMarker records when keys stored before the address index existed were indexed.
+type backfillMarker struct {
+	CompletedAt int64 `json:"completed_at"`
+}
+
+// ReadAddressOwner loads the index entry for address, returning nil if the address is not indexed.
+func ReadAddressOwner(ctx context.Context, s logical.Storage, address string) (*model.AddressOwner, error) {
+	key, err := model.NormalizeAddress(address)
+	if err != nil {
+		return nil, err
+	}
+	entry, err := s.Get(ctx, storagekey.AddressIndexKey(key))
+	if err != nil {
+		return nil, fmt.Errorf("get address index %s: %w", key, err)
+	}
+	if entry == nil {
+		return nil, nil
+	}
+	var owner model.AddressOwner
+	if err := entry.DecodeJSON(&owner); err != nil {
+		return nil, fmt.Errorf("decode address index %s: %w", key, err)
+	}
+	return &owner, nil
+}
+
+// IndexAddress records owner for address. The first owner of an address keeps it: when the same key is held by
+// another wallet or account, the existing entry is left unchanged and false is returned.
+func IndexAddress(ctx context.Context, s logical.Storage, address string, owner *model.AddressOwner) (bool, error) {
+	existing, err := ReadAddressOwner(ctx, s, address)
+	if err != nil {
+		return false, err
+	}
+	if existing != nil && !existing.SameResource(owner) {
+		return false, nil
+	}
+	key, err := model.NormalizeAddress(address)
+	if err != nil {
+		return false, err
+	}
+	entry, err := logical.StorageEntryJSON(storagekey.AddressIndexKey(key), owner)
+	if err != nil {
+		return false, fmt.Errorf("encode address index %s: %w", key, err)
+	}
+	if err := s.Put(ctx, entry); err != nil {
+		return false, fmt.Errorf("put address index %s: %w", key, err)
+	}
+	return true, nil
+}
+
+// UnindexAddress removes the entry for address if it belongs to the same wallet or account as owner.
+func UnindexAddress(ctx context.Context, s logical.Storage, address string, owner *model.AddressOwner) error {
+	existing, err := ReadAddressOwner(ctx, s, address)
+	if err != nil {
+		return err
+	}
+	if !existing.SameResource(owner) {
+		return nil
+	}
+	key, err := model.NormalizeAddress(address)
+	if err != nil {
+		return err
+	}
+	if err := s.Delete(ctx, storagekey.AddressIndexKey(key)); err != nil {
+		return fmt.Errorf("delete address index %s: %w", key, err)
+	}
+	return nil
+}
+
+// BackfillDone reports whether keys stored before the address index existed have been indexed.
+func BackfillDone(ctx context.Context, s logical.Storage) (bool, error) {
+	entry, err := s.Get(ctx, storagekey.AddressIndexBackfillKey())
+	if err != nil {
+		return false, fmt.Errorf("get address index backfill marker: %w", err)
+	}
+	return entry != nil, nil
+}
+
+// MarkBackfillDone records that the address index backfill has completed.
+func MarkBackfillDone(ctx context.Context, s logical.Storage, now time.Time) error {
+	entry, err := logical.StorageEntryJSON(storagekey.AddressIndexBackfillKey(), &backfillMarker{CompletedAt: now.Unix()})
+	if err != nil {
+		return fmt.Errorf("encode address index backfill marker: %w", err)
+	}
+	if err := s.Put(ctx, entry); err != nil {
+		return fmt.Errorf("put address index backfill marker: %w", err)
+	}
+	return nil
+}
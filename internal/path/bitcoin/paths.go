@@ -0,0 +1,296 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package bitcoin implements the wallets/:wallet_id/bitcoin paths that derive BIP-84 native SegWit (P2WPKH)
+// addresses from a wallet's seed and sign the wallet's inputs of a PSBT.
+package bitcoin
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/logical"
+
+	"github.com/bsostech/vault-blockchain/internal/bitcoinutil"
+	"github.com/bsostech/vault-blockchain/internal/model"
+	"github.com/bsostech/vault-blockchain/internal/path/config"
+	"github.com/bsostech/vault-blockchain/internal/path/journal"
+	"github.com/bsostech/vault-blockchain/internal/path/storagekey"
+	"github.com/bsostech/vault-blockchain/internal/path/wallet"
+)
+
+// Paths returns the wallet Bitcoin paths. walletMu holds the per-wallet locks shared with the wallet paths.
+func Paths(walletMu *sync.Map) []*framework.Path {
+	return []*framework.Path{
+		pathBitcoinAccount(),
+		pathBitcoinSignPSBT(walletMu),
+		pathBitcoinJournal(),
+		pathBitcoinJournalVerify(),
+		pathBitcoinJournalRecord(),
+	}
+}
+
+// patternBitcoinBase is the wallets/:wallet_id/bitcoin prefix of every path in this package.
+func patternBitcoinBase() string {
+	return "wallets/" + framework.GenericNameRegex("wallet_id") + "/bitcoin"
+}
+
+// networkField is the network parameter shared by the Bitcoin paths.
+var networkField = &framework.FieldSchema{
+	Type:        framework.TypeString,
+	Description: "Bitcoin network: mainnet (default), testnet or regtest. Test networks use coin type 1'.",
+	Default:     model.BitcoinNetworkMainnet,
+}
+
+// pathBitcoinAccount registers read on wallets/:wallet_id/bitcoin/accounts/:index.
+func pathBitcoinAccount() *framework.Path {
+	return &framework.Path{
+		Pattern:      patternBitcoinBase() + "/accounts/" + framework.GenericNameRegex("index"),
+		HelpSynopsis: "Derive the BIP-84 native SegWit (P2WPKH) address of the wallet at m/84'/0'/0'/<change>/<index>.",
+		Fields: map[string]*framework.FieldSchema{
+			"wallet_id": {
+				Type:        framework.TypeString,
+				Description: "Logical wallet identifier in the path.",
+			},
+			"index": {
+				Type:        framework.TypeString,
+				Description: "Address index (decimal) in the path.",
+			},
+			"network": networkField,
+			"change": {
+				Type:        framework.TypeBool,
+				Description: "Derive from the internal (change) chain, m/84'/<coin_type>'/0'/1/<index>. Default false.",
+			},
+		},
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.ReadOperation: handleBitcoinAccountRead,
+		},
+	}
+}
+
+// pathBitcoinSignPSBT registers sign-psbt on wallets/:wallet_id/bitcoin/sign-psbt.
+func pathBitcoinSignPSBT(walletMu *sync.Map) *framework.Path {
+	return &framework.Path{
+		Pattern:      patternBitcoinBase() + "/sign-psbt",
+		HelpSynopsis: "Sign the PSBT inputs whose BIP-32 derivation belongs to the wallet's BIP-84 keys.",
+		HelpDescription: "Inputs are matched by the wallet's master key fingerprint and a m/84'/<coin_type>'/0'/<change>/<index> " +
+			"derivation path, and must spend a P2WPKH output of the derived key. Other inputs are left untouched.",
+		Fields: map[string]*framework.FieldSchema{
+			"wallet_id": {
+				Type:        framework.TypeString,
+				Description: "Logical wallet identifier in the path.",
+			},
+			"psbt": {
+				Type:        framework.TypeString,
+				Description: "Base64-encoded PSBT (BIP-174) with witness or non-witness UTXOs and BIP-32 derivations for the wallet's inputs.",
+			},
+			"network": networkField,
+			"finalize": {
+				Type:        framework.TypeBool,
+				Description: "Finalize the inputs and, once every input is final, return the signed transaction. Default false.",
+			},
+		},
+		ExistenceCheck: wallet.ExistenceWalletSeed(),
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.CreateOperation: wallet.WithWalletLock(walletMu, handleBitcoinSignPSBT),
+			logical.UpdateOperation: wallet.WithWalletLock(walletMu, handleBitcoinSignPSBT),
+		},
+	}
+}
+
+// bitcoinJournalFields returns field schemas shared by the Bitcoin journal paths.
+func bitcoinJournalFields() map[string]*framework.FieldSchema {
+	return map[string]*framework.FieldSchema{
+		"wallet_id": {
+			Type:        framework.TypeString,
+			Description: "Logical wallet identifier in the path.",
+		},
+	}
+}
+
+// pathBitcoinJournal registers LIST on wallets/:wallet_id/bitcoin/journal for record sequence numbers.
+func pathBitcoinJournal() *framework.Path {
+	return &framework.Path{
+		Pattern:      patternBitcoinBase() + "/journal/?",
+		HelpSynopsis: "List the sequence numbers of the wallet's Bitcoin signing journal.",
+		Fields:       bitcoinJournalFields(),
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.ListOperation: journal.HandleList(bitcoinJournalPrefix),
+		},
+	}
+}
+
+// pathBitcoinJournalVerify registers read on wallets/:wallet_id/bitcoin/journal/verify.
+func pathBitcoinJournalVerify() *framework.Path {
+	return &framework.Path{
+		Pattern:      patternBitcoinBase() + "/journal/verify",
+		HelpSynopsis: "Check the hash chain of the wallet's Bitcoin signing journal.",
+		Fields:       bitcoinJournalFields(),
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.ReadOperation: journal.HandleVerify(bitcoinJournalPrefix),
+		},
+	}
+}
+
+// pathBitcoinJournalRecord registers read on wallets/:wallet_id/bitcoin/journal/:seq.
+func pathBitcoinJournalRecord() *framework.Path {
+	fields := bitcoinJournalFields()
+	fields["seq"] = journal.SeqField()
+	return &framework.Path{
+		Pattern:      patternBitcoinBase() + "/journal/" + journal.PatternSeq,
+		HelpSynopsis: "Read one record of the wallet's Bitcoin signing journal.",
+		Fields:       fields,
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.ReadOperation: journal.HandleRead(bitcoinJournalPrefix),
+		},
+	}
+}
+
+// bitcoinJournalPrefix resolves the Bitcoin journal prefix of the wallet named in the request path.
+func bitcoinJournalPrefix(wrapper *model.FieldDataWrapper) (string, *logical.Response) {
+	walletID := wrapper.GetString("wallet_id", "")
+	if walletID == "" {
+		return "", logical.ErrorResponse("wallet_id is required")
+	}
+	return storagekey.WalletBitcoinJournalPrefix(walletID), nil
+}
+
+// handleBitcoinAccountRead returns the P2WPKH address, derivation path and compressed public key at index.
+func handleBitcoinAccountRead(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	wrapper := model.NewFieldDataWrapper(data)
+	walletID, err := wrapper.MustGetString("wallet_id")
+	if err != nil || walletID == "" {
+		return logical.ErrorResponse("wallet_id is required"), nil
+	}
+	indexStr, err := wrapper.MustGetString("index")
+	if err != nil {
+		return logical.ErrorResponse("index is required"), nil
+	}
+	index, err := wallet.ParseAddressIndex(indexStr)
+	if err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	network := strings.ToLower(wrapper.GetString("network", model.BitcoinNetworkMainnet))
+	if _, err := model.BitcoinNetworkParams(network); err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	seed, errResp, err := wallet.LoadWalletSeed(ctx, req.Storage, walletID)
+	if err != nil || errResp != nil {
+		return errResp, err
+	}
+	account, err := seed.BitcoinAccount(network, wrapper.GetBool("change", false), index)
+	if err != nil {
+		return nil, err
+	}
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"wallet_id":       walletID,
+			"account_index":   indexStr,
+			"network":         network,
+			"address":         account.Address,
+			"derivation_path": account.DerivationPath,
+			"public_key":      account.PublicKey,
+		},
+	}, nil
+}
+
+// handleBitcoinSignPSBT signs the wallet's inputs of a PSBT and optionally finalizes it. The serialized unsigned
+// transaction and its txid are journaled.
+// Caller must hold the per-wallet mutex from walletMu.
+func handleBitcoinSignPSBT(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	wrapper := model.NewFieldDataWrapper(data)
+	cfg, err := config.Read(ctx, req.Storage)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.CheckSigning(model.SigningModeBitcoinPSBT, nil); err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	walletID, err := wrapper.MustGetString("wallet_id")
+	if err != nil || walletID == "" {
+		return logical.ErrorResponse("wallet_id is required"), nil
+	}
+	encoded, err := wrapper.MustGetString("psbt")
+	if err != nil || strings.TrimSpace(encoded) == "" {
+		return logical.ErrorResponse("psbt is required"), nil
+	}
+	network := strings.ToLower(wrapper.GetString("network", model.BitcoinNetworkMainnet))
+	params, err := model.BitcoinNetworkParams(network)
+	if err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	packet, err := bitcoinutil.DecodePSBT(encoded)
+	if err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	seed, errResp, err := wallet.LoadWalletSeed(ctx, req.Storage, walletID)
+	if err != nil || errResp != nil {
+		return errResp, err
+	}
+	fingerprint, err := seed.MasterKeyFingerprint()
+	if err != nil {
+		return nil, err
+	}
+
+	signed, err := bitcoinutil.SignPSBT(packet, func(d *psbt.Bip32Derivation) (*btcec.PrivateKey, error) {
+		if d.MasterKeyFingerprint != fingerprint {
+			return nil, nil
+		}
+		if _, _, ok := model.ParseBIP84Path(params, d.Bip32Path); !ok {
+			return nil, nil
+		}
+		return seed.BitcoinPrivateKey(d.Bip32Path)
+	})
+	if err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	if len(signed) == 0 {
+		return logical.ErrorResponse("no input of the PSBT derives from wallet %s on %s", walletID, network), nil
+	}
+
+	out := map[string]interface{}{
+		"signed_inputs": signed,
+		"complete":      false,
+	}
+	if wrapper.GetBool("finalize", false) {
+		rawTx, txid, complete, err := bitcoinutil.FinalizePSBT(packet)
+		if err != nil {
+			return logical.ErrorResponse("%s", err.Error()), nil
+		}
+		out["complete"] = complete
+		if complete {
+			out["signed_transaction"] = rawTx
+			out["txid"] = txid
+		}
+	}
+	updated, err := packet.B64Encode()
+	if err != nil {
+		return nil, err
+	}
+	out["psbt"] = updated
+	var unsigned bytes.Buffer
+	if err := packet.UnsignedTx.Serialize(&unsigned); err != nil {
+		return nil, err
+	}
+	return journal.RecordTx(ctx, req, storagekey.WalletBitcoinJournalPrefix(walletID), model.JournalOpBitcoinSignPSBT, "",
+		unsigned.Bytes(), packet.UnsignedTx.TxHash().String(), &logical.Response{Data: out}, nil)
+}
@@ -0,0 +1,253 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package bitcoin
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/logical"
+
+	"github.com/bsostech/vault-blockchain/internal/model"
+	"github.com/bsostech/vault-blockchain/internal/path/journal"
+	"github.com/bsostech/vault-blockchain/internal/path/storagekey"
+)
+
+const testMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+// fieldData builds FieldData for raw against the schema of p.
+func fieldData(p *framework.Path, raw map[string]interface{}) *framework.FieldData {
+	return &framework.FieldData{Raw: raw, Schema: p.Fields}
+}
+
+// mustPutWalletSeed stores a WalletSeed for walletID.
+func mustPutWalletSeed(ctx context.Context, t *testing.T, s logical.Storage, walletID string) {
+	t.Helper()
+	entry, err := logical.StorageEntryJSON(storagekey.SeedKey(walletID), &model.WalletSeed{Mnemonic: testMnemonic})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Put(ctx, entry); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestHandleBitcoinAccountRead verifies the BIP-84 reference address and the testnet change variant.
+func TestHandleBitcoinAccountRead(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	mustPutWalletSeed(ctx, t, s, "w1")
+	req := &logical.Request{Storage: s}
+	p := pathBitcoinAccount()
+
+	resp, err := handleBitcoinAccountRead(ctx, req, fieldData(p, map[string]interface{}{"wallet_id": "w1", "index": "0"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || resp.IsError() {
+		t.Fatalf("resp=%v want success.", resp)
+	}
+	if resp.Data["address"] != "bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu" || resp.Data["derivation_path"] != "m/84'/0'/0'/0/0" {
+		t.Fatalf("data=%v want BIP-84 reference address.", resp.Data)
+	}
+
+	resp, err = handleBitcoinAccountRead(ctx, req, fieldData(p, map[string]interface{}{
+		"wallet_id": "w1", "index": "3", "network": "testnet", "change": true,
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || resp.IsError() || resp.Data["derivation_path"] != "m/84'/1'/0'/1/3" || resp.Data["network"] != "testnet" {
+		t.Fatalf("resp=%v want testnet change path.", resp)
+	}
+
+	for _, raw := range []map[string]interface{}{
+		{"wallet_id": "missing", "index": "0"},
+		{"wallet_id": "w1", "index": "0", "network": "signet"},
+		{"wallet_id": "w1", "index": "x"},
+	} {
+		resp, err := handleBitcoinAccountRead(ctx, req, fieldData(p, raw))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp == nil || !resp.IsError() {
+			t.Fatalf("%v: resp=%v want error response.", raw, resp)
+		}
+	}
+}
+
+// testSpendPSBT builds a regtest PSBT spending the wallet's receive address 0 and a foreign output.
+func testSpendPSBT(t *testing.T, fingerprint uint32) (*psbt.Packet, []byte) {
+	t.Helper()
+	seed := &model.WalletSeed{Mnemonic: testMnemonic}
+	params := &chaincfg.RegressionNetParams
+	path := model.BIP84Path(params, false, 0)
+	key, err := seed.BitcoinPrivateKey(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer key.Zero()
+	pub := key.PubKey().SerializeCompressed()
+	addr, err := btcutil.NewAddressWitnessPubKeyHash(btcutil.Hash160(pub), params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	script, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx := wire.NewMsgTx(2)
+	tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{1}, 0), nil, nil))
+	tx.AddTxOut(wire.NewTxOut(40_000, script))
+	p, err := psbt.NewFromUnsignedTx(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.Inputs[0].WitnessUtxo = wire.NewTxOut(50_000, script)
+	p.Inputs[0].Bip32Derivation = []*psbt.Bip32Derivation{{PubKey: pub, MasterKeyFingerprint: fingerprint, Bip32Path: path}}
+	return p, script
+}
+
+// TestHandleBitcoinSignPSBT verifies the wallet's input is signed and finalized into a valid transaction, and
+// that PSBTs of another key or network are refused.
+func TestHandleBitcoinSignPSBT(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	mustPutWalletSeed(ctx, t, s, "w1")
+	req := &logical.Request{Storage: s}
+	p := pathBitcoinSignPSBT(new(sync.Map))
+	fingerprint, err := (&model.WalletSeed{Mnemonic: testMnemonic}).MasterKeyFingerprint()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	packet, script := testSpendPSBT(t, fingerprint)
+	encoded, err := packet.B64Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := handleBitcoinSignPSBT(ctx, req, fieldData(p, map[string]interface{}{
+		"wallet_id": "w1", "psbt": encoded, "network": "regtest", "finalize": true,
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || resp.IsError() {
+		t.Fatalf("resp=%v want success.", resp)
+	}
+	if resp.Data["complete"] != true || resp.Data["txid"] == "" {
+		t.Fatalf("data=%v want complete transaction.", resp.Data)
+	}
+	signedPSBT, err := psbt.NewFromRawBytes(strings.NewReader(resp.Data["psbt"].(string)), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx, err := psbt.Extract(signedPSBT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prevOuts := txscript.NewCannedPrevOutputFetcher(script, 50_000)
+	vm, err := txscript.NewEngine(script, tx, 0, txscript.StandardVerifyFlags, nil, txscript.NewTxSigHashes(tx, prevOuts), 50_000, prevOuts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.Execute(); err != nil {
+		t.Fatalf("script verification: %v", err)
+	}
+	records, _, _, err := journal.Verify(ctx, s, storagekey.WalletBitcoinJournalPrefix("w1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].Operation != model.JournalOpBitcoinSignPSBT || records[0].TxHash != resp.Data["txid"] {
+		t.Fatalf("records=%+v want one sign-psbt record with the txid.", records)
+	}
+
+	for name, raw := range map[string]map[string]interface{}{
+		"mainnet":     {"wallet_id": "w1", "psbt": encoded},
+		"fingerprint": {"wallet_id": "w1", "psbt": mustEncode(t, fingerprint+1), "network": "regtest"},
+		"malformed":   {"wallet_id": "w1", "psbt": "bm90IGEgcHNidA==", "network": "regtest"},
+		"missing":     {"wallet_id": "w2", "psbt": encoded, "network": "regtest"},
+	} {
+		resp, err := handleBitcoinSignPSBT(ctx, req, fieldData(p, raw))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if resp == nil || !resp.IsError() {
+			t.Fatalf("%s: resp=%v want error response.", name, resp)
+		}
+	}
+	resp, err = pathBitcoinJournalVerify().Callbacks[logical.ReadOperation](ctx, req, fieldData(pathBitcoinJournalVerify(), map[string]interface{}{
+		"wallet_id": "w1",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Data["valid"] != true || resp.Data["records"] != 1 {
+		t.Fatalf("verify=%v want rejected requests unrecorded.", resp.Data)
+	}
+}
+
+// TestHandleBitcoinSignPSBT_signingModeDisabled verifies allowed_signing_modes can turn PSBT signing off.
+func TestHandleBitcoinSignPSBT_signingModeDisabled(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	mustPutWalletSeed(ctx, t, s, "w1")
+	entry, err := logical.StorageEntryJSON(storagekey.ConfigKey(), &model.Config{AllowedSigningModes: []string{model.SigningModeEIP1559}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Put(ctx, entry); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := handleBitcoinSignPSBT(ctx, &logical.Request{Storage: s}, fieldData(pathBitcoinSignPSBT(new(sync.Map)), map[string]interface{}{
+		"wallet_id": "w1", "psbt": mustEncode(t, 0), "network": "regtest",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || !resp.IsError() {
+		t.Fatalf("resp=%v want signing mode error.", resp)
+	}
+}
+
+// mustEncode returns testSpendPSBT for fingerprint as base64.
+func mustEncode(t *testing.T, fingerprint uint32) string {
+	t.Helper()
+	packet, _ := testSpendPSBT(t, fingerprint)
+	encoded, err := packet.B64Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return encoded
+}
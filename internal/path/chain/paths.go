@@ -0,0 +1,193 @@
+// Package chain implements the chains/ paths that register permitted chains and their signing policies.
+package chain
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/logical"
+
+	"github.com/bsostech/vault-blockchain/internal/model"
+	"github.com/bsostech/vault-blockchain/internal/path/storagekey"
+)
+
+// Paths returns the chain policy paths.
+func Paths() []*framework.Path {
+	return []*framework.Path{
+		pathListChains(),
+		pathChain(),
+	}
+}
+
+// pathListChains registers LIST on chains/.
+func pathListChains() *framework.Path {
+	return &framework.Path{
+		Pattern:      "chains/?",
+		HelpSynopsis: "List registered chain IDs.",
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.ListOperation: handleListChains,
+		},
+	}
+}
+
+// pathChain registers read/write/delete on chains/:chain_id.
+func pathChain() *framework.Path {
+	return &framework.Path{
+		Pattern:      "chains/(?P<chain_id>\\d+)",
+		HelpSynopsis: "Register a permitted chain and its signing policy.",
+		Fields: map[string]*framework.FieldSchema{
+			"chain_id": {
+				Type:        framework.TypeString,
+				Description: "Chain ID (decimal). 0 permits EIP-7702 authorizations valid on every chain.",
+			},
+			"name": {
+				Type:        framework.TypeString,
+				Description: "Human-readable chain name; used in policy errors. Required.",
+			},
+			"max_value": {
+				Type:        framework.TypeString,
+				Description: "Max value per transaction in wei (decimal). Empty means no limit.",
+			},
+			"max_gas_price": {
+				Type:        framework.TypeString,
+				Description: "Max gas_price (legacy, eip2930) or max_fee_per_gas (eip1559, blob, eip7702) in wei (decimal). Empty means no limit.",
+			},
+			"allow_contract_creation": {
+				Type:        framework.TypeBool,
+				Description: "Allow transactions without a recipient (contract creation). Default false.",
+			},
+		},
+		ExistenceCheck: existenceChain,
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.ReadOperation:   handleChainRead,
+			logical.CreateOperation: handleChainWrite,
+			logical.UpdateOperation: handleChainWrite,
+			logical.DeleteOperation: handleChainDelete,
+		},
+	}
+}
+
+// chainIDFromRequest returns the chain_id path field, which must be the canonical decimal form CheckTx looks up:
+// "01" would be stored apart from "1" and never match a transaction.
+func chainIDFromRequest(data *framework.FieldData) (string, *logical.Response) {
+	chainID := model.NewFieldDataWrapper(data).GetString("chain_id", "")
+	if bi, ok := new(big.Int).SetString(chainID, 10); !ok || bi.String() != chainID {
+		return "", logical.ErrorResponse("chain_id must be a decimal integer without leading zeros")
+	}
+	return chainID, nil
+}
+
+// existenceChain reports whether a policy is registered for chain_id.
+func existenceChain(ctx context.Context, req *logical.Request, data *framework.FieldData) (bool, error) {
+	chainID, errResp := chainIDFromRequest(data)
+	if errResp != nil {
+		return false, nil
+	}
+	p, err := ReadPolicy(ctx, req.Storage, chainID)
+	if err != nil {
+		return false, err
+	}
+	return p != nil, nil
+}
+
+// handleListChains returns the registered chain IDs, sorted numerically.
+func handleListChains(ctx context.Context, req *logical.Request, _ *framework.FieldData) (*logical.Response, error) {
+	children, err := req.Storage.List(ctx, storagekey.ChainsListPrefix())
+	if err != nil {
+		return nil, fmt.Errorf("list chain policies: %w", err)
+	}
+	ids := make([]string, 0, len(children))
+	for _, child := range children {
+		if id := strings.TrimSuffix(child, "/"); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		a, _ := new(big.Int).SetString(ids[i], 10)
+		b, _ := new(big.Int).SetString(ids[j], 10)
+		if a == nil || b == nil {
+			return ids[i] < ids[j]
+		}
+		return a.Cmp(b) < 0
+	})
+	return logical.ListResponse(ids), nil
+}
+
+// handleChainRead returns the policy registered for chain_id.
+func handleChainRead(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	chainID, errResp := chainIDFromRequest(data)
+	if errResp != nil {
+		return errResp, nil
+	}
+	p, err := ReadPolicy(ctx, req.Storage, chainID)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return nil, nil
+	}
+	return &logical.Response{Data: policyResponseData(chainID, p)}, nil
+}
+
+// handleChainWrite merges the supplied fields into the chain's policy and persists it.
+// Fields that are not supplied keep their stored value; an empty limit removes it.
+func handleChainWrite(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	chainID, errResp := chainIDFromRequest(data)
+	if errResp != nil {
+		return errResp, nil
+	}
+	p, err := ReadPolicy(ctx, req.Storage, chainID)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		p = &model.ChainPolicy{}
+	}
+	if raw, ok := data.GetOk("name"); ok {
+		p.Name = strings.TrimSpace(raw.(string))
+	}
+	if raw, ok := data.GetOk("max_value"); ok {
+		p.MaxValue = strings.TrimSpace(raw.(string))
+	}
+	if raw, ok := data.GetOk("max_gas_price"); ok {
+		p.MaxGasPrice = strings.TrimSpace(raw.(string))
+	}
+	if raw, ok := data.GetOk("allow_contract_creation"); ok {
+		p.AllowContractCreation = raw.(bool)
+	}
+
+	if err := p.Validate(); err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	if err := WritePolicy(ctx, req.Storage, chainID, p); err != nil {
+		return nil, err
+	}
+	return &logical.Response{Data: policyResponseData(chainID, p)}, nil
+}
+
+// handleChainDelete unregisters chain_id.
+func handleChainDelete(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	chainID, errResp := chainIDFromRequest(data)
+	if errResp != nil {
+		return errResp, nil
+	}
+	if err := req.Storage.Delete(ctx, storagekey.ChainKey(chainID)); err != nil {
+		return nil, fmt.Errorf("delete chain policy %s: %w", chainID, err)
+	}
+	return nil, nil
+}
+
+// policyResponseData builds the Vault response map for a chain policy.
+func policyResponseData(chainID string, p *model.ChainPolicy) map[string]interface{} {
+	return map[string]interface{}{
+		"chain_id":                chainID,
+		"name":                    p.Name,
+		"max_value":               p.MaxValue,
+		"max_gas_price":           p.MaxGasPrice,
+		"allow_contract_creation": p.AllowContractCreation,
+	}
+}
@@ -0,0 +1,191 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package chain
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/logical"
+
+	"github.com/bsostech/vault-blockchain/internal/model"
+)
+
+// chainFieldData builds FieldData using the chains/:chain_id path schema.
+func chainFieldData(raw map[string]interface{}) *framework.FieldData {
+	return &framework.FieldData{Raw: raw, Schema: pathChain().Fields}
+}
+
+// TestHandleChain_writeReadListDelete verifies the chain policy lifecycle and partial-write merging.
+func TestHandleChain_writeReadListDelete(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	req := &logical.Request{Storage: new(logical.InmemStorage)}
+
+	for _, raw := range []map[string]interface{}{
+		{"chain_id": "11155111", "name": "sepolia"},
+		{"chain_id": "1", "name": "mainnet", "max_value": "1000", "allow_contract_creation": true},
+		{"chain_id": "1", "max_gas_price": "50"},
+	} {
+		resp, err := handleChainWrite(ctx, req, chainFieldData(raw))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp == nil || resp.IsError() {
+			t.Fatalf("write %v: resp=%v want success.", raw, resp)
+		}
+	}
+
+	resp, err := handleChainRead(ctx, req, chainFieldData(map[string]interface{}{"chain_id": "1"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Data["name"] != "mainnet" || resp.Data["max_value"] != "1000" || resp.Data["max_gas_price"] != "50" ||
+		resp.Data["allow_contract_creation"] != true {
+		t.Fatalf("data=%v want merged policy.", resp.Data)
+	}
+
+	resp, err = handleListChains(ctx, req, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys, _ := resp.Data["keys"].([]string)
+	if len(keys) != 2 || keys[0] != "1" || keys[1] != "11155111" {
+		t.Fatalf("keys=%v want [1 11155111].", keys)
+	}
+
+	if _, err := handleChainDelete(ctx, req, chainFieldData(map[string]interface{}{"chain_id": "1"})); err != nil {
+		t.Fatal(err)
+	}
+	resp, err = handleChainRead(ctx, req, chainFieldData(map[string]interface{}{"chain_id": "1"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp != nil {
+		t.Fatalf("resp=%v want nil after delete.", resp)
+	}
+}
+
+// TestHandleChainWrite_rejectsInvalid verifies a missing name and bad limits return logical errors.
+func TestHandleChainWrite_rejectsInvalid(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	req := &logical.Request{Storage: new(logical.InmemStorage)}
+	for _, raw := range []map[string]interface{}{
+		{"chain_id": "1"},
+		{"chain_id": "1", "name": "mainnet", "max_value": "abc"},
+		{"chain_id": "00", "name": "zero"},
+		{"chain_id": "01", "name": "mainnet"},
+	} {
+		resp, err := handleChainWrite(ctx, req, chainFieldData(raw))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp == nil || !resp.IsError() {
+			t.Fatalf("write %v: resp=%v want logical error.", raw, resp)
+		}
+	}
+}
+
+// TestHandleChain_nonCanonicalChainID verifies chain IDs with leading zeros are refused on every operation, so a
+// policy can only be stored under the key CheckTx looks up.
+func TestHandleChain_nonCanonicalChainID(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	req := &logical.Request{Storage: s}
+	if err := WritePolicy(ctx, s, "1", &model.ChainPolicy{Name: "mainnet"}); err != nil {
+		t.Fatal(err)
+	}
+	fd := chainFieldData(map[string]interface{}{"chain_id": "01", "name": "mainnet"})
+	for name, h := range map[string]framework.OperationFunc{
+		"read":   handleChainRead,
+		"write":  handleChainWrite,
+		"delete": handleChainDelete,
+	} {
+		resp, err := h(ctx, req, fd)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if resp == nil || !resp.IsError() {
+			t.Fatalf("%s: resp=%v want logical error.", name, resp)
+		}
+	}
+	if exists, err := existenceChain(ctx, req, fd); err != nil || exists {
+		t.Fatalf("exists=%v err=%v want false.", exists, err)
+	}
+	if keys, _ := s.List(ctx, "chains/"); len(keys) != 1 || keys[0] != "1" {
+		t.Fatalf("keys=%v want only chain 1.", keys)
+	}
+}
+
+// TestCheckTx_unregisteredChain verifies every chain is permitted until one is registered.
+func TestCheckTx_unregisteredChain(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	in := &model.TxPolicyInput{ChainID: big.NewInt(5), Value: big.NewInt(0)}
+	if err := CheckTx(ctx, s, in); err != nil {
+		t.Fatalf("no chains registered: %v", err)
+	}
+	if err := WritePolicy(ctx, s, "1", &model.ChainPolicy{Name: "mainnet"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := CheckTx(ctx, s, in); !errors.Is(err, model.ErrChainPolicyViolation) {
+		t.Fatalf("err=%v want ErrChainPolicyViolation.", err)
+	}
+}
+
+// TestCheckAuthorization_chainZero verifies chain 0 authorizations need chains/0 even with no chain registered,
+// and that other chains follow CheckTx.
+func TestCheckAuthorization_chainZero(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	delegate := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	if err := CheckAuthorization(ctx, s, big.NewInt(0), delegate); !errors.Is(err, model.ErrChainPolicyViolation) {
+		t.Fatalf("unregistered chain 0: err=%v want ErrChainPolicyViolation.", err)
+	}
+	if err := CheckAuthorization(ctx, s, big.NewInt(5), delegate); err != nil {
+		t.Fatalf("no chains registered: %v", err)
+	}
+	resp, err := handleChainWrite(ctx, &logical.Request{Storage: s}, chainFieldData(map[string]interface{}{
+		"chain_id": "0", "name": "every chain",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || resp.IsError() {
+		t.Fatalf("write chain 0: resp=%v want success.", resp)
+	}
+	if err := CheckAuthorization(ctx, s, big.NewInt(0), delegate); err != nil {
+		t.Fatalf("registered chain 0: %v", err)
+	}
+	if err := CheckAuthorization(ctx, s, big.NewInt(5), delegate); !errors.Is(err, model.ErrChainPolicyViolation) {
+		t.Fatalf("unregistered chain 5: err=%v want ErrChainPolicyViolation.", err)
+	}
+}
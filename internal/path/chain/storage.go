@@ -0,0 +1,136 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package chain
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/hashicorp/vault/sdk/logical"
+
+	"github.com/bsostech/vault-blockchain/internal/ethutil"
+	"github.com/bsostech/vault-blockchain/internal/model"
+	"github.com/bsostech/vault-blockchain/internal/path/storagekey"
+)
+
+// ReadPolicy loads the policy registered for chainID, or returns nil when the chain is not registered.
+func ReadPolicy(ctx context.Context, s logical.Storage, chainID string) (*model.ChainPolicy, error) {
+	entry, err := s.Get(ctx, storagekey.ChainKey(chainID))
+	if err != nil {
+		return nil, fmt.Errorf("get chain policy %s: %w", chainID, err)
+	}
+	if entry == nil {
+		return nil, nil
+	}
+	var p model.ChainPolicy
+	if err := entry.DecodeJSON(&p); err != nil {
+		return nil, fmt.Errorf("decode chain policy %s: %w", chainID, err)
+	}
+	return &p, nil
+}
+
+// WritePolicy persists p for chainID. Callers validate it first.
+func WritePolicy(ctx context.Context, s logical.Storage, chainID string, p *model.ChainPolicy) error {
+	entry, err := logical.StorageEntryJSON(storagekey.ChainKey(chainID), p)
+	if err != nil {
+		return fmt.Errorf("encode chain policy %s: %w", chainID, err)
+	}
+	if err := s.Put(ctx, entry); err != nil {
+		return fmt.Errorf("put chain policy %s: %w", chainID, err)
+	}
+	return nil
+}
+
+// CheckTx enforces the registered chain policies on a transaction about to be signed.
+//
+// While no chain is registered every chain is permitted; once any chain is registered, transactions
+// for unregistered chains are rejected. Rejections wrap model.ErrChainPolicyViolation.
+func CheckTx(ctx context.Context, s logical.Storage, in *model.TxPolicyInput) error {
+	if in == nil || in.ChainID == nil {
+		return nil
+	}
+	p, err := ReadPolicy(ctx, s, in.ChainID.String())
+	if err != nil {
+		return err
+	}
+	if p != nil {
+		return p.Check(in)
+	}
+	registered, err := s.List(ctx, storagekey.ChainsListPrefix())
+	if err != nil {
+		return fmt.Errorf("list chain policies: %w", err)
+	}
+	if len(registered) > 0 {
+		return fmt.Errorf("%w: chain %s is not registered under chains/", model.ErrChainPolicyViolation, in.ChainID.String())
+	}
+	return nil
+}
+
+// AuthorizationChainID is the chain ID of an EIP-7702 authorization that is valid on every chain.
+const AuthorizationChainID = "0"
+
+// CheckAuthorization enforces the registered chain policies on an EIP-7702 authorization for chainID that
+// delegates to delegate. Chain ID 0 makes the authorization valid on every chain, so it is rejected unless
+// chains/0 is registered, even while no other chain is. Rejections wrap model.ErrChainPolicyViolation.
+func CheckAuthorization(ctx context.Context, s logical.Storage, chainID *big.Int, delegate common.Address) error {
+	if chainID == nil {
+		return nil
+	}
+	if chainID.Sign() == 0 {
+		p, err := ReadPolicy(ctx, s, AuthorizationChainID)
+		if err != nil {
+			return err
+		}
+		if p == nil {
+			return fmt.Errorf("%w: chain 0 (every chain) is not registered under chains/", model.ErrChainPolicyViolation)
+		}
+	}
+	return CheckTx(ctx, s, &model.TxPolicyInput{ChainID: chainID, To: &delegate})
+}
+
+// DelegatesFromRequest returns the delegation addresses of the request's EIP-7702 authorization_list for
+// TxPolicyInput.Delegates. A list that does not parse yields none; signing rejects it afterwards.
+func DelegatesFromRequest(wrapper *model.FieldDataWrapper) []common.Address {
+	authList, err := ethutil.ParseAuthorizationListJSON(wrapper.GetString("authorization_list", ""))
+	if err != nil {
+		return nil
+	}
+	delegates := make([]common.Address, 0, len(authList))
+	for _, auth := range authList {
+		delegates = append(delegates, auth.Address)
+	}
+	return delegates
+}
+
+// TxPolicyInputFromRequest builds the policy input for a sign-tx request.
+// feeKeys names the fee-cap field and its aliases; an absent or invalid fee leaves FeeCap nil.
+func TxPolicyInputFromRequest(
+	wrapper *model.FieldDataWrapper,
+	chainID, value *big.Int,
+	to *common.Address,
+	txData []byte,
+	feeKeys ...string,
+) *model.TxPolicyInput {
+	feeCap, err := wrapper.MustGetBigIntAny(feeKeys...)
+	if err != nil {
+		feeCap = nil
+	}
+	return &model.TxPolicyInput{ChainID: chainID, Value: value, FeeCap: feeCap, To: to, Data: txData}
+}
@@ -0,0 +1,197 @@
+// Package config implements the mount-level config path.
+package config
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/logical"
+
+	"github.com/bsostech/vault-blockchain/internal/model"
+	"github.com/bsostech/vault-blockchain/internal/path/storagekey"
+)
+
+// Paths returns the config path.
+func Paths() []*framework.Path {
+	return []*framework.Path{
+		pathConfig(),
+	}
+}
+
+// pathConfig registers read/write/delete on config.
+func pathConfig() *framework.Path {
+	return &framework.Path{
+		Pattern:      "config",
+		HelpSynopsis: "Read, write or reset mount-level limits, allow-lists and derivation defaults.",
+		Fields: map[string]*framework.FieldSchema{
+			"max_batch_derived_accounts": {
+				Type:        framework.TypeString,
+				Description: "Max derived accounts per accounts/batch request (decimal). Default 10000.",
+			},
+			"max_bulk_read_derived_span": {
+				Type:        framework.TypeString,
+				Description: "Max inclusive start..end span for derived account range reads (decimal). Default 10000.",
+			},
+			"mnemonic_strength": {
+				Type:        framework.TypeString,
+				Description: "Entropy bits for generated mnemonics: 128, 160, 192, 224 or 256. Default 256 (24 words).",
+			},
+			"default_gas_limit": {
+				Type:        framework.TypeString,
+				Description: "Gas limit used when a sign-tx request omits gas_limit (decimal). Default 21000.",
+			},
+			"allowed_chain_ids": {
+				Type:        framework.TypeCommaStringSlice,
+				Description: "Chain IDs (decimal) sign-tx and sign-authorization may use. Empty allows any.",
+			},
+			"allowed_signing_modes": {
+				Type:        framework.TypeCommaStringSlice,
+				Description: "Signing modes to allow (legacy, eip2930, eip1559, blob, eip7702, sign, sign-message, sign-eip712, sign-authorization, bitcoin-psbt, solana-tx). Empty allows all.",
+			},
+			"default_derivation_path": {
+				Type:        framework.TypeString,
+				Description: "Derivation path template for new wallets; {index} marks the address index, otherwise it is appended. Default m/44'/60'/0'/0.",
+			},
+			"deletion_retention": {
+				Type:        framework.TypeDurationSecond,
+				Description: "How long soft-deleted wallets and accounts stay restorable (e.g. 720h). Default 30 days.",
+			},
+			"allow_key_export": {
+				Type:        framework.TypeBool,
+				Description: "Allow accounts/:name/export to return keystore v3 JSON for single-key accounts. Default false.",
+			},
+			"replacement_fee_bump_percent": {
+				Type:        framework.TypeString,
+				Description: "Percentage by which sign-tx/replace and sign-tx/cancel raise fees (decimal, at least 10). Default 10.",
+			},
+		},
+		ExistenceCheck: existenceConfig,
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.ReadOperation:   handleConfigRead,
+			logical.CreateOperation: handleConfigWrite,
+			logical.UpdateOperation: handleConfigWrite,
+			logical.DeleteOperation: handleConfigDelete,
+		},
+	}
+}
+
+// existenceConfig reports whether a config entry is stored.
+func existenceConfig(ctx context.Context, req *logical.Request, _ *framework.FieldData) (bool, error) {
+	cfg, err := ReadStored(ctx, req.Storage)
+	if err != nil {
+		return false, err
+	}
+	return cfg != nil, nil
+}
+
+// handleConfigRead returns the effective config (stored values with defaults filled in).
+func handleConfigRead(ctx context.Context, req *logical.Request, _ *framework.FieldData) (*logical.Response, error) {
+	cfg, err := Read(ctx, req.Storage)
+	if err != nil {
+		return nil, err
+	}
+	return &logical.Response{Data: configResponseData(cfg)}, nil
+}
+
+// handleConfigWrite merges the supplied fields into the stored config and persists it.
+// Fields that are not supplied keep their stored value; an empty string resets a field to its default.
+func handleConfigWrite(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	cfg, err := ReadStored(ctx, req.Storage)
+	if err != nil {
+		return nil, err
+	}
+	if cfg == nil {
+		cfg = &model.Config{}
+	}
+
+	intFields := map[string]*int{
+		"max_batch_derived_accounts":   &cfg.MaxBatchDerivedAccounts,
+		"max_bulk_read_derived_span":   &cfg.MaxBulkReadDerivedSpan,
+		"mnemonic_strength":            &cfg.MnemonicStrength,
+		"replacement_fee_bump_percent": &cfg.ReplacementFeeBumpPercent,
+	}
+	for key, dst := range intFields {
+		raw, ok := data.GetOk(key)
+		if !ok {
+			continue
+		}
+		v, err := parseOptionalUint(raw.(string), 31)
+		if err != nil {
+			return logical.ErrorResponse("%s must be a non-negative decimal integer", key), nil
+		}
+		*dst = int(v)
+	}
+	if raw, ok := data.GetOk("default_gas_limit"); ok {
+		v, err := parseOptionalUint(raw.(string), 64)
+		if err != nil {
+			return logical.ErrorResponse("default_gas_limit must be a non-negative decimal integer"), nil
+		}
+		cfg.DefaultGasLimit = v
+	}
+	if raw, ok := data.GetOk("allowed_chain_ids"); ok {
+		cfg.AllowedChainIDs = raw.([]string)
+	}
+	if raw, ok := data.GetOk("allowed_signing_modes"); ok {
+		cfg.AllowedSigningModes = raw.([]string)
+	}
+	if raw, ok := data.GetOk("default_derivation_path"); ok {
+		cfg.DefaultDerivationPath = strings.TrimSpace(raw.(string))
+	}
+	if raw, ok := data.GetOk("deletion_retention"); ok {
+		cfg.DeletionRetentionSeconds = int64(raw.(int))
+	}
+	if raw, ok := data.GetOk("allow_key_export"); ok {
+		cfg.AllowKeyExport = raw.(bool)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	if err := Write(ctx, req.Storage, cfg); err != nil {
+		return nil, err
+	}
+	return &logical.Response{Data: configResponseData(cfg.WithDefaults())}, nil
+}
+
+// handleConfigDelete removes the stored config so every setting falls back to its default.
+func handleConfigDelete(ctx context.Context, req *logical.Request, _ *framework.FieldData) (*logical.Response, error) {
+	if err := req.Storage.Delete(ctx, storagekey.ConfigKey()); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+// parseOptionalUint parses a decimal field value; the empty string means 0 (use the default).
+func parseOptionalUint(raw string, bitSize int) (uint64, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return 0, nil
+	}
+	return strconv.ParseUint(raw, 10, bitSize)
+}
+
+// configResponseData builds the Vault response map for an effective config.
+func configResponseData(cfg *model.Config) map[string]interface{} {
+	chainIDs := cfg.AllowedChainIDs
+	if chainIDs == nil {
+		chainIDs = []string{}
+	}
+	modes := cfg.AllowedSigningModes
+	if modes == nil {
+		modes = []string{}
+	}
+	return map[string]interface{}{
+		"max_batch_derived_accounts":   cfg.MaxBatchDerivedAccounts,
+		"max_bulk_read_derived_span":   cfg.MaxBulkReadDerivedSpan,
+		"mnemonic_strength":            cfg.MnemonicStrength,
+		"default_gas_limit":            cfg.DefaultGasLimit,
+		"allowed_chain_ids":            chainIDs,
+		"allowed_signing_modes":        modes,
+		"default_derivation_path":      cfg.DefaultDerivationPath,
+		"deletion_retention":           cfg.DeletionRetentionSeconds,
+		"allow_key_export":             cfg.AllowKeyExport,
+		"replacement_fee_bump_percent": cfg.ReplacementFeeBumpPercent,
+	}
+}
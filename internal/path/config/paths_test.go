@@ -0,0 +1,161 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/logical"
+
+	"github.com/bsostech/vault-blockchain/internal/model"
+)
+
+// configFieldData builds FieldData using the config path schema.
+func configFieldData(raw map[string]interface{}) *framework.FieldData {
+	return &framework.FieldData{Raw: raw, Schema: pathConfig().Fields}
+}
+
+// TestHandleConfig_defaultsWhenUnset verifies read returns the compile-time defaults without a stored entry.
+func TestHandleConfig_defaultsWhenUnset(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	req := &logical.Request{Storage: new(logical.InmemStorage)}
+	resp, err := handleConfigRead(ctx, req, configFieldData(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Data["max_batch_derived_accounts"] != model.DefaultMaxBatchDerivedAccounts {
+		t.Fatalf("max_batch_derived_accounts=%v want %d.", resp.Data["max_batch_derived_accounts"], model.DefaultMaxBatchDerivedAccounts)
+	}
+	if resp.Data["default_gas_limit"] != model.DefaultGasLimit {
+		t.Fatalf("default_gas_limit=%v want %d.", resp.Data["default_gas_limit"], model.DefaultGasLimit)
+	}
+	if resp.Data["default_derivation_path"] != model.DefaultEthereumDerivationPath {
+		t.Fatalf("default_derivation_path=%v want %s.", resp.Data["default_derivation_path"], model.DefaultEthereumDerivationPath)
+	}
+}
+
+// TestHandleConfig_writeMergeDelete verifies partial writes merge into the stored entry and delete resets it.
+func TestHandleConfig_writeMergeDelete(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	req := &logical.Request{Storage: s}
+
+	resp, err := handleConfigWrite(ctx, req, configFieldData(map[string]interface{}{
+		"max_batch_derived_accounts": "50",
+		"allowed_chain_ids":          "1,11155111",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || resp.IsError() {
+		t.Fatalf("resp=%v want success.", resp)
+	}
+
+	resp, err = handleConfigWrite(ctx, req, configFieldData(map[string]interface{}{
+		"default_gas_limit":            "60000",
+		"deletion_retention":           "48h",
+		"allow_key_export":             true,
+		"replacement_fee_bump_percent": "25",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || resp.IsError() {
+		t.Fatalf("resp=%v want success.", resp)
+	}
+
+	cfg, err := Read(ctx, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.MaxBatchDerivedAccounts != 50 {
+		t.Fatalf("MaxBatchDerivedAccounts=%d want 50.", cfg.MaxBatchDerivedAccounts)
+	}
+	if cfg.DefaultGasLimit != 60000 {
+		t.Fatalf("DefaultGasLimit=%d want 60000.", cfg.DefaultGasLimit)
+	}
+	if cfg.DeletionRetention() != 48*time.Hour {
+		t.Fatalf("DeletionRetention=%s want 48h.", cfg.DeletionRetention())
+	}
+	if !cfg.AllowKeyExport {
+		t.Fatal("AllowKeyExport=false want true.")
+	}
+	if cfg.ReplacementFeeBumpPercent != 25 {
+		t.Fatalf("ReplacementFeeBumpPercent=%d want 25.", cfg.ReplacementFeeBumpPercent)
+	}
+	if len(cfg.AllowedChainIDs) != 2 {
+		t.Fatalf("AllowedChainIDs=%v want 2 entries.", cfg.AllowedChainIDs)
+	}
+
+	exists, err := existenceConfig(ctx, req, configFieldData(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !exists {
+		t.Fatal("expected config to exist after write.")
+	}
+
+	if _, err := handleConfigDelete(ctx, req, configFieldData(nil)); err != nil {
+		t.Fatal(err)
+	}
+	stored, err := ReadStored(ctx, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored != nil {
+		t.Fatalf("stored=%+v want nil after delete.", stored)
+	}
+}
+
+// TestHandleConfigWrite_invalidReturnsLogicalError verifies validation failures are logical errors and nothing is stored.
+func TestHandleConfigWrite_invalidReturnsLogicalError(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	req := &logical.Request{Storage: s}
+
+	for _, raw := range []map[string]interface{}{
+		{"allowed_signing_modes": "eth_sign"},
+		{"mnemonic_strength": "100"},
+		{"max_bulk_read_derived_span": "-1"},
+		{"default_derivation_path": "44'/60'"},
+	} {
+		resp, err := handleConfigWrite(ctx, req, configFieldData(raw))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp == nil || !resp.IsError() {
+			t.Fatalf("raw=%v resp=%v want logical error response.", raw, resp)
+		}
+	}
+	stored, err := ReadStored(ctx, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored != nil {
+		t.Fatalf("stored=%+v want nil.", stored)
+	}
+}
@@ -0,0 +1,66 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package config
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/vault/sdk/logical"
+
+	"github.com/bsostech/vault-blockchain/internal/model"
+	"github.com/bsostech/vault-blockchain/internal/path/storagekey"
+)
+
+// ReadStored loads the stored config entry, or returns nil when none has been written.
+func ReadStored(ctx context.Context, s logical.Storage) (*model.Config, error) {
+	entry, err := s.Get(ctx, storagekey.ConfigKey())
+	if err != nil {
+		return nil, fmt.Errorf("get config: %w", err)
+	}
+	if entry == nil {
+		return nil, nil
+	}
+	var cfg model.Config
+	if err := entry.DecodeJSON(&cfg); err != nil {
+		return nil, fmt.Errorf("decode config: %w", err)
+	}
+	return &cfg, nil
+}
+
+// Read returns the effective mount config: the stored entry with unset fields filled by defaults.
+// Handlers call it at request time so config writes take effect without a plugin reload.
+func Read(ctx context.Context, s logical.Storage) (*model.Config, error) {
+	cfg, err := ReadStored(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.WithDefaults(), nil
+}
+
+// Write persists cfg. Callers validate it first.
+func Write(ctx context.Context, s logical.Storage, cfg *model.Config) error {
+	entry, err := logical.StorageEntryJSON(storagekey.ConfigKey(), cfg)
+	if err != nil {
+		return fmt.Errorf("encode config: %w", err)
+	}
+	if err := s.Put(ctx, entry); err != nil {
+		return fmt.Errorf("put config: %w", err)
+	}
+	return nil
+}
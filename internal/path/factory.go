@@ -18,20 +18,57 @@
 package path
 
 import (
+	"strings"
 	"sync"
 
 	"github.com/hashicorp/vault/sdk/framework"
 
 	"github.com/bsostech/vault-blockchain/internal/path/account"
+	"github.com/bsostech/vault-blockchain/internal/path/addressindex"
+	"github.com/bsostech/vault-blockchain/internal/path/bitcoin"
+	"github.com/bsostech/vault-blockchain/internal/path/chain"
+	"github.com/bsostech/vault-blockchain/internal/path/config"
+	"github.com/bsostech/vault-blockchain/internal/path/rpc"
+	"github.com/bsostech/vault-blockchain/internal/path/solana"
+	"github.com/bsostech/vault-blockchain/internal/path/transaction"
 	"github.com/bsostech/vault-blockchain/internal/path/wallet"
 )
 
-// GetPaths returns all framework paths.
-func GetPaths(walletMu *sync.Map) []*framework.Path {
-	acctPaths := account.Paths()
+// rawSignPatternSuffix is the pattern suffix of the raw Keccak-256 `sign` paths in both modes.
+const rawSignPatternSuffix = "/sign"
+
+// Options holds mount-level settings that change which paths are registered.
+type Options struct {
+	// DisableRawSign drops the raw Keccak-256 `sign` paths; sign-message, sign-eip712 and sign-tx remain.
+	DisableRawSign bool
+}
+
+// GetPaths returns all framework paths. walletMu and accountMu hold the per-wallet and per-account locks;
+// dispatch routes JSON-RPC facade calls back through the backend.
+func GetPaths(walletMu, accountMu *sync.Map, dispatch rpc.Dispatcher, opts Options) []*framework.Path {
+	acctPaths := account.Paths(accountMu)
 	walletPaths := wallet.Paths(walletMu)
-	out := make([]*framework.Path, 0, len(acctPaths)+len(walletPaths))
-	out = append(out, acctPaths...)
-	out = append(out, walletPaths...)
+	configPaths := config.Paths()
+	chainPaths := chain.Paths()
+	addressPaths := addressindex.Paths()
+	rpcPaths := rpc.Paths(dispatch)
+	txPaths := transaction.Paths()
+	bitcoinPaths := bitcoin.Paths(walletMu)
+	solanaPaths := solana.Paths(walletMu)
+	all := make([]*framework.Path, 0,
+		len(acctPaths)+len(walletPaths)+len(configPaths)+len(chainPaths)+len(addressPaths)+len(rpcPaths)+len(txPaths)+
+			len(bitcoinPaths)+len(solanaPaths))
+	for _, paths := range [][]*framework.Path{
+		acctPaths, walletPaths, configPaths, chainPaths, addressPaths, rpcPaths, txPaths, bitcoinPaths, solanaPaths,
+	} {
+		all = append(all, paths...)
+	}
+	out := make([]*framework.Path, 0, len(all))
+	for _, p := range all {
+		if opts.DisableRawSign && strings.HasSuffix(p.Pattern, rawSignPatternSuffix) {
+			continue
+		}
+		out = append(out, p)
+	}
 	return out
 }
@@ -18,6 +18,7 @@
 package path_test
 
 import (
+	"strings"
 	"sync"
 	"testing"
 
@@ -25,6 +26,13 @@ import (
 
 	"github.com/bsostech/vault-blockchain/internal/path"
 	"github.com/bsostech/vault-blockchain/internal/path/account"
+	"github.com/bsostech/vault-blockchain/internal/path/addressindex"
+	"github.com/bsostech/vault-blockchain/internal/path/bitcoin"
+	"github.com/bsostech/vault-blockchain/internal/path/chain"
+	"github.com/bsostech/vault-blockchain/internal/path/config"
+	"github.com/bsostech/vault-blockchain/internal/path/rpc"
+	"github.com/bsostech/vault-blockchain/internal/path/solana"
+	"github.com/bsostech/vault-blockchain/internal/path/transaction"
 	"github.com/bsostech/vault-blockchain/internal/path/wallet"
 )
 
@@ -32,13 +40,14 @@ import (
 func TestGetPaths(t *testing.T) {
 	t.Parallel()
 
-	var walletMu sync.Map
-	got := path.GetPaths(&walletMu)
+	var walletMu, accountMu sync.Map
+	got := path.GetPaths(&walletMu, &accountMu, nil, path.Options{})
 	if len(got) == 0 {
 		t.Fatal("expected non-empty paths.")
 	}
 
-	wantLen := len(account.Paths()) + len(wallet.Paths(&walletMu))
+	wantLen := len(account.Paths(&accountMu)) + len(wallet.Paths(&walletMu)) + len(config.Paths()) + len(chain.Paths()) +
+		len(addressindex.Paths()) + len(rpc.Paths(nil)) + len(transaction.Paths()) + len(bitcoin.Paths(&walletMu)) + len(solana.Paths(&walletMu))
 	if len(got) != wantLen {
 		t.Fatalf("len(got)=%d want %d.", len(got), wantLen)
 	}
@@ -76,3 +85,27 @@ func TestGetPaths(t *testing.T) {
 	_ = framework.Path{}
 }
 
+// TestGetPaths_disableRawSign verifies DisableRawSign drops only the raw sign paths.
+func TestGetPaths_disableRawSign(t *testing.T) {
+	t.Parallel()
+
+	var walletMu, accountMu sync.Map
+	all := path.GetPaths(&walletMu, &accountMu, nil, path.Options{})
+	got := path.GetPaths(&walletMu, &accountMu, nil, path.Options{DisableRawSign: true})
+	if len(got) != len(all)-2 {
+		t.Fatalf("len(got)=%d want %d.", len(got), len(all)-2)
+	}
+
+	var hasSignMessage bool
+	for _, p := range got {
+		if strings.HasSuffix(p.Pattern, "/sign") {
+			t.Fatalf("raw sign pattern %q still registered.", p.Pattern)
+		}
+		if strings.HasSuffix(p.Pattern, "/sign-message") {
+			hasSignMessage = true
+		}
+	}
+	if !hasSignMessage {
+		t.Fatal("expected sign-message paths to remain registered.")
+	}
+}
@@ -0,0 +1,195 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package journal keeps the tamper-evident signing journal of wallet-derived and single-key accounts: one
+// hash-chained record per successful sign, sign-eip712, sign-tx or decrypt request.
+package journal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/logical"
+
+	"github.com/bsostech/vault-blockchain/internal/model"
+)
+
+// PatternSeq matches a journal record sequence number path segment.
+const PatternSeq = "(?P<seq>\\d+)"
+
+// PrefixFunc resolves the journal storage prefix of the key addressed by the request path. A non-nil response
+// is returned to the caller as is.
+type PrefixFunc func(wrapper *model.FieldDataWrapper) (string, *logical.Response)
+
+// SeqField returns the schema of the seq path field.
+func SeqField() *framework.FieldSchema {
+	return &framework.FieldSchema{
+		Type:        framework.TypeString,
+		Description: "Journal record sequence number in the path.",
+	}
+}
+
+// Record appends a record of operation on payload to the journal at prefix once the wrapped handler succeeds.
+// Error responses pass through unrecorded; a failure to record withholds the response.
+func Record(
+	ctx context.Context,
+	req *logical.Request,
+	prefix, operation, address string,
+	payload []byte,
+	resp *logical.Response,
+	err error,
+) (*logical.Response, error) {
+	return RecordTx(ctx, req, prefix, operation, address, payload, "", resp, err)
+}
+
+// RecordTx is Record for a transaction signed outside the sign-tx paths, such as a PSBT; txHash identifies the
+// transaction on its own chain.
+func RecordTx(
+	ctx context.Context,
+	req *logical.Request,
+	prefix, operation, address string,
+	payload []byte,
+	txHash string,
+	resp *logical.Response,
+	err error,
+) (*logical.Response, error) {
+	if err != nil || resp == nil || resp.IsError() {
+		return resp, err
+	}
+	record := newRecord(req, time.Now(), operation, address, model.JournalPayloadDigest(payload), txHash)
+	if err := Append(ctx, req.Storage, prefix, record); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// RecordSignedTx appends a sign-tx record to the journal at prefix once the wrapped handler succeeds. The
+// operation, sender, payload (the raw signed transaction) and tx hash are taken from the sign-tx response.
+func RecordSignedTx(
+	ctx context.Context,
+	req *logical.Request,
+	prefix string,
+	resp *logical.Response,
+	err error,
+) (*logical.Response, error) {
+	if err != nil || resp == nil || resp.IsError() {
+		return resp, err
+	}
+	txType, _ := resp.Data["type"].(string)
+	from, _ := resp.Data["address_from"].(string)
+	txHash, _ := resp.Data["transaction_hash"].(string)
+	rawHex, _ := resp.Data["signed_transaction"].(string)
+	raw, err := hexutil.Decode(rawHex)
+	if err != nil {
+		return nil, fmt.Errorf("decode signed transaction for journal: %w", err)
+	}
+	record := newRecord(req, time.Now(), model.JournalOpSignTxPrefix+txType, from, model.JournalPayloadDigest(raw), txHash)
+	if err := Append(ctx, req.Storage, prefix, record); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// HandleList returns a handler listing the record sequence numbers of the journal resolved by prefixFn.
+func HandleList(prefixFn PrefixFunc) framework.OperationFunc {
+	return func(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+		prefix, errResp := prefixFn(model.NewFieldDataWrapper(data))
+		if errResp != nil {
+			return errResp, nil
+		}
+		seqs, err := ListSeqs(ctx, req.Storage, prefix)
+		if err != nil {
+			return nil, err
+		}
+		keys := make([]string, 0, len(seqs))
+		for _, seq := range seqs {
+			keys = append(keys, strconv.FormatUint(seq, 10))
+		}
+		return logical.ListResponse(keys), nil
+	}
+}
+
+// HandleRead returns a handler reading one record of the journal resolved by prefixFn.
+func HandleRead(prefixFn PrefixFunc) framework.OperationFunc {
+	return func(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+		wrapper := model.NewFieldDataWrapper(data)
+		prefix, errResp := prefixFn(wrapper)
+		if errResp != nil {
+			return errResp, nil
+		}
+		seq, err := strconv.ParseUint(wrapper.GetString("seq", ""), 10, 64)
+		if err != nil {
+			return logical.ErrorResponse("seq must be a decimal integer"), nil
+		}
+		record, err := ReadRecord(ctx, req.Storage, prefix, seq)
+		if err != nil {
+			return nil, err
+		}
+		if record == nil {
+			return nil, nil
+		}
+		return &logical.Response{Data: RecordResponseData(record)}, nil
+	}
+}
+
+// HandleVerify returns a handler checking the hash chain of the journal resolved by prefixFn. A broken chain is
+// reported in the response data rather than as an error.
+func HandleVerify(prefixFn PrefixFunc) framework.OperationFunc {
+	return func(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+		prefix, errResp := prefixFn(model.NewFieldDataWrapper(data))
+		if errResp != nil {
+			return errResp, nil
+		}
+		records, head, brokenAt, err := Verify(ctx, req.Storage, prefix)
+		if err != nil && !errors.Is(err, model.ErrJournalBroken) {
+			return nil, err
+		}
+		out := map[string]interface{}{
+			"valid":   err == nil,
+			"records": len(records),
+		}
+		if head != nil {
+			out["head_seq"] = head.Seq
+			out["head_hash"] = head.Hash
+		}
+		if err != nil {
+			out["broken_at"] = brokenAt
+			out["error"] = err.Error()
+		}
+		return &logical.Response{Data: out}, nil
+	}
+}
+
+// RecordResponseData builds the response fields of a journal record.
+func RecordResponseData(r *model.JournalRecord) map[string]interface{} {
+	return map[string]interface{}{
+		"seq":            r.Seq,
+		"time":           time.Unix(r.Time, 0).UTC().Format(time.RFC3339),
+		"entity_id":      r.EntityID,
+		"operation":      r.Operation,
+		"address":        r.Address,
+		"payload_digest": r.PayloadDigest,
+		"tx_hash":        r.TxHash,
+		"prev_hash":      r.PrevHash,
+		"hash":           r.Hash,
+	}
+}
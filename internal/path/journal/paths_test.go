@@ -0,0 +1,156 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package journal
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/logical"
+
+	"github.com/bsostech/vault-blockchain/internal/model"
+	"github.com/bsostech/vault-blockchain/internal/path/storagekey"
+)
+
+const testPrefix = "accounts/alice/journal/"
+
+// testPrefixFunc resolves every request to testPrefix.
+func testPrefixFunc(*model.FieldDataWrapper) (string, *logical.Response) {
+	return testPrefix, nil
+}
+
+// testFieldData builds FieldData carrying the seq path field.
+func testFieldData(raw map[string]interface{}) *framework.FieldData {
+	return &framework.FieldData{Raw: raw, Schema: map[string]*framework.FieldSchema{"seq": SeqField()}}
+}
+
+// mustRecordSigns appends n successful sign records to testPrefix.
+func mustRecordSigns(ctx context.Context, t *testing.T, req *logical.Request, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		resp, err := Record(ctx, req, testPrefix, model.JournalOpSign, "0xabc", []byte{byte(i)},
+			&logical.Response{Data: map[string]interface{}{"signature": "0x01"}}, nil)
+		if err != nil || resp == nil {
+			t.Fatalf("resp=%v err=%v", resp, err)
+		}
+	}
+}
+
+// verifyData runs HandleVerify against testPrefix.
+func verifyData(ctx context.Context, t *testing.T, req *logical.Request) map[string]interface{} {
+	t.Helper()
+	resp, err := HandleVerify(testPrefixFunc)(ctx, req, testFieldData(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.Data
+}
+
+// TestRecord_listReadVerify verifies records chain in order, error responses are not recorded and the
+// list, read and verify handlers report them.
+func TestRecord_listReadVerify(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	req := &logical.Request{Storage: new(logical.InmemStorage), EntityID: "entity-1"}
+	mustRecordSigns(ctx, t, req, 2)
+	if _, err := Record(ctx, req, testPrefix, model.JournalOpSign, "0xabc", nil, logical.ErrorResponse("denied"), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := HandleList(testPrefixFunc)(ctx, req, testFieldData(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys, _ := resp.Data["keys"].([]string)
+	if len(keys) != 2 || keys[0] != "1" || keys[1] != "2" {
+		t.Fatalf("keys=%v want [1 2].", keys)
+	}
+
+	resp, err = HandleRead(testPrefixFunc)(ctx, req, testFieldData(map[string]interface{}{"seq": "2"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, err := ReadRecord(ctx, req.Storage, testPrefix, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Data["entity_id"] != "entity-1" || resp.Data["operation"] != model.JournalOpSign || resp.Data["prev_hash"] != first.Hash {
+		t.Fatalf("data=%v want entity-1 sign linked to record 1.", resp.Data)
+	}
+	if resp.Data["payload_digest"] != model.JournalPayloadDigest([]byte{1}) {
+		t.Fatalf("payload_digest=%v", resp.Data["payload_digest"])
+	}
+
+	resp, err = HandleRead(testPrefixFunc)(ctx, req, testFieldData(map[string]interface{}{"seq": "3"}))
+	if err != nil || resp != nil {
+		t.Fatalf("resp=%v err=%v want not found.", resp, err)
+	}
+
+	if got := verifyData(ctx, t, req); got["valid"] != true || got["records"] != 2 || got["head_seq"] != uint64(2) {
+		t.Fatalf("verify=%v want valid with 2 records.", got)
+	}
+}
+
+// TestVerify_detectsTampering verifies an edited record and a deleted head are reported, while a record left past
+// the head by an interrupted append is ignored.
+func TestVerify_detectsTampering(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	req := &logical.Request{Storage: new(logical.InmemStorage)}
+	mustRecordSigns(ctx, t, req, 3)
+
+	orphan := &model.JournalRecord{Seq: 4, Operation: model.JournalOpSign}
+	entry, err := logical.StorageEntryJSON(storagekey.JournalRecordKey(testPrefix, 4), orphan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := req.Storage.Put(ctx, entry); err != nil {
+		t.Fatal(err)
+	}
+	if got := verifyData(ctx, t, req); got["valid"] != true || got["records"] != 3 {
+		t.Fatalf("verify=%v want interrupted append ignored.", got)
+	}
+
+	second, err := ReadRecord(ctx, req.Storage, testPrefix, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second.TxHash = "0xdead"
+	entry, err = logical.StorageEntryJSON(storagekey.JournalRecordKey(testPrefix, 2), second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := req.Storage.Put(ctx, entry); err != nil {
+		t.Fatal(err)
+	}
+	if got := verifyData(ctx, t, req); got["valid"] != false || got["broken_at"] != uint64(2) {
+		t.Fatalf("verify=%v want broken at 2.", got)
+	}
+
+	other := &logical.Request{Storage: new(logical.InmemStorage)}
+	mustRecordSigns(ctx, t, other, 2)
+	if err := other.Storage.Delete(ctx, storagekey.JournalHeadKey(testPrefix)); err != nil {
+		t.Fatal(err)
+	}
+	if got := verifyData(ctx, t, other); got["valid"] != false {
+		t.Fatalf("verify=%v want missing head reported.", got)
+	}
+}
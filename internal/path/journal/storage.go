@@ -0,0 +1,156 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package journal
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/vault/sdk/logical"
+
+	"github.com/bsostech/vault-blockchain/internal/model"
+	"github.com/bsostech/vault-blockchain/internal/path/storagekey"
+)
+
+// ReadHead loads the head of the journal at prefix, or returns nil for an empty journal.
+func ReadHead(ctx context.Context, s logical.Storage, prefix string) (*model.JournalHead, error) {
+	entry, err := s.Get(ctx, storagekey.JournalHeadKey(prefix))
+	if err != nil {
+		return nil, fmt.Errorf("get journal head %s: %w", prefix, err)
+	}
+	if entry == nil {
+		return nil, nil
+	}
+	var head model.JournalHead
+	if err := entry.DecodeJSON(&head); err != nil {
+		return nil, fmt.Errorf("decode journal head %s: %w", prefix, err)
+	}
+	return &head, nil
+}
+
+// ReadRecord loads record seq of the journal at prefix, or returns nil if it is absent.
+func ReadRecord(ctx context.Context, s logical.Storage, prefix string, seq uint64) (*model.JournalRecord, error) {
+	entry, err := s.Get(ctx, storagekey.JournalRecordKey(prefix, seq))
+	if err != nil {
+		return nil, fmt.Errorf("get journal record %s%d: %w", prefix, seq, err)
+	}
+	if entry == nil {
+		return nil, nil
+	}
+	var record model.JournalRecord
+	if err := entry.DecodeJSON(&record); err != nil {
+		return nil, fmt.Errorf("decode journal record %s%d: %w", prefix, seq, err)
+	}
+	return &record, nil
+}
+
+// ListSeqs returns the sequence numbers stored in the journal at prefix in ascending order. Entries whose name
+// is not a sequence number are skipped.
+func ListSeqs(ctx context.Context, s logical.Storage, prefix string) ([]uint64, error) {
+	keys, err := s.List(ctx, storagekey.JournalRecordsPrefix(prefix))
+	if err != nil {
+		return nil, fmt.Errorf("list journal records %s: %w", prefix, err)
+	}
+	seqs := make([]uint64, 0, len(keys))
+	for _, k := range keys {
+		seq, err := strconv.ParseUint(k, 10, 64)
+		if err != nil {
+			continue
+		}
+		seqs = append(seqs, seq)
+	}
+	sort.Slice(seqs, func(i, j int) bool { return seqs[i] < seqs[j] })
+	return seqs, nil
+}
+
+// Append links record to the journal at prefix, stores it and advances the head. The record is written before
+// the head, so an interrupted append leaves the head on the previous record and the next append overwrites it.
+// Callers must serialize appends to the same journal.
+func Append(ctx context.Context, s logical.Storage, prefix string, record *model.JournalRecord) error {
+	head, err := ReadHead(ctx, s, prefix)
+	if err != nil {
+		return err
+	}
+	if err := record.Link(head); err != nil {
+		return err
+	}
+	entry, err := logical.StorageEntryJSON(storagekey.JournalRecordKey(prefix, record.Seq), record)
+	if err != nil {
+		return fmt.Errorf("encode journal record %s%d: %w", prefix, record.Seq, err)
+	}
+	if err := s.Put(ctx, entry); err != nil {
+		return fmt.Errorf("put journal record %s%d: %w", prefix, record.Seq, err)
+	}
+	entry, err = logical.StorageEntryJSON(storagekey.JournalHeadKey(prefix), record.Head())
+	if err != nil {
+		return fmt.Errorf("encode journal head %s: %w", prefix, err)
+	}
+	if err := s.Put(ctx, entry); err != nil {
+		return fmt.Errorf("put journal head %s: %w", prefix, err)
+	}
+	return nil
+}
+
+// Verify loads every record of the journal at prefix and checks the hash chain against the head. It returns the
+// records, the head and, when the chain is broken, the first sequence number that fails with an error wrapping
+// model.ErrJournalBroken. A last record just past the head is left by an interrupted append and is ignored.
+func Verify(ctx context.Context, s logical.Storage, prefix string) ([]model.JournalRecord, *model.JournalHead, uint64, error) {
+	head, err := ReadHead(ctx, s, prefix)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	seqs, err := ListSeqs(ctx, s, prefix)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	pending := uint64(1)
+	if head != nil {
+		pending = head.Seq + 1
+	}
+	if n := len(seqs); n > 0 && seqs[n-1] == pending {
+		seqs = seqs[:n-1]
+	}
+	records := make([]model.JournalRecord, 0, len(seqs))
+	for _, seq := range seqs {
+		record, err := ReadRecord(ctx, s, prefix, seq)
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		if record == nil {
+			continue
+		}
+		records = append(records, *record)
+	}
+	brokenAt, err := model.VerifyJournal(records, head)
+	return records, head, brokenAt, err
+}
+
+// newRecord returns an unlinked record for an operation performed now on behalf of req.
+func newRecord(req *logical.Request, now time.Time, operation, address, payloadDigest, txHash string) *model.JournalRecord {
+	return &model.JournalRecord{
+		Time:          now.Unix(),
+		EntityID:      req.EntityID,
+		Operation:     operation,
+		Address:       address,
+		PayloadDigest: payloadDigest,
+		TxHash:        txHash,
+	}
+}
@@ -0,0 +1,248 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package nonces tracks the transaction nonces issued per chain and address, so concurrent sign-tx callers can
+// pass `nonce: "auto"` instead of supplying one.
+package nonces
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/logical"
+
+	"github.com/bsostech/vault-blockchain/internal/model"
+)
+
+// PatternChainID matches the chain_id path segment of the nonce paths.
+const PatternChainID = "(?P<chain_id>\\d+)"
+
+// AddressFunc resolves the signing address of the key addressed by the request path. A non-nil response is
+// returned to the caller as is.
+type AddressFunc func(ctx context.Context, s logical.Storage, wrapper *model.FieldDataWrapper) (string, *logical.Response, error)
+
+// Fields returns the field schemas shared by the nonce paths, to be merged with the key's path fields.
+func Fields() map[string]*framework.FieldSchema {
+	return map[string]*framework.FieldSchema{
+		"chain_id": {
+			Type:        framework.TypeString,
+			Description: "Chain ID (decimal) in the path.",
+		},
+		"next": {
+			Type:        framework.TypeString,
+			Description: "Next nonce to issue (decimal), e.g. the chain's pending transaction count.",
+		},
+		"nonce": {
+			Type:        framework.TypeString,
+			Description: "Issued nonce (decimal) to give back for reuse.",
+		},
+	}
+}
+
+// FromRequest returns the sign-tx nonce: the request's decimal `nonce`, or for "auto" the next nonce of the
+// tracker for address on chainID without consuming it; RecordSignedTx consumes it once the transaction is signed.
+// Callers must hold the key's lock from before this call until RecordSignedTx returns.
+func FromRequest(
+	ctx context.Context,
+	s logical.Storage,
+	wrapper *model.FieldDataWrapper,
+	chainID *big.Int,
+	address string,
+) (uint64, *logical.Response, error) {
+	if strings.TrimSpace(wrapper.GetString("nonce", "")) != model.NonceAuto {
+		return wrapper.GetUint64("nonce", 0), nil, nil
+	}
+	t, err := Read(ctx, s, chainID.String(), address)
+	if err != nil {
+		return 0, nil, err
+	}
+	if t == nil {
+		return 0, logical.ErrorResponse("no nonce tracker for %s on chain %s; set it through the nonces/%s path first",
+			address, chainID.String(), chainID.String()), nil
+	}
+	return t.Peek(), nil, nil
+}
+
+// RecordSignedTx marks the nonce of a successfully signed transaction as used in the tracker of its sender on
+// chainID, creating the tracker on first use. Error responses pass through.
+func RecordSignedTx(
+	ctx context.Context,
+	s logical.Storage,
+	chainID *big.Int,
+	resp *logical.Response,
+	err error,
+) (*logical.Response, error) {
+	if err != nil || resp == nil || resp.IsError() {
+		return resp, err
+	}
+	n, ok := resp.Data["nonce"].(uint64)
+	from, _ := resp.Data["address_from"].(string)
+	if !ok || from == "" {
+		return resp, nil
+	}
+	t, err := Read(ctx, s, chainID.String(), from)
+	if err != nil {
+		return nil, err
+	}
+	if t == nil {
+		t = &model.NonceTracker{}
+	}
+	t.MarkUsed(n)
+	if err := Write(ctx, s, chainID.String(), from, t); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// HandleRead returns a handler reading the nonce tracker of the key resolved by addressFn.
+func HandleRead(addressFn AddressFunc) framework.OperationFunc {
+	return func(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+		wrapper := model.NewFieldDataWrapper(data)
+		chainID, address, errResp, err := resolve(ctx, req.Storage, wrapper, addressFn)
+		if errResp != nil || err != nil {
+			return errResp, err
+		}
+		t, err := Read(ctx, req.Storage, chainID, address)
+		if err != nil {
+			return nil, err
+		}
+		if t == nil {
+			return nil, nil
+		}
+		return &logical.Response{Data: trackerResponseData(chainID, address, t)}, nil
+	}
+}
+
+// HandleWrite returns a handler setting the next nonce of the key resolved by addressFn, creating the tracker.
+func HandleWrite(addressFn AddressFunc) framework.OperationFunc {
+	return func(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+		wrapper := model.NewFieldDataWrapper(data)
+		chainID, address, errResp, err := resolve(ctx, req.Storage, wrapper, addressFn)
+		if errResp != nil || err != nil {
+			return errResp, err
+		}
+		next, err := strconv.ParseUint(strings.TrimSpace(wrapper.GetString("next", "")), 10, 64)
+		if err != nil {
+			return logical.ErrorResponse("next must be a non-negative decimal integer"), nil
+		}
+		t, err := Read(ctx, req.Storage, chainID, address)
+		if err != nil {
+			return nil, err
+		}
+		if t == nil {
+			t = &model.NonceTracker{}
+		}
+		t.Set(next)
+		if err := Write(ctx, req.Storage, chainID, address, t); err != nil {
+			return nil, err
+		}
+		return &logical.Response{Data: trackerResponseData(chainID, address, t)}, nil
+	}
+}
+
+// HandleReserve returns a handler issuing the next nonce of the key resolved by addressFn without signing, for
+// transactions assembled elsewhere and later signed with that explicit nonce.
+func HandleReserve(addressFn AddressFunc) framework.OperationFunc {
+	return func(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+		wrapper := model.NewFieldDataWrapper(data)
+		chainID, address, errResp, err := resolve(ctx, req.Storage, wrapper, addressFn)
+		if errResp != nil || err != nil {
+			return errResp, err
+		}
+		t, err := Read(ctx, req.Storage, chainID, address)
+		if err != nil {
+			return nil, err
+		}
+		if t == nil {
+			return logical.ErrorResponse("no nonce tracker for %s on chain %s; set it first", address, chainID), nil
+		}
+		n := t.Take()
+		if err := Write(ctx, req.Storage, chainID, address, t); err != nil {
+			return nil, err
+		}
+		out := trackerResponseData(chainID, address, t)
+		out["nonce"] = n
+		return &logical.Response{Data: out}, nil
+	}
+}
+
+// HandleRelease returns a handler giving an issued nonce of the key resolved by addressFn back for reuse, e.g.
+// after its transaction was dropped. The next auto nonce fills the lowest gap first.
+func HandleRelease(addressFn AddressFunc) framework.OperationFunc {
+	return func(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+		wrapper := model.NewFieldDataWrapper(data)
+		chainID, address, errResp, err := resolve(ctx, req.Storage, wrapper, addressFn)
+		if errResp != nil || err != nil {
+			return errResp, err
+		}
+		n, err := strconv.ParseUint(strings.TrimSpace(wrapper.GetString("nonce", "")), 10, 64)
+		if err != nil {
+			return logical.ErrorResponse("nonce must be a non-negative decimal integer"), nil
+		}
+		t, err := Read(ctx, req.Storage, chainID, address)
+		if err != nil {
+			return nil, err
+		}
+		if t == nil {
+			return logical.ErrorResponse("no nonce tracker for %s on chain %s", address, chainID), nil
+		}
+		if err := t.Release(n); err != nil {
+			if errors.Is(err, model.ErrNonceNotIssued) {
+				return logical.ErrorResponse("%s", err.Error()), nil
+			}
+			return nil, err
+		}
+		if err := Write(ctx, req.Storage, chainID, address, t); err != nil {
+			return nil, err
+		}
+		return &logical.Response{Data: trackerResponseData(chainID, address, t)}, nil
+	}
+}
+
+// resolve returns the canonical chain ID from the path and the address of the key resolved by addressFn.
+func resolve(
+	ctx context.Context,
+	s logical.Storage,
+	wrapper *model.FieldDataWrapper,
+	addressFn AddressFunc,
+) (chainID, address string, errResp *logical.Response, err error) {
+	id, ok := new(big.Int).SetString(wrapper.GetString("chain_id", ""), 10)
+	if !ok || id.Sign() <= 0 {
+		return "", "", logical.ErrorResponse("chain_id must be a positive decimal integer"), nil
+	}
+	address, errResp, err = addressFn(ctx, s, wrapper)
+	if errResp != nil || err != nil {
+		return "", "", errResp, err
+	}
+	return id.String(), address, nil, nil
+}
+
+// trackerResponseData builds the response fields of a nonce tracker.
+func trackerResponseData(chainID, address string, t *model.NonceTracker) map[string]interface{} {
+	released := make([]uint64, 0, len(t.Released))
+	released = append(released, t.Released...)
+	return map[string]interface{}{
+		"chain_id": chainID,
+		"address":  address,
+		"next":     t.Next,
+		"released": released,
+	}
+}
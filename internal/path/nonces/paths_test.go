@@ -0,0 +1,207 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package nonces
+
+import (
+	"context"
+	"math/big"
+	"slices"
+	"testing"
+
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/logical"
+
+	"github.com/bsostech/vault-blockchain/internal/model"
+)
+
+const testAddress = "0x00000000000000000000000000000000000000aa"
+
+var testChainID = big.NewInt(5)
+
+// testAddressFunc resolves every request to testAddress.
+func testAddressFunc(context.Context, logical.Storage, *model.FieldDataWrapper) (string, *logical.Response, error) {
+	return testAddress, nil, nil
+}
+
+// testFieldData builds FieldData for raw, with chain_id set to testChainID, against Fields.
+func testFieldData(raw map[string]interface{}) *framework.FieldData {
+	if raw == nil {
+		raw = map[string]interface{}{}
+	}
+	raw["chain_id"] = testChainID.String()
+	return &framework.FieldData{Raw: raw, Schema: Fields()}
+}
+
+// mustCall runs h and fails on an error or error response.
+func mustCall(ctx context.Context, t *testing.T, h framework.OperationFunc, req *logical.Request, raw map[string]interface{}) *logical.Response {
+	t.Helper()
+	resp, err := h(ctx, req, testFieldData(raw))
+	if err != nil || resp == nil || resp.IsError() {
+		t.Fatalf("resp=%v err=%v want success.", resp, err)
+	}
+	return resp
+}
+
+// signedResp builds a sign-tx response for nonce from testAddress.
+func signedResp(nonce uint64) *logical.Response {
+	return &logical.Response{Data: map[string]interface{}{"nonce": nonce, "address_from": testAddress}}
+}
+
+// TestFromRequest covers explicit nonces and "auto" with and without a tracker; auto does not consume the nonce.
+func TestFromRequest(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	fromRequest := func(nonce string) (uint64, *logical.Response) {
+		t.Helper()
+		n, errResp, err := FromRequest(ctx, s, model.NewFieldDataWrapper(testFieldData(map[string]interface{}{"nonce": nonce})), testChainID, testAddress)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return n, errResp
+	}
+
+	if n, errResp := fromRequest("7"); errResp != nil || n != 7 {
+		t.Fatalf("n=%d resp=%v want explicit 7.", n, errResp)
+	}
+	if _, errResp := fromRequest(model.NonceAuto); errResp == nil || !errResp.IsError() {
+		t.Fatalf("resp=%v want error without a tracker.", errResp)
+	}
+	if err := Write(ctx, s, testChainID.String(), testAddress, &model.NonceTracker{Next: 4, Released: []uint64{2}}); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if n, errResp := fromRequest(" auto "); errResp != nil || n != 2 {
+			t.Fatalf("n=%d resp=%v want released nonce 2, unconsumed.", n, errResp)
+		}
+	}
+	if _, err := RecordSignedTx(ctx, s, testChainID, signedResp(2), nil); err != nil {
+		t.Fatal(err)
+	}
+	if n, errResp := fromRequest(model.NonceAuto); errResp != nil || n != 4 {
+		t.Fatalf("n=%d resp=%v want 4 once 2 is signed.", n, errResp)
+	}
+	if t2, _ := Read(ctx, s, "1", testAddress); t2 != nil {
+		t.Fatalf("tracker=%+v want trackers scoped per chain.", t2)
+	}
+}
+
+// TestReserveRelease verifies released nonces are issued again lowest first, before next, and that releasing the
+// highest issued nonce lowers next instead of leaving a gap.
+func TestReserveRelease(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	req := &logical.Request{Storage: new(logical.InmemStorage)}
+	if resp, err := HandleReserve(testAddressFunc)(ctx, req, testFieldData(nil)); err != nil || resp == nil || !resp.IsError() {
+		t.Fatalf("resp=%v err=%v want error without a tracker.", resp, err)
+	}
+	mustCall(ctx, t, HandleWrite(testAddressFunc), req, map[string]interface{}{"next": "10"})
+
+	reserve := func() uint64 {
+		t.Helper()
+		return mustCall(ctx, t, HandleReserve(testAddressFunc), req, nil).Data["nonce"].(uint64)
+	}
+	for want := uint64(10); want < 15; want++ {
+		if got := reserve(); got != want {
+			t.Fatalf("reserved %d want %d.", got, want)
+		}
+	}
+	for _, n := range []string{"13", "11"} {
+		mustCall(ctx, t, HandleRelease(testAddressFunc), req, map[string]interface{}{"nonce": n})
+	}
+	resp := mustCall(ctx, t, HandleRelease(testAddressFunc), req, map[string]interface{}{"nonce": "14"})
+	if resp.Data["next"] != uint64(13) || !slices.Equal(resp.Data["released"].([]uint64), []uint64{11}) {
+		t.Fatalf("data=%v want next 13 with 11 released after the top two are given back.", resp.Data)
+	}
+	for _, want := range []uint64{11, 13, 14} {
+		if got := reserve(); got != want {
+			t.Fatalf("reserved %d want %d.", got, want)
+		}
+	}
+
+	for name, nonce := range map[string]string{"not issued": "15", "not decimal": "x"} {
+		resp, err := HandleRelease(testAddressFunc)(ctx, req, testFieldData(map[string]interface{}{"nonce": nonce}))
+		if err != nil || resp == nil || !resp.IsError() {
+			t.Fatalf("%s: resp=%v err=%v want error.", name, resp, err)
+		}
+	}
+}
+
+// TestSet_belowUsed verifies setting next below nonces already used (e.g. to the chain's pending count after
+// transactions were dropped) reissues from there and forgets released nonces at or above it.
+func TestSet_belowUsed(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	req := &logical.Request{Storage: s}
+	mustCall(ctx, t, HandleWrite(testAddressFunc), req, map[string]interface{}{"next": "0"})
+	for n := uint64(0); n < 6; n++ {
+		if _, err := RecordSignedTx(ctx, s, testChainID, signedResp(n), nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	mustCall(ctx, t, HandleRelease(testAddressFunc), req, map[string]interface{}{"nonce": "1"})
+	mustCall(ctx, t, HandleRelease(testAddressFunc), req, map[string]interface{}{"nonce": "3"})
+
+	resp := mustCall(ctx, t, HandleWrite(testAddressFunc), req, map[string]interface{}{"next": "2"})
+	if resp.Data["next"] != uint64(2) || !slices.Equal(resp.Data["released"].([]uint64), []uint64{1}) {
+		t.Fatalf("data=%v want next 2 keeping only released nonce 1.", resp.Data)
+	}
+	if resp, err := HandleWrite(testAddressFunc)(ctx, req, testFieldData(map[string]interface{}{"next": "-1"})); err != nil || !resp.IsError() {
+		t.Fatalf("resp=%v err=%v want error for a negative next.", resp, err)
+	}
+
+	// A later signature at a used nonce moves next past it again.
+	if _, err := RecordSignedTx(ctx, s, testChainID, signedResp(5), nil); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := Read(ctx, s, testChainID.String(), testAddress); got == nil || got.Next != 6 || got.Peek() != 1 {
+		t.Fatalf("tracker=%+v want next 6 with 1 still released.", got)
+	}
+}
+
+// TestRecordSignedTx_errorPassesThrough verifies failed signing requests do not mark a nonce used or create a
+// tracker.
+func TestRecordSignedTx_errorPassesThrough(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	cases := []struct {
+		name string
+		resp *logical.Response
+		err  error
+	}{
+		{"error response", logical.ErrorResponse("rejected"), nil},
+		{"error", signedResp(3), context.Canceled},
+		{"nil response", nil, nil},
+		{"no nonce", &logical.Response{Data: map[string]interface{}{"address_from": testAddress}}, nil},
+	}
+	for _, tc := range cases {
+		resp, err := RecordSignedTx(ctx, s, testChainID, tc.resp, tc.err)
+		if resp != tc.resp || err != tc.err {
+			t.Fatalf("%s: resp=%v err=%v want passed through.", tc.name, resp, err)
+		}
+		if got, err := Read(ctx, s, testChainID.String(), testAddress); err != nil || got != nil {
+			t.Fatalf("%s: tracker=%+v err=%v want none.", tc.name, got, err)
+		}
+	}
+}
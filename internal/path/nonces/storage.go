@@ -0,0 +1,94 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package nonces
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/vault/sdk/logical"
+
+	"github.com/bsostech/vault-blockchain/internal/model"
+	"github.com/bsostech/vault-blockchain/internal/path/storagekey"
+)
+
+// Read loads the nonce tracker of address on chainID, or returns nil if none is stored.
+func Read(ctx context.Context, s logical.Storage, chainID, address string) (*model.NonceTracker, error) {
+	key, err := model.NormalizeAddress(address)
+	if err != nil {
+		return nil, err
+	}
+	entry, err := s.Get(ctx, storagekey.NonceKey(chainID, key))
+	if err != nil {
+		return nil, fmt.Errorf("get nonce tracker %s/%s: %w", chainID, key, err)
+	}
+	if entry == nil {
+		return nil, nil
+	}
+	var t model.NonceTracker
+	if err := entry.DecodeJSON(&t); err != nil {
+		return nil, fmt.Errorf("decode nonce tracker %s/%s: %w", chainID, key, err)
+	}
+	return &t, nil
+}
+
+// Write stores the nonce tracker of address on chainID.
+func Write(ctx context.Context, s logical.Storage, chainID, address string, t *model.NonceTracker) error {
+	key, err := model.NormalizeAddress(address)
+	if err != nil {
+		return err
+	}
+	entry, err := logical.StorageEntryJSON(storagekey.NonceKey(chainID, key), t)
+	if err != nil {
+		return fmt.Errorf("encode nonce tracker %s/%s: %w", chainID, key, err)
+	}
+	if err := s.Put(ctx, entry); err != nil {
+		return fmt.Errorf("put nonce tracker %s/%s: %w", chainID, key, err)
+	}
+	return nil
+}
+
+// ListChainIDs returns the chain IDs that have at least one nonce tracker.
+func ListChainIDs(ctx context.Context, s logical.Storage) ([]string, error) {
+	keys, err := s.List(ctx, storagekey.NoncesListPrefix())
+	if err != nil {
+		return nil, fmt.Errorf("list nonce chains: %w", err)
+	}
+	chainIDs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if strings.HasSuffix(key, "/") {
+			chainIDs = append(chainIDs, strings.TrimSuffix(key, "/"))
+		}
+	}
+	return chainIDs, nil
+}
+
+// DeleteAll removes the nonce trackers of address on each of chainIDs, as listed by ListChainIDs.
+func DeleteAll(ctx context.Context, s logical.Storage, chainIDs []string, address string) error {
+	key, err := model.NormalizeAddress(address)
+	if err != nil {
+		return err
+	}
+	for _, chainID := range chainIDs {
+		if err := s.Delete(ctx, storagekey.NonceKey(chainID, key)); err != nil {
+			return fmt.Errorf("delete nonce tracker %s/%s: %w", chainID, key, err)
+		}
+	}
+	return nil
+}
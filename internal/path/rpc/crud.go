@@ -0,0 +1,360 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/logical"
+
+	"github.com/bsostech/vault-blockchain/internal/model"
+)
+
+// caller dispatches JSON-RPC methods to the signing paths of the resolved account, carrying the identity
+// and storage of the parent rpc request. scope is the wallet or single-key account named in the rpc path; only
+// its addresses resolve, so the ACL on the rpc path governs every key the call can reach.
+type caller struct {
+	dispatch Dispatcher
+	parent   *logical.Request
+	scope    *model.AddressOwner
+}
+
+// accounts implements eth_accounts: every address of scope the mount can sign for.
+func (c *caller) accounts(ctx context.Context) (interface{}, error) {
+	signers, err := listSigners(ctx, c.parent.Storage, c.scope)
+	if err != nil {
+		return nil, err
+	}
+	addresses := make([]string, 0, len(signers))
+	for _, s := range signers {
+		addresses = append(addresses, s.address.Hex())
+	}
+	return addresses, nil
+}
+
+// ethSign implements eth_sign [address, data]: an EIP-191 personal_sign signature over hex data.
+func (c *caller) ethSign(ctx context.Context, params []interface{}) (interface{}, error) {
+	if len(params) < 2 {
+		return nil, invalidParams("eth_sign expects [address, data]")
+	}
+	data, ok := params[1].(string)
+	if !ok {
+		return nil, invalidParams("data must be a hex string")
+	}
+	if _, err := hexutil.Decode(data); err != nil {
+		return nil, invalidParams("invalid data hex: %s", err.Error())
+	}
+	return c.signMessage(ctx, params[0], map[string]interface{}{"data": data})
+}
+
+// personalSign implements personal_sign [data, address]. Data that is not 0x-prefixed hex is signed as UTF-8
+// text, matching wallet providers.
+func (c *caller) personalSign(ctx context.Context, params []interface{}) (interface{}, error) {
+	if len(params) < 2 {
+		return nil, invalidParams("personal_sign expects [data, address]")
+	}
+	data, ok := params[0].(string)
+	if !ok {
+		return nil, invalidParams("data must be a string")
+	}
+	fields := map[string]interface{}{"message": data}
+	if _, err := hexutil.Decode(data); err == nil {
+		fields = map[string]interface{}{"data": data}
+	}
+	return c.signMessage(ctx, params[1], fields)
+}
+
+// signMessage signs fields through the sign-message path of from and returns the signature.
+func (c *caller) signMessage(ctx context.Context, from interface{}, fields map[string]interface{}) (interface{}, error) {
+	owner, err := c.resolve(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.call(ctx, owner.PathPrefix()+"/sign-message", signingFields(owner, fields))
+	if err != nil {
+		return nil, err
+	}
+	return resp.Data["signature"], nil
+}
+
+// signTypedData implements eth_signTypedData_v4 [address, typedData]; typedData may be a JSON string or object.
+func (c *caller) signTypedData(ctx context.Context, params []interface{}) (interface{}, error) {
+	if len(params) < 2 {
+		return nil, invalidParams("eth_signTypedData_v4 expects [address, typedData]")
+	}
+	payload, ok := params[1].(string)
+	if !ok {
+		encoded, err := json.Marshal(params[1])
+		if err != nil {
+			return nil, invalidParams("invalid typed data: %s", err.Error())
+		}
+		payload = string(encoded)
+	}
+	owner, err := c.resolve(ctx, params[0])
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.call(ctx, owner.PathPrefix()+"/sign-eip712", signingFields(owner, map[string]interface{}{"payload": payload}))
+	if err != nil {
+		return nil, err
+	}
+	return resp.Data["signature"], nil
+}
+
+// signTxResult is the eth_signTransaction result: the signed wire encoding and the decoded transaction.
+type signTxResult struct {
+	Raw string                `json:"raw"`
+	Tx  *ethtypes.Transaction `json:"tx"`
+}
+
+// signTransaction implements eth_signTransaction [tx]. The tx type follows the fee fields: maxFeePerGas or
+// maxPriorityFeePerGas select eip1559, accessList alone eip2930, otherwise legacy; an explicit type overrides.
+func (c *caller) signTransaction(ctx context.Context, params []interface{}) (interface{}, error) {
+	if len(params) < 1 {
+		return nil, invalidParams("eth_signTransaction expects [transaction]")
+	}
+	tx, ok := params[0].(map[string]interface{})
+	if !ok {
+		return nil, invalidParams("transaction must be an object")
+	}
+	owner, err := c.resolve(ctx, tx["from"])
+	if err != nil {
+		return nil, err
+	}
+	txType, fields, err := signTxFields(tx)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.call(ctx, owner.PathPrefix()+"/sign-tx/"+txType, signingFields(owner, fields))
+	if err != nil {
+		return nil, err
+	}
+	raw, _ := resp.Data["signed_transaction"].(string)
+	rawBytes, err := hexutil.Decode(raw)
+	if err != nil {
+		return nil, fmt.Errorf("decode signed transaction: %w", err)
+	}
+	var signed ethtypes.Transaction
+	if err := signed.UnmarshalBinary(rawBytes); err != nil {
+		return nil, fmt.Errorf("decode signed transaction: %w", err)
+	}
+	return &signTxResult{Raw: raw, Tx: &signed}, nil
+}
+
+// signTxFields maps a JSON-RPC transaction object onto the sign-tx fields and selects the sign-tx type.
+func signTxFields(tx map[string]interface{}) (string, map[string]interface{}, error) {
+	fields := make(map[string]interface{})
+	quantities := []struct{ param, field string }{
+		{"chainId", "chain_id"},
+		{"nonce", "nonce"},
+		{"gas", "gas_limit"},
+		{"value", "value"},
+		{"gasPrice", "gas_price"},
+		{"maxFeePerGas", "max_fee_per_gas"},
+		{"maxPriorityFeePerGas", "max_priority_fee_per_gas"},
+	}
+	for _, q := range quantities {
+		raw, ok := tx[q.param]
+		if !ok || raw == nil {
+			continue
+		}
+		n, err := parseQuantity(raw)
+		if err != nil {
+			return "", nil, invalidParams("invalid %s: %s", q.param, err.Error())
+		}
+		fields[q.field] = n.String()
+	}
+	if _, ok := fields["chain_id"]; !ok {
+		return "", nil, invalidParams("chainId is required")
+	}
+	if _, ok := fields["nonce"]; !ok {
+		return "", nil, invalidParams("nonce is required")
+	}
+	if to, ok := tx["to"].(string); ok && to != "" {
+		if !common.IsHexAddress(to) {
+			return "", nil, invalidParams("invalid to address %q", to)
+		}
+		fields["to"] = to
+	}
+	input, _ := tx["input"].(string)
+	if input == "" {
+		input, _ = tx["data"].(string)
+	}
+	if input != "" {
+		fields["data"] = input
+	}
+	if al, ok := tx["accessList"]; ok && al != nil {
+		encoded, err := json.Marshal(al)
+		if err != nil {
+			return "", nil, invalidParams("invalid accessList: %s", err.Error())
+		}
+		fields["access_list"] = string(encoded)
+	}
+
+	txType := "legacy"
+	switch {
+	case fields["max_fee_per_gas"] != nil || fields["max_priority_fee_per_gas"] != nil:
+		txType = "eip1559"
+	case fields["access_list"] != nil:
+		txType = "eip2930"
+	}
+	if raw, ok := tx["type"]; ok && raw != nil {
+		n, err := parseQuantity(raw)
+		if err != nil {
+			return "", nil, invalidParams("invalid type: %s", err.Error())
+		}
+		switch n.Uint64() {
+		case ethtypes.LegacyTxType:
+			txType = "legacy"
+		case ethtypes.AccessListTxType:
+			txType = "eip2930"
+		case ethtypes.DynamicFeeTxType:
+			txType = "eip1559"
+		default:
+			return "", nil, invalidParams("transaction type %s is not supported", n.String())
+		}
+	}
+	return txType, fields, nil
+}
+
+// parseQuantity parses a JSON-RPC quantity: a 0x-prefixed hex string, a decimal string or a JSON number.
+func parseQuantity(raw interface{}) (*big.Int, error) {
+	var s string
+	switch v := raw.(type) {
+	case string:
+		s = strings.TrimSpace(v)
+	case json.Number:
+		s = v.String()
+	case float64:
+		if v < 0 || v != float64(uint64(v)) {
+			return nil, fmt.Errorf("%v is not a non-negative integer", v)
+		}
+		return new(big.Int).SetUint64(uint64(v)), nil
+	default:
+		return nil, fmt.Errorf("unsupported quantity %v", raw)
+	}
+	digits, base := s, 10
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		digits, base = s[2:], 16
+	}
+	n, ok := new(big.Int).SetString(digits, base)
+	if !ok || n.Sign() < 0 {
+		return nil, fmt.Errorf("%q is not a non-negative integer", s)
+	}
+	return n, nil
+}
+
+// resolve returns the account of scope that owns a from address parameter.
+func (c *caller) resolve(ctx context.Context, from interface{}) (*model.AddressOwner, error) {
+	addrStr, _ := from.(string)
+	if !common.IsHexAddress(addrStr) {
+		return nil, invalidParams("from must be a hex address")
+	}
+	owner, err := resolveAddress(ctx, c.parent.Storage, addrStr, c.scope)
+	if errors.Is(err, errAddressNotIndexed) || errors.Is(err, errAddressNotOwned) {
+		return nil, &rpcError{Code: codeSignerRejected, Message: fmt.Sprintf("unknown account %s", addrStr)}
+	}
+	if errors.Is(err, errAddressRetired) {
+		return nil, &rpcError{Code: codeSignerRejected, Message: err.Error()}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return owner, nil
+}
+
+// call dispatches fields to path and turns error responses from the target path into codeSignerRejected errors.
+func (c *caller) call(ctx context.Context, path string, fields map[string]interface{}) (*logical.Response, error) {
+	resp, err := c.forward(ctx, path, fields)
+	if err != nil {
+		return nil, err
+	}
+	if resp.IsError() {
+		return nil, &rpcError{Code: codeSignerRejected, Message: resp.Error().Error()}
+	}
+	return resp, nil
+}
+
+// forward dispatches fields to path as an update with the parent request's storage and identity, returning the
+// target path's response unchanged.
+func (c *caller) forward(ctx context.Context, path string, fields map[string]interface{}) (*logical.Response, error) {
+	req := &logical.Request{
+		ID:            c.parent.ID,
+		Operation:     logical.UpdateOperation,
+		Path:          path,
+		Data:          fields,
+		Storage:       c.parent.Storage,
+		Connection:    c.parent.Connection,
+		DisplayName:   c.parent.DisplayName,
+		EntityID:      c.parent.EntityID,
+		MountPoint:    c.parent.MountPoint,
+		MountType:     c.parent.MountType,
+		MountAccessor: c.parent.MountAccessor,
+	}
+	resp, err := c.dispatch(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("dispatch %s: %w", path, err)
+	}
+	if resp == nil {
+		return nil, fmt.Errorf("dispatch %s: empty response", path)
+	}
+	return resp, nil
+}
+
+// makeHandleByAddressSignTx returns the by-address/:address/sign-tx/:tx_type handler. It resolves the owner of
+// address through the address index and forwards the request body to the owner's sign-tx path, returning that
+// path's response unchanged. A non-nil scopeFn names the wallet or account from the path that must own address.
+func makeHandleByAddressSignTx(dispatch Dispatcher, scopeFn func(*framework.FieldData) *model.AddressOwner) framework.OperationFunc {
+	return func(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+		wrapper := model.NewFieldDataWrapper(data)
+		addr, err := wrapper.MustGetString("address")
+		if err != nil {
+			return logical.ErrorResponse("address is required"), nil
+		}
+		txType, err := wrapper.MustGetString("tx_type")
+		if err != nil {
+			return logical.ErrorResponse("tx_type is required"), nil
+		}
+		var scope *model.AddressOwner
+		if scopeFn != nil {
+			scope = scopeFn(data)
+		}
+		owner, err := resolveAddress(ctx, req.Storage, addr, scope)
+		if errors.Is(err, errAddressNotIndexed) || errors.Is(err, errAddressNotOwned) || errors.Is(err, errAddressRetired) {
+			return logical.ErrorResponse("%s", err.Error()), nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		fields := make(map[string]interface{}, len(req.Data))
+		for k, v := range req.Data {
+			fields[k] = v
+		}
+		c := &caller{dispatch: dispatch, parent: req, scope: scope}
+		return c.forward(ctx, owner.PathPrefix()+"/sign-tx/"+txType, signingFields(owner, fields))
+	}
+}
@@ -0,0 +1,294 @@
+// Package rpc implements the rpc paths, an Ethereum JSON-RPC 2.0 signer facade over the signing paths of one
+// wallet or single-key account, and the by-address paths that sign with whichever account owns an address.
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/logical"
+
+	"github.com/bsostech/vault-blockchain/internal/model"
+	"github.com/bsostech/vault-blockchain/internal/path/addressindex"
+)
+
+// jsonRPCVersion is the only protocol version the facade accepts.
+const jsonRPCVersion = "2.0"
+
+// JSON-RPC 2.0 error codes returned by the facade.
+const (
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	// codeSignerRejected reports a request refused by the target signing path (policy, limits, bad fields).
+	codeSignerRejected = -32000
+)
+
+// Dispatcher routes a synthesized request to another path of the same backend.
+type Dispatcher func(ctx context.Context, req *logical.Request) (*logical.Response, error)
+
+// Paths returns the JSON-RPC facade and by-address paths. dispatch forwards each call to the signing path of the
+// resolved account.
+func Paths(dispatch Dispatcher) []*framework.Path {
+	return []*framework.Path{
+		pathWalletRPC(dispatch),
+		pathAccountRPC(dispatch),
+		pathByAddressSignTx(dispatch),
+		pathWalletByAddressSignTx(dispatch),
+		pathAccountByAddressSignTx(dispatch),
+	}
+}
+
+// patternByAddressSignTx matches by-address/:address/sign-tx/:tx_type.
+const patternByAddressSignTx = "by-address/" + addressindex.PatternAddress + "/sign-tx/(?P<tx_type>legacy|eip2930|eip1559|blob|eip7702)"
+
+// walletScope limits a call to the derived accounts of the wallet_id in the path.
+func walletScope(data *framework.FieldData) *model.AddressOwner {
+	return &model.AddressOwner{Type: model.AddressOwnerWallet, WalletID: data.Get("wallet_id").(string)}
+}
+
+// accountScope limits a call to the single-key account name in the path.
+func accountScope(data *framework.FieldData) *model.AddressOwner {
+	return &model.AddressOwner{Type: model.AddressOwnerAccount, Name: data.Get("name").(string)}
+}
+
+// byAddressFields returns the path fields shared by the by-address paths.
+func byAddressFields() map[string]*framework.FieldSchema {
+	return map[string]*framework.FieldSchema{
+		"address": {
+			Type:        framework.TypeString,
+			Description: "Hex address in the path (any letter case).",
+		},
+		"tx_type": {
+			Type:        framework.TypeString,
+			Description: "Transaction type in the path: legacy, eip2930, eip1559, blob or eip7702.",
+		},
+	}
+}
+
+// pathByAddressSignTx registers by-address/:address/sign-tx/:tx_type, which takes the same fields as the
+// sign-tx paths of the owning wallet-derived or single-key account.
+func pathByAddressSignTx(dispatch Dispatcher) *framework.Path {
+	return &framework.Path{
+		Pattern:      patternByAddressSignTx,
+		HelpSynopsis: "Sign a transaction with the wallet-derived or single-key account that owns the address.",
+		Fields:       byAddressFields(),
+		// The remaining fields belong to the target sign-tx path and are validated there.
+		TakesArbitraryInput: true,
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			// Without an ExistenceCheck Vault maps every write to Update.
+			logical.UpdateOperation: makeHandleByAddressSignTx(dispatch, nil),
+		},
+	}
+}
+
+// pathWalletByAddressSignTx registers wallets/:wallet_id/by-address/:address/sign-tx/:tx_type, which signs only
+// when a derived account of the wallet owns the address. Policies can scope it by the wallet prefix.
+func pathWalletByAddressSignTx(dispatch Dispatcher) *framework.Path {
+	fields := byAddressFields()
+	fields["wallet_id"] = &framework.FieldSchema{
+		Type:        framework.TypeString,
+		Description: "Wallet that must own the address.",
+	}
+	return &framework.Path{
+		Pattern:             "wallets/" + framework.GenericNameRegex("wallet_id") + "/" + patternByAddressSignTx,
+		HelpSynopsis:        "Sign a transaction with the derived account of the wallet that owns the address.",
+		Fields:              fields,
+		TakesArbitraryInput: true,
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.UpdateOperation: makeHandleByAddressSignTx(dispatch, walletScope),
+		},
+	}
+}
+
+// pathAccountByAddressSignTx registers accounts/:name/by-address/:address/sign-tx/:tx_type, which signs only
+// when the address is a key version of the account at or above min_signing_version. Policies can scope it by the
+// account prefix.
+func pathAccountByAddressSignTx(dispatch Dispatcher) *framework.Path {
+	fields := byAddressFields()
+	fields["name"] = &framework.FieldSchema{
+		Type:        framework.TypeString,
+		Description: "Single-key account that must own the address.",
+	}
+	return &framework.Path{
+		Pattern:             "accounts/" + framework.GenericNameRegex("name") + "/" + patternByAddressSignTx,
+		HelpSynopsis:        "Sign a transaction with the single-key account if it owns the address.",
+		Fields:              fields,
+		TakesArbitraryInput: true,
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.UpdateOperation: makeHandleByAddressSignTx(dispatch, accountScope),
+		},
+	}
+}
+
+// pathWalletRPC registers POST on wallets/:wallet_id/rpc for JSON-RPC 2.0 signer calls with the wallet's derived
+// accounts.
+func pathWalletRPC(dispatch Dispatcher) *framework.Path {
+	fields := rpcFields()
+	fields["wallet_id"] = &framework.FieldSchema{
+		Type:        framework.TypeString,
+		Description: "Wallet whose derived accounts the call may sign with.",
+	}
+	return &framework.Path{
+		Pattern:      "wallets/" + framework.GenericNameRegex("wallet_id") + "/rpc",
+		HelpSynopsis: "Ethereum JSON-RPC 2.0 signer for a wallet's derived accounts.",
+		Fields:       fields,
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			// Without an ExistenceCheck Vault maps every write to Update.
+			logical.UpdateOperation: makeHandleRPC(dispatch, walletScope),
+		},
+	}
+}
+
+// pathAccountRPC registers POST on accounts/:name/rpc for JSON-RPC 2.0 signer calls with a single-key account.
+func pathAccountRPC(dispatch Dispatcher) *framework.Path {
+	fields := rpcFields()
+	fields["name"] = &framework.FieldSchema{
+		Type:        framework.TypeString,
+		Description: "Single-key account the call may sign with.",
+	}
+	return &framework.Path{
+		Pattern:      "accounts/" + framework.GenericNameRegex("name") + "/rpc",
+		HelpSynopsis: "Ethereum JSON-RPC 2.0 signer for a single-key account.",
+		Fields:       fields,
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			// Without an ExistenceCheck Vault maps every write to Update.
+			logical.UpdateOperation: makeHandleRPC(dispatch, accountScope),
+		},
+	}
+}
+
+// rpcFields returns the JSON-RPC 2.0 request fields shared by the rpc paths.
+func rpcFields() map[string]*framework.FieldSchema {
+	return map[string]*framework.FieldSchema{
+		"jsonrpc": {
+			Type:        framework.TypeString,
+			Description: "Protocol version; must be \"2.0\".",
+		},
+		"id": {
+			Type:        framework.TypeString,
+			Description: "Request id, echoed unchanged in the response.",
+		},
+		"method": {
+			Type:        framework.TypeString,
+			Description: "eth_accounts, eth_sign, personal_sign, eth_signTransaction or eth_signTypedData_v4.",
+		},
+		"params": {
+			Type:        framework.TypeSlice,
+			Description: "Positional method parameters.",
+		},
+	}
+}
+
+// rpcError is a JSON-RPC 2.0 error object.
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Error implements error.
+func (e *rpcError) Error() string {
+	return e.Message
+}
+
+// invalidParams returns a codeInvalidParams error with a formatted message.
+func invalidParams(format string, args ...interface{}) *rpcError {
+	return &rpcError{Code: codeInvalidParams, Message: fmt.Sprintf(format, args...)}
+}
+
+// rpcResponse is a JSON-RPC 2.0 response body. Exactly one of Result and Error is set.
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      interface{}     `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+// makeHandleRPC returns the rpc handler for the wallet or single-key account scopeFn reads from the path. id and
+// params are read from the raw request body rather than the field schema so that numeric ids and nested
+// transaction objects keep their JSON types.
+func makeHandleRPC(dispatch Dispatcher, scopeFn func(*framework.FieldData) *model.AddressOwner) framework.OperationFunc {
+	return func(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+		id := req.Data["id"]
+		if v, _ := data.GetOk("jsonrpc"); v != jsonRPCVersion {
+			return respondRPC(id, nil, &rpcError{Code: codeInvalidRequest, Message: `jsonrpc must be "2.0"`})
+		}
+		method, _ := data.GetOk("method")
+		methodStr, _ := method.(string)
+		if methodStr == "" {
+			return respondRPC(id, nil, &rpcError{Code: codeInvalidRequest, Message: "method is required"})
+		}
+		params, paramsErr := paramsFromRequest(req.Data["params"])
+		if paramsErr != nil {
+			return respondRPC(id, nil, paramsErr)
+		}
+		call := &caller{dispatch: dispatch, parent: req, scope: scopeFn(data)}
+		var result interface{}
+		var err error
+		switch methodStr {
+		case "eth_accounts":
+			result, err = call.accounts(ctx)
+		case "eth_sign":
+			result, err = call.ethSign(ctx, params)
+		case "personal_sign":
+			result, err = call.personalSign(ctx, params)
+		case "eth_signTypedData_v4":
+			result, err = call.signTypedData(ctx, params)
+		case "eth_signTransaction":
+			result, err = call.signTransaction(ctx, params)
+		default:
+			err = &rpcError{Code: codeMethodNotFound, Message: fmt.Sprintf("method %s is not supported", methodStr)}
+		}
+		var rpcErr *rpcError
+		if err != nil && !errors.As(err, &rpcErr) {
+			return nil, err
+		}
+		return respondRPC(id, result, rpcErr)
+	}
+}
+
+// paramsFromRequest normalizes params to a positional list. A JSON string (as sent by `vault write
+// params=...`) is decoded; a missing value is an empty list.
+func paramsFromRequest(raw interface{}) ([]interface{}, *rpcError) {
+	switch v := raw.(type) {
+	case nil:
+		return nil, nil
+	case []interface{}:
+		return v, nil
+	case string:
+		var params []interface{}
+		if err := json.Unmarshal([]byte(v), &params); err != nil {
+			return nil, invalidParams("params must be a JSON array: %s", err.Error())
+		}
+		return params, nil
+	default:
+		return nil, invalidParams("params must be an array")
+	}
+}
+
+// respondRPC encodes a JSON-RPC response as the raw HTTP body so JSON-RPC clients can read it directly.
+func respondRPC(id, result interface{}, rpcErr *rpcError) (*logical.Response, error) {
+	body := rpcResponse{JSONRPC: jsonRPCVersion, ID: id, Error: rpcErr}
+	if rpcErr == nil {
+		encoded, err := json.Marshal(result)
+		if err != nil {
+			return nil, fmt.Errorf("encode rpc result: %w", err)
+		}
+		body.Result = encoded
+	}
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("encode rpc response: %w", err)
+	}
+	return &logical.Response{
+		Data: map[string]interface{}{
+			logical.HTTPContentType: "application/json",
+			logical.HTTPRawBody:     raw,
+			logical.HTTPStatusCode:  http.StatusOK,
+		},
+	}, nil
+}
@@ -0,0 +1,492 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/logical"
+
+	"github.com/bsostech/vault-blockchain/internal/path/account"
+	"github.com/bsostech/vault-blockchain/internal/path/config"
+	"github.com/bsostech/vault-blockchain/internal/path/wallet"
+)
+
+const (
+	testMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	// testWalletAddress is m/44'/60'/0'/0/0 of testMnemonic.
+	testWalletAddress = "0x9858EfFD232B4033E47d90003D41EC34EcaEda94"
+	testPrivateKeyHex = "4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318"
+)
+
+// testRPCBackend returns a backend with the account, wallet, config and rpc paths over in-memory storage,
+// holding one single-key account ("alice") and one wallet-derived account ("w1" index 0).
+func testRPCBackend(t *testing.T) (*framework.Backend, logical.Storage) {
+	t.Helper()
+	var walletMu, accountMu sync.Map
+	b := &framework.Backend{BackendType: logical.TypeLogical}
+	b.Paths = append(append(append(append(b.Paths,
+		account.Paths(&accountMu)...), wallet.Paths(&walletMu)...), config.Paths()...), Paths(b.HandleRequest)...)
+	s := new(logical.InmemStorage)
+	for _, r := range []struct {
+		op   logical.Operation
+		path string
+		data map[string]interface{}
+	}{
+		{logical.CreateOperation, "accounts/alice/import", map[string]interface{}{"private_key": testPrivateKeyHex}},
+		{logical.CreateOperation, "wallets/w1/import", map[string]interface{}{"mnemonic": testMnemonic}},
+		{logical.UpdateOperation, "wallets/w1/accounts", map[string]interface{}{}},
+	} {
+		resp, err := b.HandleRequest(context.Background(), &logical.Request{Operation: r.op, Path: r.path, Data: r.data, Storage: s})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp != nil && resp.IsError() {
+			t.Fatalf("%s: %v.", r.path, resp.Error())
+		}
+	}
+	return b, s
+}
+
+// callRPC posts a JSON-RPC request to rpcPath and decodes the raw response body.
+func callRPC(t *testing.T, b *framework.Backend, s logical.Storage, rpcPath, method string, params ...interface{}) map[string]interface{} {
+	t.Helper()
+	data := map[string]interface{}{"jsonrpc": "2.0", "id": json.Number("7"), "method": method}
+	if params != nil {
+		data["params"] = params
+	}
+	resp, err := b.HandleRequest(context.Background(), &logical.Request{
+		Operation: logical.UpdateOperation, Path: rpcPath, Data: data, Storage: s,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || resp.Data[logical.HTTPStatusCode] != 200 {
+		t.Fatalf("resp=%v want raw 200 response.", resp)
+	}
+	var body map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(resp.Data[logical.HTTPRawBody].([]byte)))
+	decoder.UseNumber()
+	if err := decoder.Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body["jsonrpc"] != "2.0" || body["id"] != json.Number("7") {
+		t.Fatalf("body=%v want jsonrpc 2.0 and id 7.", body)
+	}
+	return body
+}
+
+// rpcErrorCode returns the error code of a JSON-RPC response body, or 0 when it carries a result.
+func rpcErrorCode(t *testing.T, body map[string]interface{}) int64 {
+	t.Helper()
+	e, ok := body["error"].(map[string]interface{})
+	if !ok {
+		return 0
+	}
+	code, err := e["code"].(json.Number).Int64()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return code
+}
+
+// recoverSigner returns the address that produced a 27/28-style signature over hash.
+func recoverSigner(t *testing.T, hash []byte, sigHex interface{}) common.Address {
+	t.Helper()
+	s, _ := sigHex.(string)
+	sig, err := hexutil.Decode(s)
+	if err != nil || len(sig) != 65 {
+		t.Fatalf("signature %v: want 65 bytes hex.", sigHex)
+	}
+	sig[64] -= 27
+	pub, err := crypto.SigToPub(hash, sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return crypto.PubkeyToAddress(*pub)
+}
+
+// TestRPC_accounts verifies eth_accounts lists only the keys of the wallet or account in the path: derived
+// accounts by segment and index, the latest single-key version, and nothing once the owner is deleted.
+func TestRPC_accounts(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	b, s := testRPCBackend(t)
+	write := func(path string, data map[string]interface{}) {
+		t.Helper()
+		resp, err := b.HandleRequest(ctx, &logical.Request{Operation: logical.UpdateOperation, Path: path, Data: data, Storage: s})
+		if err != nil || (resp != nil && resp.IsError()) {
+			t.Fatalf("%s: resp=%v err=%v.", path, resp, err)
+		}
+	}
+	accountsOf := func(rpcPath string) []interface{} {
+		t.Helper()
+		body := callRPC(t, b, s, rpcPath, "eth_accounts")
+		if code := rpcErrorCode(t, body); code != 0 {
+			t.Fatalf("%s: error=%v want result.", rpcPath, body["error"])
+		}
+		got, _ := body["result"].([]interface{})
+		return got
+	}
+	for i := 0; i < 10; i++ {
+		write("wallets/w1/accounts", map[string]interface{}{})
+	}
+	write("accounts/alice/rotate", nil)
+
+	got := accountsOf("wallets/w1/rpc")
+	if len(got) != 11 || got[0] != testWalletAddress {
+		t.Fatalf("result=%v want 11 derived accounts starting at %s.", got, testWalletAddress)
+	}
+	for i, addr := range got {
+		resp, err := b.HandleRequest(ctx, &logical.Request{
+			Operation: logical.ReadOperation, Path: "wallets/w1/accounts/" + strconv.Itoa(i), Storage: s,
+		})
+		if err != nil || resp == nil || !strings.EqualFold(resp.Data["address"].(string), addr.(string)) {
+			t.Fatalf("result[%d]=%v resp=%v err=%v want the address of index %d.", i, addr, resp, err, i)
+		}
+	}
+	keys := callRPC(t, b, s, "accounts/alice/rpc", "eth_accounts")
+	got, _ = keys["result"].([]interface{})
+	var versions []string
+	for v := 2; v >= 1; v-- {
+		acct, err := account.ReadSingleKeySigningAccount(ctx, s, "alice", v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		versions = append(versions, common.HexToAddress(acct.AddressStr).Hex())
+	}
+	if len(got) != 2 || got[0] != versions[0] || got[1] != versions[1] {
+		t.Fatalf("result=%v want both key versions %v, newest first.", got, versions)
+	}
+	write("accounts/alice/keys", map[string]interface{}{"min_signing_version": 2})
+	keys = callRPC(t, b, s, "accounts/alice/rpc", "eth_accounts")
+	if got, _ = keys["result"].([]interface{}); len(got) != 1 || got[0] != versions[0] {
+		t.Fatalf("result=%v want only %s above min_signing_version.", got, versions[0])
+	}
+
+	if _, err := b.HandleRequest(ctx, &logical.Request{Operation: logical.DeleteOperation, Path: "wallets/w1", Storage: s}); err != nil {
+		t.Fatal(err)
+	}
+	if got := accountsOf("wallets/w1/rpc"); len(got) != 0 {
+		t.Fatalf("result=%v want none for a deleted wallet.", got)
+	}
+	if got := accountsOf("wallets/other/rpc"); len(got) != 0 {
+		t.Fatalf("result=%v want none for a wallet without keys.", got)
+	}
+}
+
+// TestRPC_scopedToPathOwner verifies an rpc path only signs for addresses of the wallet or account it names.
+func TestRPC_scopedToPathOwner(t *testing.T) {
+	t.Parallel()
+
+	b, s := testRPCBackend(t)
+	pk, err := crypto.HexToECDSA(testPrivateKeyHex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	alice := crypto.PubkeyToAddress(pk.PublicKey).Hex()
+	for _, tc := range []struct{ rpcPath, from string }{
+		{"wallets/w1/rpc", alice},
+		{"accounts/alice/rpc", testWalletAddress},
+		{"accounts/bob/rpc", alice},
+		{"wallets/w2/rpc", testWalletAddress},
+	} {
+		body := callRPC(t, b, s, tc.rpcPath, "personal_sign", "hi", tc.from)
+		if code := rpcErrorCode(t, body); code != codeSignerRejected {
+			t.Fatalf("%s from %s: code=%d want %d.", tc.rpcPath, tc.from, code, codeSignerRejected)
+		}
+		msg := body["error"].(map[string]interface{})["message"]
+		if msg != "unknown account "+tc.from {
+			t.Fatalf("%s from %s: message=%v want unknown account.", tc.rpcPath, tc.from, msg)
+		}
+	}
+}
+
+// TestRPC_signMessages verifies personal_sign, eth_sign and eth_signTypedData_v4 resolve from and sign with its key.
+func TestRPC_signMessages(t *testing.T) {
+	t.Parallel()
+
+	b, s := testRPCBackend(t)
+	pk, err := crypto.HexToECDSA(testPrivateKeyHex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	alice := crypto.PubkeyToAddress(pk.PublicKey)
+
+	body := callRPC(t, b, s, "accounts/alice/rpc", "personal_sign", "hello vault", alice.Hex())
+	if got := recoverSigner(t, accounts.TextHash([]byte("hello vault")), body["result"]); got != alice {
+		t.Fatalf("personal_sign signer=%s want %s.", got.Hex(), alice.Hex())
+	}
+
+	body = callRPC(t, b, s, "wallets/w1/rpc", "eth_sign", testWalletAddress, "0xdeadbeef")
+	if got := recoverSigner(t, accounts.TextHash([]byte{0xde, 0xad, 0xbe, 0xef}), body["result"]); got.Hex() != testWalletAddress {
+		t.Fatalf("eth_sign signer=%s want %s.", got.Hex(), testWalletAddress)
+	}
+
+	typedData := map[string]interface{}{
+		"types": map[string]interface{}{
+			"EIP712Domain": []interface{}{map[string]interface{}{"name": "name", "type": "string"}},
+			"Mail":         []interface{}{map[string]interface{}{"name": "contents", "type": "string"}},
+		},
+		"primaryType": "Mail",
+		"domain":      map[string]interface{}{"name": "rpc-test"},
+		"message":     map[string]interface{}{"contents": "hi"},
+	}
+	body = callRPC(t, b, s, "wallets/w1/rpc", "eth_signTypedData_v4", testWalletAddress, typedData)
+	if code := rpcErrorCode(t, body); code != 0 {
+		t.Fatalf("eth_signTypedData_v4 error=%v want result.", body["error"])
+	}
+	if sig, _ := body["result"].(string); len(sig) != 132 {
+		t.Fatalf("eth_signTypedData_v4 result=%v want 65-byte signature.", body["result"])
+	}
+}
+
+// TestRPC_signTransaction verifies eth_signTransaction maps hex quantities onto sign-tx and returns raw and tx.
+func TestRPC_signTransaction(t *testing.T) {
+	t.Parallel()
+
+	b, s := testRPCBackend(t)
+	body := callRPC(t, b, s, "wallets/w1/rpc", "eth_signTransaction", map[string]interface{}{
+		"from":                 testWalletAddress,
+		"to":                   "0x000000000000000000000000000000000000dEaD",
+		"chainId":              "0xaa36a7",
+		"nonce":                "0x3",
+		"gas":                  "0x5208",
+		"value":                "0xde0b6b3a7640000",
+		"maxFeePerGas":         "0x77359400",
+		"maxPriorityFeePerGas": "0x3b9aca00",
+	})
+	if code := rpcErrorCode(t, body); code != 0 {
+		t.Fatalf("error=%v want result.", body["error"])
+	}
+	result, _ := body["result"].(map[string]interface{})
+	raw, _ := result["raw"].(string)
+	rawBytes, err := hexutil.Decode(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tx ethtypes.Transaction
+	if err := tx.UnmarshalBinary(rawBytes); err != nil {
+		t.Fatal(err)
+	}
+	if tx.Type() != ethtypes.DynamicFeeTxType || tx.Nonce() != 3 || tx.Gas() != 21000 || tx.ChainId().Uint64() != 11155111 {
+		t.Fatalf("tx type=%d nonce=%d gas=%d chain=%s want eip1559 nonce 3 gas 21000 chain 11155111.",
+			tx.Type(), tx.Nonce(), tx.Gas(), tx.ChainId())
+	}
+	from, err := ethtypes.Sender(ethtypes.LatestSignerForChainID(tx.ChainId()), &tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if from.Hex() != testWalletAddress {
+		t.Fatalf("sender=%s want %s.", from.Hex(), testWalletAddress)
+	}
+	txObj, _ := result["tx"].(map[string]interface{})
+	if txObj["hash"] != tx.Hash().Hex() {
+		t.Fatalf("tx.hash=%v want %s.", txObj["hash"], tx.Hash().Hex())
+	}
+}
+
+// TestRPC_errors verifies protocol, method, params and account errors are JSON-RPC error objects.
+func TestRPC_errors(t *testing.T) {
+	t.Parallel()
+
+	b, s := testRPCBackend(t)
+	unknown := "0x000000000000000000000000000000000000bEEF"
+	for _, tc := range []struct {
+		name   string
+		method string
+		params []interface{}
+		want   int64
+	}{
+		{"unknown method", "eth_sendTransaction", nil, codeMethodNotFound},
+		{"unknown account", "personal_sign", []interface{}{"hi", unknown}, codeSignerRejected},
+		{"bad address", "eth_sign", []interface{}{"alice", "0x00"}, codeInvalidParams},
+		{"missing params", "eth_sign", nil, codeInvalidParams},
+		{"missing chainId", "eth_signTransaction", []interface{}{map[string]interface{}{"from": testWalletAddress, "nonce": "0x0"}}, codeInvalidParams},
+		{"unsupported type", "eth_signTransaction", []interface{}{map[string]interface{}{
+			"from": testWalletAddress, "nonce": "0x0", "chainId": "0x1", "type": "0x3",
+		}}, codeInvalidParams},
+	} {
+		body := callRPC(t, b, s, "wallets/w1/rpc", tc.method, tc.params...)
+		if got := rpcErrorCode(t, body); got != tc.want {
+			t.Fatalf("%s: code=%d want %d (body %v).", tc.name, got, tc.want, body)
+		}
+	}
+
+	resp, err := b.HandleRequest(context.Background(), &logical.Request{
+		Operation: logical.UpdateOperation,
+		Path:      "wallets/w1/rpc",
+		Data:      map[string]interface{}{"jsonrpc": "1.0", "method": "eth_accounts"},
+		Storage:   s,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var body rpcResponse
+	if err := json.Unmarshal(resp.Data[logical.HTTPRawBody].([]byte), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Error == nil || body.Error.Code != codeInvalidRequest || body.ID != nil {
+		t.Fatalf("body=%+v want invalid request with null id.", body)
+	}
+}
+
+// TestByAddressSignTx verifies by-address sign-tx forwards to the owning account's sign-tx path in both modes,
+// passes its responses through, signs with the key version behind the address, and refuses unknown addresses and
+// those below min_signing_version.
+func TestByAddressSignTx(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	b, s := testRPCBackend(t)
+	pk, err := crypto.HexToECDSA(testPrivateKeyHex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	alice := crypto.PubkeyToAddress(pk.PublicKey).Hex()
+	signByAddress := func(addr string, data map[string]interface{}) *logical.Response {
+		t.Helper()
+		resp, err := b.HandleRequest(ctx, &logical.Request{
+			Operation: logical.UpdateOperation,
+			Path:      "by-address/" + addr + "/sign-tx/legacy",
+			Data:      data,
+			Storage:   s,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+	tx := map[string]interface{}{
+		"chain_id":  "1",
+		"nonce":     "0",
+		"to":        "0x000000000000000000000000000000000000dEaD",
+		"gas_price": "1000000000",
+	}
+
+	for _, addr := range []string{alice, strings.ToLower(testWalletAddress)} {
+		resp := signByAddress(addr, tx)
+		if resp == nil || resp.IsError() {
+			t.Fatalf("%s: resp=%v want signed tx.", addr, resp)
+		}
+		if !strings.EqualFold(resp.Data["address_from"].(string), addr) {
+			t.Fatalf("address_from=%v want %s.", resp.Data["address_from"], addr)
+		}
+	}
+
+	resp := signByAddress(testWalletAddress, map[string]interface{}{"nonce": "0"})
+	if resp == nil || !resp.IsError() {
+		t.Fatalf("resp=%v want the target path's error for a missing chain_id.", resp)
+	}
+	resp = signByAddress("0x000000000000000000000000000000000000bEEF", tx)
+	if resp == nil || !resp.IsError() || !strings.Contains(resp.Error().Error(), "no wallet or account") {
+		t.Fatalf("resp=%v want unknown address error.", resp)
+	}
+
+	if _, err := b.HandleRequest(ctx, &logical.Request{
+		Operation: logical.UpdateOperation, Path: "accounts/alice/rotate", Storage: s,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	tx["nonce"] = "1"
+	resp = signByAddress(alice, tx)
+	if resp == nil || resp.IsError() || !strings.EqualFold(resp.Data["address_from"].(string), alice) {
+		t.Fatalf("resp=%v want signed by the previous key version %s.", resp, alice)
+	}
+	body := callRPC(t, b, s, "accounts/alice/rpc", "personal_sign", "hi", alice)
+	if _, ok := body["result"].(string); !ok {
+		t.Fatalf("body=%v want a signature from the previous key version.", body)
+	}
+
+	if _, err := b.HandleRequest(ctx, &logical.Request{
+		Operation: logical.UpdateOperation, Path: "accounts/alice/keys", Data: map[string]interface{}{"min_signing_version": 2}, Storage: s,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	resp = signByAddress(alice, tx)
+	if resp == nil || !resp.IsError() || !strings.Contains(resp.Error().Error(), "min_signing_version") {
+		t.Fatalf("resp=%v want retired address error.", resp)
+	}
+	body = callRPC(t, b, s, "accounts/alice/rpc", "personal_sign", "hi", alice)
+	if code := rpcErrorCode(t, body); code != codeSignerRejected {
+		t.Fatalf("code=%d want %d for a retired address.", code, codeSignerRejected)
+	}
+}
+
+// TestByAddressSignTx_scoped verifies the by-address paths under a wallet or account sign only for that owner's
+// addresses, so policies can scope them by the owner's prefix.
+func TestByAddressSignTx_scoped(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	b, s := testRPCBackend(t)
+	pk, err := crypto.HexToECDSA(testPrivateKeyHex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	alice := crypto.PubkeyToAddress(pk.PublicKey).Hex()
+	tx := map[string]interface{}{
+		"chain_id":  "1",
+		"nonce":     "0",
+		"to":        "0x000000000000000000000000000000000000dEaD",
+		"gas_price": "1000000000",
+	}
+	for _, tc := range []struct {
+		prefix, address string
+		wantErr         string
+	}{
+		{"wallets/w1", testWalletAddress, ""},
+		{"accounts/alice", strings.ToLower(alice), ""},
+		{"wallets/w1", alice, "not a key of this wallet or account"},
+		{"accounts/alice", testWalletAddress, "not a key of this wallet or account"},
+		{"wallets/w2", testWalletAddress, "not a key of this wallet or account"},
+		{"accounts/alice", "0x000000000000000000000000000000000000bEEF", "no wallet or account"},
+	} {
+		resp, err := b.HandleRequest(ctx, &logical.Request{
+			Operation: logical.UpdateOperation,
+			Path:      tc.prefix + "/by-address/" + tc.address + "/sign-tx/legacy",
+			Data:      tx,
+			Storage:   s,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tc.wantErr == "" {
+			if resp == nil || resp.IsError() || !strings.EqualFold(resp.Data["address_from"].(string), tc.address) {
+				t.Fatalf("%s %s: resp=%v want signed tx.", tc.prefix, tc.address, resp)
+			}
+			continue
+		}
+		if resp == nil || !resp.IsError() || !strings.Contains(resp.Error().Error(), tc.wantErr) {
+			t.Fatalf("%s %s: resp=%v want error %q.", tc.prefix, tc.address, resp, tc.wantErr)
+		}
+	}
+}
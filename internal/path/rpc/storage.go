@@ -0,0 +1,165 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/hashicorp/vault/sdk/logical"
+
+	"github.com/bsostech/vault-blockchain/internal/model"
+	"github.com/bsostech/vault-blockchain/internal/path/account"
+	"github.com/bsostech/vault-blockchain/internal/path/addressindex"
+	"github.com/bsostech/vault-blockchain/internal/path/storagekey"
+	"github.com/bsostech/vault-blockchain/internal/path/wallet"
+)
+
+var (
+	// errAddressNotIndexed is returned when no wallet-derived or single-key account owns the address.
+	errAddressNotIndexed = errors.New("no wallet or account owns this address")
+	// errAddressNotOwned is returned when the address belongs to a wallet or account other than the one in the path.
+	errAddressNotOwned = errors.New("address is not a key of this wallet or account")
+	// errAddressRetired is returned for the address of a single-key key version below min_signing_version.
+	errAddressRetired = errors.New("address belongs to a key version below min_signing_version")
+)
+
+// signer is an address the facade can sign for and its address index entry.
+type signer struct {
+	address common.Address
+	owner   *model.AddressOwner
+}
+
+// listSigners returns the addresses of scope's live keys from the address index: the derived accounts of a
+// wallet ordered by segment and index, or the key versions of a single-key account allowed to sign, newest
+// first. A deleted wallet or account has none.
+func listSigners(ctx context.Context, s logical.Storage, scope *model.AddressOwner) ([]signer, error) {
+	addresses, err := listChildren(ctx, s, storagekey.AddressIndexListPrefix())
+	if err != nil {
+		return nil, fmt.Errorf("list address index: %w", err)
+	}
+	minSigningVersion := 0
+	if scope.Type == model.AddressOwnerWallet {
+		tomb, err := wallet.ReadWalletTombstone(ctx, s, scope.WalletID)
+		if err != nil || tomb != nil {
+			return nil, err
+		}
+	} else {
+		keyring, err := account.ReadSingleKeyAccountKeyring(ctx, s, scope.Name)
+		if errors.Is(err, account.ErrSingleKeyAccountMissing) || errors.Is(err, account.ErrSingleKeyAccountDeleted) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		minSigningVersion = keyring.MinSigningVersion
+	}
+	var out []signer
+	for _, addr := range addresses {
+		owner, err := addressindex.ReadAddressOwner(ctx, s, addr)
+		if err != nil {
+			return nil, err
+		}
+		if !scope.SameResource(owner) || (owner.Type == model.AddressOwnerAccount && owner.Version < minSigningVersion) {
+			continue
+		}
+		out = append(out, signer{address: common.HexToAddress(addr), owner: owner})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		ai, aj := parseSegment(out[i].owner.Account), parseSegment(out[j].owner.Account)
+		if ai != aj {
+			return ai < aj
+		}
+		if out[i].owner.Index != out[j].owner.Index {
+			return parseSegment(out[i].owner.Index) < parseSegment(out[j].owner.Index)
+		}
+		return out[i].owner.Version > out[j].owner.Version
+	})
+	return out, nil
+}
+
+// resolveAddress returns the wallet-derived or single-key account that owns addr, looked up in the address
+// index. With a non-nil scope, addresses owned by any other wallet or account return errAddressNotOwned. An address of a
+// single-key version below min_signing_version does not resolve. Deleted owners still resolve so the signing path
+// reports them.
+func resolveAddress(ctx context.Context, s logical.Storage, addr string, scope *model.AddressOwner) (*model.AddressOwner, error) {
+	owner, err := addressindex.ReadAddressOwner(ctx, s, addr)
+	if err != nil {
+		return nil, err
+	}
+	if owner == nil {
+		return nil, errAddressNotIndexed
+	}
+	if scope != nil && !scope.SameResource(owner) {
+		return nil, errAddressNotOwned
+	}
+	if owner.Type == model.AddressOwnerAccount {
+		keyring, err := account.ReadSingleKeyAccountKeyring(ctx, s, owner.Name)
+		switch {
+		case errors.Is(err, account.ErrSingleKeyAccountMissing), errors.Is(err, account.ErrSingleKeyAccountDeleted):
+		case err != nil:
+			return nil, err
+		case owner.Version < keyring.MinSigningVersion:
+			return nil, fmt.Errorf("%w: version %d of account %s", errAddressRetired, owner.Version, owner.Name)
+		}
+	}
+	return owner, nil
+}
+
+// listChildren lists the distinct direct children of prefix with trailing slashes removed, sorted.
+func listChildren(ctx context.Context, s logical.Storage, prefix string) ([]string, error) {
+	keys, err := s.List(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]struct{}, len(keys))
+	out := make([]string, 0, len(keys))
+	for _, key := range keys {
+		child := strings.TrimSuffix(key, "/")
+		if child == "" {
+			continue
+		}
+		if _, dup := seen[child]; dup {
+			continue
+		}
+		seen[child] = struct{}{}
+		out = append(out, child)
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+// signingFields adds the key version of a single-key owner to the fields forwarded to its signing path, so the
+// path signs with the key behind the resolved address rather than the latest one.
+func signingFields(owner *model.AddressOwner, fields map[string]interface{}) map[string]interface{} {
+	if owner.Type == model.AddressOwnerAccount {
+		fields["key_version"] = owner.Version
+	}
+	return fields
+}
+
+// parseSegment parses a decimal account segment or index; an empty segment is 0.
+func parseSegment(segment string) uint64 {
+	n, _ := strconv.ParseUint(segment, 10, 32)
+	return n
+}
@@ -0,0 +1,190 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package signedtxs keeps the transactions signed by the sign-tx paths and builds same-nonce replacements of
+// them for the sign-tx/replace (speed-up) and sign-tx/cancel paths.
+package signedtxs
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/logical"
+
+	"github.com/bsostech/vault-blockchain/internal/ethutil"
+	"github.com/bsostech/vault-blockchain/internal/model"
+	"github.com/bsostech/vault-blockchain/internal/path/config"
+)
+
+// Fields returns the field schemas of the replace and cancel paths, to be merged with the key's path fields.
+func Fields() map[string]*framework.FieldSchema {
+	return map[string]*framework.FieldSchema{
+		"transaction_hash": {
+			Type:        framework.TypeString,
+			Description: "0x hash of a transaction signed by this wallet or account, looked up in its signed-transaction records.",
+		},
+		"signed_transaction": {
+			Type:        framework.TypeString,
+			Description: "0x hex of the signed transaction to replace, instead of transaction_hash.",
+		},
+		"fee_bump_percent": {
+			Type:        framework.TypeString,
+			Description: "Percentage to raise the fees by (decimal, at least 10). Default: config replacement_fee_bump_percent.",
+		},
+	}
+}
+
+// RecordSignedTx keeps a successfully signed transaction in the records at prefix so it can later be replaced by
+// hash. Error responses pass through.
+func RecordSignedTx(ctx context.Context, s logical.Storage, prefix string, resp *logical.Response, err error) (*logical.Response, error) {
+	if err != nil || resp == nil || resp.IsError() {
+		return resp, err
+	}
+	txHash, _ := resp.Data["transaction_hash"].(string)
+	raw, _ := resp.Data["signed_transaction"].(string)
+	from, _ := resp.Data["address_from"].(string)
+	if txHash == "" || raw == "" {
+		return resp, nil
+	}
+	if err := Write(ctx, s, prefix, txHash, &model.SignedTxRecord{Raw: raw, From: from, Time: time.Now().Unix()}); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// Replacement is an unsigned same-nonce replacement built from a request, checked against the mount config.
+type Replacement struct {
+	ChainID  *big.Int
+	From     common.Address
+	Replaced common.Hash
+	Unsigned *ethtypes.Transaction
+}
+
+// Prepare resolves the transaction to replace from the request's transaction_hash, looked up in the records at
+// prefix, or signed_transaction and builds its replacement: a speed-up, or for cancel a zero-value self-transfer,
+// with fees raised by fee_bump_percent. The replacement's type and chain must be allowed by the mount config.
+func Prepare(ctx context.Context, s logical.Storage, prefix string, wrapper *model.FieldDataWrapper, cancel bool) (*Replacement, *logical.Response, error) {
+	orig, errResp, err := originalFromRequest(ctx, s, prefix, wrapper)
+	if errResp != nil || err != nil {
+		return nil, errResp, err
+	}
+	cfg, err := config.Read(ctx, s)
+	if err != nil {
+		return nil, nil, err
+	}
+	percent := uint64(cfg.ReplacementFeeBumpPercent)
+	if raw := strings.TrimSpace(wrapper.GetString("fee_bump_percent", "")); raw != "" {
+		percent, err = strconv.ParseUint(raw, 10, 32)
+		if err != nil || percent < model.MinReplacementFeeBumpPercent {
+			return nil, logical.ErrorResponse("fee_bump_percent must be a decimal integer of at least %d", model.MinReplacementFeeBumpPercent), nil
+		}
+	}
+	if !orig.Protected() {
+		return nil, logical.ErrorResponse("transaction to replace is not replay-protected (EIP-155)"), nil
+	}
+	chainID := orig.ChainId()
+	from, err := ethtypes.Sender(ethtypes.LatestSignerForChainID(chainID), orig)
+	if err != nil {
+		return nil, logical.ErrorResponse("recover sender of the transaction to replace: %s", err.Error()), nil
+	}
+	unsigned, err := ethutil.ReplacementTx(orig, from, cancel, percent)
+	if err != nil {
+		return nil, logical.ErrorResponse("%s", err.Error()), nil
+	}
+	if err := cfg.CheckSigning(ethutil.TxTypeLabel(unsigned), chainID); err != nil {
+		return nil, logical.ErrorResponse("%s", err.Error()), nil
+	}
+	return &Replacement{ChainID: chainID, From: from, Replaced: orig.Hash(), Unsigned: unsigned}, nil, nil
+}
+
+// PolicyInput returns the chain, destination and velocity policy input of the replacement, including the
+// delegates of a set-code speed-up.
+func (r *Replacement) PolicyInput() *model.TxPolicyInput {
+	in := &model.TxPolicyInput{
+		ChainID:  r.ChainID,
+		Value:    r.Unsigned.Value(),
+		FeeCap:   r.Unsigned.GasFeeCap(),
+		To:       r.Unsigned.To(),
+		Data:     r.Unsigned.Data(),
+		Replaces: r.Replaced.Hex(),
+	}
+	for _, auth := range r.Unsigned.SetCodeAuthorizations() {
+		in.Delegates = append(in.Delegates, auth.Address)
+	}
+	return in
+}
+
+// Sign signs the replacement with the key of address, which must have sent the replaced transaction, and
+// builds the sign-tx response with replaced_transaction_hash added.
+func (r *Replacement) Sign(address string, key *ecdsa.PrivateKey) (*logical.Response, error) {
+	if !common.IsHexAddress(address) || common.HexToAddress(address) != r.From {
+		return logical.ErrorResponse("transaction %s was sent by %s, not by this account (%s)", r.Replaced.Hex(), r.From.Hex(), address), nil
+	}
+	signedTx, err := ethutil.SignReplacementTx(r.ChainID, r.Unsigned, key)
+	if err != nil {
+		return nil, err
+	}
+	data, err := ethutil.SignedTxResponseData(signedTx)
+	if err != nil {
+		return nil, err
+	}
+	data["replaced_transaction_hash"] = r.Replaced.Hex()
+	return &logical.Response{Data: data}, nil
+}
+
+// originalFromRequest returns the transaction named by transaction_hash, from the signed-transaction records at
+// prefix, or decoded from signed_transaction.
+func originalFromRequest(ctx context.Context, s logical.Storage, prefix string, wrapper *model.FieldDataWrapper) (*ethtypes.Transaction, *logical.Response, error) {
+	hashStr := strings.TrimSpace(wrapper.GetString("transaction_hash", ""))
+	rawStr := strings.TrimSpace(wrapper.GetString("signed_transaction", ""))
+	switch {
+	case hashStr != "" && rawStr != "":
+		return nil, logical.ErrorResponse("give either transaction_hash or signed_transaction, not both"), nil
+	case hashStr != "":
+		b, err := hexutil.Decode(hashStr)
+		if err != nil || len(b) != common.HashLength {
+			return nil, logical.ErrorResponse("transaction_hash must be a 0x hex 32-byte hash"), nil
+		}
+		record, err := Read(ctx, s, prefix, hashStr)
+		if err != nil {
+			return nil, nil, err
+		}
+		if record == nil {
+			return nil, logical.ErrorResponse("no transaction %s was signed by this key; pass signed_transaction instead", hashStr), nil
+		}
+		rawStr = record.Raw
+	case rawStr == "":
+		return nil, logical.ErrorResponse("transaction_hash or signed_transaction is required"), nil
+	}
+	raw, err := hexutil.Decode(rawStr)
+	if err != nil {
+		return nil, logical.ErrorResponse("signed_transaction must be 0x hex: %s", err.Error()), nil
+	}
+	tx, _, err := ethutil.DecodeSignedTx(raw)
+	if err != nil {
+		return nil, logical.ErrorResponse("%s", err.Error()), nil
+	}
+	return tx, nil, nil
+}
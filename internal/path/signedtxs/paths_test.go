@@ -0,0 +1,241 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package signedtxs
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/logical"
+	"github.com/holiman/uint256"
+
+	"github.com/bsostech/vault-blockchain/internal/model"
+)
+
+const testPrefix = "accounts/alice/signed_txs/"
+
+var testTo = common.HexToAddress("0x0000000000000000000000000000000000000001")
+
+// testWrapper builds a FieldDataWrapper for raw against Fields.
+func testWrapper(raw map[string]interface{}) *model.FieldDataWrapper {
+	return model.NewFieldDataWrapper(&framework.FieldData{Raw: raw, Schema: Fields()})
+}
+
+// mustKey returns a fresh secp256k1 key.
+func mustKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+// mustSign signs inner with key under signer and returns the transaction with its 0x hex encoding.
+func mustSign(t *testing.T, inner ethtypes.TxData, signer ethtypes.Signer, key *ecdsa.PrivateKey) (*ethtypes.Transaction, string) {
+	t.Helper()
+	tx, err := ethtypes.SignNewTx(key, signer, inner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tx, hexutil.Encode(raw)
+}
+
+// TestPrepare resolves the transaction to replace from transaction_hash or signed_transaction and rejects the
+// request forms and originals that cannot be replaced.
+func TestPrepare(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	key := mustKey(t)
+	chainID := big.NewInt(1)
+	orig, origRaw := mustSign(t, &ethtypes.DynamicFeeTx{
+		ChainID: chainID, Nonce: 4, GasTipCap: big.NewInt(10), GasFeeCap: big.NewInt(100), Gas: 21000, To: &testTo, Value: big.NewInt(5),
+	}, ethtypes.LatestSignerForChainID(chainID), key)
+	if _, err := RecordSignedTx(ctx, s, testPrefix, &logical.Response{Data: map[string]interface{}{
+		"transaction_hash":   orig.Hash().Hex(),
+		"signed_transaction": origRaw,
+	}}, nil); err != nil {
+		t.Fatal(err)
+	}
+	_, unprotectedRaw := mustSign(t, &ethtypes.LegacyTx{
+		Nonce: 4, GasPrice: big.NewInt(100), Gas: 21000, To: &testTo, Value: big.NewInt(5),
+	}, ethtypes.HomesteadSigner{}, key)
+	unknownHash := "0x" + strings.Repeat("ab", 32)
+
+	cases := []struct {
+		name    string
+		raw     map[string]interface{}
+		wantErr string
+	}{
+		{"hash", map[string]interface{}{"transaction_hash": orig.Hash().Hex()}, ""},
+		{"hash upper case", map[string]interface{}{"transaction_hash": "0x" + strings.ToUpper(orig.Hash().Hex()[2:])}, ""},
+		{"raw", map[string]interface{}{"signed_transaction": origRaw}, ""},
+		{"both", map[string]interface{}{"transaction_hash": orig.Hash().Hex(), "signed_transaction": origRaw}, "not both"},
+		{"neither", map[string]interface{}{}, "is required"},
+		{"unknown hash", map[string]interface{}{"transaction_hash": unknownHash}, "was signed by this key"},
+		{"short hash", map[string]interface{}{"transaction_hash": "0xabcd"}, "32-byte hash"},
+		{"bad raw", map[string]interface{}{"signed_transaction": "zz"}, "0x hex"},
+		{"fee bump below minimum", map[string]interface{}{"signed_transaction": origRaw, "fee_bump_percent": "9"}, "at least 10"},
+		{"fee bump not decimal", map[string]interface{}{"signed_transaction": origRaw, "fee_bump_percent": "ten"}, "at least 10"},
+		{"non-EIP-155 original", map[string]interface{}{"signed_transaction": unprotectedRaw}, "replay-protected"},
+	}
+	for _, tc := range cases {
+		r, errResp, err := Prepare(ctx, s, testPrefix, testWrapper(tc.raw), false)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if tc.wantErr != "" {
+			if errResp == nil || !strings.Contains(errResp.Error().Error(), tc.wantErr) {
+				t.Fatalf("%s: resp=%v want error containing %q", tc.name, errResp, tc.wantErr)
+			}
+			continue
+		}
+		if errResp != nil {
+			t.Fatalf("%s: resp=%v want replacement", tc.name, errResp)
+		}
+		if r.Replaced != orig.Hash() || r.Unsigned.Nonce() != 4 || r.Unsigned.GasFeeCap().Int64() != 110 ||
+			r.From != crypto.PubkeyToAddress(key.PublicKey) {
+			t.Fatalf("%s: replacement=%+v want nonce 4, fee cap 110 from the signer", tc.name, r)
+		}
+	}
+
+	// Records are scoped to their prefix.
+	if _, errResp, err := Prepare(ctx, s, "accounts/bob/signed_txs/", testWrapper(map[string]interface{}{
+		"transaction_hash": orig.Hash().Hex(),
+	}), false); err != nil || errResp == nil {
+		t.Fatalf("resp=%v err=%v want unknown hash under another prefix", errResp, err)
+	}
+}
+
+// TestReplacementSign signs with the sender's key and refuses any other account.
+func TestReplacementSign(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	key, other := mustKey(t), mustKey(t)
+	chainID := big.NewInt(5)
+	_, raw := mustSign(t, &ethtypes.LegacyTx{
+		Nonce: 1, GasPrice: big.NewInt(100), Gas: 21000, To: &testTo, Value: big.NewInt(5),
+	}, ethtypes.LatestSignerForChainID(chainID), key)
+
+	cases := []struct {
+		name    string
+		address string
+		key     *ecdsa.PrivateKey
+		wantErr bool
+	}{
+		{"sender", crypto.PubkeyToAddress(key.PublicKey).Hex(), key, false},
+		{"other account", crypto.PubkeyToAddress(other.PublicKey).Hex(), other, true},
+		{"not an address", "alice", key, true},
+	}
+	for _, tc := range cases {
+		r, errResp, err := Prepare(ctx, new(logical.InmemStorage), testPrefix, testWrapper(map[string]interface{}{
+			"signed_transaction": raw,
+		}), true)
+		if err != nil || errResp != nil {
+			t.Fatalf("%s: resp=%v err=%v", tc.name, errResp, err)
+		}
+		resp, err := r.Sign(tc.address, tc.key)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if tc.wantErr {
+			if resp == nil || !resp.IsError() || !strings.Contains(resp.Error().Error(), "not by this account") {
+				t.Fatalf("%s: resp=%v want sender mismatch", tc.name, resp)
+			}
+			continue
+		}
+		if resp == nil || resp.IsError() || resp.Data["replaced_transaction_hash"] != r.Replaced.Hex() || resp.Data["value"] != "0" {
+			t.Fatalf("%s: resp=%v want signed cancellation", tc.name, resp)
+		}
+	}
+}
+
+// TestReplacementPolicyInput carries the replaced hash and, for a set-code speed-up, its delegates.
+func TestReplacementPolicyInput(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	key := mustKey(t)
+	chainID := big.NewInt(1)
+	delegates := []common.Address{
+		common.HexToAddress("0x0000000000000000000000000000000000000007"),
+		common.HexToAddress("0x0000000000000000000000000000000000000008"),
+	}
+	var auths []ethtypes.SetCodeAuthorization
+	for i, d := range delegates {
+		auth, err := ethtypes.SignSetCode(key, ethtypes.SetCodeAuthorization{ChainID: *uint256.NewInt(1), Address: d, Nonce: uint64(i)})
+		if err != nil {
+			t.Fatal(err)
+		}
+		auths = append(auths, auth)
+	}
+	_, setCodeRaw := mustSign(t, &ethtypes.SetCodeTx{
+		ChainID: uint256.NewInt(1), Nonce: 2, GasTipCap: uint256.NewInt(10), GasFeeCap: uint256.NewInt(100), Gas: 60000,
+		To: testTo, Value: uint256.NewInt(3), AuthList: auths,
+	}, ethtypes.LatestSignerForChainID(chainID), key)
+	_, dynamicRaw := mustSign(t, &ethtypes.DynamicFeeTx{
+		ChainID: chainID, Nonce: 2, GasTipCap: big.NewInt(10), GasFeeCap: big.NewInt(100), Gas: 21000, To: &testTo, Value: big.NewInt(3),
+	}, ethtypes.LatestSignerForChainID(chainID), key)
+
+	cases := []struct {
+		name          string
+		raw           string
+		cancel        bool
+		wantValue     int64
+		wantDelegates []common.Address
+	}{
+		{"set-code speed-up", setCodeRaw, false, 3, delegates},
+		{"set-code cancel", setCodeRaw, true, 0, nil},
+		{"dynamic-fee speed-up", dynamicRaw, false, 3, nil},
+	}
+	for _, tc := range cases {
+		r, errResp, err := Prepare(ctx, new(logical.InmemStorage), testPrefix, testWrapper(map[string]interface{}{
+			"signed_transaction": tc.raw,
+		}), tc.cancel)
+		if err != nil || errResp != nil {
+			t.Fatalf("%s: resp=%v err=%v", tc.name, errResp, err)
+		}
+		in := r.PolicyInput()
+		if in.ChainID.Cmp(chainID) != 0 || in.Value.Int64() != tc.wantValue || in.Replaces != r.Replaced.Hex() {
+			t.Fatalf("%s: input=%+v want chain 1, value %d, replaces %s", tc.name, in, tc.wantValue, r.Replaced.Hex())
+		}
+		if len(in.Delegates) != len(tc.wantDelegates) {
+			t.Fatalf("%s: delegates=%v want %v", tc.name, in.Delegates, tc.wantDelegates)
+		}
+		for i := range in.Delegates {
+			if in.Delegates[i] != tc.wantDelegates[i] {
+				t.Fatalf("%s: delegates=%v want %v", tc.name, in.Delegates, tc.wantDelegates)
+			}
+		}
+	}
+}
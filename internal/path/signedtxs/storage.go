@@ -0,0 +1,59 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package signedtxs
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/vault/sdk/logical"
+
+	"github.com/bsostech/vault-blockchain/internal/model"
+	"github.com/bsostech/vault-blockchain/internal/path/storagekey"
+)
+
+// Read loads the record at prefix of the transaction with the 0x hash txHash, or returns nil if none is stored.
+func Read(ctx context.Context, s logical.Storage, prefix, txHash string) (*model.SignedTxRecord, error) {
+	key := strings.ToLower(txHash)
+	entry, err := s.Get(ctx, storagekey.SignedTxKey(prefix, key))
+	if err != nil {
+		return nil, fmt.Errorf("get signed tx %s: %w", key, err)
+	}
+	if entry == nil {
+		return nil, nil
+	}
+	var record model.SignedTxRecord
+	if err := entry.DecodeJSON(&record); err != nil {
+		return nil, fmt.Errorf("decode signed tx %s: %w", key, err)
+	}
+	return &record, nil
+}
+
+// Write stores the record of the transaction with the 0x hash txHash at prefix.
+func Write(ctx context.Context, s logical.Storage, prefix, txHash string, record *model.SignedTxRecord) error {
+	key := strings.ToLower(txHash)
+	entry, err := logical.StorageEntryJSON(storagekey.SignedTxKey(prefix, key), record)
+	if err != nil {
+		return fmt.Errorf("encode signed tx %s: %w", key, err)
+	}
+	if err := s.Put(ctx, entry); err != nil {
+		return fmt.Errorf("put signed tx %s: %w", key, err)
+	}
+	return nil
+}
@@ -0,0 +1,309 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package solana implements the wallets/:wallet_id/solana paths that derive ed25519 Solana accounts from a
+// wallet's seed via SLIP-0010 and sign serialized transaction messages.
+package solana
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/btcsuite/btcd/btcutil/base58"
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/logical"
+
+	"github.com/bsostech/vault-blockchain/internal/model"
+	"github.com/bsostech/vault-blockchain/internal/path/config"
+	"github.com/bsostech/vault-blockchain/internal/path/journal"
+	"github.com/bsostech/vault-blockchain/internal/path/storagekey"
+	"github.com/bsostech/vault-blockchain/internal/path/wallet"
+	"github.com/bsostech/vault-blockchain/internal/solanautil"
+	"github.com/bsostech/vault-blockchain/pkg/utils"
+)
+
+// Message encodings accepted by sign-tx.
+const (
+	encodingBase64 = "base64"
+	encodingBase58 = "base58"
+	encodingHex    = "hex"
+)
+
+// Paths returns the wallet Solana paths. walletMu holds the per-wallet locks shared with the wallet paths.
+func Paths(walletMu *sync.Map) []*framework.Path {
+	return []*framework.Path{
+		pathSolanaAccount(),
+		pathSolanaSignTx(walletMu),
+		pathSolanaJournal(),
+		pathSolanaJournalVerify(),
+		pathSolanaJournalRecord(),
+	}
+}
+
+// patternSolanaAccount is the wallets/:wallet_id/solana/accounts/:index prefix of every path in this package.
+func patternSolanaAccount() string {
+	return "wallets/" + framework.GenericNameRegex("wallet_id") + "/solana/accounts/" + framework.GenericNameRegex("index")
+}
+
+// solanaAccountFields are the path fields shared by the Solana paths.
+func solanaAccountFields() map[string]*framework.FieldSchema {
+	return map[string]*framework.FieldSchema{
+		"wallet_id": {
+			Type:        framework.TypeString,
+			Description: "Logical wallet identifier in the path.",
+		},
+		"index": {
+			Type:        framework.TypeString,
+			Description: "Account index (decimal) in the path; the key is derived at m/44'/501'/<index>'/0'.",
+		},
+	}
+}
+
+// pathSolanaAccount registers read on wallets/:wallet_id/solana/accounts/:index.
+func pathSolanaAccount() *framework.Path {
+	return &framework.Path{
+		Pattern:      patternSolanaAccount(),
+		HelpSynopsis: "Derive the Solana address of the wallet at m/44'/501'/<index>'/0' (SLIP-0010 ed25519).",
+		Fields:       solanaAccountFields(),
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.ReadOperation: handleSolanaAccountRead,
+		},
+	}
+}
+
+// pathSolanaSignTx registers sign-tx on wallets/:wallet_id/solana/accounts/:index/sign-tx.
+func pathSolanaSignTx(walletMu *sync.Map) *framework.Path {
+	fields := solanaAccountFields()
+	fields["message"] = &framework.FieldSchema{
+		Type:        framework.TypeString,
+		Description: "Serialized legacy or v0 transaction message (the bytes every signer signs).",
+	}
+	fields["encoding"] = &framework.FieldSchema{
+		Type:        framework.TypeString,
+		Description: "Encoding of message: base64 (default), base58 or hex.",
+		Default:     encodingBase64,
+	}
+	return &framework.Path{
+		Pattern:      patternSolanaAccount() + "/sign-tx",
+		HelpSynopsis: "Sign a serialized Solana transaction message with the account's ed25519 key.",
+		HelpDescription: "The account must be one of the message's required signers. When it is the only one, the " +
+			"signed transaction is returned as well.",
+		Fields:         fields,
+		ExistenceCheck: wallet.ExistenceWalletSeed(),
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.CreateOperation: wallet.WithWalletLock(walletMu, handleSolanaSignTx),
+			logical.UpdateOperation: wallet.WithWalletLock(walletMu, handleSolanaSignTx),
+		},
+	}
+}
+
+// pathSolanaJournal registers LIST on wallets/:wallet_id/solana/accounts/:index/journal for record sequence numbers.
+func pathSolanaJournal() *framework.Path {
+	return &framework.Path{
+		Pattern:      patternSolanaAccount() + "/journal/?",
+		HelpSynopsis: "List the sequence numbers of a Solana account's signing journal.",
+		Fields:       solanaAccountFields(),
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.ListOperation: journal.HandleList(solanaJournalPrefix),
+		},
+	}
+}
+
+// pathSolanaJournalVerify registers read on wallets/:wallet_id/solana/accounts/:index/journal/verify.
+func pathSolanaJournalVerify() *framework.Path {
+	return &framework.Path{
+		Pattern:      patternSolanaAccount() + "/journal/verify",
+		HelpSynopsis: "Check the hash chain of a Solana account's signing journal.",
+		Fields:       solanaAccountFields(),
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.ReadOperation: journal.HandleVerify(solanaJournalPrefix),
+		},
+	}
+}
+
+// pathSolanaJournalRecord registers read on wallets/:wallet_id/solana/accounts/:index/journal/:seq.
+func pathSolanaJournalRecord() *framework.Path {
+	fields := solanaAccountFields()
+	fields["seq"] = journal.SeqField()
+	return &framework.Path{
+		Pattern:      patternSolanaAccount() + "/journal/" + journal.PatternSeq,
+		HelpSynopsis: "Read one record of a Solana account's signing journal.",
+		Fields:       fields,
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.ReadOperation: journal.HandleRead(solanaJournalPrefix),
+		},
+	}
+}
+
+// solanaJournalPrefix resolves the journal prefix of the Solana account addressed by the request path.
+func solanaJournalPrefix(wrapper *model.FieldDataWrapper) (string, *logical.Response) {
+	walletID := wrapper.GetString("wallet_id", "")
+	indexStr := wrapper.GetString("index", "")
+	if walletID == "" || indexStr == "" {
+		return "", logical.ErrorResponse("wallet_id and index are required")
+	}
+	index, err := wallet.ParseAddressIndex(indexStr)
+	if err != nil {
+		return "", logical.ErrorResponse("%s", err.Error())
+	}
+	return storagekey.WalletSolanaJournalPrefix(walletID, fmt.Sprint(index)), nil
+}
+
+// solanaAccountFromRequest reads wallet_id and index and loads the seed of an active wallet.
+func solanaAccountFromRequest(ctx context.Context, s logical.Storage, wrapper *model.FieldDataWrapper) (*model.WalletSeed, string, uint32, *logical.Response, error) {
+	walletID, err := wrapper.MustGetString("wallet_id")
+	if err != nil || walletID == "" {
+		return nil, "", 0, logical.ErrorResponse("wallet_id is required"), nil
+	}
+	indexStr, err := wrapper.MustGetString("index")
+	if err != nil {
+		return nil, "", 0, logical.ErrorResponse("index is required"), nil
+	}
+	index, err := wallet.ParseAddressIndex(indexStr)
+	if err != nil {
+		return nil, "", 0, logical.ErrorResponse("%s", err.Error()), nil
+	}
+	seed, errResp, err := wallet.LoadWalletSeed(ctx, s, walletID)
+	if err != nil || errResp != nil {
+		return nil, "", 0, errResp, err
+	}
+	return seed, walletID, index, nil, nil
+}
+
+// handleSolanaAccountRead returns the base58 address and derivation path at index.
+func handleSolanaAccountRead(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	wrapper := model.NewFieldDataWrapper(data)
+	seed, walletID, index, errResp, err := solanaAccountFromRequest(ctx, req.Storage, wrapper)
+	if err != nil || errResp != nil {
+		return errResp, err
+	}
+	account, err := seed.SolanaAccount(index)
+	if err != nil {
+		return nil, err
+	}
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"wallet_id":       walletID,
+			"account_index":   fmt.Sprint(index),
+			"address":         account.Address,
+			"derivation_path": account.DerivationPath,
+		},
+	}, nil
+}
+
+// handleSolanaSignTx signs a transaction message for which the account is a required signer. The message is
+// journaled, with the signature as the transaction ID when the account is the fee payer.
+// Caller must hold the per-wallet mutex from walletMu.
+func handleSolanaSignTx(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	wrapper := model.NewFieldDataWrapper(data)
+	cfg, err := config.Read(ctx, req.Storage)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.CheckSigning(model.SigningModeSolanaTx, nil); err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	encoding := strings.ToLower(wrapper.GetString("encoding", encodingBase64))
+	encoded, err := wrapper.MustGetString("message")
+	if err != nil || strings.TrimSpace(encoded) == "" {
+		return logical.ErrorResponse("message is required"), nil
+	}
+	msg, err := decodeMessage(strings.TrimSpace(encoded), encoding)
+	if err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	parsed, err := solanautil.ParseMessage(msg)
+	if err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	seed, walletID, index, errResp, err := solanaAccountFromRequest(ctx, req.Storage, wrapper)
+	if err != nil || errResp != nil {
+		return errResp, err
+	}
+	key, err := seed.SolanaPrivateKey(index)
+	if err != nil {
+		return nil, err
+	}
+	defer utils.ZeroBytes(key)
+	pub := key.Public().(ed25519.PublicKey)
+	signerIndex := parsed.SignerIndex(pub)
+	if signerIndex < 0 {
+		return logical.ErrorResponse("account %s is not a required signer of the message", base58.Encode(pub)), nil
+	}
+	sig := ed25519.Sign(key, msg)
+
+	out := map[string]interface{}{
+		"address":         base58.Encode(pub),
+		"signature":       base58.Encode(sig),
+		"signer_index":    signerIndex,
+		"message_version": parsed.Version,
+	}
+	if len(parsed.Signers) == 1 {
+		out["signed_transaction"] = encodeMessage(solanautil.Transaction(msg, [][]byte{sig}), encoding)
+	}
+	// The first signature of a transaction is its ID.
+	txID := ""
+	if signerIndex == 0 {
+		txID = base58.Encode(sig)
+	}
+	prefix := storagekey.WalletSolanaJournalPrefix(walletID, fmt.Sprint(index))
+	return journal.RecordTx(ctx, req, prefix, model.JournalOpSolanaSignTx, base58.Encode(pub), msg, txID,
+		&logical.Response{Data: out}, nil)
+}
+
+// decodeMessage decodes s in encoding.
+func decodeMessage(s, encoding string) ([]byte, error) {
+	switch encoding {
+	case encodingBase64:
+		b, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return nil, fmt.Errorf("message is not valid base64: %w", err)
+		}
+		return b, nil
+	case encodingBase58:
+		b := base58.Decode(s)
+		if len(b) == 0 {
+			return nil, fmt.Errorf("message is not valid base58")
+		}
+		return b, nil
+	case encodingHex:
+		b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+		if err != nil {
+			return nil, fmt.Errorf("message is not valid hex: %w", err)
+		}
+		return b, nil
+	default:
+		return nil, fmt.Errorf("encoding %q is unknown (want base64, base58 or hex)", encoding)
+	}
+}
+
+// encodeMessage encodes b in encoding, which decodeMessage has accepted.
+func encodeMessage(b []byte, encoding string) string {
+	switch encoding {
+	case encodingBase58:
+		return base58.Encode(b)
+	case encodingHex:
+		return hex.EncodeToString(b)
+	default:
+		return base64.StdEncoding.EncodeToString(b)
+	}
+}
@@ -0,0 +1,220 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package solana
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/hex"
+	"sync"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil/base58"
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/logical"
+
+	"github.com/bsostech/vault-blockchain/internal/model"
+	"github.com/bsostech/vault-blockchain/internal/path/journal"
+	"github.com/bsostech/vault-blockchain/internal/path/storagekey"
+)
+
+const (
+	testMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+	// testAddress is the Solana address of testMnemonic at m/44'/501'/0'/0'.
+	testAddress = "HAgk14JpMQLgt6rVgv7cBQFJWFto5Dqxi472uT3DKpqk"
+)
+
+// fieldData builds FieldData for raw against the schema of p.
+func fieldData(p *framework.Path, raw map[string]interface{}) *framework.FieldData {
+	return &framework.FieldData{Raw: raw, Schema: p.Fields}
+}
+
+// mustPutWalletSeed stores a WalletSeed for walletID.
+func mustPutWalletSeed(ctx context.Context, t *testing.T, s logical.Storage, walletID string) {
+	t.Helper()
+	entry, err := logical.StorageEntryJSON(storagekey.SeedKey(walletID), &model.WalletSeed{Mnemonic: testMnemonic})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Put(ctx, entry); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// testMessage returns a legacy message whose required signers are signers, followed by one read-only
+// program key and an opaque instruction tail.
+func testMessage(signers ...[]byte) []byte {
+	msg := []byte{byte(len(signers)), 0, 1, byte(len(signers) + 1)}
+	for _, s := range signers {
+		msg = append(msg, s...)
+	}
+	msg = append(msg, make([]byte, ed25519.PublicKeySize)...)
+	return append(msg, bytes.Repeat([]byte{0xab}, 40)...)
+}
+
+// TestHandleSolanaAccountRead verifies the reference address and error responses for bad requests.
+func TestHandleSolanaAccountRead(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	mustPutWalletSeed(ctx, t, s, "w1")
+	req := &logical.Request{Storage: s}
+	p := pathSolanaAccount()
+
+	resp, err := handleSolanaAccountRead(ctx, req, fieldData(p, map[string]interface{}{"wallet_id": "w1", "index": "0"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || resp.IsError() {
+		t.Fatalf("resp=%v want success.", resp)
+	}
+	if resp.Data["address"] != testAddress || resp.Data["derivation_path"] != "m/44'/501'/0'/0'" {
+		t.Fatalf("data=%v want reference address.", resp.Data)
+	}
+
+	for _, raw := range []map[string]interface{}{
+		{"wallet_id": "missing", "index": "0"},
+		{"wallet_id": "w1", "index": "2147483648"},
+	} {
+		resp, err := handleSolanaAccountRead(ctx, req, fieldData(p, raw))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp == nil || !resp.IsError() {
+			t.Fatalf("%v: resp=%v want error response.", raw, resp)
+		}
+	}
+}
+
+// TestHandleSolanaSignTx verifies the signature over the message, the assembled transaction for a sole
+// signer, that messages the account does not sign are refused, and that only signed messages are journaled.
+func TestHandleSolanaSignTx(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	mustPutWalletSeed(ctx, t, s, "w1")
+	req := &logical.Request{Storage: s}
+	p := pathSolanaSignTx(new(sync.Map))
+	pub := ed25519.PublicKey(base58.Decode(testAddress))
+
+	msg := testMessage(pub)
+	resp, err := handleSolanaSignTx(ctx, req, fieldData(p, map[string]interface{}{
+		"wallet_id": "w1", "index": "0", "message": base64.StdEncoding.EncodeToString(msg),
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || resp.IsError() {
+		t.Fatalf("resp=%v want success.", resp)
+	}
+	sig := base58.Decode(resp.Data["signature"].(string))
+	if !ed25519.Verify(pub, msg, sig) {
+		t.Fatal("signature does not verify.")
+	}
+	tx, err := base64.StdEncoding.DecodeString(resp.Data["signed_transaction"].(string))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx[0] != 1 || !bytes.Equal(tx[1:65], sig) || !bytes.Equal(tx[65:], msg) {
+		t.Fatalf("signed_transaction=%x want signature then message.", tx)
+	}
+
+	other := ed25519.NewKeyFromSeed(bytes.Repeat([]byte{7}, ed25519.SeedSize)).Public().(ed25519.PublicKey)
+	multi := testMessage(other, pub)
+	resp, err = handleSolanaSignTx(ctx, req, fieldData(p, map[string]interface{}{
+		"wallet_id": "w1", "index": "0", "message": hex.EncodeToString(multi), "encoding": "hex",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || resp.IsError() || resp.Data["signer_index"] != 1 {
+		t.Fatalf("resp=%v want signer index 1.", resp)
+	}
+	if _, ok := resp.Data["signed_transaction"]; ok {
+		t.Fatal("signed_transaction returned for a message with another signer.")
+	}
+
+	for name, raw := range map[string]map[string]interface{}{
+		"foreign":  {"wallet_id": "w1", "index": "1", "message": base64.StdEncoding.EncodeToString(msg)},
+		"encoding": {"wallet_id": "w1", "index": "0", "message": "00", "encoding": "base32"},
+		"short":    {"wallet_id": "w1", "index": "0", "message": base58.Encode(msg[:10]), "encoding": "base58"},
+		"missing":  {"wallet_id": "w2", "index": "0", "message": base64.StdEncoding.EncodeToString(msg)},
+	} {
+		resp, err := handleSolanaSignTx(ctx, req, fieldData(p, raw))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if resp == nil || !resp.IsError() {
+			t.Fatalf("%s: resp=%v want error response.", name, resp)
+		}
+	}
+
+	records, _, _, err := journal.Verify(ctx, s, storagekey.WalletSolanaJournalPrefix("w1", "0"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("records=%+v want 2.", records)
+	}
+	if records[0].Operation != model.JournalOpSolanaSignTx || records[0].Address != testAddress ||
+		records[0].PayloadDigest != model.JournalPayloadDigest(msg) || records[0].TxHash != base58.Encode(sig) {
+		t.Fatalf("record=%+v want sign-tx of msg with its signature as the transaction ID.", records[0])
+	}
+	if records[1].PayloadDigest != model.JournalPayloadDigest(multi) || records[1].TxHash != "" {
+		t.Fatalf("record=%+v want sign-tx of the co-signed message without a transaction ID.", records[1])
+	}
+	resp, err = pathSolanaJournal().Callbacks[logical.ListOperation](ctx, req, fieldData(pathSolanaJournal(), map[string]interface{}{
+		"wallet_id": "w1", "index": "0",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keys, _ := resp.Data["keys"].([]string); len(keys) != 2 {
+		t.Fatalf("keys=%v want 2 records.", resp.Data["keys"])
+	}
+}
+
+// TestHandleSolanaSignTx_deletedWallet verifies a soft-deleted wallet refuses to sign.
+func TestHandleSolanaSignTx_deletedWallet(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	mustPutWalletSeed(ctx, t, s, "w1")
+	entry, err := logical.StorageEntryJSON(storagekey.WalletTombstoneKey("w1"), &model.Tombstone{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Put(ctx, entry); err != nil {
+		t.Fatal(err)
+	}
+	msg := testMessage(base58.Decode(testAddress))
+	resp, err := handleSolanaSignTx(ctx, &logical.Request{Storage: s}, fieldData(pathSolanaSignTx(new(sync.Map)), map[string]interface{}{
+		"wallet_id": "w1", "index": "0", "message": base64.StdEncoding.EncodeToString(msg),
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || !resp.IsError() {
+		t.Fatalf("resp=%v want wallet deleted error.", resp)
+	}
+}
@@ -15,21 +15,57 @@
 // specific language governing permissions and limitations
 // under the License.
 
-// Package storagekey builds logical storage paths for wallet seeds, derived accounts, and single-key accounts.
+// Package storagekey builds logical storage paths for wallet seeds, derived accounts, single-key accounts, config and chain policies.
 package storagekey
 
 import "fmt"
 
-// SingleKeyAccountKey returns the storage path for a single-key account (JSON with private key material).
+// SingleKeyAccountKey returns the legacy storage path for an unversioned single-key account (JSON with private
+// key material). Entries here are migrated to SingleKeyAccountKeysKey on mount initialization or first rotation.
 func SingleKeyAccountKey(name string) string {
 	return fmt.Sprintf("accounts/%s/address", name)
 }
 
+// SingleKeyAccountKeysKey returns the storage path for a single-key account's versioned keyring.
+func SingleKeyAccountKeysKey(name string) string {
+	return fmt.Sprintf("accounts/%s/keys", name)
+}
+
 // SingleKeyAccountsRootPrefix is the list prefix for top-level names under accounts/.
 func SingleKeyAccountsRootPrefix() string {
 	return "accounts/"
 }
 
+// SingleKeyAccountPrefix returns the prefix holding every entry of a single-key account.
+func SingleKeyAccountPrefix(name string) string {
+	return fmt.Sprintf("accounts/%s/", name)
+}
+
+// SingleKeyAccountTombstoneKey returns the storage path marking a single-key account as soft-deleted.
+func SingleKeyAccountTombstoneKey(name string) string {
+	return fmt.Sprintf("accounts/%s/tombstone", name)
+}
+
+// SingleKeyAccountPolicyKey returns the storage path for a single-key account's destination policy.
+func SingleKeyAccountPolicyKey(name string) string {
+	return fmt.Sprintf("accounts/%s/policy", name)
+}
+
+// SingleKeyAccountJournalPrefix returns the prefix holding a single-key account's signing journal.
+func SingleKeyAccountJournalPrefix(name string) string {
+	return fmt.Sprintf("accounts/%s/journal/", name)
+}
+
+// WalletPrefix returns the prefix holding every entry of a wallet (seed, counters, accounts, policies, ledgers).
+func WalletPrefix(walletID string) string {
+	return fmt.Sprintf("wallets/%s/", walletID)
+}
+
+// WalletTombstoneKey returns the storage path marking a wallet as soft-deleted.
+func WalletTombstoneKey(walletID string) string {
+	return fmt.Sprintf("wallets/%s/tombstone", walletID)
+}
+
 // SeedKey returns the storage path for a wallet's BIP-39 seed.
 func SeedKey(walletID string) string {
 	return fmt.Sprintf("wallets/%s/seed", walletID)
@@ -45,7 +81,159 @@ func AccountsListPrefix(walletID string) string {
 	return fmt.Sprintf("wallets/%s/accounts/", walletID)
 }
 
+// SegmentAccountKey returns the storage path for a derived account under BIP-44 account segment account.
+// Segment "0" is the flat AccountKey layout used before account segments existed.
+func SegmentAccountKey(walletID, account, index string) string {
+	if account == "0" {
+		return AccountKey(walletID, index)
+	}
+	return fmt.Sprintf("wallets/%s/segments/%s/accounts/%s", walletID, account, index)
+}
+
+// SegmentAccountsListPrefix returns the list prefix for derived account indices under an account segment.
+func SegmentAccountsListPrefix(walletID, account string) string {
+	if account == "0" {
+		return AccountsListPrefix(walletID)
+	}
+	return fmt.Sprintf("wallets/%s/segments/%s/accounts/", walletID, account)
+}
+
+// WalletPolicyKey returns the storage path for a wallet's destination policy.
+func WalletPolicyKey(walletID string) string {
+	return fmt.Sprintf("wallets/%s/policy", walletID)
+}
+
+// WalletLimitsKey returns the storage path for a wallet's velocity limits.
+func WalletLimitsKey(walletID string) string {
+	return fmt.Sprintf("wallets/%s/limits", walletID)
+}
+
+// WalletSpendKey returns the storage path for a wallet's rolling spend ledger.
+func WalletSpendKey(walletID string) string {
+	return fmt.Sprintf("wallets/%s/spend", walletID)
+}
+
+// WalletXpubKey returns the storage path for the cached account-level extended public key of an account segment.
+func WalletXpubKey(walletID, account string) string {
+	return fmt.Sprintf("wallets/%s/xpub/%s", walletID, account)
+}
+
+// WalletBackupsKey returns the storage path for the audit records of a wallet's encrypted backups.
+func WalletBackupsKey(walletID string) string {
+	return fmt.Sprintf("wallets/%s/backups", walletID)
+}
+
+// WalletJournalPrefix returns the prefix holding the signing journal of a derived account.
+func WalletJournalPrefix(walletID, account, index string) string {
+	return fmt.Sprintf("wallets/%s/journal/%s/%s/", walletID, account, index)
+}
+
+// WalletBitcoinJournalPrefix returns the prefix holding the signing journal of a wallet's Bitcoin keys. The
+// account segment of WalletJournalPrefix is numeric, so the two never collide.
+func WalletBitcoinJournalPrefix(walletID string) string {
+	return fmt.Sprintf("wallets/%s/journal/bitcoin/", walletID)
+}
+
+// WalletSolanaJournalPrefix returns the prefix holding the signing journal of a wallet's Solana account at index.
+func WalletSolanaJournalPrefix(walletID, index string) string {
+	return fmt.Sprintf("wallets/%s/journal/solana/%s/", walletID, index)
+}
+
+// JournalRecordKey returns the storage path of record seq within the journal at prefix.
+func JournalRecordKey(prefix string, seq uint64) string {
+	return fmt.Sprintf("%srecords/%d", prefix, seq)
+}
+
+// JournalRecordsPrefix returns the list prefix for record sequence numbers within the journal at prefix.
+func JournalRecordsPrefix(prefix string) string {
+	return prefix + "records/"
+}
+
+// JournalHeadKey returns the storage path of the head (last sequence number and hash) of the journal at prefix.
+func JournalHeadKey(prefix string) string {
+	return prefix + "head"
+}
+
+// ShamirImportKey returns the storage path for SLIP-39 shares submitted toward reconstructing a wallet. It sits
+// outside wallets/ so a pending reconstruction does not list as a wallet.
+func ShamirImportKey(walletID string) string {
+	return fmt.Sprintf("shamir_imports/%s", walletID)
+}
+
+// SegmentsPrefix returns the list prefix for a wallet's non-zero account segments.
+func SegmentsPrefix(walletID string) string {
+	return fmt.Sprintf("wallets/%s/segments/", walletID)
+}
+
 // CounterKey returns the storage path for a wallet's auto-increment account counter.
 func CounterKey(walletID string) string {
 	return fmt.Sprintf("wallets/%s/counter", walletID)
 }
+
+// SegmentCounterKey returns the storage path for the auto-increment counter of an account segment.
+// Segment "0" shares CounterKey.
+func SegmentCounterKey(walletID, account string) string {
+	if account == "0" {
+		return CounterKey(walletID)
+	}
+	return fmt.Sprintf("wallets/%s/segments/%s/counter", walletID, account)
+}
+
+// ConfigKey returns the storage path for the mount-level configuration.
+func ConfigKey() string {
+	return "config"
+}
+
+// ChainKey returns the storage path for a registered chain's signing policy.
+func ChainKey(chainID string) string {
+	return fmt.Sprintf("chains/%s", chainID)
+}
+
+// ChainsListPrefix is the list prefix for registered chain IDs.
+func ChainsListPrefix() string {
+	return "chains/"
+}
+
+// AddressIndexKey returns the storage path of the address index entry for a lowercase 0x address.
+func AddressIndexKey(address string) string {
+	return fmt.Sprintf("addresses/%s", address)
+}
+
+// AddressIndexListPrefix is the list prefix for address index entries.
+func AddressIndexListPrefix() string {
+	return "addresses/"
+}
+
+// NonceKey returns the storage path of the nonce tracker for a lowercase 0x address on chainID.
+func NonceKey(chainID, address string) string {
+	return fmt.Sprintf("nonces/%s/%s", chainID, address)
+}
+
+// NoncesListPrefix is the list prefix for the chain IDs that have nonce trackers.
+func NoncesListPrefix() string {
+	return "nonces/"
+}
+
+// WalletSignedTxsPrefix returns the prefix holding the records of transactions signed by a wallet's derived
+// accounts. It sits under the wallet so purge removes them.
+func WalletSignedTxsPrefix(walletID string) string {
+	return fmt.Sprintf("wallets/%s/signed_txs/", walletID)
+}
+
+// SingleKeyAccountSignedTxsPrefix returns the prefix holding the records of transactions signed by a single-key
+// account.
+func SingleKeyAccountSignedTxsPrefix(name string) string {
+	return fmt.Sprintf("accounts/%s/signed_txs/", name)
+}
+
+// SignedTxKey returns the storage path of the record of a signed transaction by its lowercase 0x hash within the
+// signed-transaction records at prefix.
+func SignedTxKey(prefix, txHash string) string {
+	return prefix + txHash
+}
+
+// AddressIndexBackfillKey returns the storage path of the marker recording that keys stored before the address
+// index existed have been indexed.
+func AddressIndexBackfillKey() string {
+	return "address_index_backfill"
+}
@@ -29,6 +29,9 @@ func TestKeys(t *testing.T) {
 	if got := storagekey.SingleKeyAccountKey("alice"); got != "accounts/alice/address" {
 		t.Fatal(got)
 	}
+	if got := storagekey.SingleKeyAccountKeysKey("alice"); got != "accounts/alice/keys" {
+		t.Fatal(got)
+	}
 	if got := storagekey.SingleKeyAccountsRootPrefix(); got != "accounts/" {
 		t.Fatal(got)
 	}
@@ -41,4 +44,109 @@ func TestKeys(t *testing.T) {
 	if got := storagekey.AccountsListPrefix("my-id"); got != "wallets/my-id/accounts/" {
 		t.Fatal(got)
 	}
+	if got := storagekey.SegmentAccountKey("my-id", "0", "3"); got != "wallets/my-id/accounts/3" {
+		t.Fatal(got)
+	}
+	if got := storagekey.SegmentAccountKey("my-id", "2", "3"); got != "wallets/my-id/segments/2/accounts/3" {
+		t.Fatal(got)
+	}
+	if got := storagekey.SegmentAccountsListPrefix("my-id", "2"); got != "wallets/my-id/segments/2/accounts/" {
+		t.Fatal(got)
+	}
+	if got := storagekey.SegmentCounterKey("my-id", "0"); got != "wallets/my-id/counter" {
+		t.Fatal(got)
+	}
+	if got := storagekey.SegmentCounterKey("my-id", "2"); got != "wallets/my-id/segments/2/counter" {
+		t.Fatal(got)
+	}
+	if got := storagekey.SingleKeyAccountPrefix("alice"); got != "accounts/alice/" {
+		t.Fatal(got)
+	}
+	if got := storagekey.SingleKeyAccountTombstoneKey("alice"); got != "accounts/alice/tombstone" {
+		t.Fatal(got)
+	}
+	if got := storagekey.WalletPrefix("my-id"); got != "wallets/my-id/" {
+		t.Fatal(got)
+	}
+	if got := storagekey.WalletTombstoneKey("my-id"); got != "wallets/my-id/tombstone" {
+		t.Fatal(got)
+	}
+	if got := storagekey.SingleKeyAccountPolicyKey("alice"); got != "accounts/alice/policy" {
+		t.Fatal(got)
+	}
+	if got := storagekey.WalletPolicyKey("my-id"); got != "wallets/my-id/policy" {
+		t.Fatal(got)
+	}
+	if got := storagekey.WalletLimitsKey("my-id"); got != "wallets/my-id/limits" {
+		t.Fatal(got)
+	}
+	if got := storagekey.WalletSpendKey("my-id"); got != "wallets/my-id/spend" {
+		t.Fatal(got)
+	}
+	if got := storagekey.WalletXpubKey("my-id", "0"); got != "wallets/my-id/xpub/0" {
+		t.Fatal(got)
+	}
+	if got := storagekey.WalletBackupsKey("my-id"); got != "wallets/my-id/backups" {
+		t.Fatal(got)
+	}
+	if got := storagekey.SegmentsPrefix("my-id"); got != "wallets/my-id/segments/" {
+		t.Fatal(got)
+	}
+	if got := storagekey.ShamirImportKey("my-id"); got != "shamir_imports/my-id" {
+		t.Fatal(got)
+	}
+	if got := storagekey.SingleKeyAccountJournalPrefix("alice"); got != "accounts/alice/journal/" {
+		t.Fatal(got)
+	}
+	if got := storagekey.WalletJournalPrefix("my-id", "0", "3"); got != "wallets/my-id/journal/0/3/" {
+		t.Fatal(got)
+	}
+	if got := storagekey.WalletBitcoinJournalPrefix("my-id"); got != "wallets/my-id/journal/bitcoin/" {
+		t.Fatal(got)
+	}
+	if got := storagekey.WalletSolanaJournalPrefix("my-id", "2"); got != "wallets/my-id/journal/solana/2/" {
+		t.Fatal(got)
+	}
+	if got := storagekey.JournalRecordKey("accounts/alice/journal/", 7); got != "accounts/alice/journal/records/7" {
+		t.Fatal(got)
+	}
+	if got := storagekey.JournalRecordsPrefix("accounts/alice/journal/"); got != "accounts/alice/journal/records/" {
+		t.Fatal(got)
+	}
+	if got := storagekey.JournalHeadKey("accounts/alice/journal/"); got != "accounts/alice/journal/head" {
+		t.Fatal(got)
+	}
+	if got := storagekey.ConfigKey(); got != "config" {
+		t.Fatal(got)
+	}
+	if got := storagekey.ChainKey("1"); got != "chains/1" {
+		t.Fatal(got)
+	}
+	if got := storagekey.ChainsListPrefix(); got != "chains/" {
+		t.Fatal(got)
+	}
+	if got := storagekey.AddressIndexKey("0xabc"); got != "addresses/0xabc" {
+		t.Fatal(got)
+	}
+	if got := storagekey.AddressIndexListPrefix(); got != "addresses/" {
+		t.Fatal(got)
+	}
+	if got := storagekey.NonceKey("1", "0xabc"); got != "nonces/1/0xabc" {
+		t.Fatal(got)
+	}
+	if got := storagekey.NoncesListPrefix(); got != "nonces/" {
+		t.Fatal(got)
+	}
+	if got := storagekey.WalletSignedTxsPrefix("my-id"); got != "wallets/my-id/signed_txs/" {
+		t.Fatal(got)
+	}
+	if got := storagekey.SingleKeyAccountSignedTxsPrefix("alice"); got != "accounts/alice/signed_txs/" {
+		t.Fatal(got)
+	}
+	if got := storagekey.SignedTxKey("accounts/alice/signed_txs/", "0xabc"); got != "accounts/alice/signed_txs/0xabc" {
+		t.Fatal(got)
+	}
+	if got := storagekey.AddressIndexBackfillKey(); got != "address_index_backfill" {
+		t.Fatal(got)
+	}
 }
@@ -0,0 +1,104 @@
+// Package transaction implements the tx/ paths that inspect signed transactions without touching any key.
+package transaction
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/logical"
+
+	"github.com/bsostech/vault-blockchain/internal/ethutil"
+	"github.com/bsostech/vault-blockchain/internal/model"
+	"github.com/bsostech/vault-blockchain/internal/path/addressindex"
+)
+
+// Paths returns the transaction inspection paths.
+func Paths() []*framework.Path {
+	return []*framework.Path{
+		pathTxDecode(),
+	}
+}
+
+// pathTxDecode registers write on tx/decode. It reads nothing but the address index and writes nothing.
+func pathTxDecode() *framework.Path {
+	return &framework.Path{
+		Pattern:      "tx/decode",
+		HelpSynopsis: "Decode a signed transaction, recover its sender and report whether this mount manages the sender key.",
+		Fields: map[string]*framework.FieldSchema{
+			"signed_transaction": {
+				Type:        framework.TypeString,
+				Description: "0x hex of a signed transaction of any supported type, in EIP-2718 binary form or RLP-wrapped form. Blob transactions may include the sidecar. Required.",
+			},
+		},
+		// Without an ExistenceCheck Vault maps every write to Update.
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.UpdateOperation: handleTxDecode,
+		},
+	}
+}
+
+// handleTxDecode decodes a signed transaction into the fields sign-tx returns, adds the fields sign-tx takes as
+// input, and reports the indexed owner of the recovered sender.
+func handleTxDecode(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	rawHex, err := model.NewFieldDataWrapper(data).MustGetString("signed_transaction")
+	if err != nil {
+		return logical.ErrorResponse("signed_transaction is required"), nil
+	}
+	raw, err := hexutil.Decode(strings.TrimSpace(rawHex))
+	if err != nil {
+		return logical.ErrorResponse("signed_transaction must be 0x hex: %s", err.Error()), nil
+	}
+	tx, encoding, err := ethutil.DecodeSignedTx(raw)
+	if err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	out, err := ethutil.SignedTxResponseData(tx)
+	if err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	out["encoding"] = encoding
+	out["chain_id"] = tx.ChainId().String()
+	out["nonce"] = tx.Nonce()
+	out["data"] = hexutil.Encode(tx.Data())
+	switch tx.Type() {
+	case ethtypes.DynamicFeeTxType, ethtypes.BlobTxType, ethtypes.SetCodeTxType:
+		out["max_priority_fee_per_gas"] = tx.GasTipCap().String()
+	}
+	if tx.Type() != ethtypes.LegacyTxType {
+		out["access_list"] = accessListResponse(tx.AccessList())
+	}
+
+	from, _ := out["address_from"].(string)
+	owner, err := addressindex.ReadAddressOwner(ctx, req.Storage, from)
+	if err != nil {
+		return nil, err
+	}
+	out["managed"] = owner != nil
+	if owner != nil {
+		ownerData, err := addressindex.OwnerResponseData(ctx, req.Storage, owner)
+		if err != nil {
+			return nil, err
+		}
+		out["owner"] = ownerData
+	}
+	return &logical.Response{Data: out}, nil
+}
+
+// accessListResponse renders an access list in the JSON shape sign-tx accepts in access_list.
+func accessListResponse(al ethtypes.AccessList) []interface{} {
+	out := make([]interface{}, 0, len(al))
+	for _, tuple := range al {
+		keys := make([]string, 0, len(tuple.StorageKeys))
+		for _, k := range tuple.StorageKeys {
+			keys = append(keys, k.Hex())
+		}
+		out = append(out, map[string]interface{}{
+			"address":     tuple.Address.Hex(),
+			"storageKeys": keys,
+		})
+	}
+	return out
+}
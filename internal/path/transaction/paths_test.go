@@ -0,0 +1,132 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package transaction
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/logical"
+
+	"github.com/bsostech/vault-blockchain/internal/ethutil"
+	"github.com/bsostech/vault-blockchain/internal/model"
+	"github.com/bsostech/vault-blockchain/internal/path/addressindex"
+)
+
+// decode runs handleTxDecode on a signed transaction hex.
+func decode(t *testing.T, req *logical.Request, signedHex string) *logical.Response {
+	t.Helper()
+
+	fd := &framework.FieldData{
+		Raw:    map[string]interface{}{"signed_transaction": signedHex},
+		Schema: pathTxDecode().Fields,
+	}
+	resp, err := handleTxDecode(context.Background(), req, fd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil {
+		t.Fatal("expected non-nil response.")
+	}
+	return resp
+}
+
+// TestHandleTxDecode verifies decode returns the sign-tx fields and the recovered sender for both encodings,
+// and flags the sender as managed once the address index has an owner for it.
+func TestHandleTxDecode(t *testing.T) {
+	t.Parallel()
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	to := common.HexToAddress("0x0000000000000000000000000000000000000004")
+	al := ethtypes.AccessList{{Address: to, StorageKeys: []common.Hash{{0x01}}}}
+	tx, err := ethutil.SignEIP1559(big.NewInt(11155111), 5, 30_000, big.NewInt(7), []byte{0xca, 0xfe}, &to,
+		big.NewInt(1_000_000_000), big.NewInt(2_000_000_000), al, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bin, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := &logical.Request{Storage: new(logical.InmemStorage)}
+	resp := decode(t, req, hexutil.Encode(bin))
+	if resp.IsError() {
+		t.Fatalf("unexpected error: %v.", resp.Error())
+	}
+	want := map[string]interface{}{
+		"type": "eip1559", "encoding": ethutil.TxEncodingBinary, "transaction_hash": tx.Hash().Hex(),
+		"signed_transaction": hexutil.Encode(bin), "address_from": from.Hex(), "address_to": to.Hex(),
+		"chain_id": "11155111", "nonce": uint64(5), "value": "7", "gas_limit": uint64(30_000),
+		"gas_price": "2000000000", "max_priority_fee_per_gas": "1000000000", "data": "0xcafe", "managed": false,
+	}
+	for k, v := range want {
+		if resp.Data[k] != v {
+			t.Fatalf("%s=%v want %v.", k, resp.Data[k], v)
+		}
+	}
+	if got, _ := resp.Data["access_list"].([]interface{}); len(got) != 1 {
+		t.Fatalf("access_list=%v want one tuple.", resp.Data["access_list"])
+	}
+	if _, ok := resp.Data["owner"]; ok {
+		t.Fatal("owner reported for an unmanaged sender.")
+	}
+
+	if _, err := addressindex.IndexAddress(context.Background(), req.Storage, from.Hex(),
+		model.NewAccountAddressOwner("alice", 2)); err != nil {
+		t.Fatal(err)
+	}
+	wrapped, err := rlp.EncodeToBytes(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp = decode(t, req, hexutil.Encode(wrapped))
+	if resp.IsError() {
+		t.Fatalf("unexpected error: %v.", resp.Error())
+	}
+	if resp.Data["encoding"] != ethutil.TxEncodingRLP || resp.Data["signed_transaction"] != hexutil.Encode(bin) {
+		t.Fatalf("encoding=%v signed_transaction=%v want rlp and the binary form.", resp.Data["encoding"], resp.Data["signed_transaction"])
+	}
+	owner, _ := resp.Data["owner"].(map[string]interface{})
+	if resp.Data["managed"] != true || owner["name"] != "alice" || owner["path"] != "accounts/alice" || owner["deleted"] != false {
+		t.Fatalf("managed=%v owner=%v want alice.", resp.Data["managed"], owner)
+	}
+}
+
+// TestHandleTxDecode_invalid verifies missing, non-hex and undecodable input is a client error.
+func TestHandleTxDecode_invalid(t *testing.T) {
+	t.Parallel()
+
+	req := &logical.Request{Storage: new(logical.InmemStorage)}
+	for _, in := range []string{"", "cafe", "0xzz", "0xdeadbeef"} {
+		if resp := decode(t, req, in); !resp.IsError() {
+			t.Fatalf("input %q: expected error response.", in)
+		}
+	}
+}
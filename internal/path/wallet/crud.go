@@ -19,20 +19,39 @@ package wallet
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"math/big"
 	"net/http"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/hashicorp/vault/sdk/framework"
+	"github.com/hashicorp/vault/sdk/helper/wrapping"
 	"github.com/hashicorp/vault/sdk/logical"
 	"github.com/tyler-smith/go-bip39"
 
+	"github.com/bsostech/vault-blockchain/internal/ageutil"
+	"github.com/bsostech/vault-blockchain/internal/ethutil"
 	"github.com/bsostech/vault-blockchain/internal/model"
+	"github.com/bsostech/vault-blockchain/internal/path/account"
+	"github.com/bsostech/vault-blockchain/internal/path/addressindex"
+	"github.com/bsostech/vault-blockchain/internal/path/config"
+	"github.com/bsostech/vault-blockchain/internal/path/journal"
+	"github.com/bsostech/vault-blockchain/internal/path/nonces"
+	"github.com/bsostech/vault-blockchain/internal/path/signedtxs"
 	"github.com/bsostech/vault-blockchain/internal/path/storagekey"
+	"github.com/bsostech/vault-blockchain/internal/slip39"
+	"github.com/bsostech/vault-blockchain/pkg/utils"
 )
 
 // errWalletAlreadyExists indicates a seed is already stored for wallet_id.
@@ -41,17 +60,19 @@ var errWalletAlreadyExists = errors.New("wallet_id already exists")
 // errAccountIndexLimitReached indicates the next derived index would exceed BIP-44 bounds.
 var errAccountIndexLimitReached = errors.New("account index limit reached")
 
-// maxBatchDerivedAccounts caps how many derived accounts one batch request may create.
-const maxBatchDerivedAccounts = 10000
-
-// maxBulkReadDerivedSpan is the maximum inclusive range size (end - start + 1) for bulk metadata read.
-const maxBulkReadDerivedSpan = 10000
+// SLIP-39 bounds: at most 16 shares per group, and PBKDF2 iterations of 10000<<iteration_exponent.
+const (
+	maxShamirShares            = 16
+	maxShamirIterationExponent = 15
+)
 
 // putWalletSeedIfAbsent writes the BIP-39 seed JSON under wallets/<id>/seed if absent.
+// passphrase is the optional BIP-39 passphrase and derivationPath the derivation path template; both are
+// fixed for the wallet's lifetime.
 func putWalletSeedIfAbsent(
 	ctx context.Context,
 	req *logical.Request,
-	walletID, mnemonic string,
+	walletID, mnemonic, passphrase, derivationPath string,
 ) error {
 	seedKey := storagekey.SeedKey(walletID)
 	existing, err := req.Storage.Get(ctx, seedKey)
@@ -62,7 +83,7 @@ func putWalletSeedIfAbsent(
 		return errWalletAlreadyExists
 	}
 
-	seed := &model.WalletSeed{Mnemonic: mnemonic}
+	seed := &model.WalletSeed{Mnemonic: mnemonic, Passphrase: passphrase, DerivationPath: derivationPath}
 	entry, err := logical.StorageEntryJSON(seedKey, seed)
 	if err != nil {
 		return fmt.Errorf("encode wallet seed %s: %w", walletID, err)
@@ -73,9 +94,10 @@ func putWalletSeedIfAbsent(
 	return nil
 }
 
-// generateMnemonic24 returns a random BIP-39 mnemonic with 256-bit entropy (24 words).
-func generateMnemonic24() (string, error) {
-	entropy, err := bip39.NewEntropy(256)
+// generateMnemonic returns a random BIP-39 mnemonic with the given entropy size in bits
+// (256 bits gives 24 words).
+func generateMnemonic(strength int) (string, error) {
+	entropy, err := bip39.NewEntropy(strength)
 	if err != nil {
 		return "", fmt.Errorf("generate entropy: %w", err)
 	}
@@ -86,6 +108,29 @@ func generateMnemonic24() (string, error) {
 	return mnemonic, nil
 }
 
+// walletDerivationPathFromRequest returns the request's derivation_path template, or the config default
+// when it is empty. An invalid template yields a logical error response.
+func walletDerivationPathFromRequest(wrapper *model.FieldDataWrapper, cfg *model.Config) (string, *logical.Response) {
+	tmpl := strings.TrimSpace(wrapper.GetString("derivation_path", ""))
+	if tmpl == "" {
+		return cfg.DefaultDerivationPath, nil
+	}
+	if err := model.ValidateDerivationTemplate(tmpl); err != nil {
+		return "", logical.ErrorResponse("%s", err.Error())
+	}
+	return tmpl, nil
+}
+
+// accountSegmentFromRequest returns the optional BIP-44 account segment and its canonical storage form.
+// A malformed value yields a logical error response.
+func accountSegmentFromRequest(wrapper *model.FieldDataWrapper) (uint32, string, *logical.Response) {
+	account, accountStr, err := ParseAccountSegment(strings.TrimSpace(wrapper.GetString("account", "")))
+	if err != nil {
+		return 0, "", logical.ErrorResponse("%s", err.Error())
+	}
+	return account, accountStr, nil
+}
+
 // respondWalletConflict returns HTTP 409 when the wallet_id already has a stored seed.
 func respondWalletConflict(req *logical.Request) (*logical.Response, error) {
 	return logical.RespondWithStatusCode(
@@ -95,19 +140,30 @@ func respondWalletConflict(req *logical.Request) (*logical.Response, error) {
 	)
 }
 
-// handleWalletCreateAuto generates a 24-word mnemonic, stores it for wallet_id, and returns only the id.
+// handleWalletCreateAuto generates a mnemonic (config mnemonic_strength, 24 words by default),
+// stores it for wallet_id, and returns only the id.
 func handleWalletCreateAuto(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
-	walletID, err := model.NewFieldDataWrapper(data).MustGetString("wallet_id")
+	wrapper := model.NewFieldDataWrapper(data)
+	walletID, err := wrapper.MustGetString("wallet_id")
 	if err != nil || walletID == "" {
 		return logical.ErrorResponse("wallet_id is required"), nil
 	}
+	cfg, err := config.Read(ctx, req.Storage)
+	if err != nil {
+		return nil, err
+	}
 
-	mnemonic, err := generateMnemonic24()
+	mnemonic, err := generateMnemonic(cfg.MnemonicStrength)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := putWalletSeedIfAbsent(ctx, req, walletID, mnemonic); err != nil {
+	passphrase := wrapper.GetString("passphrase", "")
+	derivationPath, errResp := walletDerivationPathFromRequest(wrapper, cfg)
+	if errResp != nil {
+		return errResp, nil
+	}
+	if err := putWalletSeedIfAbsent(ctx, req, walletID, mnemonic, passphrase, derivationPath); err != nil {
 		if errors.Is(err, errWalletAlreadyExists) {
 			return respondWalletConflict(req)
 		}
@@ -116,7 +172,8 @@ func handleWalletCreateAuto(ctx context.Context, req *logical.Request, data *fra
 
 	return &logical.Response{
 		Data: map[string]interface{}{
-			"wallet_id": walletID,
+			"wallet_id":       walletID,
+			"derivation_path": derivationPath,
 		},
 	}, nil
 }
@@ -135,8 +192,28 @@ func handleWalletImport(ctx context.Context, req *logical.Request, data *framewo
 	if !bip39.IsMnemonicValid(mnemonic) {
 		return logical.ErrorResponse("invalid mnemonic"), nil
 	}
+	return importWalletSeed(ctx, req, wrapper, walletID, mnemonic)
+}
+
+// importWalletSeed stores a validated mnemonic for wallet_id with the request's optional passphrase and
+// derivation_path template. Shared by handleWalletImport and handleWalletShamirImport.
+func importWalletSeed(
+	ctx context.Context,
+	req *logical.Request,
+	wrapper *model.FieldDataWrapper,
+	walletID, mnemonic string,
+) (*logical.Response, error) {
+	cfg, err := config.Read(ctx, req.Storage)
+	if err != nil {
+		return nil, err
+	}
 
-	if err := putWalletSeedIfAbsent(ctx, req, walletID, mnemonic); err != nil {
+	passphrase := wrapper.GetString("passphrase", "")
+	derivationPath, errResp := walletDerivationPathFromRequest(wrapper, cfg)
+	if errResp != nil {
+		return errResp, nil
+	}
+	if err := putWalletSeedIfAbsent(ctx, req, walletID, mnemonic, passphrase, derivationPath); err != nil {
 		if errors.Is(err, errWalletAlreadyExists) {
 			return respondWalletConflict(req)
 		}
@@ -145,12 +222,14 @@ func handleWalletImport(ctx context.Context, req *logical.Request, data *framewo
 
 	return &logical.Response{
 		Data: map[string]interface{}{
-			"wallet_id": walletID,
+			"wallet_id":       walletID,
+			"derivation_path": derivationPath,
 		},
 	}, nil
 }
 
-// handleListWallets returns sorted wallet_id values that have a stored seed entry.
+// handleListWallets returns sorted wallet_id values that have a stored seed entry. Key info carries a deleted
+// flag so soft-deleted wallets stay listable until purged.
 func handleListWallets(ctx context.Context, req *logical.Request, _ *framework.FieldData) (*logical.Response, error) {
 	children, err := req.Storage.List(ctx, "wallets/")
 	if err != nil {
@@ -170,13 +249,29 @@ func handleListWallets(ctx context.Context, req *logical.Request, _ *framework.F
 		ids = append(ids, id)
 	}
 	sort.Strings(ids)
-	return logical.ListResponse(ids), nil
+	info := make(map[string]interface{}, len(ids))
+	for _, id := range ids {
+		tomb, err := ReadWalletTombstone(ctx, req.Storage, id)
+		if err != nil {
+			return nil, err
+		}
+		info[id] = map[string]interface{}{"deleted": tomb != nil}
+	}
+	return logical.ListResponseWithInfo(ids, info), nil
 }
 
-// createNextDerivedAccount allocates the current counter index, persists derived metadata, and advances the counter.
+// createNextDerivedAccount allocates the current counter index of the account segment, persists derived
+// metadata, and advances that segment's counter.
 // Caller must hold the per-wallet mutex from walletMu and ensure the wallet seed exists.
-func createNextDerivedAccount(ctx context.Context, req *logical.Request, walletID, mnemonic string) (indexStr, address, derivationPath string, err error) {
-	nextIndex, err := ReadWalletCounter(ctx, req.Storage, walletID)
+func createNextDerivedAccount(
+	ctx context.Context,
+	req *logical.Request,
+	walletID string,
+	account uint32,
+	seed *model.WalletSeed,
+) (indexStr, address, derivationPath string, err error) {
+	accountStr := strconv.FormatUint(uint64(account), 10)
+	nextIndex, err := ReadSegmentCounter(ctx, req.Storage, walletID, accountStr)
 	if err != nil {
 		return "", "", "", err
 	}
@@ -184,9 +279,9 @@ func createNextDerivedAccount(ctx context.Context, req *logical.Request, walletI
 		return "", "", "", errAccountIndexLimitReached
 	}
 
-	address, derivationPath, err = model.DeriveEthereumAccount(mnemonic, nextIndex)
+	address, derivationPath, err = seed.DeriveAccountAt(account, nextIndex)
 	if err != nil {
-		return "", "", "", fmt.Errorf("derive account %s/%d: %w", walletID, nextIndex, err)
+		return "", "", "", fmt.Errorf("derive account %s/%s/%d: %w", walletID, accountStr, nextIndex, err)
 	}
 
 	indexStr = fmt.Sprintf("%d", nextIndex)
@@ -194,29 +289,37 @@ func createNextDerivedAccount(ctx context.Context, req *logical.Request, walletI
 		Address:        address,
 		DerivationPath: derivationPath,
 	}
-	entry, err := logical.StorageEntryJSON(storagekey.AccountKey(walletID, indexStr), derived)
+	entry, err := logical.StorageEntryJSON(storagekey.SegmentAccountKey(walletID, accountStr, indexStr), derived)
 	if err != nil {
-		return "", "", "", fmt.Errorf("encode derived account %s/%s: %w", walletID, indexStr, err)
+		return "", "", "", fmt.Errorf("encode derived account %s/%s/%s: %w", walletID, accountStr, indexStr, err)
 	}
 	if err := req.Storage.Put(ctx, entry); err != nil {
-		return "", "", "", fmt.Errorf("put derived account %s/%s: %w", walletID, indexStr, err)
+		return "", "", "", fmt.Errorf("put derived account %s/%s/%s: %w", walletID, accountStr, indexStr, err)
+	}
+	if _, err := addressindex.IndexAddress(ctx, req.Storage, address, model.NewWalletAddressOwner(walletID, accountStr, indexStr)); err != nil {
+		return "", "", "", err
 	}
-	if err := WriteWalletCounter(ctx, req.Storage, walletID, nextIndex+1); err != nil {
+	if err := WriteSegmentCounter(ctx, req.Storage, walletID, accountStr, nextIndex+1); err != nil {
 		return "", "", "", err
 	}
 	return indexStr, address, derivationPath, nil
 }
 
-// makeHandleDerivedAccountCreate returns a handler that derives m/44'/60'/0'/0/<counter> and
+// makeHandleDerivedAccountCreate returns a handler that derives m/44'/60'/<account>'/0/<counter> and
 // persists address metadata for the wallet. The index is assigned automatically using a
-// per-wallet counter. walletMu serialises the counter read-increment-write sequence so that
+// per-segment counter. walletMu serialises the counter read-increment-write sequence so that
 // concurrent requests to the same wallet_id cannot derive the same address.
 func makeHandleDerivedAccountCreate(walletMu *sync.Map) framework.OperationFunc {
 	return func(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
-		walletID, err := model.NewFieldDataWrapper(data).MustGetString("wallet_id")
+		wrapper := model.NewFieldDataWrapper(data)
+		walletID, err := wrapper.MustGetString("wallet_id")
 		if err != nil || walletID == "" {
 			return logical.ErrorResponse("wallet_id is required"), nil
 		}
+		account, accountStr, errResp := accountSegmentFromRequest(wrapper)
+		if errResp != nil {
+			return errResp, nil
+		}
 
 		mu, _ := walletMu.LoadOrStore(walletID, &sync.Mutex{})
 		mu.(*sync.Mutex).Lock()
@@ -229,8 +332,18 @@ func makeHandleDerivedAccountCreate(walletMu *sync.Map) framework.OperationFunc
 		if seed == nil || seed.Mnemonic == "" {
 			return logical.ErrorResponse("wallet not found"), nil
 		}
+		tomb, err := ReadWalletTombstone(ctx, req.Storage, walletID)
+		if err != nil {
+			return nil, err
+		}
+		if tomb != nil {
+			return logical.ErrorResponse("%s", ErrWalletDeleted.Error()), nil
+		}
+		if _, err := model.AccountPathTemplate(seed.PathTemplate(), account); err != nil {
+			return logical.ErrorResponse("%s", err.Error()), nil
+		}
 
-		indexStr, address, derivationPath, err := createNextDerivedAccount(ctx, req, walletID, seed.Mnemonic)
+		indexStr, address, derivationPath, err := createNextDerivedAccount(ctx, req, walletID, account, seed)
 		if err != nil {
 			if errors.Is(err, errAccountIndexLimitReached) {
 				return logical.ErrorResponse("account index limit reached (max 2147483647)"), nil
@@ -241,6 +354,7 @@ func makeHandleDerivedAccountCreate(walletMu *sync.Map) framework.OperationFunc
 		return &logical.Response{
 			Data: map[string]interface{}{
 				"address":         address,
+				"account":         accountStr,
 				"account_index":   indexStr,
 				"derivation_path": derivationPath,
 			},
@@ -248,7 +362,7 @@ func makeHandleDerivedAccountCreate(walletMu *sync.Map) framework.OperationFunc
 	}
 }
 
-// makeHandleBatchDerivedAccountCreate returns a handler that creates up to maxBatchDerivedAccounts
+// makeHandleBatchDerivedAccountCreate returns a handler that creates up to config max_batch_derived_accounts
 // derived accounts in one request, holding the same per-wallet mutex for the whole loop.
 func makeHandleBatchDerivedAccountCreate(walletMu *sync.Map) framework.OperationFunc {
 	return func(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
@@ -257,6 +371,10 @@ func makeHandleBatchDerivedAccountCreate(walletMu *sync.Map) framework.Operation
 		if err != nil || walletID == "" {
 			return logical.ErrorResponse("wallet_id is required"), nil
 		}
+		account, accountStr, errResp := accountSegmentFromRequest(wrapper)
+		if errResp != nil {
+			return errResp, nil
+		}
 		countStr, err := wrapper.MustGetString("count")
 		if err != nil || countStr == "" {
 			return logical.ErrorResponse("count is required"), nil
@@ -265,8 +383,12 @@ func makeHandleBatchDerivedAccountCreate(walletMu *sync.Map) framework.Operation
 		if err != nil || count < 1 {
 			return logical.ErrorResponse("count must be a positive integer"), nil
 		}
-		if count > maxBatchDerivedAccounts {
-			return logical.ErrorResponse("count must be <= %d", maxBatchDerivedAccounts), nil
+		cfg, err := config.Read(ctx, req.Storage)
+		if err != nil {
+			return nil, err
+		}
+		if count > cfg.MaxBatchDerivedAccounts {
+			return logical.ErrorResponse("count must be <= %d", cfg.MaxBatchDerivedAccounts), nil
 		}
 
 		mu, _ := walletMu.LoadOrStore(walletID, &sync.Mutex{})
@@ -280,8 +402,18 @@ func makeHandleBatchDerivedAccountCreate(walletMu *sync.Map) framework.Operation
 		if seed == nil || seed.Mnemonic == "" {
 			return logical.ErrorResponse("wallet not found"), nil
 		}
+		tomb, err := ReadWalletTombstone(ctx, req.Storage, walletID)
+		if err != nil {
+			return nil, err
+		}
+		if tomb != nil {
+			return logical.ErrorResponse("%s", ErrWalletDeleted.Error()), nil
+		}
+		if _, err := model.AccountPathTemplate(seed.PathTemplate(), account); err != nil {
+			return logical.ErrorResponse("%s", err.Error()), nil
+		}
 
-		nextStart, err := ReadWalletCounter(ctx, req.Storage, walletID)
+		nextStart, err := ReadSegmentCounter(ctx, req.Storage, walletID, accountStr)
 		if err != nil {
 			return nil, err
 		}
@@ -293,7 +425,7 @@ func makeHandleBatchDerivedAccountCreate(walletMu *sync.Map) framework.Operation
 
 		accounts := make([]interface{}, 0, count)
 		for i := 0; i < count; i++ {
-			indexStr, address, derivationPath, err := createNextDerivedAccount(ctx, req, walletID, seed.Mnemonic)
+			indexStr, address, derivationPath, err := createNextDerivedAccount(ctx, req, walletID, account, seed)
 			if err != nil {
 				if errors.Is(err, errAccountIndexLimitReached) {
 					return logical.ErrorResponse("account index limit reached (max 2147483647)"), nil
@@ -310,6 +442,7 @@ func makeHandleBatchDerivedAccountCreate(walletMu *sync.Map) framework.Operation
 		return &logical.Response{
 			Data: map[string]interface{}{
 				"wallet_id": walletID,
+				"account":   accountStr,
 				"accounts":  accounts,
 			},
 		}, nil
@@ -319,7 +452,7 @@ func makeHandleBatchDerivedAccountCreate(walletMu *sync.Map) framework.Operation
 // parseInclusiveIndexRange validates start/end query parameters from FieldData (Method A: both required).
 // Returns (start, end, nil, nil) on valid input; (0, 0, errResp, nil) on logical validation error;
 // (0, 0, nil, err) is never returned but kept for signature consistency with handler conventions.
-func parseInclusiveIndexRange(data *framework.FieldData) (start, end int, errResp *logical.Response, err error) {
+func parseInclusiveIndexRange(data *framework.FieldData, maxSpan int) (start, end int, errResp *logical.Response, err error) {
 	wrapper := model.NewFieldDataWrapper(data)
 	startStr, e := wrapper.MustGetString("start")
 	if e != nil || startStr == "" {
@@ -348,21 +481,30 @@ func parseInclusiveIndexRange(data *framework.FieldData) (start, end int, errRes
 		return 0, 0, logical.ErrorResponse("start must be <= end"), nil
 	}
 	span := uint64(endVal) - uint64(startVal) + 1
-	if span > maxBulkReadDerivedSpan {
-		return 0, 0, logical.ErrorResponse("range must include at most %d indices (end - start + 1)", maxBulkReadDerivedSpan), nil
+	if span > uint64(maxSpan) {
+		return 0, 0, logical.ErrorResponse("range must include at most %d indices (end - start + 1)", maxSpan), nil
 	}
 	return startVal, endVal, nil, nil
 }
 
 // handleReadDerivedAccountsRange returns address and derivation_path for each index in [start, end].
 // Both start and end are required query parameters. Every index in the range must exist in storage;
-// span (end - start + 1) must be <= maxBulkReadDerivedSpan.
+// span (end - start + 1) must be <= config max_bulk_read_derived_span.
 func handleReadDerivedAccountsRange(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
-	walletID, err := model.NewFieldDataWrapper(data).MustGetString("wallet_id")
+	wrapper := model.NewFieldDataWrapper(data)
+	walletID, err := wrapper.MustGetString("wallet_id")
 	if err != nil || walletID == "" {
 		return logical.ErrorResponse("wallet_id is required"), nil
 	}
-	startVal, endVal, errResp, err := parseInclusiveIndexRange(data)
+	_, accountStr, errResp := accountSegmentFromRequest(wrapper)
+	if errResp != nil {
+		return errResp, nil
+	}
+	cfg, err := config.Read(ctx, req.Storage)
+	if err != nil {
+		return nil, err
+	}
+	startVal, endVal, errResp, err := parseInclusiveIndexRange(data, cfg.MaxBulkReadDerivedSpan)
 	if err != nil {
 		return nil, err
 	}
@@ -373,16 +515,16 @@ func handleReadDerivedAccountsRange(ctx context.Context, req *logical.Request, d
 	accounts := make([]interface{}, 0, endVal-startVal+1)
 	for idx := startVal; idx <= endVal; idx++ {
 		indexStr := strconv.Itoa(idx)
-		entry, err := req.Storage.Get(ctx, storagekey.AccountKey(walletID, indexStr))
+		entry, err := req.Storage.Get(ctx, storagekey.SegmentAccountKey(walletID, accountStr, indexStr))
 		if err != nil {
-			return nil, fmt.Errorf("get derived account %s/%s: %w", walletID, indexStr, err)
+			return nil, fmt.Errorf("get derived account %s/%s/%s: %w", walletID, accountStr, indexStr, err)
 		}
 		if entry == nil {
 			return logical.ErrorResponse("derived account not found at index %s", indexStr), nil
 		}
 		var derived model.DerivedAccount
 		if err := entry.DecodeJSON(&derived); err != nil {
-			return nil, fmt.Errorf("decode derived account %s/%s: %w", walletID, indexStr, err)
+			return nil, fmt.Errorf("decode derived account %s/%s/%s: %w", walletID, accountStr, indexStr, err)
 		}
 		accounts = append(accounts, map[string]interface{}{
 			"account_index":   indexStr,
@@ -394,25 +536,31 @@ func handleReadDerivedAccountsRange(ctx context.Context, req *logical.Request, d
 	return &logical.Response{
 		Data: map[string]interface{}{
 			"wallet_id": walletID,
+			"account":   accountStr,
 			"accounts":  accounts,
 		},
 	}, nil
 }
 
-// handleDerivedAccountRead returns stored address and derivation path for wallet_id and index.
+// handleDerivedAccountRead returns stored address and derivation path for wallet_id, account and index.
 func handleDerivedAccountRead(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
-	walletID, err := model.NewFieldDataWrapper(data).MustGetString("wallet_id")
+	wrapper := model.NewFieldDataWrapper(data)
+	walletID, err := wrapper.MustGetString("wallet_id")
 	if err != nil || walletID == "" {
 		return logical.ErrorResponse("wallet_id is required"), nil
 	}
-	indexStr, err := model.NewFieldDataWrapper(data).MustGetString("index")
+	indexStr, err := wrapper.MustGetString("index")
 	if err != nil || indexStr == "" {
 		return logical.ErrorResponse("index is required"), nil
 	}
+	_, accountStr, errResp := accountSegmentFromRequest(wrapper)
+	if errResp != nil {
+		return errResp, nil
+	}
 
-	entry, err := req.Storage.Get(ctx, storagekey.AccountKey(walletID, indexStr))
+	entry, err := req.Storage.Get(ctx, storagekey.SegmentAccountKey(walletID, accountStr, indexStr))
 	if err != nil {
-		return nil, fmt.Errorf("get derived account %s/%s: %w", walletID, indexStr, err)
+		return nil, fmt.Errorf("get derived account %s/%s/%s: %w", walletID, accountStr, indexStr, err)
 	}
 	if entry == nil {
 		return logical.ErrorResponse("derived account not found"), nil
@@ -420,27 +568,34 @@ func handleDerivedAccountRead(ctx context.Context, req *logical.Request, data *f
 
 	var derived model.DerivedAccount
 	if err := entry.DecodeJSON(&derived); err != nil {
-		return nil, fmt.Errorf("decode derived account %s/%s: %w", walletID, indexStr, err)
+		return nil, fmt.Errorf("decode derived account %s/%s/%s: %w", walletID, accountStr, indexStr, err)
 	}
 
 	return &logical.Response{
 		Data: map[string]interface{}{
 			"address":         derived.Address,
+			"account":         accountStr,
 			"account_index":   indexStr,
 			"derivation_path": derived.DerivationPath,
 		},
 	}, nil
 }
 
-// handleListDerivedAccounts returns sorted index strings for derived accounts that exist in storage.
+// handleListDerivedAccounts returns sorted index strings for derived accounts of the account segment
+// that exist in storage.
 func handleListDerivedAccounts(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
-	walletID, err := model.NewFieldDataWrapper(data).MustGetString("wallet_id")
+	wrapper := model.NewFieldDataWrapper(data)
+	walletID, err := wrapper.MustGetString("wallet_id")
 	if err != nil || walletID == "" {
 		return logical.ErrorResponse("wallet_id is required"), nil
 	}
-	children, err := req.Storage.List(ctx, storagekey.AccountsListPrefix(walletID))
+	_, accountStr, errResp := accountSegmentFromRequest(wrapper)
+	if errResp != nil {
+		return errResp, nil
+	}
+	children, err := req.Storage.List(ctx, storagekey.SegmentAccountsListPrefix(walletID, accountStr))
 	if err != nil {
-		return nil, fmt.Errorf("list derived accounts %s: %w", walletID, err)
+		return nil, fmt.Errorf("list derived accounts %s/%s: %w", walletID, accountStr, err)
 	}
 
 	indices := make([]int, 0, len(children))
@@ -463,3 +618,1119 @@ func handleListDerivedAccounts(ctx context.Context, req *logical.Request, data *
 	}
 	return logical.ListResponse(keys), nil
 }
+
+// existenceWalletPolicy reports whether a destination policy is stored for wallet_id.
+func existenceWalletPolicy(ctx context.Context, req *logical.Request, data *framework.FieldData) (bool, error) {
+	walletID := model.NewFieldDataWrapper(data).GetString("wallet_id", "")
+	if walletID == "" {
+		return false, nil
+	}
+	p, err := ReadWalletDestinationPolicy(ctx, req.Storage, walletID)
+	if err != nil {
+		return false, err
+	}
+	return p != nil, nil
+}
+
+// handleWalletPolicyRead returns the wallet's destination policy.
+func handleWalletPolicyRead(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	walletID, err := model.NewFieldDataWrapper(data).MustGetString("wallet_id")
+	if err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	p, err := ReadWalletDestinationPolicy(ctx, req.Storage, walletID)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return nil, nil
+	}
+	return &logical.Response{Data: destinationPolicyResponseData(p)}, nil
+}
+
+// handleWalletPolicyWrite merges allow/deny into the wallet's destination policy; omitted lists are kept.
+func handleWalletPolicyWrite(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	walletID, err := model.NewFieldDataWrapper(data).MustGetString("wallet_id")
+	if err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	seed, err := ReadWalletSeed(ctx, req.Storage, walletID)
+	if err != nil {
+		return nil, err
+	}
+	if seed == nil {
+		return logical.ErrorResponse("wallet not found"), nil
+	}
+	p, err := ReadWalletDestinationPolicy(ctx, req.Storage, walletID)
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		p = &model.DestinationPolicy{}
+	}
+	if raw, ok := data.GetOk("allow"); ok {
+		p.Allow = raw.([]string)
+	}
+	if raw, ok := data.GetOk("deny"); ok {
+		p.Deny = raw.([]string)
+	}
+	if err := p.Normalize(); err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	if err := WriteWalletDestinationPolicy(ctx, req.Storage, walletID, p); err != nil {
+		return nil, err
+	}
+	return &logical.Response{Data: destinationPolicyResponseData(p)}, nil
+}
+
+// handleWalletPolicyDelete removes the wallet's destination policy.
+func handleWalletPolicyDelete(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	walletID, err := model.NewFieldDataWrapper(data).MustGetString("wallet_id")
+	if err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	if err := req.Storage.Delete(ctx, storagekey.WalletPolicyKey(walletID)); err != nil {
+		return nil, fmt.Errorf("delete wallet policy %s: %w", walletID, err)
+	}
+	return nil, nil
+}
+
+// destinationPolicyResponseData builds the Vault response map for a destination policy.
+func destinationPolicyResponseData(p *model.DestinationPolicy) map[string]interface{} {
+	allow, deny := p.Allow, p.Deny
+	if allow == nil {
+		allow = []string{}
+	}
+	if deny == nil {
+		deny = []string{}
+	}
+	return map[string]interface{}{
+		"allow": allow,
+		"deny":  deny,
+	}
+}
+
+// LoadWalletSeed returns the seed of a wallet that exists and is not soft-deleted, or an error response.
+func LoadWalletSeed(ctx context.Context, s logical.Storage, walletID string) (*model.WalletSeed, *logical.Response, error) {
+	seed, err := ReadWalletSeed(ctx, s, walletID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if seed == nil || seed.Mnemonic == "" {
+		return nil, logical.ErrorResponse("wallet not found"), nil
+	}
+	tomb, err := ReadWalletTombstone(ctx, s, walletID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if tomb != nil {
+		return nil, logical.ErrorResponse("%s", ErrWalletDeleted.Error()), nil
+	}
+	return seed, nil, nil
+}
+
+// WithWalletLock wraps h so it runs while holding walletMu's mutex for the request's wallet_id.
+// Sign-tx paths use it so the velocity check and the spend record cannot interleave across requests, and every
+// journaled path, including the Bitcoin and Solana paths of a wallet, so journal appends are serialized.
+func WithWalletLock(walletMu *sync.Map, h framework.OperationFunc) framework.OperationFunc {
+	return func(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+		walletID := model.NewFieldDataWrapper(data).GetString("wallet_id", "")
+		mu, _ := walletMu.LoadOrStore(walletID, &sync.Mutex{})
+		mu.(*sync.Mutex).Lock()
+		defer mu.(*sync.Mutex).Unlock()
+		return h(ctx, req, data)
+	}
+}
+
+// recordWalletSpend appends the value of a successfully signed transaction to the wallet's spend ledger,
+// pruning entries older than the velocity window. A replacement is recorded with the value it adds above the
+// transaction it replaces, which stays in the ledger. Error responses and zero-value transactions that replace
+// nothing pass through.
+func recordWalletSpend(
+	ctx context.Context,
+	s logical.Storage,
+	walletID, accountStr, indexStr string,
+	resp *logical.Response,
+	err error,
+) (*logical.Response, error) {
+	if err != nil || resp == nil || resp.IsError() {
+		return resp, err
+	}
+	value, _ := resp.Data["value"].(string)
+	replaced, _ := resp.Data["replaced_transaction_hash"].(string)
+	spends := value != "" && value != "0"
+	if !spends && replaced == "" {
+		return resp, nil
+	}
+	limits, err := ReadWalletVelocityLimits(ctx, s, walletID)
+	if err != nil {
+		return nil, err
+	}
+	ledger, err := ReadWalletSpendLedger(ctx, s, walletID)
+	if err != nil {
+		return nil, err
+	}
+	txHash, _ := resp.Data["transaction_hash"].(string)
+	now := time.Now()
+	since := now.Add(-limits.Window())
+	entry := model.SpendEntry{Index: spendLedgerIndex(accountStr, indexStr), Value: value, TxHash: txHash, Replaces: replaced, Time: now.Unix()}
+	if replaced != "" {
+		v, _ := new(big.Int).SetString(value, 10)
+		entry.Value = ledger.ReplacementValue(replaced, v, since).String()
+	}
+	ledger.Record(entry, since)
+	if err := WriteWalletSpendLedger(ctx, s, walletID, ledger); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// recordWalletSignedTx records a successfully signed transaction in the wallet's spend ledger, the sender's
+// nonce tracker, the signed-transaction records and the derived account's signing journal.
+func recordWalletSignedTx(
+	ctx context.Context,
+	req *logical.Request,
+	walletID, accountStr, indexStr string,
+	chainID *big.Int,
+	resp *logical.Response,
+	err error,
+) (*logical.Response, error) {
+	resp, err = recordWalletSpend(ctx, req.Storage, walletID, accountStr, indexStr, resp, err)
+	resp, err = nonces.RecordSignedTx(ctx, req.Storage, chainID, resp, err)
+	resp, err = signedtxs.RecordSignedTx(ctx, req.Storage, storagekey.WalletSignedTxsPrefix(walletID), resp, err)
+	return journal.RecordSignedTx(ctx, req, storagekey.WalletJournalPrefix(walletID, accountStr, indexStr), resp, err)
+}
+
+// walletJournalPrefix resolves the journal prefix of the derived account addressed by the request path.
+func walletJournalPrefix(wrapper *model.FieldDataWrapper) (string, *logical.Response) {
+	walletID := wrapper.GetString("wallet_id", "")
+	indexStr := wrapper.GetString("index", "")
+	if walletID == "" || indexStr == "" {
+		return "", logical.ErrorResponse("wallet_id and index are required")
+	}
+	_, accountStr, errResp := accountSegmentFromRequest(wrapper)
+	if errResp != nil {
+		return "", errResp
+	}
+	return storagekey.WalletJournalPrefix(walletID, accountStr, indexStr), nil
+}
+
+// walletNonceAddress resolves the address of the derived account addressed by the request path.
+func walletNonceAddress(ctx context.Context, s logical.Storage, wrapper *model.FieldDataWrapper) (string, *logical.Response, error) {
+	walletID := wrapper.GetString("wallet_id", "")
+	indexStr := wrapper.GetString("index", "")
+	if walletID == "" || indexStr == "" {
+		return "", logical.ErrorResponse("wallet_id and index are required"), nil
+	}
+	_, accountStr, errResp := accountSegmentFromRequest(wrapper)
+	if errResp != nil {
+		return "", errResp, nil
+	}
+	derived, err := ReadSegmentDerivedAccount(ctx, s, walletID, accountStr, indexStr)
+	if err != nil {
+		return "", nil, err
+	}
+	if derived == nil {
+		return "", logical.ErrorResponse("derived account not found"), nil
+	}
+	return derived.Address, nil, nil
+}
+
+// existenceWalletLimits reports whether velocity limits are stored for wallet_id.
+func existenceWalletLimits(ctx context.Context, req *logical.Request, data *framework.FieldData) (bool, error) {
+	walletID := model.NewFieldDataWrapper(data).GetString("wallet_id", "")
+	if walletID == "" {
+		return false, nil
+	}
+	l, err := ReadWalletVelocityLimits(ctx, req.Storage, walletID)
+	if err != nil {
+		return false, err
+	}
+	return l != nil, nil
+}
+
+// handleWalletLimitsRead returns the wallet's velocity limits.
+func handleWalletLimitsRead(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	walletID, err := model.NewFieldDataWrapper(data).MustGetString("wallet_id")
+	if err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	l, err := ReadWalletVelocityLimits(ctx, req.Storage, walletID)
+	if err != nil {
+		return nil, err
+	}
+	if l == nil {
+		return nil, nil
+	}
+	return &logical.Response{Data: velocityLimitsResponseData(l)}, nil
+}
+
+// handleWalletLimitsWrite merges the supplied fields into the wallet's velocity limits; omitted fields are kept
+// and an empty cap removes it.
+func handleWalletLimitsWrite(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	walletID, err := model.NewFieldDataWrapper(data).MustGetString("wallet_id")
+	if err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	seed, err := ReadWalletSeed(ctx, req.Storage, walletID)
+	if err != nil {
+		return nil, err
+	}
+	if seed == nil {
+		return logical.ErrorResponse("wallet not found"), nil
+	}
+	l, err := ReadWalletVelocityLimits(ctx, req.Storage, walletID)
+	if err != nil {
+		return nil, err
+	}
+	if l == nil {
+		l = &model.VelocityLimits{}
+	}
+	if raw, ok := data.GetOk("window"); ok {
+		l.WindowSeconds = int64(raw.(int))
+	}
+	if raw, ok := data.GetOk("wallet_max_value"); ok {
+		l.WalletMaxValue = strings.TrimSpace(raw.(string))
+	}
+	if raw, ok := data.GetOk("account_max_value"); ok {
+		l.AccountMaxValue = strings.TrimSpace(raw.(string))
+	}
+	if err := l.Validate(); err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	if err := WriteWalletVelocityLimits(ctx, req.Storage, walletID, l); err != nil {
+		return nil, err
+	}
+	return &logical.Response{Data: velocityLimitsResponseData(l)}, nil
+}
+
+// handleWalletLimitsDelete removes the wallet's velocity limits. The spend ledger is kept.
+func handleWalletLimitsDelete(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	walletID, err := model.NewFieldDataWrapper(data).MustGetString("wallet_id")
+	if err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	if err := req.Storage.Delete(ctx, storagekey.WalletLimitsKey(walletID)); err != nil {
+		return nil, fmt.Errorf("delete wallet limits %s: %w", walletID, err)
+	}
+	return nil, nil
+}
+
+// velocityLimitsResponseData builds the Vault response map for velocity limits.
+func velocityLimitsResponseData(l *model.VelocityLimits) map[string]interface{} {
+	return map[string]interface{}{
+		"window":            int64(l.Window().Seconds()),
+		"wallet_max_value":  l.WalletMaxValue,
+		"account_max_value": l.AccountMaxValue,
+	}
+}
+
+// handleWalletSpendRead returns the native value signed within the current window, for the wallet and
+// per derived account, alongside the configured caps.
+func handleWalletSpendRead(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	walletID, err := model.NewFieldDataWrapper(data).MustGetString("wallet_id")
+	if err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	seed, err := ReadWalletSeed(ctx, req.Storage, walletID)
+	if err != nil {
+		return nil, err
+	}
+	if seed == nil {
+		return logical.ErrorResponse("wallet not found"), nil
+	}
+	limits, err := ReadWalletVelocityLimits(ctx, req.Storage, walletID)
+	if err != nil {
+		return nil, err
+	}
+	ledger, err := ReadWalletSpendLedger(ctx, req.Storage, walletID)
+	if err != nil {
+		return nil, err
+	}
+	since := time.Now().Add(-limits.Window())
+	walletSpent, _ := ledger.Totals("", since)
+	accounts := make(map[string]interface{})
+	for index, spent := range ledger.AccountTotals(since) {
+		accounts[index] = spent.String()
+	}
+	out := map[string]interface{}{
+		"window":       int64(limits.Window().Seconds()),
+		"wallet_spent": walletSpent.String(),
+		"accounts":     accounts,
+	}
+	if limits != nil {
+		out["wallet_max_value"] = limits.WalletMaxValue
+		out["account_max_value"] = limits.AccountMaxValue
+	}
+	return &logical.Response{Data: out}, nil
+}
+
+// walletXpubForSegment returns the cached account-level extended public key of an account segment, deriving
+// it from the seed and storing it on first use so later watch-only reads never load the mnemonic.
+// It returns nil when the wallet does not exist.
+func walletXpubForSegment(
+	ctx context.Context,
+	s logical.Storage,
+	walletID string,
+	account uint32,
+) (*model.WalletXpub, error) {
+	accountStr := strconv.FormatUint(uint64(account), 10)
+	x, err := ReadWalletXpub(ctx, s, walletID, accountStr)
+	if err != nil || x != nil {
+		return x, err
+	}
+	seed, err := ReadWalletSeed(ctx, s, walletID)
+	if err != nil {
+		return nil, err
+	}
+	if seed == nil || seed.Mnemonic == "" {
+		return nil, nil
+	}
+	tmpl, err := model.AccountPathTemplate(seed.PathTemplate(), account)
+	if err != nil {
+		return nil, err
+	}
+	accountPath, childPath, err := model.SplitWatchOnlyTemplate(tmpl)
+	if err != nil {
+		return nil, err
+	}
+	xpub, err := model.ExtendedPublicKeyAtPath(seed.Mnemonic, seed.Passphrase, accountPath)
+	if err != nil {
+		return nil, fmt.Errorf("derive xpub %s/%s: %w", walletID, accountStr, err)
+	}
+	x = &model.WalletXpub{Xpub: xpub, Path: accountPath, ChildPath: childPath}
+	if err := WriteWalletXpub(ctx, s, walletID, accountStr, x); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// respondWalletXpubError maps walletXpubForSegment errors caused by the wallet's template to logical responses.
+func respondWalletXpubError(err error) (*logical.Response, error) {
+	if errors.Is(err, model.ErrAccountSegmentUnsupported) || errors.Is(err, model.ErrTemplateNotWatchOnly) {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	return nil, err
+}
+
+// handleWalletXpubRead returns the extended public key at the account level of the wallet's derivation path
+// template for the account segment, or at an explicit path when one is given.
+func handleWalletXpubRead(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	wrapper := model.NewFieldDataWrapper(data)
+	walletID, err := wrapper.MustGetString("wallet_id")
+	if err != nil || walletID == "" {
+		return logical.ErrorResponse("wallet_id is required"), nil
+	}
+	if path := strings.TrimSpace(wrapper.GetString("path", "")); path != "" {
+		if path != "m" {
+			if _, err := model.ParseDerivationPath(path); err != nil {
+				return logical.ErrorResponse("%s", err.Error()), nil
+			}
+		}
+		seed, err := ReadWalletSeed(ctx, req.Storage, walletID)
+		if err != nil {
+			return nil, err
+		}
+		if seed == nil || seed.Mnemonic == "" {
+			return logical.ErrorResponse("wallet not found"), nil
+		}
+		xpub, err := model.ExtendedPublicKeyAtPath(seed.Mnemonic, seed.Passphrase, path)
+		if err != nil {
+			return nil, fmt.Errorf("derive xpub %s at %s: %w", walletID, path, err)
+		}
+		return &logical.Response{Data: map[string]interface{}{"xpub": xpub, "path": path}}, nil
+	}
+	account, accountStr, errResp := accountSegmentFromRequest(wrapper)
+	if errResp != nil {
+		return errResp, nil
+	}
+	x, err := walletXpubForSegment(ctx, req.Storage, walletID, account)
+	if err != nil {
+		return respondWalletXpubError(err)
+	}
+	if x == nil {
+		return logical.ErrorResponse("wallet not found"), nil
+	}
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"xpub":       x.Xpub,
+			"path":       x.Path,
+			"child_path": x.ChildPath,
+			"account":    accountStr,
+		},
+	}, nil
+}
+
+// handleWalletXpubAddresses returns the addresses for an inclusive start..end index range of an account
+// segment, derived from the cached extended public key only. Unlike the accounts range read, the indices need
+// not have been created.
+func handleWalletXpubAddresses(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	wrapper := model.NewFieldDataWrapper(data)
+	walletID, err := wrapper.MustGetString("wallet_id")
+	if err != nil || walletID == "" {
+		return logical.ErrorResponse("wallet_id is required"), nil
+	}
+	account, accountStr, errResp := accountSegmentFromRequest(wrapper)
+	if errResp != nil {
+		return errResp, nil
+	}
+	cfg, err := config.Read(ctx, req.Storage)
+	if err != nil {
+		return nil, err
+	}
+	startVal, endVal, errResp, err := parseInclusiveIndexRange(data, cfg.MaxBulkReadDerivedSpan)
+	if err != nil {
+		return nil, err
+	}
+	if errResp != nil {
+		return errResp, nil
+	}
+	x, err := walletXpubForSegment(ctx, req.Storage, walletID, account)
+	if err != nil {
+		return respondWalletXpubError(err)
+	}
+	if x == nil {
+		return logical.ErrorResponse("wallet not found"), nil
+	}
+
+	accounts := make([]interface{}, 0, endVal-startVal+1)
+	for idx := startVal; idx <= endVal; idx++ {
+		indexStr := strconv.Itoa(idx)
+		address, err := model.AddressFromExtendedPublicKey(x.Xpub, x.ChildPath, uint32(idx))
+		if err != nil {
+			return nil, fmt.Errorf("derive watch-only address %s/%s/%s: %w", walletID, accountStr, indexStr, err)
+		}
+		accounts = append(accounts, map[string]interface{}{
+			"account_index":   indexStr,
+			"address":         address,
+			"derivation_path": x.Path + "/" + strings.Replace(x.ChildPath, model.DerivationPathIndexPlaceholder, indexStr, 1),
+		})
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"wallet_id": walletID,
+			"account":   accountStr,
+			"xpub":      x.Xpub,
+			"accounts":  accounts,
+		},
+	}, nil
+}
+
+// handleWalletRead returns the wallet's derivation path template and deletion status.
+func handleWalletRead(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	walletID, err := model.NewFieldDataWrapper(data).MustGetString("wallet_id")
+	if err != nil || walletID == "" {
+		return logical.ErrorResponse("wallet_id is required"), nil
+	}
+	seed, err := ReadWalletSeed(ctx, req.Storage, walletID)
+	if err != nil {
+		return nil, err
+	}
+	if seed == nil {
+		return nil, nil
+	}
+	tomb, err := ReadWalletTombstone(ctx, req.Storage, walletID)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := config.Read(ctx, req.Storage)
+	if err != nil {
+		return nil, err
+	}
+	out := model.TombstoneResponseData(tomb, cfg.DeletionRetention())
+	out["wallet_id"] = walletID
+	out["derivation_path"] = seed.PathTemplate()
+	return &logical.Response{Data: out}, nil
+}
+
+// handleWalletDelete soft-deletes the wallet: a tombstone disables signing and account creation until the
+// wallet is restored. Deleting an already deleted wallet keeps the original deletion time.
+func handleWalletDelete(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	walletID, err := model.NewFieldDataWrapper(data).MustGetString("wallet_id")
+	if err != nil || walletID == "" {
+		return logical.ErrorResponse("wallet_id is required"), nil
+	}
+	seed, err := ReadWalletSeed(ctx, req.Storage, walletID)
+	if err != nil {
+		return nil, err
+	}
+	if seed == nil {
+		return logical.ErrorResponse("wallet not found"), nil
+	}
+	tomb, err := ReadWalletTombstone(ctx, req.Storage, walletID)
+	if err != nil {
+		return nil, err
+	}
+	if tomb == nil {
+		if err := WriteWalletTombstone(ctx, req.Storage, walletID, model.NewTombstone(time.Now())); err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+// handleWalletRestore removes the tombstone of a soft-deleted wallet while the config deletion_retention
+// window is open.
+func handleWalletRestore(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	walletID, err := model.NewFieldDataWrapper(data).MustGetString("wallet_id")
+	if err != nil || walletID == "" {
+		return logical.ErrorResponse("wallet_id is required"), nil
+	}
+	tomb, err := ReadWalletTombstone(ctx, req.Storage, walletID)
+	if err != nil {
+		return nil, err
+	}
+	if tomb == nil {
+		return logical.ErrorResponse("wallet is not deleted"), nil
+	}
+	cfg, err := config.Read(ctx, req.Storage)
+	if err != nil {
+		return nil, err
+	}
+	if !tomb.Restorable(cfg.DeletionRetention(), time.Now()) {
+		return logical.ErrorResponse("restore window ended at %s; purge the wallet instead",
+			tomb.RestoreDeadline(cfg.DeletionRetention()).UTC().Format(time.RFC3339)), nil
+	}
+	if err := req.Storage.Delete(ctx, storagekey.WalletTombstoneKey(walletID)); err != nil {
+		return nil, fmt.Errorf("delete wallet tombstone %s: %w", walletID, err)
+	}
+	return &logical.Response{Data: map[string]interface{}{"wallet_id": walletID, "deleted": false}}, nil
+}
+
+// handleWalletPurge permanently removes every storage entry of a soft-deleted wallet: the seed, the counters,
+// all derived accounts with their address index entries and nonce trackers, the wallet's policies, limits, ledgers
+// and signed-transaction records, and any pending Shamir import under the same wallet_id.
+func handleWalletPurge(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	walletID, err := model.NewFieldDataWrapper(data).MustGetString("wallet_id")
+	if err != nil || walletID == "" {
+		return logical.ErrorResponse("wallet_id is required"), nil
+	}
+	tomb, err := ReadWalletTombstone(ctx, req.Storage, walletID)
+	if err != nil {
+		return nil, err
+	}
+	if tomb == nil {
+		return logical.ErrorResponse("wallet must be deleted before it is purged"), nil
+	}
+	view := logical.NewStorageView(req.Storage, storagekey.WalletPrefix(walletID))
+	keys, err := logical.CollectKeys(ctx, view)
+	if err != nil {
+		return nil, fmt.Errorf("collect wallet keys %s: %w", walletID, err)
+	}
+	chainIDs, err := nonces.ListChainIDs(ctx, req.Storage)
+	if err != nil {
+		return nil, err
+	}
+	if err := req.Storage.Delete(ctx, storagekey.ShamirImportKey(walletID)); err != nil {
+		return nil, fmt.Errorf("delete shamir import %s: %w", walletID, err)
+	}
+	// Remove the tombstone last so an interrupted purge can be retried.
+	for _, key := range keys {
+		if key == "tombstone" {
+			continue
+		}
+		if err := purgeDerivedAccountRefs(ctx, req.Storage, chainIDs, walletID, key); err != nil {
+			return nil, err
+		}
+		if err := view.Delete(ctx, key); err != nil {
+			return nil, fmt.Errorf("purge wallet %s key %s: %w", walletID, key, err)
+		}
+	}
+	if err := req.Storage.Delete(ctx, storagekey.WalletTombstoneKey(walletID)); err != nil {
+		return nil, fmt.Errorf("delete wallet tombstone %s: %w", walletID, err)
+	}
+	return &logical.Response{Data: map[string]interface{}{"wallet_id": walletID, "purged_keys": len(keys)}}, nil
+}
+
+// purgeDerivedAccountRefs removes the address index entry and the nonce trackers on chainIDs of the derived account
+// stored at key (relative to the wallet prefix); other keys are ignored. Trackers of an address indexed to another
+// wallet or account holding the same key are kept.
+func purgeDerivedAccountRefs(ctx context.Context, s logical.Storage, chainIDs []string, walletID, key string) error {
+	parts := strings.Split(key, "/")
+	var accountStr, indexStr string
+	switch {
+	case len(parts) == 2 && parts[0] == "accounts":
+		accountStr, indexStr = "0", parts[1]
+	case len(parts) == 4 && parts[0] == "segments" && parts[2] == "accounts":
+		accountStr, indexStr = parts[1], parts[3]
+	default:
+		return nil
+	}
+	entry, err := s.Get(ctx, storagekey.WalletPrefix(walletID)+key)
+	if err != nil {
+		return fmt.Errorf("get derived account %s/%s/%s: %w", walletID, accountStr, indexStr, err)
+	}
+	if entry == nil {
+		return nil
+	}
+	var derived model.DerivedAccount
+	if err := entry.DecodeJSON(&derived); err != nil {
+		return fmt.Errorf("decode derived account %s/%s/%s: %w", walletID, accountStr, indexStr, err)
+	}
+	self := model.NewWalletAddressOwner(walletID, accountStr, indexStr)
+	owner, err := addressindex.ReadAddressOwner(ctx, s, derived.Address)
+	if err != nil {
+		return err
+	}
+	if err := addressindex.UnindexAddress(ctx, s, derived.Address, self); err != nil {
+		return err
+	}
+	if owner != nil && !owner.SameResource(self) {
+		return nil
+	}
+	return nonces.DeleteAll(ctx, s, chainIDs, derived.Address)
+}
+
+// backupEncryptFunc encrypts a backup payload to one recipient.
+type backupEncryptFunc func(plaintext []byte) ([]byte, error)
+
+// parseBackupRecipient parses an age X25519 recipient (age1...) or a hex secp256k1 public key. It returns the
+// matching encryption (age or ECIES) and the canonical recipient recorded in backup history: the age1 string or
+// the compressed public key.
+func parseBackupRecipient(recipient string) (backupEncryptFunc, string, error) {
+	if model.IsAgeRecipient(recipient) {
+		pub, err := ageutil.ParseRecipient(recipient)
+		if err != nil {
+			return nil, "", err
+		}
+		canonical, err := ageutil.FormatRecipient(pub)
+		if err != nil {
+			return nil, "", err
+		}
+		return func(plaintext []byte) ([]byte, error) { return ageutil.Encrypt(pub, plaintext) }, canonical, nil
+	}
+	key, err := ethutil.ParseECIESPublicKey(recipient)
+	if err != nil {
+		return nil, "", err
+	}
+	encrypt := func(plaintext []byte) ([]byte, error) { return ethutil.EncryptECIES(key, plaintext) }
+	return encrypt, hexutil.Encode(crypto.CompressPubkey(key.ExportECDSA())), nil
+}
+
+// handleWalletBackupCreate encrypts the wallet's seed, passphrase, derivation path template and segment counters
+// to a caller-supplied age X25519 recipient or secp256k1 public key (ECIES) and records the backup in wallet
+// metadata. The plaintext never leaves the plugin; wrap_ttl additionally response-wraps the ciphertext.
+func handleWalletBackupCreate(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	wrapper := model.NewFieldDataWrapper(data)
+	walletID, err := wrapper.MustGetString("wallet_id")
+	if err != nil || walletID == "" {
+		return logical.ErrorResponse("wallet_id is required"), nil
+	}
+	recipient, err := wrapper.MustGetString("recipient")
+	if err != nil || recipient == "" {
+		return logical.ErrorResponse("recipient is required"), nil
+	}
+	encrypt, canonicalRecipient, err := parseBackupRecipient(recipient)
+	if err != nil {
+		return logical.ErrorResponse("invalid recipient: %s", err.Error()), nil
+	}
+	seed, err := ReadWalletSeed(ctx, req.Storage, walletID)
+	if err != nil {
+		return nil, err
+	}
+	if seed == nil || seed.Mnemonic == "" {
+		return logical.ErrorResponse("wallet not found"), nil
+	}
+	counters, err := ReadWalletSegmentCounters(ctx, req.Storage, walletID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	plaintext, err := json.Marshal(&model.WalletBackup{
+		Version:        model.WalletBackupFormatVersion,
+		WalletID:       walletID,
+		Mnemonic:       seed.Mnemonic,
+		Passphrase:     seed.Passphrase,
+		DerivationPath: seed.PathTemplate(),
+		Counters:       counters,
+		CreatedAt:      now.Unix(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("encode wallet backup %s: %w", walletID, err)
+	}
+	ciphertext, err := encrypt(plaintext)
+	utils.ZeroBytes(plaintext)
+	if err != nil {
+		return nil, err
+	}
+
+	digest := sha256.Sum256(ciphertext)
+	record := model.WalletBackupRecord{
+		CreatedAt:        now.Unix(),
+		Recipient:        canonicalRecipient,
+		CiphertextSHA256: hex.EncodeToString(digest[:]),
+		EntityID:         req.EntityID,
+	}
+	records, err := ReadWalletBackupRecords(ctx, req.Storage, walletID)
+	if err != nil {
+		return nil, err
+	}
+	if err := WriteWalletBackupRecords(ctx, req.Storage, walletID, append(records, record)); err != nil {
+		return nil, err
+	}
+
+	out := walletBackupRecordResponseData(record)
+	out["wallet_id"] = walletID
+	out["backup"] = hexutil.Encode(ciphertext)
+	resp := &logical.Response{Data: out}
+	if raw, ok := data.GetOk("wrap_ttl"); ok && raw.(int) > 0 {
+		resp.WrapInfo = &wrapping.ResponseWrapInfo{TTL: time.Duration(raw.(int)) * time.Second}
+	}
+	return resp, nil
+}
+
+// handleWalletBackupList returns the audit records of the wallet's backups, oldest first.
+func handleWalletBackupList(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	walletID, err := model.NewFieldDataWrapper(data).MustGetString("wallet_id")
+	if err != nil || walletID == "" {
+		return logical.ErrorResponse("wallet_id is required"), nil
+	}
+	seed, err := ReadWalletSeed(ctx, req.Storage, walletID)
+	if err != nil {
+		return nil, err
+	}
+	if seed == nil {
+		return nil, nil
+	}
+	records, err := ReadWalletBackupRecords(ctx, req.Storage, walletID)
+	if err != nil {
+		return nil, err
+	}
+	backups := make([]map[string]interface{}, 0, len(records))
+	for _, record := range records {
+		backups = append(backups, walletBackupRecordResponseData(record))
+	}
+	return &logical.Response{Data: map[string]interface{}{"wallet_id": walletID, "backups": backups}}, nil
+}
+
+// handleWalletBackupRestore decrypts a backup with a single-key account of this mount (ECIES backups) or a
+// caller-supplied age identity (age backups), re-imports the seed under wallet_id and re-derives every account the
+// source wallet had allocated, restoring each segment's counter. max_batch_derived_accounts does not apply: the
+// source wallet allocated those accounts already.
+// Caller must hold the per-wallet mutex from walletMu.
+func handleWalletBackupRestore(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	wrapper := model.NewFieldDataWrapper(data)
+	walletID, err := wrapper.MustGetString("wallet_id")
+	if err != nil || walletID == "" {
+		return logical.ErrorResponse("wallet_id is required"), nil
+	}
+	backupHex, err := wrapper.MustGetString("backup")
+	if err != nil || backupHex == "" {
+		return logical.ErrorResponse("backup is required"), nil
+	}
+	ciphertext, err := hexutil.Decode(backupHex)
+	if err != nil {
+		return logical.ErrorResponse("backup must be 0x-prefixed hex"), nil
+	}
+	var plaintext []byte
+	if ageutil.IsEncrypted(ciphertext) {
+		identityStr, err := wrapper.MustGetString("identity")
+		if err != nil || identityStr == "" {
+			return logical.ErrorResponse("identity is required to restore an age backup"), nil
+		}
+		identity, err := ageutil.ParseIdentity(identityStr)
+		if err != nil {
+			return logical.ErrorResponse("invalid identity: %s", err.Error()), nil
+		}
+		plaintext, err = ageutil.Decrypt(identity, ciphertext)
+		if err != nil {
+			return logical.ErrorResponse("backup cannot be decrypted by the identity"), nil
+		}
+	} else {
+		recipientAccount, err := wrapper.MustGetString("recipient_account")
+		if err != nil || recipientAccount == "" {
+			return logical.ErrorResponse("recipient_account is required"), nil
+		}
+		keyring, err := account.ReadSingleKeyAccountKeyring(ctx, req.Storage, recipientAccount)
+		if err != nil {
+			return account.RespondLoadSingleKeyAccountError(err)
+		}
+		plaintext, _, err = keyring.Decrypt(ciphertext)
+		if err != nil {
+			return logical.ErrorResponse("backup cannot be decrypted by account %s", recipientAccount), nil
+		}
+	}
+	var backup model.WalletBackup
+	err = json.Unmarshal(plaintext, &backup)
+	utils.ZeroBytes(plaintext)
+	if err != nil {
+		return logical.ErrorResponse("backup payload is malformed"), nil
+	}
+	if err := backup.Validate(); err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+
+	segments := make([]uint32, 0, len(backup.Counters))
+	total := 0
+	for accountStr, next := range backup.Counters {
+		segment, _, err := ParseAccountSegment(accountStr)
+		if err != nil {
+			return logical.ErrorResponse("backup counters: %s", err.Error()), nil
+		}
+		segments = append(segments, segment)
+		total += int(next)
+	}
+	sort.Slice(segments, func(i, j int) bool { return segments[i] < segments[j] })
+
+	if err := putWalletSeedIfAbsent(ctx, req, walletID, backup.Mnemonic, backup.Passphrase, backup.DerivationPath); err != nil {
+		if errors.Is(err, errWalletAlreadyExists) {
+			return respondWalletConflict(req)
+		}
+		return nil, err
+	}
+	seed := &model.WalletSeed{Mnemonic: backup.Mnemonic, Passphrase: backup.Passphrase, DerivationPath: backup.DerivationPath}
+	for _, segment := range segments {
+		next := backup.Counters[strconv.FormatUint(uint64(segment), 10)]
+		for i := uint32(0); i < next; i++ {
+			if _, _, _, err := createNextDerivedAccount(ctx, req, walletID, segment, seed); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"wallet_id":         walletID,
+			"source_wallet_id":  backup.WalletID,
+			"derivation_path":   seed.PathTemplate(),
+			"restored_accounts": total,
+		},
+	}, nil
+}
+
+// walletBackupRecordResponseData renders a backup audit record for API responses.
+func walletBackupRecordResponseData(record model.WalletBackupRecord) map[string]interface{} {
+	out := map[string]interface{}{
+		"created_at":        time.Unix(record.CreatedAt, 0).UTC().Format(time.RFC3339),
+		"recipient":         record.Recipient,
+		"ciphertext_sha256": record.CiphertextSHA256,
+	}
+	if record.EntityID != "" {
+		out["entity_id"] = record.EntityID
+	}
+	if record.Format != "" {
+		out["format"] = record.Format
+	}
+	return out
+}
+
+// handleWalletShamirSplit splits the wallet's mnemonic into threshold-of-N SLIP-39 shares, one per recipient, and
+// encrypts each share to its custodian's age X25519 recipient or secp256k1 public key (ECIES). Every share is recorded in the wallet's
+// backup history. The BIP-39 passphrase is not part of the shares.
+// Caller must hold the per-wallet mutex from walletMu.
+func handleWalletShamirSplit(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	wrapper := model.NewFieldDataWrapper(data)
+	walletID, err := wrapper.MustGetString("wallet_id")
+	if err != nil || walletID == "" {
+		return logical.ErrorResponse("wallet_id is required"), nil
+	}
+	var recipients []string
+	if raw, ok := data.GetOk("recipients"); ok {
+		recipients = raw.([]string)
+	}
+	if len(recipients) == 0 {
+		return logical.ErrorResponse("recipients is required"), nil
+	}
+	if len(recipients) > maxShamirShares {
+		return logical.ErrorResponse("at most %d recipients are supported", maxShamirShares), nil
+	}
+	rawThreshold, ok := data.GetOk("threshold")
+	if !ok {
+		return logical.ErrorResponse("threshold is required"), nil
+	}
+	threshold := rawThreshold.(int)
+	if threshold < 2 || threshold > len(recipients) {
+		return logical.ErrorResponse("threshold must be between 2 and the number of recipients (%d)", len(recipients)), nil
+	}
+	iterationExponent := data.Get("iteration_exponent").(int)
+	if iterationExponent < 0 || iterationExponent > maxShamirIterationExponent {
+		return logical.ErrorResponse("iteration_exponent must be between 0 and %d", maxShamirIterationExponent), nil
+	}
+
+	encrypts := make([]backupEncryptFunc, len(recipients))
+	canonical := make([]string, len(recipients))
+	seen := make(map[string]struct{}, len(recipients))
+	for i, recipient := range recipients {
+		encrypt, canonicalRecipient, err := parseBackupRecipient(recipient)
+		if err != nil {
+			return logical.ErrorResponse("invalid recipient %d: %s", i, err.Error()), nil
+		}
+		if _, dup := seen[canonicalRecipient]; dup {
+			return logical.ErrorResponse("recipients must be distinct; %s is listed twice", canonicalRecipient), nil
+		}
+		seen[canonicalRecipient] = struct{}{}
+		encrypts[i], canonical[i] = encrypt, canonicalRecipient
+	}
+	seed, err := ReadWalletSeed(ctx, req.Storage, walletID)
+	if err != nil {
+		return nil, err
+	}
+	if seed == nil || seed.Mnemonic == "" {
+		return logical.ErrorResponse("wallet not found"), nil
+	}
+
+	shares, err := model.SplitMnemonicShares(seed.Mnemonic, threshold, len(encrypts), uint8(iterationExponent))
+	if err != nil {
+		return nil, fmt.Errorf("split wallet %s: %w", walletID, err)
+	}
+	records, err := ReadWalletBackupRecords(ctx, req.Storage, walletID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	out := make([]map[string]interface{}, len(shares))
+	for i, share := range shares {
+		ciphertext, err := encrypts[i]([]byte(share))
+		if err != nil {
+			return nil, err
+		}
+		digest := sha256.Sum256(ciphertext)
+		record := model.WalletBackupRecord{
+			CreatedAt:        now.Unix(),
+			Recipient:        canonical[i],
+			CiphertextSHA256: hex.EncodeToString(digest[:]),
+			EntityID:         req.EntityID,
+			Format:           model.WalletBackupFormatSLIP39Share,
+		}
+		records = append(records, record)
+		entry := walletBackupRecordResponseData(record)
+		entry["member_index"] = i
+		entry["share"] = hexutil.Encode(ciphertext)
+		out[i] = entry
+	}
+	if err := WriteWalletBackupRecords(ctx, req.Storage, walletID, records); err != nil {
+		return nil, err
+	}
+
+	resp := &logical.Response{
+		Data: map[string]interface{}{
+			"wallet_id":            walletID,
+			"threshold":            threshold,
+			"share_count":          len(shares),
+			"iteration_exponent":   iterationExponent,
+			"derivation_path":      seed.PathTemplate(),
+			"passphrase_protected": seed.Passphrase != "",
+			"shares":               out,
+		},
+	}
+	if raw, ok := data.GetOk("wrap_ttl"); ok && raw.(int) > 0 {
+		resp.WrapInfo = &wrapping.ResponseWrapInfo{TTL: time.Duration(raw.(int)) * time.Second}
+	}
+	return resp, nil
+}
+
+// handleWalletShamirImport accumulates SLIP-39 shares for wallet_id, across requests if need be, so custodians
+// can each submit their own share. Once the threshold is met the mnemonic is reconstructed and imported like
+// handleWalletImport, and the pending shares are discarded.
+// Caller must hold the per-wallet mutex from walletMu.
+func handleWalletShamirImport(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	wrapper := model.NewFieldDataWrapper(data)
+	walletID, err := wrapper.MustGetString("wallet_id")
+	if err != nil || walletID == "" {
+		return logical.ErrorResponse("wallet_id is required"), nil
+	}
+	var submitted []string
+	if raw, ok := data.GetOk("shares"); ok {
+		submitted = raw.([]string)
+	}
+	if len(submitted) == 0 {
+		return logical.ErrorResponse("shares is required"), nil
+	}
+	seed, err := ReadWalletSeed(ctx, req.Storage, walletID)
+	if err != nil {
+		return nil, err
+	}
+	if seed != nil {
+		return respondWalletConflict(req)
+	}
+
+	pending, err := ReadShamirImport(ctx, req.Storage, walletID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if pending == nil || pending.Expired(now) {
+		pending = &model.ShamirImport{CreatedAt: now.Unix()}
+	}
+	shares := append([]string(nil), pending.Shares...)
+	for _, m := range submitted {
+		share, err := slip39.ParseShare(m)
+		if err != nil {
+			return logical.ErrorResponse("%s", err.Error()), nil
+		}
+		if normalized := share.Mnemonic(); !slices.Contains(shares, normalized) {
+			shares = append(shares, normalized)
+		}
+	}
+
+	mnemonic, err := model.CombineMnemonicShares(shares)
+	if errors.Is(err, slip39.ErrInsufficientShares) {
+		pending.Shares = shares
+		if err := WriteShamirImport(ctx, req.Storage, walletID, pending); err != nil {
+			return nil, err
+		}
+		return &logical.Response{Data: shamirImportProgressData(walletID, pending)}, nil
+	}
+	if err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	resp, err := importWalletSeed(ctx, req, wrapper, walletID, mnemonic)
+	if err != nil || resp.IsError() {
+		return resp, err
+	}
+	if err := req.Storage.Delete(ctx, storagekey.ShamirImportKey(walletID)); err != nil {
+		return nil, fmt.Errorf("delete shamir import %s: %w", walletID, err)
+	}
+	resp.Data["complete"] = true
+	resp.Data["shares_received"] = len(shares)
+	return resp, nil
+}
+
+// handleWalletShamirImportRead reports the progress of a pending reconstruction without revealing shares. An
+// expired import reads as none.
+func handleWalletShamirImportRead(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	walletID, err := model.NewFieldDataWrapper(data).MustGetString("wallet_id")
+	if err != nil || walletID == "" {
+		return logical.ErrorResponse("wallet_id is required"), nil
+	}
+	pending, err := ReadShamirImport(ctx, req.Storage, walletID)
+	if err != nil {
+		return nil, err
+	}
+	if pending == nil || pending.Expired(time.Now()) {
+		return nil, nil
+	}
+	return &logical.Response{Data: shamirImportProgressData(walletID, pending)}, nil
+}
+
+// handleWalletShamirImportDelete discards the shares submitted toward a pending reconstruction.
+// Caller must hold the per-wallet mutex from walletMu.
+func handleWalletShamirImportDelete(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	walletID, err := model.NewFieldDataWrapper(data).MustGetString("wallet_id")
+	if err != nil || walletID == "" {
+		return logical.ErrorResponse("wallet_id is required"), nil
+	}
+	if err := req.Storage.Delete(ctx, storagekey.ShamirImportKey(walletID)); err != nil {
+		return nil, fmt.Errorf("delete shamir import %s: %w", walletID, err)
+	}
+	return nil, nil
+}
+
+// shamirImportProgressData renders a pending reconstruction: shares received so far and the member threshold.
+func shamirImportProgressData(walletID string, pending *model.ShamirImport) map[string]interface{} {
+	threshold := 0
+	if len(pending.Shares) > 0 {
+		if share, err := slip39.ParseShare(pending.Shares[0]); err == nil {
+			threshold = int(share.MemberThreshold)
+		}
+	}
+	return map[string]interface{}{
+		"wallet_id":       walletID,
+		"complete":        false,
+		"shares_received": len(pending.Shares),
+		"threshold":       threshold,
+		"started_at":      time.Unix(pending.CreatedAt, 0).UTC().Format(time.RFC3339),
+		"expires_at":      pending.ExpiresAt().UTC().Format(time.RFC3339),
+	}
+}
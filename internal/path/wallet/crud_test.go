@@ -19,22 +19,37 @@ package wallet
 
 import (
 	"context"
+	"crypto/ecdh"
+	"crypto/rand"
+	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 	"sync"
 	"testing"
+	"time"
 
+	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	ethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/crypto/ecies"
 	"github.com/ethereum/go-ethereum/signer/core/apitypes"
 	"github.com/hashicorp/vault/sdk/framework"
 	"github.com/hashicorp/vault/sdk/logical"
 	"github.com/tyler-smith/go-bip39"
 
+	"github.com/bsostech/vault-blockchain/internal/ageutil"
 	"github.com/bsostech/vault-blockchain/internal/model"
+	"github.com/bsostech/vault-blockchain/internal/path/addressindex"
+	"github.com/bsostech/vault-blockchain/internal/path/chain"
+	"github.com/bsostech/vault-blockchain/internal/path/config"
+	"github.com/bsostech/vault-blockchain/internal/path/journal"
+	"github.com/bsostech/vault-blockchain/internal/path/nonces"
+	"github.com/bsostech/vault-blockchain/internal/path/signedtxs"
 	"github.com/bsostech/vault-blockchain/internal/path/storagekey"
+	"github.com/bsostech/vault-blockchain/pkg/utils"
 )
 
 const testMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
@@ -62,8 +77,21 @@ func walletFieldData(raw map[string]interface{}) *framework.FieldData {
 		"max_priority_fee_per_gas",
 		"maxPriorityFeePerGas",
 		"access_list",
+		"max_fee_per_blob_gas",
+		"blob_versioned_hashes",
+		"blobs",
+		"blob_sidecar_version",
+		"authorization_list",
+		"address",
+		"message",
+		"version",
+		"validator",
 		"payload",
 		"mnemonic",
+		"passphrase",
+		"derivation_path",
+		"account",
+		"path",
 	}
 
 	schema := make(map[string]*framework.FieldSchema, len(raw)+len(baseKeys))
@@ -156,6 +184,73 @@ func TestHandleWalletSign_recoversAddress(t *testing.T) {
 	}
 }
 
+// TestHandleWalletSignMessage_personalSign verifies sign-message applies the EIP-191 prefix and returns v as 27/28.
+func TestHandleWalletSignMessage_personalSign(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	mustPutWalletSeed(ctx, t, s, "w1m", testMnemonic)
+	derived := mustPutDerivedAccount(ctx, t, s, "w1m", "0", testMnemonic)
+
+	req := &logical.Request{Storage: s}
+	resp, err := handleWalletSignMessage(ctx, req, walletFieldData(map[string]interface{}{
+		"wallet_id": "w1m",
+		"index":     "0",
+		"message":   "hello",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || resp.IsError() {
+		t.Fatalf("resp=%v want success.", resp)
+	}
+
+	sigHex, _ := resp.Data["signature"].(string)
+	sig, err := hexutil.Decode(sigHex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := sig[crypto.RecoveryIDOffset]; v != 27 && v != 28 {
+		t.Fatalf("v=%d want 27 or 28.", v)
+	}
+	sig[crypto.RecoveryIDOffset] -= 27
+	hash := accounts.TextHash([]byte("hello"))
+	pub, err := crypto.SigToPub(hash, sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gotAddr := crypto.PubkeyToAddress(*pub)
+	wantAddr := common.HexToAddress(derived.Address)
+	if gotAddr != wantAddr {
+		t.Fatalf("recovered address=%s want %s.", gotAddr, wantAddr)
+	}
+}
+
+// TestHandleWalletSignMessage_intendedValidatorRequiresValidator verifies version 0x00 without validator is a logical error.
+func TestHandleWalletSignMessage_intendedValidatorRequiresValidator(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	mustPutWalletSeed(ctx, t, s, "w1n", testMnemonic)
+	_ = mustPutDerivedAccount(ctx, t, s, "w1n", "0", testMnemonic)
+
+	req := &logical.Request{Storage: s}
+	resp, err := handleWalletSignMessage(ctx, req, walletFieldData(map[string]interface{}{
+		"wallet_id": "w1n",
+		"index":     "0",
+		"data":      "0x0102",
+		"version":   "0x00",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || !resp.IsError() {
+		t.Fatalf("resp=%v want logical error response.", resp)
+	}
+}
+
 // TestHandleWalletEncryptDecrypt_roundTrip verifies ECIES encrypt then decrypt for a derived wallet account.
 func TestHandleWalletEncryptDecrypt_roundTrip(t *testing.T) {
 	t.Parallel()
@@ -405,652 +500,2631 @@ func TestHandleWalletSignTxEIP1559_smoke(t *testing.T) {
 	}
 }
 
-func TestTypedDataFromPayloadWallet_rejectsInvalidJSON(t *testing.T) {
-	t.Parallel()
-
-	_, err := typedDataFromPayloadWallet("{")
-	if err == nil {
-		t.Fatal("expected error.")
-	}
-}
-
-func TestTypedDataFromPayloadWallet_requiresPrimaryType(t *testing.T) {
+// TestHandleWalletSignTxEIP2930_smoke verifies EIP-2930 sign-tx returns a type-1 tx carrying the access list.
+func TestHandleWalletSignTxEIP2930_smoke(t *testing.T) {
 	t.Parallel()
 
-	_, err := typedDataFromPayloadWallet(`{"types":{},"primaryType":"   ","domain":{},"message":{}}`)
-	if err == nil {
-		t.Fatal("expected error.")
-	}
-}
-
-func TestTypedDataFromPayloadWallet_validJSON(t *testing.T) {
-	t.Parallel()
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	mustPutWalletSeed(ctx, t, s, "w5b", testMnemonic)
+	derived := mustPutDerivedAccount(ctx, t, s, "w5b", "0", testMnemonic)
 
-	_, err := typedDataFromPayloadWallet(`{"types":{"EIP712Domain":[],"Mail":[]},"primaryType":"Mail","domain":{},"message":{}}`)
+	req := &logical.Request{Storage: s}
+	to := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	resp, err := handleWalletSignTxEIP2930(ctx, req, walletFieldData(map[string]interface{}{
+		"wallet_id":   "w5b",
+		"index":       "0",
+		"chain_id":    "1",
+		"gas_limit":   "30000",
+		"gas_price":   "3",
+		"nonce":       "0",
+		"to":          to.Hex(),
+		"access_list": `[{"address":"0x0000000000000000000000000000000000000002","storageKeys":[]}]`,
+	}))
 	if err != nil {
 		t.Fatal(err)
 	}
-}
-
-func TestTypedDataFromPayloadWallet_acceptsSnakeCasePrimaryType(t *testing.T) {
-	t.Parallel()
+	if resp == nil || resp.Data == nil {
+		t.Fatal("expected response data.")
+	}
+	if resp.Data["type"] != "eip2930" {
+		t.Fatalf("type=%v want %v.", resp.Data["type"], "eip2930")
+	}
+	if resp.Data["gas_price"] != "3" {
+		t.Fatalf("gas_price=%v want %v.", resp.Data["gas_price"], "3")
+	}
 
-	_, err := typedDataFromPayloadWallet(`{"types":{"EIP712Domain":[],"Mail":[]},"primary_type":"Mail","domain":{},"message":{}}`)
+	signedHex, _ := resp.Data["signed_transaction"].(string)
+	raw, err := hexutil.Decode(signedHex)
 	if err != nil {
 		t.Fatal(err)
 	}
-}
-
-// TestLoadSigningKeyForTx_cleanupZeroesKey verifies the cleanup callback zeroes derived signing material.
-func TestLoadSigningKeyForTx_cleanupZeroesKey(t *testing.T) {
-	t.Parallel()
-
-	ctx := context.Background()
-	s := new(logical.InmemStorage)
-	mustPutWalletSeed(ctx, t, s, "w6", testMnemonic)
-	_ = mustPutDerivedAccount(ctx, t, s, "w6", "0", testMnemonic)
-
-	pk, _, cleanup, err := loadSigningKeyForTx(ctx, s, "w6", "0")
-	if err != nil {
+	var tx ethtypes.Transaction
+	if err := tx.UnmarshalBinary(raw); err != nil {
 		t.Fatal(err)
 	}
-	if pk == nil || pk.D == nil {
-		t.Fatal("expected non-nil key.")
+	if tx.Type() != ethtypes.AccessListTxType {
+		t.Fatalf("tx.Type()=%d want %d.", tx.Type(), ethtypes.AccessListTxType)
 	}
-	if pk.D.Sign() == 0 {
-		t.Fatal("sanity: expected non-zero D before cleanup.")
+	if len(tx.AccessList()) != 1 {
+		t.Fatalf("len(tx.AccessList())=%d want 1.", len(tx.AccessList()))
 	}
-	cleanup()
-	if pk.D.Sign() != 0 {
-		t.Fatalf("after cleanup D.Sign()=%d want 0.", pk.D.Sign())
+	signer := ethtypes.LatestSignerForChainID(tx.ChainId())
+	from, err := ethtypes.Sender(signer, &tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if from != common.HexToAddress(derived.Address) {
+		t.Fatalf("recovered from=%s want %s.", from.Hex(), derived.Address)
 	}
 }
 
-// TestHandleWalletImport_invalidMnemonic verifies invalid BIP-39 input returns a logical error.
-func TestHandleWalletImport_invalidMnemonic(t *testing.T) {
+// TestHandleWalletSignTxEIP2930_invalidAccessListReturnsLogicalError verifies malformed access_list JSON is a client error.
+func TestHandleWalletSignTxEIP2930_invalidAccessListReturnsLogicalError(t *testing.T) {
 	t.Parallel()
 
 	ctx := context.Background()
 	s := new(logical.InmemStorage)
-	req := &logical.Request{Storage: s}
+	mustPutWalletSeed(ctx, t, s, "w5c", testMnemonic)
+	_ = mustPutDerivedAccount(ctx, t, s, "w5c", "0", testMnemonic)
 
-	resp, err := handleWalletImport(ctx, req, walletFieldData(map[string]interface{}{
-		"wallet_id": "wimp",
-		"mnemonic":  "not-a-mnemonic",
+	req := &logical.Request{Storage: s}
+	resp, err := handleWalletSignTxEIP2930(ctx, req, walletFieldData(map[string]interface{}{
+		"wallet_id":   "w5c",
+		"index":       "0",
+		"chain_id":    "1",
+		"gas_limit":   "21000",
+		"gas_price":   "1",
+		"access_list": "{not-json",
 	}))
 	if err != nil {
 		t.Fatal(err)
 	}
-	if resp == nil {
-		t.Fatal("expected response.")
+	if resp == nil || !resp.IsError() {
+		t.Fatalf("resp=%v want logical error response.", resp)
 	}
 }
 
-// TestHandleWalletImport_conflictReturns409 verifies duplicate import returns HTTP 409.
-func TestHandleWalletImport_conflictReturns409(t *testing.T) {
+// TestHandleWalletSignTxBlob_smoke verifies blob sign-tx from raw blobs returns a signed type-3 transaction.
+func TestHandleWalletSignTxBlob_smoke(t *testing.T) {
 	t.Parallel()
 
 	ctx := context.Background()
 	s := new(logical.InmemStorage)
-	req := &logical.Request{Storage: s}
-
-	if !bip39.IsMnemonicValid(testMnemonic) {
-		t.Fatal("sanity: expected test mnemonic to be valid.")
-	}
+	mustPutWalletSeed(ctx, t, s, "w5d", testMnemonic)
+	derived := mustPutDerivedAccount(ctx, t, s, "w5d", "0", testMnemonic)
 
-	resp, err := handleWalletImport(ctx, req, walletFieldData(map[string]interface{}{
-		"wallet_id": "wdup",
-		"mnemonic":  testMnemonic,
+	req := &logical.Request{Storage: s}
+	to := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	resp, err := handleWalletSignTxBlob(ctx, req, walletFieldData(map[string]interface{}{
+		"wallet_id":                "w5d",
+		"index":                    "0",
+		"chain_id":                 "1",
+		"gas_limit":                "21000",
+		"max_fee_per_gas":          "2",
+		"max_priority_fee_per_gas": "1",
+		"max_fee_per_blob_gas":     "3",
+		"to":                       to.Hex(),
+		"blobs":                    `["0x0001"]`,
+		"blob_sidecar_version":     "0",
 	}))
 	if err != nil {
 		t.Fatal(err)
 	}
-	if resp == nil || resp.Data == nil {
-		t.Fatal("expected response data.")
+	if resp == nil || resp.Data == nil || resp.IsError() {
+		t.Fatalf("resp=%v want response data.", resp)
+	}
+	if resp.Data["type"] != "blob" {
+		t.Fatalf("type=%v want %v.", resp.Data["type"], "blob")
 	}
 
-	resp2, err := handleWalletImport(ctx, req, walletFieldData(map[string]interface{}{
-		"wallet_id": "wdup",
-		"mnemonic":  testMnemonic,
-	}))
+	signedHex, _ := resp.Data["signed_transaction"].(string)
+	var tx ethtypes.Transaction
+	if err := tx.UnmarshalBinary(hexutil.MustDecode(signedHex)); err != nil {
+		t.Fatal(err)
+	}
+	if tx.Type() != ethtypes.BlobTxType || len(tx.BlobHashes()) != 1 {
+		t.Fatalf("tx.Type()=%d blob hashes=%d want %d/1.", tx.Type(), len(tx.BlobHashes()), ethtypes.BlobTxType)
+	}
+	signer := ethtypes.LatestSignerForChainID(tx.ChainId())
+	from, err := ethtypes.Sender(signer, &tx)
 	if err != nil {
 		t.Fatal(err)
 	}
-	if resp2 == nil {
-		t.Fatal("expected response.")
+	if from != common.HexToAddress(derived.Address) {
+		t.Fatalf("recovered from=%s want %s.", from.Hex(), derived.Address)
 	}
-	code, _ := resp2.Data[logical.HTTPStatusCode].(int)
-	if code != http.StatusConflict {
-		t.Fatalf("status=%d want %d.", code, http.StatusConflict)
+	network, _ := resp.Data["signed_transaction_network"].(string)
+	if network == "" {
+		t.Fatal("expected signed_transaction_network for raw blobs.")
+	}
+	var wrapped ethtypes.Transaction
+	if err := wrapped.UnmarshalBinary(hexutil.MustDecode(network)); err != nil {
+		t.Fatal(err)
+	}
+	if wrapped.BlobTxSidecar() == nil || wrapped.Hash() != tx.Hash() {
+		t.Fatal("network form must carry the sidecar and share the canonical hash.")
 	}
 }
 
-// TestHandleListWallets_sorted verifies listed wallet ids are sorted lexicographically.
-func TestHandleListWallets_sorted(t *testing.T) {
+// TestHandleWalletSignTxBlob_requiresTo verifies blob txs without a recipient are rejected as a client error.
+func TestHandleWalletSignTxBlob_requiresTo(t *testing.T) {
 	t.Parallel()
 
 	ctx := context.Background()
 	s := new(logical.InmemStorage)
-	mustPutWalletSeed(ctx, t, s, "b", testMnemonic)
-	mustPutWalletSeed(ctx, t, s, "a", testMnemonic)
+	mustPutWalletSeed(ctx, t, s, "w5e", testMnemonic)
+	_ = mustPutDerivedAccount(ctx, t, s, "w5e", "0", testMnemonic)
 
 	req := &logical.Request{Storage: s}
-	resp, err := handleListWallets(ctx, req, walletFieldData(map[string]interface{}{}))
+	resp, err := handleWalletSignTxBlob(ctx, req, walletFieldData(map[string]interface{}{
+		"wallet_id":                "w5e",
+		"index":                    "0",
+		"chain_id":                 "1",
+		"gas_limit":                "21000",
+		"max_fee_per_gas":          "2",
+		"max_priority_fee_per_gas": "1",
+		"max_fee_per_blob_gas":     "3",
+		"blob_versioned_hashes":    `["0x0100000000000000000000000000000000000000000000000000000000000001"]`,
+	}))
 	if err != nil {
 		t.Fatal(err)
 	}
-	keys, ok := resp.Data["keys"].([]string)
-	if !ok {
-		t.Fatalf("keys type=%T want []string.", resp.Data["keys"])
-	}
-	if len(keys) != 2 || keys[0] != "a" || keys[1] != "b" {
-		t.Fatalf("keys=%v want [a b].", keys)
+	if resp == nil || !resp.IsError() {
+		t.Fatalf("resp=%v want logical error response.", resp)
 	}
 }
 
-// TestHandleDerivedAccountCreate_autoIncrement verifies successive creates assign index 0, 1.
-func TestHandleDerivedAccountCreate_autoIncrement(t *testing.T) {
+// TestHandleWalletSignAuthorization_chainZeroNeedsRegistration verifies an every-chain authorization is refused
+// until chains/0 is registered.
+func TestHandleWalletSignAuthorization_chainZeroNeedsRegistration(t *testing.T) {
 	t.Parallel()
 
 	ctx := context.Background()
 	s := new(logical.InmemStorage)
-	mustPutWalletSeed(ctx, t, s, "wauto", testMnemonic)
+	mustPutWalletSeed(ctx, t, s, "w5z", testMnemonic)
+	mustPutDerivedAccount(ctx, t, s, "w5z", "0", testMnemonic)
 	req := &logical.Request{Storage: s}
-
-	var walletMu sync.Map
-	handler := makeHandleDerivedAccountCreate(&walletMu)
-
-	// First create → index 0.
-	resp, err := handler(ctx, req, walletFieldData(map[string]interface{}{
-		"wallet_id": "wauto",
-	}))
+	raw := map[string]interface{}{
+		"wallet_id": "w5z",
+		"index":     "0",
+		"chain_id":  "0",
+		"address":   "0x0000000000000000000000000000000000000007",
+		"nonce":     "0",
+	}
+	resp, err := handleWalletSignAuthorization(ctx, req, walletFieldData(raw))
 	if err != nil {
 		t.Fatal(err)
 	}
-	if resp == nil || resp.IsError() {
-		t.Fatalf("unexpected error response: %v", resp)
+	if resp == nil || !resp.IsError() {
+		t.Fatalf("resp=%v want chain policy error.", resp)
 	}
-	if got := resp.Data["account_index"]; got != "0" {
-		t.Fatalf("account_index=%v want 0.", got)
+	if err := chain.WritePolicy(ctx, s, "0", &model.ChainPolicy{Name: "every chain"}); err != nil {
+		t.Fatal(err)
 	}
-
-	// Second create → index 1.
-	resp2, err := handler(ctx, req, walletFieldData(map[string]interface{}{
-		"wallet_id": "wauto",
-	}))
+	resp, err = handleWalletSignAuthorization(ctx, req, walletFieldData(raw))
 	if err != nil {
 		t.Fatal(err)
 	}
-	if resp2 == nil || resp2.IsError() {
-		t.Fatalf("unexpected error response: %v", resp2)
-	}
-	if got := resp2.Data["account_index"]; got != "1" {
-		t.Fatalf("account_index=%v want 1.", got)
+	if resp == nil || resp.IsError() {
+		t.Fatalf("resp=%v want success once chains/0 is registered.", resp)
 	}
 }
 
-// TestHandleDerivedAccountCreate_missingWalletReturnsLogicalError verifies missing wallet returns an error response.
-func TestHandleDerivedAccountCreate_missingWalletReturnsLogicalError(t *testing.T) {
+// TestHandleWalletSignTxEIP7702_deniedDelegate verifies the destination policy applies to EIP-7702 delegates
+// on both sign-authorization and type-4 transactions.
+func TestHandleWalletSignTxEIP7702_deniedDelegate(t *testing.T) {
 	t.Parallel()
 
 	ctx := context.Background()
 	s := new(logical.InmemStorage)
+	mustPutWalletSeed(ctx, t, s, "w5d", testMnemonic)
+	derived := mustPutDerivedAccount(ctx, t, s, "w5d", "0", testMnemonic)
 	req := &logical.Request{Storage: s}
+	const delegate = "0x0000000000000000000000000000000000000007"
+	authRaw := map[string]interface{}{
+		"wallet_id": "w5d",
+		"index":     "0",
+		"chain_id":  "1",
+		"address":   delegate,
+		"nonce":     "1",
+	}
+	authResp, err := handleWalletSignAuthorization(ctx, req, walletFieldData(authRaw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if authResp == nil || authResp.IsError() {
+		t.Fatalf("authResp=%v want success before the policy.", authResp)
+	}
 
-	var walletMu sync.Map
-	handler := makeHandleDerivedAccountCreate(&walletMu)
-
-	resp, err := handler(ctx, req, walletFieldData(map[string]interface{}{
-		"wallet_id": "nonexistent",
+	if err := WriteWalletDestinationPolicy(ctx, s, "w5d", &model.DestinationPolicy{Deny: []string{delegate}}); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := handleWalletSignAuthorization(ctx, req, walletFieldData(authRaw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || !resp.IsError() || !strings.Contains(resp.Error().Error(), "delegate") {
+		t.Fatalf("resp=%v want delegate policy error.", resp)
+	}
+	resp, err = handleWalletSignTxEIP7702(ctx, req, walletFieldData(map[string]interface{}{
+		"wallet_id":                "w5d",
+		"index":                    "0",
+		"chain_id":                 "1",
+		"nonce":                    "0",
+		"gas_limit":                "100000",
+		"max_fee_per_gas":          "2",
+		"max_priority_fee_per_gas": "1",
+		"to":                       derived.Address,
+		"authorization_list":       "[" + authResp.Data["authorization"].(string) + "]",
 	}))
 	if err != nil {
 		t.Fatal(err)
 	}
-	if resp == nil || !resp.IsError() {
-		t.Fatal("expected error response for missing wallet.")
+	if resp == nil || !resp.IsError() || !strings.Contains(resp.Error().Error(), "delegate") {
+		t.Fatalf("resp=%v want delegate policy error.", resp)
 	}
 }
 
-// TestHandleBatchDerivedAccounts_success verifies three accounts are created with consecutive indices.
-func TestHandleBatchDerivedAccounts_success(t *testing.T) {
+// TestHandleWalletSignTxEIP7702_smoke verifies a signed authorization feeds a type-4 set-code tx.
+func TestHandleWalletSignTxEIP7702_smoke(t *testing.T) {
 	t.Parallel()
 
 	ctx := context.Background()
 	s := new(logical.InmemStorage)
-	mustPutWalletSeed(ctx, t, s, "wbatch", testMnemonic)
-	req := &logical.Request{Storage: s}
-
-	var walletMu sync.Map
-	handler := makeHandleBatchDerivedAccountCreate(&walletMu)
+	mustPutWalletSeed(ctx, t, s, "w5f", testMnemonic)
+	derived := mustPutDerivedAccount(ctx, t, s, "w5f", "0", testMnemonic)
 
-	resp, err := handler(ctx, req, walletFieldData(map[string]interface{}{
-		"wallet_id": "wbatch",
-		"count":     "3",
+	req := &logical.Request{Storage: s}
+	authResp, err := handleWalletSignAuthorization(ctx, req, walletFieldData(map[string]interface{}{
+		"wallet_id": "w5f",
+		"index":     "0",
+		"chain_id":  "1",
+		"address":   "0x0000000000000000000000000000000000000007",
+		"nonce":     "1",
 	}))
 	if err != nil {
 		t.Fatal(err)
 	}
-	if resp == nil || resp.IsError() {
-		t.Fatalf("unexpected error: %v", resp)
+	if authResp == nil || authResp.IsError() {
+		t.Fatalf("authResp=%v want success.", authResp)
 	}
-	accts, ok := resp.Data["accounts"].([]interface{})
-	if !ok || len(accts) != 3 {
+	if authResp.Data["authority"] != derived.Address {
+		t.Fatalf("authority=%v want %s.", authResp.Data["authority"], derived.Address)
+	}
+
+	resp, err := handleWalletSignTxEIP7702(ctx, req, walletFieldData(map[string]interface{}{
+		"wallet_id":                "w5f",
+		"index":                    "0",
+		"chain_id":                 "1",
+		"gas_limit":                "100000",
+		"max_fee_per_gas":          "2",
+		"max_priority_fee_per_gas": "1",
+		"nonce":                    "0",
+		"to":                       derived.Address,
+		"authorization_list":       "[" + authResp.Data["authorization"].(string) + "]",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || resp.IsError() {
+		t.Fatalf("resp=%v want success.", resp)
+	}
+	if resp.Data["type"] != "eip7702" {
+		t.Fatalf("type=%v want %v.", resp.Data["type"], "eip7702")
+	}
+
+	signedHex, _ := resp.Data["signed_transaction"].(string)
+	raw, err := hexutil.Decode(signedHex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tx ethtypes.Transaction
+	if err := tx.UnmarshalBinary(raw); err != nil {
+		t.Fatal(err)
+	}
+	if tx.Type() != ethtypes.SetCodeTxType {
+		t.Fatalf("tx.Type()=%d want %d.", tx.Type(), ethtypes.SetCodeTxType)
+	}
+	if len(tx.SetCodeAuthorizations()) != 1 {
+		t.Fatalf("len(authorizations)=%d want 1.", len(tx.SetCodeAuthorizations()))
+	}
+	from, err := ethtypes.Sender(ethtypes.LatestSignerForChainID(tx.ChainId()), &tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if from != common.HexToAddress(derived.Address) {
+		t.Fatalf("recovered from=%s want %s.", from.Hex(), derived.Address)
+	}
+}
+
+// TestHandleWalletSignTxEIP7702_requiresAuthorizationList verifies a missing authorization_list is a logical error.
+func TestHandleWalletSignTxEIP7702_requiresAuthorizationList(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	mustPutWalletSeed(ctx, t, s, "w5g", testMnemonic)
+	_ = mustPutDerivedAccount(ctx, t, s, "w5g", "0", testMnemonic)
+
+	req := &logical.Request{Storage: s}
+	resp, err := handleWalletSignTxEIP7702(ctx, req, walletFieldData(map[string]interface{}{
+		"wallet_id":                "w5g",
+		"index":                    "0",
+		"chain_id":                 "1",
+		"gas_limit":                "100000",
+		"max_fee_per_gas":          "2",
+		"max_priority_fee_per_gas": "1",
+		"to":                       "0x0000000000000000000000000000000000000007",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || !resp.IsError() {
+		t.Fatalf("resp=%v want logical error response.", resp)
+	}
+}
+
+func TestTypedDataFromPayloadWallet_rejectsInvalidJSON(t *testing.T) {
+	t.Parallel()
+
+	_, err := typedDataFromPayloadWallet("{")
+	if err == nil {
+		t.Fatal("expected error.")
+	}
+}
+
+func TestTypedDataFromPayloadWallet_requiresPrimaryType(t *testing.T) {
+	t.Parallel()
+
+	_, err := typedDataFromPayloadWallet(`{"types":{},"primaryType":"   ","domain":{},"message":{}}`)
+	if err == nil {
+		t.Fatal("expected error.")
+	}
+}
+
+func TestTypedDataFromPayloadWallet_validJSON(t *testing.T) {
+	t.Parallel()
+
+	_, err := typedDataFromPayloadWallet(`{"types":{"EIP712Domain":[],"Mail":[]},"primaryType":"Mail","domain":{},"message":{}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTypedDataFromPayloadWallet_acceptsSnakeCasePrimaryType(t *testing.T) {
+	t.Parallel()
+
+	_, err := typedDataFromPayloadWallet(`{"types":{"EIP712Domain":[],"Mail":[]},"primary_type":"Mail","domain":{},"message":{}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestLoadSigningKeyForTx_cleanupZeroesKey verifies the cleanup callback zeroes derived signing material.
+func TestLoadSigningKeyForTx_cleanupZeroesKey(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	mustPutWalletSeed(ctx, t, s, "w6", testMnemonic)
+	_ = mustPutDerivedAccount(ctx, t, s, "w6", "0", testMnemonic)
+
+	pk, _, cleanup, err := loadSigningKeyForTx(ctx, s, "w6", "0", "0", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pk == nil || pk.D == nil {
+		t.Fatal("expected non-nil key.")
+	}
+	if pk.D.Sign() == 0 {
+		t.Fatal("sanity: expected non-zero D before cleanup.")
+	}
+	cleanup()
+	if pk.D.Sign() != 0 {
+		t.Fatalf("after cleanup D.Sign()=%d want 0.", pk.D.Sign())
+	}
+}
+
+// TestHandleWalletImport_invalidMnemonic verifies invalid BIP-39 input returns a logical error.
+func TestHandleWalletImport_invalidMnemonic(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	req := &logical.Request{Storage: s}
+
+	resp, err := handleWalletImport(ctx, req, walletFieldData(map[string]interface{}{
+		"wallet_id": "wimp",
+		"mnemonic":  "not-a-mnemonic",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil {
+		t.Fatal("expected response.")
+	}
+}
+
+// TestHandleWalletImport_conflictReturns409 verifies duplicate import returns HTTP 409.
+func TestHandleWalletImport_conflictReturns409(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	req := &logical.Request{Storage: s}
+
+	if !bip39.IsMnemonicValid(testMnemonic) {
+		t.Fatal("sanity: expected test mnemonic to be valid.")
+	}
+
+	resp, err := handleWalletImport(ctx, req, walletFieldData(map[string]interface{}{
+		"wallet_id": "wdup",
+		"mnemonic":  testMnemonic,
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || resp.Data == nil {
+		t.Fatal("expected response data.")
+	}
+
+	resp2, err := handleWalletImport(ctx, req, walletFieldData(map[string]interface{}{
+		"wallet_id": "wdup",
+		"mnemonic":  testMnemonic,
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp2 == nil {
+		t.Fatal("expected response.")
+	}
+	code, _ := resp2.Data[logical.HTTPStatusCode].(int)
+	if code != http.StatusConflict {
+		t.Fatalf("status=%d want %d.", code, http.StatusConflict)
+	}
+}
+
+// TestHandleWalletImport_passphraseUsedForDerivation verifies an imported passphrase is stored with the seed
+// and used both when deriving new accounts and when loading their signing keys.
+func TestHandleWalletImport_passphraseUsedForDerivation(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	req := &logical.Request{Storage: s}
+
+	resp, err := handleWalletImport(ctx, req, walletFieldData(map[string]interface{}{
+		"wallet_id":  "wpass",
+		"mnemonic":   testMnemonic,
+		"passphrase": "TREZOR",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || resp.IsError() {
+		t.Fatalf("resp=%v want success.", resp)
+	}
+	if _, ok := resp.Data["passphrase"]; ok {
+		t.Fatal("passphrase must not be returned.")
+	}
+
+	var walletMu sync.Map
+	resp, err = makeHandleDerivedAccountCreate(&walletMu)(ctx, req, walletFieldData(map[string]interface{}{"wallet_id": "wpass"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _, err := model.DeriveEthereumAccountAtPath(testMnemonic, "TREZOR", model.DefaultEthereumDerivationPath, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	noPass, _, err := model.DeriveEthereumAccount(testMnemonic, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Data["address"] != want || want == noPass {
+		t.Fatalf("address=%v want %s (without passphrase %s).", resp.Data["address"], want, noPass)
+	}
+
+	pk, _, err := LoadWalletDerivedPrivateKey(ctx, s, "wpass", "0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer utils.ZeroKey(pk)
+}
+
+// TestHandleWalletImport_derivationPathTemplate verifies an imported derivation_path template drives the
+// derivation path and address of new accounts and the key loaded for signing.
+func TestHandleWalletImport_derivationPathTemplate(t *testing.T) {
+	t.Parallel()
+
+	const tmpl = "m/44'/60'/{index}'/0/0"
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	req := &logical.Request{Storage: s}
+
+	resp, err := handleWalletImport(ctx, req, walletFieldData(map[string]interface{}{
+		"wallet_id":       "wtmpl",
+		"mnemonic":        testMnemonic,
+		"derivation_path": tmpl,
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || resp.IsError() {
+		t.Fatalf("resp=%v want success.", resp)
+	}
+	if got := resp.Data["derivation_path"]; got != tmpl {
+		t.Fatalf("derivation_path=%v want %s.", got, tmpl)
+	}
+
+	var walletMu sync.Map
+	handler := makeHandleDerivedAccountCreate(&walletMu)
+	for i := 0; i < 2; i++ {
+		resp, err = handler(ctx, req, walletFieldData(map[string]interface{}{"wallet_id": "wtmpl"}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp == nil || resp.IsError() {
+			t.Fatalf("resp=%v want success.", resp)
+		}
+	}
+	want, wantPath, err := model.DeriveEthereumAccountAtPath(testMnemonic, "", tmpl, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wantPath != "m/44'/60'/1'/0/0" {
+		t.Fatalf("wantPath=%s.", wantPath)
+	}
+	if resp.Data["derivation_path"] != wantPath || resp.Data["address"] != want {
+		t.Fatalf("data=%v want path %s address %s.", resp.Data, wantPath, want)
+	}
+
+	pk, _, err := LoadWalletDerivedPrivateKey(ctx, s, "wtmpl", "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer utils.ZeroKey(pk)
+	if got := crypto.PubkeyToAddress(pk.PublicKey).Hex(); got != want {
+		t.Fatalf("signing key address=%s want %s.", got, want)
+	}
+}
+
+// TestHandleWalletImport_invalidDerivationPath verifies a malformed template is rejected before storing the seed.
+func TestHandleWalletImport_invalidDerivationPath(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	req := &logical.Request{Storage: s}
+
+	for _, bad := range []string{"m/{index}/{index}", "m/44'/60'/x{index}", "44'/60'"} {
+		resp, err := handleWalletImport(ctx, req, walletFieldData(map[string]interface{}{
+			"wallet_id":       "wbad",
+			"mnemonic":        testMnemonic,
+			"derivation_path": bad,
+		}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp == nil || !resp.IsError() {
+			t.Fatalf("%q: resp=%v want error response.", bad, resp)
+		}
+	}
+	seed, err := ReadWalletSeed(ctx, s, "wbad")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if seed != nil {
+		t.Fatal("seed must not be stored for an invalid template.")
+	}
+}
+
+// TestHandleListWallets_sorted verifies listed wallet ids are sorted lexicographically.
+func TestHandleListWallets_sorted(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	mustPutWalletSeed(ctx, t, s, "b", testMnemonic)
+	mustPutWalletSeed(ctx, t, s, "a", testMnemonic)
+
+	req := &logical.Request{Storage: s}
+	resp, err := handleListWallets(ctx, req, walletFieldData(map[string]interface{}{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys, ok := resp.Data["keys"].([]string)
+	if !ok {
+		t.Fatalf("keys type=%T want []string.", resp.Data["keys"])
+	}
+	if len(keys) != 2 || keys[0] != "a" || keys[1] != "b" {
+		t.Fatalf("keys=%v want [a b].", keys)
+	}
+}
+
+// TestHandleDerivedAccountCreate_autoIncrement verifies successive creates assign index 0, 1.
+func TestHandleDerivedAccountCreate_autoIncrement(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	mustPutWalletSeed(ctx, t, s, "wauto", testMnemonic)
+	req := &logical.Request{Storage: s}
+
+	var walletMu sync.Map
+	handler := makeHandleDerivedAccountCreate(&walletMu)
+
+	// First create → index 0.
+	resp, err := handler(ctx, req, walletFieldData(map[string]interface{}{
+		"wallet_id": "wauto",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || resp.IsError() {
+		t.Fatalf("unexpected error response: %v", resp)
+	}
+	if got := resp.Data["account_index"]; got != "0" {
+		t.Fatalf("account_index=%v want 0.", got)
+	}
+
+	// Second create → index 1.
+	resp2, err := handler(ctx, req, walletFieldData(map[string]interface{}{
+		"wallet_id": "wauto",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp2 == nil || resp2.IsError() {
+		t.Fatalf("unexpected error response: %v", resp2)
+	}
+	if got := resp2.Data["account_index"]; got != "1" {
+		t.Fatalf("account_index=%v want 1.", got)
+	}
+}
+
+// TestHandleDerivedAccountCreate_accountSegments verifies each account' segment has its own counter,
+// storage and derivation path, and that reads, lists and key loads address the right segment.
+func TestHandleDerivedAccountCreate_accountSegments(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	mustPutWalletSeed(ctx, t, s, "wseg", testMnemonic)
+	req := &logical.Request{Storage: s}
+
+	var walletMu sync.Map
+	handler := makeHandleDerivedAccountCreate(&walletMu)
+	resp, err := handler(ctx, req, walletFieldData(map[string]interface{}{"wallet_id": "wseg"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || resp.IsError() {
+		t.Fatalf("resp=%v want success.", resp)
+	}
+	for _, want := range []string{"0", "1"} {
+		resp, err = handler(ctx, req, walletFieldData(map[string]interface{}{"wallet_id": "wseg", "account": "02"}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp == nil || resp.IsError() {
+			t.Fatalf("resp=%v want success.", resp)
+		}
+		if resp.Data["account"] != "2" || resp.Data["account_index"] != want {
+			t.Fatalf("data=%v want account 2 index %s.", resp.Data, want)
+		}
+	}
+	wantAddr, wantPath, err := (&model.WalletSeed{Mnemonic: testMnemonic}).DeriveAccountAt(2, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wantPath != "m/44'/60'/2'/0/1" || resp.Data["derivation_path"] != wantPath || resp.Data["address"] != wantAddr {
+		t.Fatalf("data=%v want path %s address %s.", resp.Data, wantPath, wantAddr)
+	}
+
+	list, err := handleListDerivedAccounts(ctx, req, walletFieldData(map[string]interface{}{"wallet_id": "wseg", "account": "2"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keys, _ := list.Data["keys"].([]string); len(keys) != 2 {
+		t.Fatalf("segment keys=%v want 2.", list.Data["keys"])
+	}
+	list, err = handleListDerivedAccounts(ctx, req, walletFieldData(map[string]interface{}{"wallet_id": "wseg"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keys, _ := list.Data["keys"].([]string); len(keys) != 1 || keys[0] != "0" {
+		t.Fatalf("account 0 keys=%v want [0].", list.Data["keys"])
+	}
+
+	read, err := handleDerivedAccountRead(ctx, req, walletFieldData(map[string]interface{}{
+		"wallet_id": "wseg",
+		"account":   "2",
+		"index":     "1",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read == nil || read.IsError() || read.Data["address"] != wantAddr {
+		t.Fatalf("read=%v want address %s.", read, wantAddr)
+	}
+
+	pk, _, err := LoadSegmentDerivedPrivateKey(ctx, s, "wseg", "2", "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer utils.ZeroKey(pk)
+	if got := crypto.PubkeyToAddress(pk.PublicKey).Hex(); got != wantAddr {
+		t.Fatalf("signing key address=%s want %s.", got, wantAddr)
+	}
+	if _, _, err := LoadSegmentDerivedPrivateKey(ctx, s, "wseg", "3", "0"); !errors.Is(err, ErrDerivedAccountMissing) {
+		t.Fatalf("err=%v want ErrDerivedAccountMissing.", err)
+	}
+}
+
+// TestHandleDerivedAccountCreate_accountSegmentRejected verifies a malformed account, or a non-zero account on a
+// wallet whose template has no fixed account' segment, returns a logical error.
+func TestHandleDerivedAccountCreate_accountSegmentRejected(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	req := &logical.Request{Storage: s}
+	resp, err := handleWalletImport(ctx, req, walletFieldData(map[string]interface{}{
+		"wallet_id":       "wledger",
+		"mnemonic":        testMnemonic,
+		"derivation_path": "m/44'/60'/{index}'/0/0",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || resp.IsError() {
+		t.Fatalf("resp=%v want success.", resp)
+	}
+
+	var walletMu sync.Map
+	handler := makeHandleDerivedAccountCreate(&walletMu)
+	for _, account := range []string{"1", "x", "2147483648"} {
+		resp, err = handler(ctx, req, walletFieldData(map[string]interface{}{"wallet_id": "wledger", "account": account}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp == nil || !resp.IsError() {
+			t.Fatalf("account %q: resp=%v want error response.", account, resp)
+		}
+	}
+}
+
+// TestHandleDerivedAccountCreate_missingWalletReturnsLogicalError verifies missing wallet returns an error response.
+func TestHandleDerivedAccountCreate_missingWalletReturnsLogicalError(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	req := &logical.Request{Storage: s}
+
+	var walletMu sync.Map
+	handler := makeHandleDerivedAccountCreate(&walletMu)
+
+	resp, err := handler(ctx, req, walletFieldData(map[string]interface{}{
+		"wallet_id": "nonexistent",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || !resp.IsError() {
+		t.Fatal("expected error response for missing wallet.")
+	}
+}
+
+// TestHandleBatchDerivedAccounts_success verifies three accounts are created with consecutive indices.
+func TestHandleBatchDerivedAccounts_success(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	mustPutWalletSeed(ctx, t, s, "wbatch", testMnemonic)
+	req := &logical.Request{Storage: s}
+
+	var walletMu sync.Map
+	handler := makeHandleBatchDerivedAccountCreate(&walletMu)
+
+	resp, err := handler(ctx, req, walletFieldData(map[string]interface{}{
+		"wallet_id": "wbatch",
+		"count":     "3",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || resp.IsError() {
+		t.Fatalf("unexpected error: %v", resp)
+	}
+	accts, ok := resp.Data["accounts"].([]interface{})
+	if !ok || len(accts) != 3 {
 		t.Fatalf("accounts=%T %v want 3 elements.", resp.Data["accounts"], resp.Data["accounts"])
 	}
-	for i, wantIdx := range []string{"0", "1", "2"} {
-		m, ok := accts[i].(map[string]interface{})
-		if !ok {
-			t.Fatalf("accounts[%d] type=%T.", i, accts[i])
-		}
-		if got := m["account_index"]; got != wantIdx {
-			t.Fatalf("accounts[%d].account_index=%v want %s.", i, got, wantIdx)
-		}
-		if m["address"] == "" || m["derivation_path"] == "" {
-			t.Fatalf("accounts[%d] missing address or path: %#v.", i, m)
-		}
+	for i, wantIdx := range []string{"0", "1", "2"} {
+		m, ok := accts[i].(map[string]interface{})
+		if !ok {
+			t.Fatalf("accounts[%d] type=%T.", i, accts[i])
+		}
+		if got := m["account_index"]; got != wantIdx {
+			t.Fatalf("accounts[%d].account_index=%v want %s.", i, got, wantIdx)
+		}
+		if m["address"] == "" || m["derivation_path"] == "" {
+			t.Fatalf("accounts[%d] missing address or path: %#v.", i, m)
+		}
+	}
+	next, err := ReadWalletCounter(ctx, s, "wbatch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next != 3 {
+		t.Fatalf("counter next=%d want 3.", next)
+	}
+}
+
+// TestHandleBatchDerivedAccounts_exceedsMax verifies count above max returns a logical error.
+func TestHandleBatchDerivedAccounts_exceedsMax(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	mustPutWalletSeed(ctx, t, s, "wmaxb", testMnemonic)
+	req := &logical.Request{Storage: s}
+
+	var walletMu sync.Map
+	handler := makeHandleBatchDerivedAccountCreate(&walletMu)
+
+	resp, err := handler(ctx, req, walletFieldData(map[string]interface{}{
+		"wallet_id": "wmaxb",
+		"count":     "10001",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || !resp.IsError() {
+		t.Fatal("expected error for count > max_batch_derived_accounts.")
+	}
+}
+
+// TestHandleWalletCreateAuto_usesConfigDefaults verifies mnemonic_strength and default_derivation_path
+// from config apply to new wallets and their derived accounts.
+func TestHandleWalletCreateAuto_usesConfigDefaults(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	if err := config.Write(ctx, s, &model.Config{
+		MnemonicStrength:      128,
+		DefaultDerivationPath: "m/44'/1'/0'/0",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	req := &logical.Request{Storage: s}
+
+	resp, err := handleWalletCreateAuto(ctx, req, walletFieldData(map[string]interface{}{
+		"wallet_id": "wcfg",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || resp.IsError() {
+		t.Fatalf("unexpected error response: %v", resp)
+	}
+	seed, err := ReadWalletSeed(ctx, s, "wcfg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if words := len(strings.Fields(seed.Mnemonic)); words != 12 {
+		t.Fatalf("mnemonic words=%d want 12.", words)
+	}
+	if seed.DerivationPath != "m/44'/1'/0'/0" {
+		t.Fatalf("DerivationPath=%q want m/44'/1'/0'/0.", seed.DerivationPath)
+	}
+
+	// Later config changes must not move existing wallets off their creation-time path.
+	if err := config.Write(ctx, s, &model.Config{}); err != nil {
+		t.Fatal(err)
+	}
+	var walletMu sync.Map
+	resp, err = makeHandleDerivedAccountCreate(&walletMu)(ctx, req, walletFieldData(map[string]interface{}{
+		"wallet_id": "wcfg",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || resp.IsError() {
+		t.Fatalf("unexpected error response: %v", resp)
+	}
+	if got := resp.Data["derivation_path"]; got != "m/44'/1'/0'/0/0" {
+		t.Fatalf("derivation_path=%v want m/44'/1'/0'/0/0.", got)
+	}
+
+	pk, derived, err := LoadWalletDerivedPrivateKey(ctx, s, "wcfg", "0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { utils.ZeroKey(pk) })
+	if derived.Address != resp.Data["address"] {
+		t.Fatalf("address=%s want %v.", derived.Address, resp.Data["address"])
+	}
+}
+
+// TestHandleBatchDerivedAccounts_respectsConfigMax verifies max_batch_derived_accounts overrides the default cap.
+func TestHandleBatchDerivedAccounts_respectsConfigMax(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	mustPutWalletSeed(ctx, t, s, "wcfgmax", testMnemonic)
+	if err := config.Write(ctx, s, &model.Config{MaxBatchDerivedAccounts: 2}); err != nil {
+		t.Fatal(err)
+	}
+	req := &logical.Request{Storage: s}
+
+	var walletMu sync.Map
+	resp, err := makeHandleBatchDerivedAccountCreate(&walletMu)(ctx, req, walletFieldData(map[string]interface{}{
+		"wallet_id": "wcfgmax",
+		"count":     "3",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || !resp.IsError() {
+		t.Fatal("expected error for count > configured max_batch_derived_accounts.")
+	}
+}
+
+// TestHandleWalletSignTxEIP1559_rejectsDisallowedMode verifies allowed_signing_modes blocks other tx types.
+func TestHandleWalletSignTxEIP1559_rejectsDisallowedMode(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	mustPutWalletSeed(ctx, t, s, "wcfgmode", testMnemonic)
+	_ = mustPutDerivedAccount(ctx, t, s, "wcfgmode", "0", testMnemonic)
+	if err := config.Write(ctx, s, &model.Config{AllowedSigningModes: []string{model.SigningModeLegacy}}); err != nil {
+		t.Fatal(err)
+	}
+
+	req := &logical.Request{Storage: s}
+	resp, err := handleWalletSignTxEIP1559(ctx, req, walletFieldData(map[string]interface{}{
+		"wallet_id":                "wcfgmode",
+		"index":                    "0",
+		"chain_id":                 "1",
+		"max_fee_per_gas":          "2",
+		"max_priority_fee_per_gas": "1",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || !resp.IsError() {
+		t.Fatalf("resp=%v want logical error response.", resp)
+	}
+	if !strings.Contains(resp.Error().Error(), "allowed_signing_modes") {
+		t.Fatalf("error=%v want mention of allowed_signing_modes.", resp.Error())
+	}
+}
+
+// TestHandleWalletSignTxEIP1559_enforcesChainPolicy verifies unregistered chains and over-limit values are rejected
+// with the failing rule named once a chain policy is registered.
+func TestHandleWalletSignTxEIP1559_enforcesChainPolicy(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	mustPutWalletSeed(ctx, t, s, "wchain", testMnemonic)
+	_ = mustPutDerivedAccount(ctx, t, s, "wchain", "0", testMnemonic)
+	if err := chain.WritePolicy(ctx, s, "1", &model.ChainPolicy{Name: "mainnet", MaxValue: "100"}); err != nil {
+		t.Fatal(err)
+	}
+
+	req := &logical.Request{Storage: s}
+	raw := map[string]interface{}{
+		"wallet_id":                "wchain",
+		"index":                    "0",
+		"chain_id":                 "5",
+		"to":                       "0x0000000000000000000000000000000000000001",
+		"value":                    "100",
+		"max_fee_per_gas":          "2",
+		"max_priority_fee_per_gas": "1",
+	}
+	for _, tc := range []struct {
+		chainID, value, want string
+	}{
+		{"5", "100", "not registered"},
+		{"1", "101", "max_value"},
+	} {
+		raw["chain_id"], raw["value"] = tc.chainID, tc.value
+		resp, err := handleWalletSignTxEIP1559(ctx, req, walletFieldData(raw))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp == nil || !resp.IsError() || !strings.Contains(resp.Error().Error(), tc.want) {
+			t.Fatalf("chain %s value %s: resp=%v want error mentioning %s.", tc.chainID, tc.value, resp, tc.want)
+		}
+	}
+
+	raw["chain_id"], raw["value"] = "1", "100"
+	resp, err := handleWalletSignTxEIP1559(ctx, req, walletFieldData(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || resp.IsError() {
+		t.Fatalf("resp=%v want success within policy.", resp)
+	}
+}
+
+// TestHandleWalletPolicy_enforcedOnSignTx verifies a wallet destination policy is stored normalized and
+// rejects transfers to unlisted addresses while allowing a wildcard-selector rule.
+func TestHandleWalletPolicy_enforcedOnSignTx(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	mustPutWalletSeed(ctx, t, s, "wpolicy", testMnemonic)
+	_ = mustPutDerivedAccount(ctx, t, s, "wpolicy", "0", testMnemonic)
+	req := &logical.Request{Storage: s}
+
+	token := "0x00000000000000000000000000000000000000AA"
+	resp, err := handleWalletPolicyWrite(ctx, req, &framework.FieldData{
+		Raw:    map[string]interface{}{"wallet_id": "wpolicy", "allow": token + ":*"},
+		Schema: pathWalletPolicy().Fields,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || resp.IsError() {
+		t.Fatalf("resp=%v want success.", resp)
+	}
+	if allow := resp.Data["allow"].([]string); len(allow) != 1 || allow[0] != strings.ToLower(token)+":*" {
+		t.Fatalf("allow=%v want normalized rule.", allow)
+	}
+
+	raw := map[string]interface{}{
+		"wallet_id": "wpolicy",
+		"index":     "0",
+		"chain_id":  "1",
+		"gas_price": "1",
+		"to":        "0x00000000000000000000000000000000000000bb",
+	}
+	resp, err = handleWalletSignTxType0(ctx, req, walletFieldData(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || !resp.IsError() || !strings.Contains(resp.Error().Error(), "allow list") {
+		t.Fatalf("resp=%v want allow list error.", resp)
+	}
+
+	raw["to"] = token
+	raw["data"] = "0xa9059cbb"
+	resp, err = handleWalletSignTxType0(ctx, req, walletFieldData(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || resp.IsError() {
+		t.Fatalf("resp=%v want success for allowed contract.", resp)
+	}
+}
+
+// TestHandleWalletPolicyWrite_rejectsUnknownWalletAndBadRule verifies policy writes need a wallet and valid rules.
+func TestHandleWalletPolicyWrite_rejectsUnknownWalletAndBadRule(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	mustPutWalletSeed(ctx, t, s, "wpolicy2", testMnemonic)
+	req := &logical.Request{Storage: s}
+	for _, raw := range []map[string]interface{}{
+		{"wallet_id": "missing", "deny": "*:*"},
+		{"wallet_id": "wpolicy2", "deny": "not-an-address"},
+	} {
+		resp, err := handleWalletPolicyWrite(ctx, req, &framework.FieldData{Raw: raw, Schema: pathWalletPolicy().Fields})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp == nil || !resp.IsError() {
+			t.Fatalf("raw=%v resp=%v want logical error.", raw, resp)
+		}
+	}
+}
+
+// TestHandleWalletLimits_enforcedOnSignTx verifies per-account and wallet caps are enforced against the
+// recorded spend and that the spend counters are readable.
+func TestHandleWalletLimits_enforcedOnSignTx(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	mustPutWalletSeed(ctx, t, s, "wlimits", testMnemonic)
+	_ = mustPutDerivedAccount(ctx, t, s, "wlimits", "0", testMnemonic)
+	_ = mustPutDerivedAccount(ctx, t, s, "wlimits", "1", testMnemonic)
+	req := &logical.Request{Storage: s}
+
+	resp, err := handleWalletLimitsWrite(ctx, req, &framework.FieldData{
+		Raw:    map[string]interface{}{"wallet_id": "wlimits", "window": "1h", "wallet_max_value": "150", "account_max_value": "100"},
+		Schema: pathWalletLimits().Fields,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || resp.IsError() || resp.Data["window"] != int64(3600) {
+		t.Fatalf("resp=%v want success with window 3600.", resp)
+	}
+
+	sign := func(index, value string) *logical.Response {
+		t.Helper()
+		resp, err := handleWalletSignTxEIP1559(ctx, req, walletFieldData(map[string]interface{}{
+			"wallet_id":                "wlimits",
+			"index":                    index,
+			"chain_id":                 "1",
+			"to":                       "0x0000000000000000000000000000000000000001",
+			"value":                    value,
+			"max_fee_per_gas":          "2",
+			"max_priority_fee_per_gas": "1",
+		}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+	if resp := sign("0", "60"); resp == nil || resp.IsError() {
+		t.Fatalf("resp=%v want success.", resp)
+	}
+	if resp := sign("0", "50"); resp == nil || !resp.IsError() || !strings.Contains(resp.Error().Error(), "account_max_value") {
+		t.Fatalf("resp=%v want account_max_value error.", resp)
+	}
+	if resp := sign("1", "80"); resp == nil || resp.IsError() {
+		t.Fatalf("resp=%v want success.", resp)
+	}
+	if resp := sign("1", "20"); resp == nil || !resp.IsError() || !strings.Contains(resp.Error().Error(), "wallet_max_value") {
+		t.Fatalf("resp=%v want wallet_max_value error.", resp)
+	}
+
+	resp, err = handleWalletSpendRead(ctx, req, &framework.FieldData{
+		Raw:    map[string]interface{}{"wallet_id": "wlimits"},
+		Schema: pathWalletSpend().Fields,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	accounts, _ := resp.Data["accounts"].(map[string]interface{})
+	if resp.Data["wallet_spent"] != "140" || accounts["0"] != "60" || accounts["1"] != "80" {
+		t.Fatalf("data=%v want wallet_spent 140, accounts 0=60 1=80.", resp.Data)
+	}
+}
+
+// TestHandleBatchDerivedAccounts_rejectsWhenWouldExceedBIP44Range verifies the batch is rejected
+// upfront (no partial accounts) when the counter is already at the last valid index.
+func TestHandleBatchDerivedAccounts_rejectsWhenWouldExceedBIP44Range(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	mustPutWalletSeed(ctx, t, s, "wspan", testMnemonic)
+	if err := WriteWalletCounter(ctx, s, "wspan", model.MaxBIP44AddressIndex); err != nil {
+		t.Fatal(err)
+	}
+	req := &logical.Request{Storage: s}
+
+	var walletMu sync.Map
+	handler := makeHandleBatchDerivedAccountCreate(&walletMu)
+
+	resp, err := handler(ctx, req, walletFieldData(map[string]interface{}{
+		"wallet_id": "wspan",
+		"count":     "2",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || !resp.IsError() {
+		t.Fatal("expected error when batch would exceed BIP-44 max index.")
+	}
+	next, err := ReadWalletCounter(ctx, s, "wspan")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next != model.MaxBIP44AddressIndex {
+		t.Fatalf("counter next=%d want unchanged %d.", next, model.MaxBIP44AddressIndex)
+	}
+}
+
+// TestHandleBatchDerivedAccounts_afterSingle verifies batch continues counter after a single create.
+func TestHandleBatchDerivedAccounts_afterSingle(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	mustPutWalletSeed(ctx, t, s, "wmix", testMnemonic)
+	req := &logical.Request{Storage: s}
+
+	var walletMu sync.Map
+	single := makeHandleDerivedAccountCreate(&walletMu)
+	batch := makeHandleBatchDerivedAccountCreate(&walletMu)
+
+	resp1, err := single(ctx, req, walletFieldData(map[string]interface{}{"wallet_id": "wmix"}))
+	if err != nil || resp1 == nil || resp1.IsError() {
+		t.Fatalf("single create: %v %v", err, resp1)
+	}
+	if resp1.Data["account_index"] != "0" {
+		t.Fatalf("first index=%v want 0.", resp1.Data["account_index"])
+	}
+
+	resp2, err := batch(ctx, req, walletFieldData(map[string]interface{}{
+		"wallet_id": "wmix",
+		"count":     "2",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp2 == nil || resp2.IsError() {
+		t.Fatalf("batch: %v", resp2)
+	}
+	accts, _ := resp2.Data["accounts"].([]interface{})
+	if len(accts) != 2 {
+		t.Fatalf("len(accounts)=%d want 2.", len(accts))
+	}
+	if m0 := accts[0].(map[string]interface{}); m0["account_index"] != "1" {
+		t.Fatalf("batch[0] index=%v want 1.", m0["account_index"])
+	}
+	if m1 := accts[1].(map[string]interface{}); m1["account_index"] != "2" {
+		t.Fatalf("batch[1] index=%v want 2.", m1["account_index"])
+	}
+}
+
+// TestHandleListDerivedAccounts_noRange verifies all stored indices are returned when no range is given.
+func TestHandleListDerivedAccounts_noRange(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	mustPutWalletSeed(ctx, t, s, "wlist", testMnemonic)
+	mustPutDerivedAccount(ctx, t, s, "wlist", "0", testMnemonic)
+	mustPutDerivedAccount(ctx, t, s, "wlist", "2", testMnemonic)
+	mustPutDerivedAccount(ctx, t, s, "wlist", "5", testMnemonic)
+	req := &logical.Request{Storage: s}
+
+	resp, err := handleListDerivedAccounts(ctx, req, walletFieldData(map[string]interface{}{
+		"wallet_id": "wlist",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys, _ := resp.Data["keys"].([]string)
+	if len(keys) != 3 || keys[0] != "0" || keys[1] != "2" || keys[2] != "5" {
+		t.Fatalf("keys=%v want [0 2 5].", keys)
+	}
+}
+
+// TestHandleListDerivedAccounts_ignoresStartEnd verifies LIST returns all indices; start/end are not filters.
+func TestHandleListDerivedAccounts_ignoresStartEnd(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	mustPutWalletSeed(ctx, t, s, "wrange", testMnemonic)
+	for _, idx := range []string{"0", "1", "2", "3", "4"} {
+		mustPutDerivedAccount(ctx, t, s, "wrange", idx, testMnemonic)
+	}
+	req := &logical.Request{Storage: s}
+
+	resp, err := handleListDerivedAccounts(ctx, req, walletFieldData(map[string]interface{}{
+		"wallet_id": "wrange",
+		"start":     "1",
+		"end":       "3",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys, _ := resp.Data["keys"].([]string)
+	if len(keys) != 5 || keys[0] != "0" || keys[4] != "4" {
+		t.Fatalf("keys=%v want [0 1 2 3 4].", keys)
+	}
+}
+
+// TestHandleReadDerivedAccountsRange_success verifies inclusive range returns full metadata.
+func TestHandleReadDerivedAccountsRange_success(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	mustPutWalletSeed(ctx, t, s, "wmeta", testMnemonic)
+	for _, idx := range []string{"0", "1", "2"} {
+		mustPutDerivedAccount(ctx, t, s, "wmeta", idx, testMnemonic)
+	}
+	req := &logical.Request{Storage: s}
+
+	resp, err := handleReadDerivedAccountsRange(ctx, req, walletFieldData(map[string]interface{}{
+		"wallet_id": "wmeta",
+		"start":     "1",
+		"end":       "2",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || resp.IsError() {
+		t.Fatalf("unexpected error: %v", resp)
+	}
+	accts, _ := resp.Data["accounts"].([]interface{})
+	if len(accts) != 2 {
+		t.Fatalf("len(accounts)=%d want 2.", len(accts))
+	}
+}
+
+// TestHandleReadDerivedAccountsRange_missingIndex verifies a gap in the range returns an error.
+func TestHandleReadDerivedAccountsRange_missingIndex(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	mustPutWalletSeed(ctx, t, s, "wgap", testMnemonic)
+	mustPutDerivedAccount(ctx, t, s, "wgap", "0", testMnemonic)
+	mustPutDerivedAccount(ctx, t, s, "wgap", "2", testMnemonic)
+	req := &logical.Request{Storage: s}
+
+	resp, err := handleReadDerivedAccountsRange(ctx, req, walletFieldData(map[string]interface{}{
+		"wallet_id": "wgap",
+		"start":     "0",
+		"end":       "2",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || !resp.IsError() {
+		t.Fatal("expected error when index 1 is missing.")
+	}
+}
+
+// TestHandleReadDerivedAccountsRange_spanExceedsMax verifies span > max_bulk_read_derived_span errors.
+func TestHandleReadDerivedAccountsRange_spanExceedsMax(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	mustPutWalletSeed(ctx, t, s, "wspan2", testMnemonic)
+	req := &logical.Request{Storage: s}
+
+	resp, err := handleReadDerivedAccountsRange(ctx, req, walletFieldData(map[string]interface{}{
+		"wallet_id": "wspan2",
+		"start":     "0",
+		"end":       "10000",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || !resp.IsError() {
+		t.Fatal("expected error for span > max_bulk_read_derived_span.")
+	}
+}
+
+// TestHandleReadDerivedAccountsRange_startAfterEnd verifies start > end returns a logical error.
+func TestHandleReadDerivedAccountsRange_startAfterEnd(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	mustPutWalletSeed(ctx, t, s, "word", testMnemonic)
+	mustPutDerivedAccount(ctx, t, s, "word", "1", testMnemonic)
+	req := &logical.Request{Storage: s}
+
+	resp, err := handleReadDerivedAccountsRange(ctx, req, walletFieldData(map[string]interface{}{
+		"wallet_id": "word",
+		"start":     "2",
+		"end":       "1",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || !resp.IsError() {
+		t.Fatal("expected error when start > end.")
+	}
+}
+
+// TestHandleReadDerivedAccountsRange_missingStart verifies omitting start returns a logical error.
+func TestHandleReadDerivedAccountsRange_missingStart(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	mustPutWalletSeed(ctx, t, s, "wmissstart", testMnemonic)
+	req := &logical.Request{Storage: s}
+
+	resp, err := handleReadDerivedAccountsRange(ctx, req, walletFieldData(map[string]interface{}{
+		"wallet_id": "wmissstart",
+		"end":       "2",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || !resp.IsError() {
+		t.Fatal("expected error when start is missing.")
+	}
+}
+
+// TestHandleReadDerivedAccountsRange_missingEnd verifies omitting end returns a logical error.
+func TestHandleReadDerivedAccountsRange_missingEnd(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	mustPutWalletSeed(ctx, t, s, "wmissend", testMnemonic)
+	req := &logical.Request{Storage: s}
+
+	resp, err := handleReadDerivedAccountsRange(ctx, req, walletFieldData(map[string]interface{}{
+		"wallet_id": "wmissend",
+		"start":     "0",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || !resp.IsError() {
+		t.Fatal("expected error when end is missing.")
+	}
+}
+
+// faultStorage wraps logical.InmemStorage and returns an error when Put is called with a
+// key matching faultKey. After faultRemaining reaches zero the fault is cleared and
+// subsequent Puts succeed normally.
+type faultStorage struct {
+	logical.Storage
+	faultKey       string
+	faultRemaining int
+}
+
+func (f *faultStorage) Put(ctx context.Context, entry *logical.StorageEntry) error {
+	if f.faultRemaining > 0 && entry.Key == f.faultKey {
+		f.faultRemaining--
+		return fmt.Errorf("injected Put failure for %q", entry.Key)
+	}
+	return f.Storage.Put(ctx, entry)
+}
+
+// TestHandleDerivedAccountCreate_partialWriteSelfHeals verifies that when WriteWalletCounter
+// fails after a successful Put(account), a retry derives the same address at the same index
+// and succeeds — confirming the deterministic BIP-44 derivation self-heals the partial write.
+func TestHandleDerivedAccountCreate_partialWriteSelfHeals(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	inner := new(logical.InmemStorage)
+	mustPutWalletSeed(ctx, t, inner, "wpartial", testMnemonic)
+
+	// Fail the first WriteWalletCounter (counter key Put) only.
+	fs := &faultStorage{
+		Storage:        inner,
+		faultKey:       storagekey.CounterKey("wpartial"),
+		faultRemaining: 1,
+	}
+	req := &logical.Request{Storage: fs}
+
+	var walletMu sync.Map
+	handler := makeHandleDerivedAccountCreate(&walletMu)
+
+	// First attempt: account is written but counter Put fails → handler returns error.
+	_, err := handler(ctx, req, walletFieldData(map[string]interface{}{
+		"wallet_id": "wpartial",
+	}))
+	if err == nil {
+		t.Fatal("expected error from injected counter Put failure.")
+	}
+
+	// Retry: fault is cleared; counter still reads 0 so same index is re-derived.
+	resp, err := handler(ctx, req, walletFieldData(map[string]interface{}{
+		"wallet_id": "wpartial",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || resp.IsError() {
+		t.Fatalf("unexpected error on retry: %v", resp)
+	}
+	// Index must still be 0 — same account, no gap introduced.
+	if got := resp.Data["account_index"]; got != "0" {
+		t.Fatalf("account_index=%v want 0 after self-heal.", got)
+	}
+
+	// Subsequent create must advance to index 1.
+	resp2, err := handler(ctx, req, walletFieldData(map[string]interface{}{
+		"wallet_id": "wpartial",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := resp2.Data["account_index"]; got != "1" {
+		t.Fatalf("account_index=%v want 1 after self-heal.", got)
+	}
+}
+
+// TestHandleDerivedAccountCreate_updateOperationCreatesAccount verifies that UpdateOperation
+// routes to the same handler as CreateOperation and produces a valid account.
+func TestHandleDerivedAccountCreate_updateOperationCreatesAccount(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	mustPutWalletSeed(ctx, t, s, "wupdate", testMnemonic)
+
+	// UpdateOperation uses the same handler; simulate it by calling the handler directly.
+	req := &logical.Request{Storage: s, Operation: logical.UpdateOperation}
+
+	var walletMu sync.Map
+	handler := makeHandleDerivedAccountCreate(&walletMu)
+
+	resp, err := handler(ctx, req, walletFieldData(map[string]interface{}{
+		"wallet_id": "wupdate",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || resp.IsError() {
+		t.Fatalf("UpdateOperation: unexpected error response: %v", resp)
+	}
+	if got := resp.Data["account_index"]; got != "0" {
+		t.Fatalf("account_index=%v want 0.", got)
+	}
+	if resp.Data["address"] == "" {
+		t.Fatal("expected non-empty address.")
+	}
+}
+
+// TestHandleWalletXpub_watchOnlyAddresses verifies the account-level xpub export and that watch-only
+// addresses match mnemonic derivation and keep working from the cached xpub once the seed is gone.
+func TestHandleWalletXpub_watchOnlyAddresses(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	mustPutWalletSeed(ctx, t, s, "wx", testMnemonic)
+	req := &logical.Request{Storage: s}
+
+	resp, err := handleWalletXpubRead(ctx, req, walletFieldData(map[string]interface{}{"wallet_id": "wx"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || resp.IsError() {
+		t.Fatalf("resp=%v want success.", resp)
+	}
+	xpub, _ := resp.Data["xpub"].(string)
+	if !strings.HasPrefix(xpub, "xpub") || resp.Data["path"] != "m/44'/60'/0'" || resp.Data["child_path"] != "0/{index}" {
+		t.Fatalf("data=%v.", resp.Data)
+	}
+
+	seg, err := handleWalletXpubRead(ctx, req, walletFieldData(map[string]interface{}{"wallet_id": "wx", "account": "1"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if seg == nil || seg.IsError() || seg.Data["path"] != "m/44'/60'/1'" || seg.Data["xpub"] == xpub {
+		t.Fatalf("segment 1 data=%v.", seg)
+	}
+
+	if err := s.Delete(ctx, storagekey.SeedKey("wx")); err != nil {
+		t.Fatal(err)
+	}
+	resp, err = handleWalletXpubAddresses(ctx, req, walletFieldData(map[string]interface{}{
+		"wallet_id": "wx",
+		"start":     "3",
+		"end":       "5",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || resp.IsError() {
+		t.Fatalf("resp=%v want success.", resp)
+	}
+	accounts, _ := resp.Data["accounts"].([]interface{})
+	if len(accounts) != 3 {
+		t.Fatalf("accounts=%v want 3.", resp.Data["accounts"])
+	}
+	for i, raw := range accounts {
+		got := raw.(map[string]interface{})
+		want, wantPath, err := model.DeriveEthereumAccount(testMnemonic, uint32(3+i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got["address"] != want || got["derivation_path"] != wantPath {
+			t.Fatalf("index %d: got %v want %s at %s.", 3+i, got, want, wantPath)
+		}
+	}
+}
+
+// TestHandleWalletXpub_hardenedIndexTemplate verifies a template with a hardened index cannot be exported at
+// the account level, while an explicit path still can.
+func TestHandleWalletXpub_hardenedIndexTemplate(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	req := &logical.Request{Storage: s}
+	if _, err := handleWalletImport(ctx, req, walletFieldData(map[string]interface{}{
+		"wallet_id":       "wxl",
+		"mnemonic":        testMnemonic,
+		"derivation_path": "m/44'/60'/{index}'/0/0",
+	})); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := handleWalletXpubRead(ctx, req, walletFieldData(map[string]interface{}{"wallet_id": "wxl"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || !resp.IsError() {
+		t.Fatalf("resp=%v want error response.", resp)
 	}
-	next, err := ReadWalletCounter(ctx, s, "wbatch")
+	resp, err = handleWalletXpubRead(ctx, req, walletFieldData(map[string]interface{}{"wallet_id": "wxl", "path": "m/44'/60'/0'"}))
 	if err != nil {
 		t.Fatal(err)
 	}
-	if next != 3 {
-		t.Fatalf("counter next=%d want 3.", next)
+	want, err := model.ExtendedPublicKeyAtPath(testMnemonic, "", "m/44'/60'/0'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || resp.IsError() || resp.Data["xpub"] != want {
+		t.Fatalf("resp=%v want xpub %s.", resp, want)
+	}
+	resp, err = handleWalletXpubRead(ctx, req, walletFieldData(map[string]interface{}{"wallet_id": "missing"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || !resp.IsError() {
+		t.Fatalf("resp=%v want error response.", resp)
 	}
 }
 
-// TestHandleBatchDerivedAccounts_exceedsMax verifies count above max returns a logical error.
-func TestHandleBatchDerivedAccounts_exceedsMax(t *testing.T) {
+// TestHandleWalletDelete_blocksUseUntilRestored verifies a soft-deleted wallet refuses signing and account
+// creation, is flagged in list and status reads, and works again after restore.
+func TestHandleWalletDelete_blocksUseUntilRestored(t *testing.T) {
 	t.Parallel()
 
 	ctx := context.Background()
 	s := new(logical.InmemStorage)
-	mustPutWalletSeed(ctx, t, s, "wmaxb", testMnemonic)
+	mustPutWalletSeed(ctx, t, s, "wdel", testMnemonic)
+	_ = mustPutDerivedAccount(ctx, t, s, "wdel", "0", testMnemonic)
 	req := &logical.Request{Storage: s}
+	signRaw := map[string]interface{}{
+		"wallet_id": "wdel",
+		"index":     "0",
+		"data":      hexutil.Encode([]byte("hello")),
+	}
 
-	var walletMu sync.Map
-	handler := makeHandleBatchDerivedAccountCreate(&walletMu)
+	resp, err := handleWalletDelete(ctx, req, walletFieldData(map[string]interface{}{"wallet_id": "wdel"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp != nil && resp.IsError() {
+		t.Fatalf("resp=%v want success.", resp)
+	}
+	first, err := ReadWalletTombstone(ctx, s, "wdel")
+	if err != nil || first == nil {
+		t.Fatalf("tombstone=%v err=%v want tombstone.", first, err)
+	}
 
-	resp, err := handler(ctx, req, walletFieldData(map[string]interface{}{
-		"wallet_id": "wmaxb",
-		"count":     "10001",
+	resp, err = handleWalletSign(ctx, req, walletFieldData(signRaw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || !resp.IsError() || !strings.Contains(resp.Error().Error(), "wallet is deleted") {
+		t.Fatalf("resp=%v want wallet is deleted error.", resp)
+	}
+
+	var walletMu sync.Map
+	resp, err = makeHandleDerivedAccountCreate(&walletMu)(ctx, req, walletFieldData(map[string]interface{}{
+		"wallet_id": "wdel",
 	}))
 	if err != nil {
 		t.Fatal(err)
 	}
 	if resp == nil || !resp.IsError() {
-		t.Fatal("expected error for count > maxBatchDerivedAccounts.")
+		t.Fatalf("resp=%v want error creating account in deleted wallet.", resp)
+	}
+
+	resp, err = handleListWallets(ctx, req, walletFieldData(map[string]interface{}{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, _ := resp.Data["key_info"].(map[string]interface{})
+	if entry, _ := info["wdel"].(map[string]interface{}); entry["deleted"] != true {
+		t.Fatalf("key_info=%v want deleted=true.", info)
+	}
+
+	resp, err = handleWalletRead(ctx, req, walletFieldData(map[string]interface{}{"wallet_id": "wdel"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || resp.Data["deleted"] != true || resp.Data["restore_until"] == nil {
+		t.Fatalf("resp=%v want deleted status with restore_until.", resp)
+	}
+
+	// A second delete keeps the original deletion time.
+	if _, err := handleWalletDelete(ctx, req, walletFieldData(map[string]interface{}{"wallet_id": "wdel"})); err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := ReadWalletTombstone(ctx, s, "wdel"); again == nil || again.DeletedAt != first.DeletedAt {
+		t.Fatalf("tombstone=%v want unchanged %v.", again, first)
+	}
+
+	resp, err = handleWalletRestore(ctx, req, walletFieldData(map[string]interface{}{"wallet_id": "wdel"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || resp.IsError() {
+		t.Fatalf("resp=%v want success.", resp)
+	}
+	resp, err = handleWalletSign(ctx, req, walletFieldData(signRaw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || resp.IsError() {
+		t.Fatalf("resp=%v want signature after restore.", resp)
+	}
+
+	resp, err = handleWalletRestore(ctx, req, walletFieldData(map[string]interface{}{"wallet_id": "wdel"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || !resp.IsError() || !strings.Contains(resp.Error().Error(), "not deleted") {
+		t.Fatalf("resp=%v want not deleted error.", resp)
 	}
 }
 
-// TestHandleBatchDerivedAccounts_rejectsWhenWouldExceedBIP44Range verifies the batch is rejected
-// upfront (no partial accounts) when the counter is already at the last valid index.
-func TestHandleBatchDerivedAccounts_rejectsWhenWouldExceedBIP44Range(t *testing.T) {
+// TestHandleWalletRestore_windowEnded verifies restore is refused once config deletion_retention has elapsed.
+func TestHandleWalletRestore_windowEnded(t *testing.T) {
 	t.Parallel()
 
 	ctx := context.Background()
 	s := new(logical.InmemStorage)
-	mustPutWalletSeed(ctx, t, s, "wspan", testMnemonic)
-	if err := WriteWalletCounter(ctx, s, "wspan", model.MaxBIP44AddressIndex); err != nil {
+	if err := config.Write(ctx, s, &model.Config{DeletionRetentionSeconds: 3600}); err != nil {
+		t.Fatal(err)
+	}
+	mustPutWalletSeed(ctx, t, s, "wold", testMnemonic)
+	if err := WriteWalletTombstone(ctx, s, "wold", model.NewTombstone(time.Now().Add(-2*time.Hour))); err != nil {
+		t.Fatal(err)
+	}
+	req := &logical.Request{Storage: s}
+
+	resp, err := handleWalletRestore(ctx, req, walletFieldData(map[string]interface{}{"wallet_id": "wold"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || !resp.IsError() || !strings.Contains(resp.Error().Error(), "restore window ended") {
+		t.Fatalf("resp=%v want restore window error.", resp)
+	}
+}
+
+// TestHandleWalletPurge_removesAllEntries verifies purge requires a prior delete and then removes every entry
+// under the wallet prefix, the nonce trackers of its derived accounts and its pending Shamir import without
+// touching other wallets or addresses.
+func TestHandleWalletPurge_removesAllEntries(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	mustPutWalletSeed(ctx, t, s, "wpurge", testMnemonic)
+	mustPutWalletSeed(ctx, t, s, "wkeep", testMnemonic)
+	derived := mustPutDerivedAccount(ctx, t, s, "wpurge", "0", testMnemonic)
+	if err := WriteWalletCounter(ctx, s, "wpurge", 1); err != nil {
+		t.Fatal(err)
+	}
+	const otherAddress = "0x00000000000000000000000000000000000000aa"
+	for _, tracker := range []struct{ chainID, address string }{{"1", derived.Address}, {"5", derived.Address}, {"1", otherAddress}} {
+		if err := nonces.Write(ctx, s, tracker.chainID, tracker.address, &model.NonceTracker{Next: 3}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := signedtxs.Write(ctx, s, storagekey.WalletSignedTxsPrefix("wpurge"), "0xabc", &model.SignedTxRecord{Raw: "0x01"}); err != nil {
+		t.Fatal(err)
+	}
+	pending, err := logical.StorageEntryJSON(storagekey.ShamirImportKey("wpurge"), &model.ShamirImport{CreatedAt: time.Now().Unix()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Put(ctx, pending); err != nil {
 		t.Fatal(err)
 	}
 	req := &logical.Request{Storage: s}
 
+	resp, err := handleWalletPurge(ctx, req, walletFieldData(map[string]interface{}{"wallet_id": "wpurge"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || !resp.IsError() || !strings.Contains(resp.Error().Error(), "must be deleted") {
+		t.Fatalf("resp=%v want must be deleted error.", resp)
+	}
+
+	if _, err := handleWalletDelete(ctx, req, walletFieldData(map[string]interface{}{"wallet_id": "wpurge"})); err != nil {
+		t.Fatal(err)
+	}
+	resp, err = handleWalletPurge(ctx, req, walletFieldData(map[string]interface{}{"wallet_id": "wpurge"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || resp.IsError() || resp.Data["purged_keys"] != 5 {
+		t.Fatalf("resp=%v want purged_keys=5.", resp)
+	}
+
+	left, err := logical.CollectKeys(ctx, logical.NewStorageView(s, storagekey.WalletPrefix("wpurge")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(left) != 0 {
+		t.Fatalf("remaining keys=%v want none.", left)
+	}
+	for _, chainID := range []string{"1", "5"} {
+		if tracker, err := nonces.Read(ctx, s, chainID, derived.Address); err != nil || tracker != nil {
+			t.Fatalf("chain %s tracker=%+v err=%v want purged.", chainID, tracker, err)
+		}
+	}
+	if tracker, err := nonces.Read(ctx, s, "1", otherAddress); err != nil || tracker == nil {
+		t.Fatalf("tracker=%+v err=%v want other address untouched.", tracker, err)
+	}
+	if entry, err := s.Get(ctx, storagekey.ShamirImportKey("wpurge")); err != nil || entry != nil {
+		t.Fatalf("shamir import=%v err=%v want purged.", entry, err)
+	}
+	if seed, err := ReadWalletSeed(ctx, s, "wkeep"); err != nil || seed == nil {
+		t.Fatalf("seed=%v err=%v want other wallet untouched.", seed, err)
+	}
+}
+
+// TestHandleWalletBackup_restoreRoundTrip verifies a backup encrypted to a single-key account re-imports the seed,
+// passphrase, template and allocated accounts, is recorded in wallet metadata, and never exposes the mnemonic.
+// Restore re-derives every allocated account even above max_batch_derived_accounts.
+func TestHandleWalletBackup_restoreRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	req := &logical.Request{Storage: s, EntityID: "entity-1"}
 	var walletMu sync.Map
-	handler := makeHandleBatchDerivedAccountCreate(&walletMu)
 
-	resp, err := handler(ctx, req, walletFieldData(map[string]interface{}{
-		"wallet_id": "wspan",
-		"count":     "2",
+	resp, err := handleWalletImport(ctx, req, walletFieldData(map[string]interface{}{
+		"wallet_id":       "wsrc",
+		"mnemonic":        testMnemonic,
+		"passphrase":      "hidden",
+		"derivation_path": "m/44'/60'/0'/0",
 	}))
+	if err != nil || resp == nil || resp.IsError() {
+		t.Fatalf("import resp=%v err=%v.", resp, err)
+	}
+	create := makeHandleDerivedAccountCreate(&walletMu)
+	for _, segment := range []string{"0", "0", "3"} {
+		resp, err = create(ctx, req, walletFieldData(map[string]interface{}{"wallet_id": "wsrc", "account": segment}))
+		if err != nil || resp == nil || resp.IsError() {
+			t.Fatalf("create resp=%v err=%v.", resp, err)
+		}
+	}
+
+	drKey, err := crypto.GenerateKey()
 	if err != nil {
 		t.Fatal(err)
 	}
-	if resp == nil || !resp.IsError() {
-		t.Fatal("expected error when batch would exceed BIP-44 max index.")
+	drPub := hexutil.Encode(crypto.FromECDSAPub(&drKey.PublicKey))[4:]
+	entry, err := logical.StorageEntryJSON(storagekey.SingleKeyAccountKey("dr"), model.NewAccount(
+		crypto.PubkeyToAddress(drKey.PublicKey).Hex(), hexutil.Encode(crypto.FromECDSA(drKey))[2:], drPub))
+	if err != nil {
+		t.Fatal(err)
 	}
-	next, err := ReadWalletCounter(ctx, s, "wspan")
+	if err := s.Put(ctx, entry); err != nil {
+		t.Fatal(err)
+	}
+
+	backupFields := pathWalletBackup(&walletMu).Fields
+	resp, err = handleWalletBackupCreate(ctx, req, &framework.FieldData{
+		Raw:    map[string]interface{}{"wallet_id": "wsrc", "recipient": drPub, "wrap_ttl": "15m"},
+		Schema: backupFields,
+	})
 	if err != nil {
 		t.Fatal(err)
 	}
-	if next != model.MaxBIP44AddressIndex {
-		t.Fatalf("counter next=%d want unchanged %d.", next, model.MaxBIP44AddressIndex)
+	if resp == nil || resp.IsError() {
+		t.Fatalf("resp=%v want success.", resp)
+	}
+	if resp.WrapInfo == nil || resp.WrapInfo.TTL != 15*time.Minute {
+		t.Fatalf("WrapInfo=%v want 15m TTL.", resp.WrapInfo)
+	}
+	backupHex, _ := resp.Data["backup"].(string)
+	if backupHex == "" || strings.Contains(fmt.Sprint(resp.Data), testMnemonic) {
+		t.Fatalf("data=%v want ciphertext without the mnemonic.", resp.Data)
+	}
+
+	resp, err = handleWalletBackupList(ctx, req, &framework.FieldData{
+		Raw:    map[string]interface{}{"wallet_id": "wsrc"},
+		Schema: backupFields,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	backups, _ := resp.Data["backups"].([]map[string]interface{})
+	if len(backups) != 1 || backups[0]["entity_id"] != "entity-1" || backups[0]["ciphertext_sha256"] == "" {
+		t.Fatalf("backups=%v want one record for entity-1.", backups)
+	}
+
+	if err := config.Write(ctx, s, &model.Config{MaxBatchDerivedAccounts: 1}); err != nil {
+		t.Fatal(err)
+	}
+	restoreFields := pathWalletBackupRestore(&walletMu).Fields
+	restoreRaw := map[string]interface{}{"wallet_id": "wdst", "backup": backupHex, "recipient_account": "dr"}
+	resp, err = handleWalletBackupRestore(ctx, req, &framework.FieldData{Raw: restoreRaw, Schema: restoreFields})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || resp.IsError() || resp.Data["restored_accounts"] != 3 || resp.Data["source_wallet_id"] != "wsrc" {
+		t.Fatalf("resp=%v want 3 restored accounts from wsrc.", resp)
+	}
+	for _, key := range [][2]string{{"0", "0"}, {"0", "1"}, {"3", "0"}} {
+		_, want, err := LoadSegmentDerivedPrivateKey(ctx, s, "wsrc", key[0], key[1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, got, err := LoadSegmentDerivedPrivateKey(ctx, s, "wdst", key[0], key[1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Address != want.Address {
+			t.Fatalf("account %s/%s address=%s want %s.", key[0], key[1], got.Address, want.Address)
+		}
+	}
+	if next, err := ReadWalletCounter(ctx, s, "wdst"); err != nil || next != 2 {
+		t.Fatalf("counter=%d err=%v want 2.", next, err)
+	}
+	if counters, err := ReadWalletSegmentCounters(ctx, s, "wdst"); err != nil || counters["3"] != 1 {
+		t.Fatalf("counters=%v err=%v want segment 3 at 1.", counters, err)
+	}
+
+	resp, err = handleWalletBackupRestore(ctx, req, &framework.FieldData{Raw: restoreRaw, Schema: restoreFields})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || resp.Data[logical.HTTPStatusCode] != http.StatusConflict {
+		t.Fatalf("resp=%v want 409 for existing wallet.", resp)
 	}
 }
 
-// TestHandleBatchDerivedAccounts_afterSingle verifies batch continues counter after a single create.
-func TestHandleBatchDerivedAccounts_afterSingle(t *testing.T) {
+// TestHandleWalletBackup_ageRoundTrip verifies a backup to an age recipient is an age file, is recorded under the
+// canonical recipient, and restores only with the matching identity.
+func TestHandleWalletBackup_ageRoundTrip(t *testing.T) {
 	t.Parallel()
 
 	ctx := context.Background()
 	s := new(logical.InmemStorage)
-	mustPutWalletSeed(ctx, t, s, "wmix", testMnemonic)
 	req := &logical.Request{Storage: s}
-
 	var walletMu sync.Map
-	single := makeHandleDerivedAccountCreate(&walletMu)
-	batch := makeHandleBatchDerivedAccountCreate(&walletMu)
+	mustPutWalletSeed(ctx, t, s, "wsrc", testMnemonic)
+	resp, err := makeHandleDerivedAccountCreate(&walletMu)(ctx, req, walletFieldData(map[string]interface{}{"wallet_id": "wsrc"}))
+	if err != nil || resp == nil || resp.IsError() {
+		t.Fatalf("create resp=%v err=%v.", resp, err)
+	}
 
-	resp1, err := single(ctx, req, walletFieldData(map[string]interface{}{"wallet_id": "wmix"}))
-	if err != nil || resp1 == nil || resp1.IsError() {
-		t.Fatalf("single create: %v %v", err, resp1)
+	identity, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
 	}
-	if resp1.Data["account_index"] != "0" {
-		t.Fatalf("first index=%v want 0.", resp1.Data["account_index"])
+	identityStr, err := ageutil.FormatIdentity(identity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recipient, err := ageutil.FormatRecipient(identity.PublicKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err = handleWalletBackupCreate(ctx, req, &framework.FieldData{
+		Raw:    map[string]interface{}{"wallet_id": "wsrc", "recipient": " " + strings.ToUpper(recipient) + " "},
+		Schema: pathWalletBackup(&walletMu).Fields,
+	})
+	if err != nil || resp == nil || resp.IsError() {
+		t.Fatalf("resp=%v err=%v want success.", resp, err)
+	}
+	if resp.Data["recipient"] != recipient {
+		t.Fatalf("recipient=%v want %s.", resp.Data["recipient"], recipient)
+	}
+	backup, err := hexutil.Decode(resp.Data["backup"].(string))
+	if err != nil || !ageutil.IsEncrypted(backup) {
+		t.Fatalf("backup=%x err=%v want an age file.", backup, err)
 	}
 
-	resp2, err := batch(ctx, req, walletFieldData(map[string]interface{}{
-		"wallet_id": "wmix",
-		"count":     "2",
+	other, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherStr, err := ageutil.FormatIdentity(other)
+	if err != nil {
+		t.Fatal(err)
+	}
+	restoreFields := pathWalletBackupRestore(&walletMu).Fields
+	for _, tc := range []struct {
+		name     string
+		identity string
+		wantErr  string
+	}{
+		{"missing identity", "", "identity is required"},
+		{"other identity", otherStr, "cannot be decrypted"},
+		{"invalid identity", recipient, "invalid identity"},
+	} {
+		raw := map[string]interface{}{"wallet_id": "wdst", "backup": resp.Data["backup"], "recipient_account": "dr"}
+		if tc.identity != "" {
+			raw["identity"] = tc.identity
+		}
+		got, err := handleWalletBackupRestore(ctx, req, &framework.FieldData{Raw: raw, Schema: restoreFields})
+		if err != nil || got == nil || !got.IsError() || !strings.Contains(got.Error().Error(), tc.wantErr) {
+			t.Fatalf("%s: resp=%v err=%v want %q.", tc.name, got, err, tc.wantErr)
+		}
+	}
+
+	got, err := handleWalletBackupRestore(ctx, req, &framework.FieldData{
+		Raw:    map[string]interface{}{"wallet_id": "wdst", "backup": resp.Data["backup"], "identity": identityStr},
+		Schema: restoreFields,
+	})
+	if err != nil || got == nil || got.IsError() || got.Data["restored_accounts"] != 1 {
+		t.Fatalf("resp=%v err=%v want 1 restored account.", got, err)
+	}
+	seed, err := ReadWalletSeed(ctx, s, "wdst")
+	if err != nil || seed == nil || seed.Mnemonic != testMnemonic {
+		t.Fatalf("seed=%v err=%v want the source mnemonic.", seed, err)
+	}
+}
+
+// TestHandleWalletShamir_splitAndReconstruct splits a wallet 2-of-3 to custodian keys, then reconstructs it from
+// two decrypted shares submitted in separate requests.
+func TestHandleWalletShamir_splitAndReconstruct(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := new(logical.InmemStorage)
+	req := &logical.Request{Storage: s, EntityID: "entity-1"}
+	var walletMu sync.Map
+
+	resp, err := handleWalletImport(ctx, req, walletFieldData(map[string]interface{}{
+		"wallet_id":       "wsrc",
+		"mnemonic":        testMnemonic,
+		"passphrase":      "hidden",
+		"derivation_path": "m/44'/60'/0'/0",
 	}))
+	if err != nil || resp == nil || resp.IsError() {
+		t.Fatalf("import resp=%v err=%v.", resp, err)
+	}
+
+	custodians := make([]*ecies.PrivateKey, 3)
+	recipients := make([]string, 3)
+	for i := range custodians {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		custodians[i] = ecies.ImportECDSA(key)
+		recipients[i] = hexutil.Encode(crypto.CompressPubkey(&key.PublicKey))
+	}
+
+	splitFields := pathWalletShamir(&walletMu).Fields
+	resp, err = handleWalletShamirSplit(ctx, req, &framework.FieldData{
+		Raw:    map[string]interface{}{"wallet_id": "wsrc", "threshold": 1, "recipients": recipients},
+		Schema: splitFields,
+	})
 	if err != nil {
 		t.Fatal(err)
 	}
-	if resp2 == nil || resp2.IsError() {
-		t.Fatalf("batch: %v", resp2)
+	if resp == nil || !resp.IsError() {
+		t.Fatalf("resp=%v want threshold error.", resp)
 	}
-	accts, _ := resp2.Data["accounts"].([]interface{})
-	if len(accts) != 2 {
-		t.Fatalf("len(accounts)=%d want 2.", len(accts))
+
+	resp, err = handleWalletShamirSplit(ctx, req, &framework.FieldData{
+		Raw:    map[string]interface{}{"wallet_id": "wsrc", "threshold": 2, "recipients": recipients, "iteration_exponent": 0},
+		Schema: splitFields,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || resp.IsError() || resp.Data["passphrase_protected"] != true {
+		t.Fatalf("resp=%v want success flagged passphrase_protected.", resp)
+	}
+	if strings.Contains(fmt.Sprint(resp.Data), "abandon") {
+		t.Fatalf("data=%v must not contain plaintext shares or the mnemonic.", resp.Data)
+	}
+	out, _ := resp.Data["shares"].([]map[string]interface{})
+	if len(out) != 3 {
+		t.Fatalf("shares=%v want 3.", out)
+	}
+	plain := make([]string, len(out))
+	for i, entry := range out {
+		if entry["recipient"] != recipients[i] || entry["format"] != model.WalletBackupFormatSLIP39Share {
+			t.Fatalf("share %d=%v want recipient %s.", i, entry, recipients[i])
+		}
+		ciphertext, err := hexutil.Decode(entry["share"].(string))
+		if err != nil {
+			t.Fatal(err)
+		}
+		pt, err := custodians[i].Decrypt(ciphertext, nil, nil)
+		if err != nil {
+			t.Fatalf("custodian %d decrypt: %v", i, err)
+		}
+		plain[i] = string(pt)
+	}
+	records, err := ReadWalletBackupRecords(ctx, s, "wsrc")
+	if err != nil || len(records) != 3 {
+		t.Fatalf("records=%v err=%v want 3.", records, err)
+	}
+
+	importFields := pathWalletShamirImport(&walletMu).Fields
+	for i := 0; i < 2; i++ {
+		resp, err = handleWalletShamirImport(ctx, req, &framework.FieldData{
+			Raw:    map[string]interface{}{"wallet_id": "wdst", "shares": []string{strings.ToUpper(plain[0])}},
+			Schema: importFields,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp == nil || resp.IsError() || resp.Data["complete"] != false || resp.Data["shares_received"] != 1 ||
+			resp.Data["threshold"] != 2 {
+			t.Fatalf("resp=%v want 1 of 2 shares pending.", resp)
+		}
+	}
+	if seed, err := ReadWalletSeed(ctx, s, "wdst"); err != nil || seed != nil {
+		t.Fatalf("seed=%v err=%v want none below threshold.", seed, err)
+	}
+	if entries, _ := s.List(ctx, "wallets/"); len(entries) != 1 {
+		t.Fatalf("wallets=%v want pending import not listed.", entries)
+	}
+
+	resp, err = handleWalletShamirImport(ctx, req, &framework.FieldData{
+		Raw: map[string]interface{}{
+			"wallet_id":       "wdst",
+			"shares":          []string{plain[2]},
+			"passphrase":      "hidden",
+			"derivation_path": "m/44'/60'/0'/0",
+		},
+		Schema: importFields,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp == nil || resp.IsError() || resp.Data["complete"] != true {
+		t.Fatalf("resp=%v want reconstruction complete.", resp)
 	}
-	if m0 := accts[0].(map[string]interface{}); m0["account_index"] != "1" {
-		t.Fatalf("batch[0] index=%v want 1.", m0["account_index"])
+	seed, err := ReadWalletSeed(ctx, s, "wdst")
+	if err != nil {
+		t.Fatal(err)
 	}
-	if m1 := accts[1].(map[string]interface{}); m1["account_index"] != "2" {
-		t.Fatalf("batch[1] index=%v want 2.", m1["account_index"])
+	if seed == nil || seed.Mnemonic != testMnemonic || seed.Passphrase != "hidden" || seed.PathTemplate() != "m/44'/60'/0'/0" {
+		t.Fatalf("seed=%+v want the source wallet seed.", seed)
+	}
+	if pending, err := ReadShamirImport(ctx, s, "wdst"); err != nil || pending != nil {
+		t.Fatalf("pending=%v err=%v want discarded.", pending, err)
 	}
 }
 
-// TestHandleListDerivedAccounts_noRange verifies all stored indices are returned when no range is given.
-func TestHandleListDerivedAccounts_noRange(t *testing.T) {
+// TestHandleWalletShamirImport_expired verifies a pending import older than model.ShamirImportTTL reads as none
+// and does not count toward the threshold of the next submission.
+func TestHandleWalletShamirImport_expired(t *testing.T) {
 	t.Parallel()
 
 	ctx := context.Background()
 	s := new(logical.InmemStorage)
-	mustPutWalletSeed(ctx, t, s, "wlist", testMnemonic)
-	mustPutDerivedAccount(ctx, t, s, "wlist", "0", testMnemonic)
-	mustPutDerivedAccount(ctx, t, s, "wlist", "2", testMnemonic)
-	mustPutDerivedAccount(ctx, t, s, "wlist", "5", testMnemonic)
 	req := &logical.Request{Storage: s}
+	var walletMu sync.Map
 
-	resp, err := handleListDerivedAccounts(ctx, req, walletFieldData(map[string]interface{}{
-		"wallet_id": "wlist",
-	}))
+	shares, err := model.SplitMnemonicShares(testMnemonic, 2, 3, 0)
 	if err != nil {
 		t.Fatal(err)
 	}
-	keys, _ := resp.Data["keys"].([]string)
-	if len(keys) != 3 || keys[0] != "0" || keys[1] != "2" || keys[2] != "5" {
-		t.Fatalf("keys=%v want [0 2 5].", keys)
+	stale := &model.ShamirImport{
+		Shares:    []string{shares[0]},
+		CreatedAt: time.Now().Add(-model.ShamirImportTTL - time.Minute).Unix(),
+	}
+	if err := WriteShamirImport(ctx, s, "wexp", stale); err != nil {
+		t.Fatal(err)
 	}
-}
-
-// TestHandleListDerivedAccounts_ignoresStartEnd verifies LIST returns all indices; start/end are not filters.
-func TestHandleListDerivedAccounts_ignoresStartEnd(t *testing.T) {
-	t.Parallel()
 
-	ctx := context.Background()
-	s := new(logical.InmemStorage)
-	mustPutWalletSeed(ctx, t, s, "wrange", testMnemonic)
-	for _, idx := range []string{"0", "1", "2", "3", "4"} {
-		mustPutDerivedAccount(ctx, t, s, "wrange", idx, testMnemonic)
+	importFields := pathWalletShamirImport(&walletMu).Fields
+	resp, err := handleWalletShamirImportRead(ctx, req, &framework.FieldData{
+		Raw:    map[string]interface{}{"wallet_id": "wexp"},
+		Schema: importFields,
+	})
+	if err != nil || resp != nil {
+		t.Fatalf("resp=%v err=%v want expired import unreported.", resp, err)
 	}
-	req := &logical.Request{Storage: s}
 
-	resp, err := handleListDerivedAccounts(ctx, req, walletFieldData(map[string]interface{}{
-		"wallet_id": "wrange",
-		"start":     "1",
-		"end":       "3",
-	}))
+	resp, err = handleWalletShamirImport(ctx, req, &framework.FieldData{
+		Raw:    map[string]interface{}{"wallet_id": "wexp", "shares": []string{shares[1]}},
+		Schema: importFields,
+	})
 	if err != nil {
 		t.Fatal(err)
 	}
-	keys, _ := resp.Data["keys"].([]string)
-	if len(keys) != 5 || keys[0] != "0" || keys[4] != "4" {
-		t.Fatalf("keys=%v want [0 1 2 3 4].", keys)
+	if resp == nil || resp.IsError() || resp.Data["complete"] != false || resp.Data["shares_received"] != 1 {
+		t.Fatalf("resp=%v want a fresh import with 1 share.", resp)
+	}
+	if seed, err := ReadWalletSeed(ctx, s, "wexp"); err != nil || seed != nil {
+		t.Fatalf("seed=%v err=%v want none from an expired share.", seed, err)
+	}
+	pending, err := ReadShamirImport(ctx, s, "wexp")
+	if err != nil || pending == nil || pending.Expired(time.Now()) {
+		t.Fatalf("pending=%+v err=%v want restarted.", pending, err)
 	}
 }
 
-// TestHandleReadDerivedAccountsRange_success verifies inclusive range returns full metadata.
-func TestHandleReadDerivedAccountsRange_success(t *testing.T) {
+// TestDerivedAccountAddressIndex_lifecycle verifies derived accounts are indexed on creation in every account
+// segment, the backfill indexes accounts stored without an entry, and purge removes the wallet's entries.
+func TestDerivedAccountAddressIndex_lifecycle(t *testing.T) {
 	t.Parallel()
 
 	ctx := context.Background()
 	s := new(logical.InmemStorage)
-	mustPutWalletSeed(ctx, t, s, "wmeta", testMnemonic)
-	for _, idx := range []string{"0", "1", "2"} {
-		mustPutDerivedAccount(ctx, t, s, "wmeta", idx, testMnemonic)
+	mustPutWalletSeed(ctx, t, s, "widx", testMnemonic)
+	legacy := mustPutDerivedAccount(ctx, t, s, "widx", "0", testMnemonic)
+	if err := WriteWalletCounter(ctx, s, "widx", 1); err != nil {
+		t.Fatal(err)
 	}
 	req := &logical.Request{Storage: s}
 
-	resp, err := handleReadDerivedAccountsRange(ctx, req, walletFieldData(map[string]interface{}{
-		"wallet_id": "wmeta",
-		"start":     "1",
-		"end":       "2",
-	}))
+	indexed, err := IndexDerivedAccountAddresses(ctx, s)
 	if err != nil {
 		t.Fatal(err)
 	}
-	if resp == nil || resp.IsError() {
-		t.Fatalf("unexpected error: %v", resp)
+	if indexed != 1 {
+		t.Fatalf("indexed=%d want 1.", indexed)
 	}
-	accts, _ := resp.Data["accounts"].([]interface{})
-	if len(accts) != 2 {
-		t.Fatalf("len(accounts)=%d want 2.", len(accts))
+	owner, err := addressindex.ReadAddressOwner(ctx, s, legacy.Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if owner == nil || owner.WalletID != "widx" || owner.Account != "0" || owner.Index != "0" {
+		t.Fatalf("owner=%+v want widx 0/0.", owner)
 	}
-}
-
-// TestHandleReadDerivedAccountsRange_missingIndex verifies a gap in the range returns an error.
-func TestHandleReadDerivedAccountsRange_missingIndex(t *testing.T) {
-	t.Parallel()
 
-	ctx := context.Background()
-	s := new(logical.InmemStorage)
-	mustPutWalletSeed(ctx, t, s, "wgap", testMnemonic)
-	mustPutDerivedAccount(ctx, t, s, "wgap", "0", testMnemonic)
-	mustPutDerivedAccount(ctx, t, s, "wgap", "2", testMnemonic)
-	req := &logical.Request{Storage: s}
+	var walletMu sync.Map
+	handler := makeHandleDerivedAccountCreate(&walletMu)
+	var created []string
+	for _, tc := range []struct {
+		account    string
+		wantPrefix string
+	}{
+		{"", "wallets/widx/accounts/1"},
+		{"3", "wallets/widx/accounts/3/0"},
+	} {
+		raw := map[string]interface{}{"wallet_id": "widx"}
+		if tc.account != "" {
+			raw["account"] = tc.account
+		}
+		resp, err := handler(ctx, req, walletFieldData(raw))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp == nil || resp.IsError() {
+			t.Fatalf("resp=%v want derived account.", resp)
+		}
+		addr := resp.Data["address"].(string)
+		owner, err := addressindex.ReadAddressOwner(ctx, s, addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if owner == nil || owner.PathPrefix() != tc.wantPrefix {
+			t.Fatalf("owner=%+v want %s.", owner, tc.wantPrefix)
+		}
+		created = append(created, addr)
+	}
 
-	resp, err := handleReadDerivedAccountsRange(ctx, req, walletFieldData(map[string]interface{}{
-		"wallet_id": "wgap",
-		"start":     "0",
-		"end":       "2",
-	}))
-	if err != nil {
+	if _, err := handleWalletDelete(ctx, req, walletFieldData(map[string]interface{}{"wallet_id": "widx"})); err != nil {
 		t.Fatal(err)
 	}
-	if resp == nil || !resp.IsError() {
-		t.Fatal("expected error when index 1 is missing.")
+	if _, err := handleWalletPurge(ctx, req, walletFieldData(map[string]interface{}{"wallet_id": "widx"})); err != nil {
+		t.Fatal(err)
+	}
+	for _, addr := range append(created, legacy.Address) {
+		if owner, err := addressindex.ReadAddressOwner(ctx, s, addr); err != nil || owner != nil {
+			t.Fatalf("owner of %s=%+v err=%v want removed by purge.", addr, owner, err)
+		}
 	}
 }
 
-// TestHandleReadDerivedAccountsRange_spanExceedsMax verifies span > maxBulkReadDerivedSpan errors.
-func TestHandleReadDerivedAccountsRange_spanExceedsMax(t *testing.T) {
+// TestWalletJournal_recordsSigningOperations verifies wallet sign-tx and sign-eip712 append chained records to the
+// derived account's journal, rejected requests are not recorded, and each account segment keeps its own journal.
+func TestWalletJournal_recordsSigningOperations(t *testing.T) {
 	t.Parallel()
 
 	ctx := context.Background()
 	s := new(logical.InmemStorage)
-	mustPutWalletSeed(ctx, t, s, "wspan2", testMnemonic)
-	req := &logical.Request{Storage: s}
+	mustPutWalletSeed(ctx, t, s, "wjournal", testMnemonic)
+	derived := mustPutDerivedAccount(ctx, t, s, "wjournal", "0", testMnemonic)
+	req := &logical.Request{Storage: s, EntityID: "entity-1"}
 
-	resp, err := handleReadDerivedAccountsRange(ctx, req, walletFieldData(map[string]interface{}{
-		"wallet_id": "wspan2",
-		"start":     "0",
-		"end":       "10000",
+	txResp, err := handleWalletSignTxEIP1559(ctx, req, walletFieldData(map[string]interface{}{
+		"wallet_id":                "wjournal",
+		"index":                    "0",
+		"chain_id":                 "1",
+		"to":                       "0x0000000000000000000000000000000000000001",
+		"max_fee_per_gas":          "2",
+		"max_priority_fee_per_gas": "1",
 	}))
-	if err != nil {
-		t.Fatal(err)
+	if err != nil || txResp == nil || txResp.IsError() {
+		t.Fatalf("resp=%v err=%v want signed tx.", txResp, err)
 	}
-	if resp == nil || !resp.IsError() {
-		t.Fatal("expected error for span > maxBulkReadDerivedSpan.")
+	resp, err := handleWalletSignEIP712(ctx, req, walletFieldData(map[string]interface{}{
+		"wallet_id": "wjournal",
+		"index":     "0",
+		"payload":   "{}",
+	}))
+	if err != nil || resp == nil || !resp.IsError() {
+		t.Fatalf("resp=%v err=%v want invalid payload error.", resp, err)
+	}
+	payload := `{"types":{"EIP712Domain":[{"name":"name","type":"string"}],"Mail":[{"name":"contents","type":"string"}]},` +
+		`"primaryType":"Mail","domain":{"name":"test"},"message":{"contents":"hello"}}`
+	if resp, err := handleWalletSignEIP712(ctx, req, walletFieldData(map[string]interface{}{
+		"wallet_id": "wjournal",
+		"index":     "0",
+		"payload":   payload,
+	})); err != nil || resp.IsError() {
+		t.Fatalf("resp=%v err=%v want signature.", resp, err)
+	}
+
+	journalData := func(p *framework.Path, op logical.Operation, raw map[string]interface{}) map[string]interface{} {
+		t.Helper()
+		resp, err := p.Callbacks[op](ctx, req, &framework.FieldData{Raw: raw, Schema: p.Fields})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.Data
+	}
+	raw := map[string]interface{}{"wallet_id": "wjournal", "index": "0"}
+	if keys, _ := journalData(pathWalletJournal(), logical.ListOperation, raw)["keys"].([]string); len(keys) != 2 {
+		t.Fatalf("keys=%v want 2 records.", keys)
+	}
+	first := journalData(pathWalletJournalRecord(), logical.ReadOperation, map[string]interface{}{"wallet_id": "wjournal", "index": "0", "seq": "1"})
+	if first["operation"] != "sign-tx/eip1559" || first["tx_hash"] != txResp.Data["transaction_hash"] || first["address"] != derived.Address {
+		t.Fatalf("record=%v want sign-tx/eip1559 with the tx hash.", first)
+	}
+	second := journalData(pathWalletJournalRecord(), logical.ReadOperation, map[string]interface{}{"wallet_id": "wjournal", "index": "0", "seq": "2"})
+	if second["operation"] != model.JournalOpSignEIP712 || second["prev_hash"] != first["hash"] ||
+		second["payload_digest"] != model.JournalPayloadDigest([]byte(payload)) {
+		t.Fatalf("record=%v want sign-eip712 linked to record 1.", second)
+	}
+	if got := journalData(pathWalletJournalVerify(), logical.ReadOperation, raw); got["valid"] != true || got["records"] != 2 {
+		t.Fatalf("verify=%v want 2 valid records.", got)
+	}
+	other := map[string]interface{}{"wallet_id": "wjournal", "account": "1", "index": "0"}
+	if got := journalData(pathWalletJournalVerify(), logical.ReadOperation, other); got["valid"] != true || got["records"] != 0 {
+		t.Fatalf("verify=%v want empty journal for account segment 1.", got)
 	}
 }
 
-// TestHandleReadDerivedAccountsRange_startAfterEnd verifies start > end returns a logical error.
-func TestHandleReadDerivedAccountsRange_startAfterEnd(t *testing.T) {
+// TestWalletJournal_recordsMessageAndAuthorization verifies sign-message and sign-authorization each append a
+// record with their own operation to the derived account's journal.
+func TestWalletJournal_recordsMessageAndAuthorization(t *testing.T) {
 	t.Parallel()
 
 	ctx := context.Background()
 	s := new(logical.InmemStorage)
-	mustPutWalletSeed(ctx, t, s, "word", testMnemonic)
-	mustPutDerivedAccount(ctx, t, s, "word", "1", testMnemonic)
+	mustPutWalletSeed(ctx, t, s, "wjmsg", testMnemonic)
+	derived := mustPutDerivedAccount(ctx, t, s, "wjmsg", "0", testMnemonic)
 	req := &logical.Request{Storage: s}
+	var walletMu sync.Map
 
-	resp, err := handleReadDerivedAccountsRange(ctx, req, walletFieldData(map[string]interface{}{
-		"wallet_id": "word",
-		"start":     "2",
-		"end":       "1",
-	}))
+	p := pathWalletSignMessage(&walletMu)
+	if resp, err := p.Callbacks[logical.UpdateOperation](ctx, req, &framework.FieldData{
+		Raw:    map[string]interface{}{"wallet_id": "wjmsg", "index": "0", "message": "hello"},
+		Schema: p.Fields,
+	}); err != nil || resp == nil || resp.IsError() {
+		t.Fatalf("resp=%v err=%v want signature.", resp, err)
+	}
+	p = pathWalletSignAuthorization(&walletMu)
+	authResp, err := p.Callbacks[logical.UpdateOperation](ctx, req, &framework.FieldData{
+		Raw: map[string]interface{}{
+			"wallet_id": "wjmsg",
+			"index":     "0",
+			"chain_id":  "1",
+			"address":   "0x0000000000000000000000000000000000000007",
+			"nonce":     "0",
+		},
+		Schema: p.Fields,
+	})
+	if err != nil || authResp == nil || authResp.IsError() {
+		t.Fatalf("resp=%v err=%v want authorization.", authResp, err)
+	}
+
+	records, head, _, err := journal.Verify(ctx, s, storagekey.WalletJournalPrefix("wjmsg", "0", "0"))
 	if err != nil {
 		t.Fatal(err)
 	}
-	if resp == nil || !resp.IsError() {
-		t.Fatal("expected error when start > end.")
+	if len(records) != 2 || head == nil || head.Seq != 2 {
+		t.Fatalf("records=%v head=%v want 2 records.", records, head)
+	}
+	if records[0].Operation != model.JournalOpSignMessage || records[0].Address != derived.Address ||
+		records[0].PayloadDigest != model.JournalPayloadDigest([]byte("hello")) {
+		t.Fatalf("record=%+v want sign-message of the message.", records[0])
+	}
+	encoded, _ := authResp.Data["authorization"].(string)
+	if records[1].Operation != model.JournalOpSignAuthorization || records[1].PrevHash != records[0].Hash ||
+		records[1].PayloadDigest != model.JournalPayloadDigest([]byte(encoded)) {
+		t.Fatalf("record=%+v want sign-authorization linked to record 1.", records[1])
 	}
 }
 
-// TestHandleReadDerivedAccountsRange_missingStart verifies omitting start returns a logical error.
-func TestHandleReadDerivedAccountsRange_missingStart(t *testing.T) {
+func TestWalletNonce_autoTracksAndFillsGaps(t *testing.T) {
 	t.Parallel()
 
 	ctx := context.Background()
 	s := new(logical.InmemStorage)
-	mustPutWalletSeed(ctx, t, s, "wmissstart", testMnemonic)
+	mustPutWalletSeed(ctx, t, s, "wnonce", testMnemonic)
+	mustPutDerivedAccount(ctx, t, s, "wnonce", "0", testMnemonic)
 	req := &logical.Request{Storage: s}
 
-	resp, err := handleReadDerivedAccountsRange(ctx, req, walletFieldData(map[string]interface{}{
-		"wallet_id": "wmissstart",
-		"end":       "2",
-	}))
-	if err != nil {
-		t.Fatal(err)
+	signAuto := func() (*logical.Response, error) {
+		return handleWalletSignTxEIP1559(ctx, req, walletFieldData(map[string]interface{}{
+			"wallet_id":                "wnonce",
+			"index":                    "0",
+			"chain_id":                 "1",
+			"nonce":                    model.NonceAuto,
+			"to":                       "0x0000000000000000000000000000000000000001",
+			"max_fee_per_gas":          "2",
+			"max_priority_fee_per_gas": "1",
+		}))
+	}
+	nonceCall := func(p *framework.Path, op logical.Operation, raw map[string]interface{}) *logical.Response {
+		t.Helper()
+		raw["wallet_id"], raw["index"], raw["chain_id"] = "wnonce", "0", "1"
+		resp, err := p.Callbacks[op](ctx, req, &framework.FieldData{Raw: raw, Schema: p.Fields})
+		if err != nil || resp == nil || resp.IsError() {
+			t.Fatalf("resp=%v err=%v want success.", resp, err)
+		}
+		return resp
 	}
-	if resp == nil || !resp.IsError() {
-		t.Fatal("expected error when start is missing.")
+
+	if resp, err := signAuto(); err != nil || resp == nil || !resp.IsError() {
+		t.Fatalf("resp=%v err=%v want error without a nonce tracker.", resp, err)
+	}
+	nonceCall(pathWalletNonce(new(sync.Map)), logical.UpdateOperation, map[string]interface{}{"next": "5"})
+	for _, want := range []uint64{5, 6} {
+		resp, err := signAuto()
+		if err != nil || resp.IsError() || resp.Data["nonce"] != want {
+			t.Fatalf("resp=%v err=%v want nonce %d.", resp, err, want)
+		}
+	}
+	if got := nonceCall(pathWalletNonceReserve(new(sync.Map)), logical.UpdateOperation, map[string]interface{}{}); got.Data["nonce"] != uint64(7) {
+		t.Fatalf("reserve=%v want nonce 7.", got.Data)
+	}
+	nonceCall(pathWalletNonceRelease(new(sync.Map)), logical.UpdateOperation, map[string]interface{}{"nonce": "6"})
+	if resp, err := signAuto(); err != nil || resp.IsError() || resp.Data["nonce"] != uint64(6) {
+		t.Fatalf("resp=%v err=%v want released nonce 6 reused.", resp, err)
+	}
+	got := nonceCall(pathWalletNonce(new(sync.Map)), logical.ReadOperation, map[string]interface{}{})
+	if got.Data["next"] != uint64(8) {
+		t.Fatalf("tracker=%v want next 8.", got.Data)
 	}
 }
 
-// TestHandleReadDerivedAccountsRange_missingEnd verifies omitting end returns a logical error.
-func TestHandleReadDerivedAccountsRange_missingEnd(t *testing.T) {
+// TestWalletSignTxReplace_cancelKeepsSpend verifies cancelling a transaction does not return its value to the
+// velocity budget: sign, cancel, then sign again over the cap is rejected.
+func TestWalletSignTxReplace_cancelKeepsSpend(t *testing.T) {
 	t.Parallel()
 
 	ctx := context.Background()
 	s := new(logical.InmemStorage)
-	mustPutWalletSeed(ctx, t, s, "wmissend", testMnemonic)
+	mustPutWalletSeed(ctx, t, s, "wrecap", testMnemonic)
+	mustPutDerivedAccount(ctx, t, s, "wrecap", "0", testMnemonic)
 	req := &logical.Request{Storage: s}
+	if resp, err := handleWalletLimitsWrite(ctx, req, &framework.FieldData{
+		Raw:    map[string]interface{}{"wallet_id": "wrecap", "wallet_max_value": "100"},
+		Schema: pathWalletLimits().Fields,
+	}); err != nil || resp.IsError() {
+		t.Fatalf("resp=%v err=%v want limits set.", resp, err)
+	}
+	sign := func(nonce string) *logical.Response {
+		t.Helper()
+		resp, err := handleWalletSignTxEIP1559(ctx, req, walletFieldData(map[string]interface{}{
+			"wallet_id":                "wrecap",
+			"index":                    "0",
+			"chain_id":                 "1",
+			"nonce":                    nonce,
+			"to":                       "0x0000000000000000000000000000000000000001",
+			"value":                    "100",
+			"max_fee_per_gas":          "100",
+			"max_priority_fee_per_gas": "10",
+		}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
 
-	resp, err := handleReadDerivedAccountsRange(ctx, req, walletFieldData(map[string]interface{}{
-		"wallet_id": "wmissend",
-		"start":     "0",
-	}))
-	if err != nil {
-		t.Fatal(err)
+	first := sign("0")
+	if first == nil || first.IsError() {
+		t.Fatalf("resp=%v want signed tx.", first)
 	}
-	if resp == nil || !resp.IsError() {
-		t.Fatal("expected error when end is missing.")
+	p := pathWalletSignTxCancel(new(sync.Map))
+	cancel, err := p.Callbacks[logical.UpdateOperation](ctx, req, &framework.FieldData{
+		Raw:    map[string]interface{}{"wallet_id": "wrecap", "index": "0", "signed_transaction": first.Data["signed_transaction"]},
+		Schema: p.Fields,
+	})
+	if err != nil || cancel == nil || cancel.IsError() {
+		t.Fatalf("resp=%v err=%v want cancellation.", cancel, err)
 	}
-}
-
-// faultStorage wraps logical.InmemStorage and returns an error when Put is called with a
-// key matching faultKey. After faultRemaining reaches zero the fault is cleared and
-// subsequent Puts succeed normally.
-type faultStorage struct {
-	logical.Storage
-	faultKey       string
-	faultRemaining int
-}
-
-func (f *faultStorage) Put(ctx context.Context, entry *logical.StorageEntry) error {
-	if f.faultRemaining > 0 && entry.Key == f.faultKey {
-		f.faultRemaining--
-		return fmt.Errorf("injected Put failure for %q", entry.Key)
+	third := sign("1")
+	if third == nil || !third.IsError() || !strings.Contains(third.Error().Error(), "velocity limit exceeded") {
+		t.Fatalf("resp=%v want velocity limit error after cancel.", third)
 	}
-	return f.Storage.Put(ctx, entry)
 }
 
-// TestHandleDerivedAccountCreate_partialWriteSelfHeals verifies that when WriteWalletCounter
-// fails after a successful Put(account), a retry derives the same address at the same index
-// and succeeds — confirming the deterministic BIP-44 derivation self-heals the partial write.
-func TestHandleDerivedAccountCreate_partialWriteSelfHeals(t *testing.T) {
+func TestWalletSignTxReplace_speedUpAndCancel(t *testing.T) {
 	t.Parallel()
 
 	ctx := context.Background()
-	inner := new(logical.InmemStorage)
-	mustPutWalletSeed(ctx, t, inner, "wpartial", testMnemonic)
-
-	// Fail the first WriteWalletCounter (counter key Put) only.
-	fs := &faultStorage{
-		Storage:        inner,
-		faultKey:       storagekey.CounterKey("wpartial"),
-		faultRemaining: 1,
+	s := new(logical.InmemStorage)
+	mustPutWalletSeed(ctx, t, s, "wreplace", testMnemonic)
+	derived := mustPutDerivedAccount(ctx, t, s, "wreplace", "0", testMnemonic)
+	mustPutDerivedAccount(ctx, t, s, "wreplace", "1", testMnemonic)
+	req := &logical.Request{Storage: s}
+	if resp, err := handleWalletLimitsWrite(ctx, req, &framework.FieldData{
+		Raw:    map[string]interface{}{"wallet_id": "wreplace", "account_max_value": "100"},
+		Schema: pathWalletLimits().Fields,
+	}); err != nil || resp.IsError() {
+		t.Fatalf("resp=%v err=%v want limits set.", resp, err)
 	}
-	req := &logical.Request{Storage: fs}
 
-	var walletMu sync.Map
-	handler := makeHandleDerivedAccountCreate(&walletMu)
-
-	// First attempt: account is written but counter Put fails → handler returns error.
-	_, err := handler(ctx, req, walletFieldData(map[string]interface{}{
-		"wallet_id": "wpartial",
+	orig, err := handleWalletSignTxEIP1559(ctx, req, walletFieldData(map[string]interface{}{
+		"wallet_id":                "wreplace",
+		"index":                    "0",
+		"chain_id":                 "1",
+		"nonce":                    "3",
+		"to":                       "0x0000000000000000000000000000000000000001",
+		"value":                    "60",
+		"max_fee_per_gas":          "100",
+		"max_priority_fee_per_gas": "10",
 	}))
-	if err == nil {
-		t.Fatal("expected error from injected counter Put failure.")
+	if err != nil || orig.IsError() {
+		t.Fatalf("resp=%v err=%v want signed tx.", orig, err)
+	}
+	replace := func(p *framework.Path, raw map[string]interface{}) *logical.Response {
+		t.Helper()
+		resp, err := p.Callbacks[logical.UpdateOperation](ctx, req, &framework.FieldData{Raw: raw, Schema: p.Fields})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
 	}
 
-	// Retry: fault is cleared; counter still reads 0 so same index is re-derived.
-	resp, err := handler(ctx, req, walletFieldData(map[string]interface{}{
-		"wallet_id": "wpartial",
-	}))
-	if err != nil {
-		t.Fatal(err)
-	}
-	if resp == nil || resp.IsError() {
-		t.Fatalf("unexpected error on retry: %v", resp)
+	// A 60 wei speed-up fits the 100 wei account cap because the replaced spend is not counted again.
+	speedUp := replace(pathWalletSignTxReplace(new(sync.Map)), map[string]interface{}{
+		"wallet_id": "wreplace", "index": "0", "transaction_hash": orig.Data["transaction_hash"],
+	})
+	if speedUp == nil || speedUp.IsError() {
+		t.Fatalf("resp=%v want replacement.", speedUp)
 	}
-	// Index must still be 0 — same account, no gap introduced.
-	if got := resp.Data["account_index"]; got != "0" {
-		t.Fatalf("account_index=%v want 0 after self-heal.", got)
+	if speedUp.Data["nonce"] != uint64(3) || speedUp.Data["value"] != "60" || speedUp.Data["gas_price"] != "110" ||
+		speedUp.Data["replaced_transaction_hash"] != orig.Data["transaction_hash"] {
+		t.Fatalf("data=%v want nonce 3, value 60, fee cap 110.", speedUp.Data)
 	}
-
-	// Subsequent create must advance to index 1.
-	resp2, err := handler(ctx, req, walletFieldData(map[string]interface{}{
-		"wallet_id": "wpartial",
-	}))
+	ledger, err := ReadWalletSpendLedger(ctx, s, "wreplace")
 	if err != nil {
 		t.Fatal(err)
 	}
-	if got := resp2.Data["account_index"]; got != "1" {
-		t.Fatalf("account_index=%v want 1 after self-heal.", got)
+	if len(ledger.Entries) != 2 || ledger.Entries[1].TxHash != speedUp.Data["transaction_hash"] || ledger.Entries[1].Value != "0" {
+		t.Fatalf("ledger=%v want the original kept and a zero-value replacement.", ledger.Entries)
 	}
-}
-
-// TestHandleDerivedAccountCreate_updateOperationCreatesAccount verifies that UpdateOperation
-// routes to the same handler as CreateOperation and produces a valid account.
-func TestHandleDerivedAccountCreate_updateOperationCreatesAccount(t *testing.T) {
-	t.Parallel()
-
-	ctx := context.Background()
-	s := new(logical.InmemStorage)
-	mustPutWalletSeed(ctx, t, s, "wupdate", testMnemonic)
-
-	// UpdateOperation uses the same handler; simulate it by calling the handler directly.
-	req := &logical.Request{Storage: s, Operation: logical.UpdateOperation}
-
-	var walletMu sync.Map
-	handler := makeHandleDerivedAccountCreate(&walletMu)
 
-	resp, err := handler(ctx, req, walletFieldData(map[string]interface{}{
-		"wallet_id": "wupdate",
-	}))
+	if resp := replace(pathWalletSignTxCancel(new(sync.Map)), map[string]interface{}{
+		"wallet_id": "wreplace", "index": "1", "signed_transaction": speedUp.Data["signed_transaction"],
+	}); resp == nil || !resp.IsError() {
+		t.Fatalf("resp=%v want error for a transaction of another account.", resp)
+	}
+	cancel := replace(pathWalletSignTxCancel(new(sync.Map)), map[string]interface{}{
+		"wallet_id": "wreplace", "index": "0", "signed_transaction": speedUp.Data["signed_transaction"], "fee_bump_percent": "20",
+	})
+	if cancel == nil || cancel.IsError() {
+		t.Fatalf("resp=%v want cancellation.", cancel)
+	}
+	if cancel.Data["nonce"] != uint64(3) || cancel.Data["value"] != "0" || cancel.Data["gas_price"] != "132" ||
+		common.HexToAddress(cancel.Data["address_to"].(string)) != common.HexToAddress(derived.Address) {
+		t.Fatalf("data=%v want zero-value self-transfer with nonce 3 and fee cap 132.", cancel.Data)
+	}
+	ledger, err = ReadWalletSpendLedger(ctx, s, "wreplace")
 	if err != nil {
 		t.Fatal(err)
 	}
-	if resp == nil || resp.IsError() {
-		t.Fatalf("UpdateOperation: unexpected error response: %v", resp)
-	}
-	if got := resp.Data["account_index"]; got != "0" {
-		t.Fatalf("account_index=%v want 0.", got)
+	if _, spent := ledger.Totals("0", time.Now().Add(-time.Hour)); spent.Int64() != 60 {
+		t.Fatalf("ledger=%v want the cancelled spend still counted.", ledger.Entries)
 	}
-	if resp.Data["address"] == "" {
-		t.Fatal("expected non-empty address.")
+
+	if resp := replace(pathWalletSignTxReplace(new(sync.Map)), map[string]interface{}{
+		"wallet_id": "wreplace", "index": "0", "transaction_hash": "0x" + strings.Repeat("ab", 32),
+	}); resp == nil || !resp.IsError() {
+		t.Fatalf("resp=%v want error for an unknown hash.", resp)
 	}
 }
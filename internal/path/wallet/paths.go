@@ -9,9 +9,11 @@ import (
 	"math/big"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto/ecies"
 	"github.com/ethereum/go-ethereum/signer/core/apitypes"
 	"github.com/hashicorp/vault/sdk/framework"
@@ -19,6 +21,12 @@ import (
 
 	"github.com/bsostech/vault-blockchain/internal/ethutil"
 	"github.com/bsostech/vault-blockchain/internal/model"
+	"github.com/bsostech/vault-blockchain/internal/path/chain"
+	"github.com/bsostech/vault-blockchain/internal/path/config"
+	"github.com/bsostech/vault-blockchain/internal/path/journal"
+	"github.com/bsostech/vault-blockchain/internal/path/nonces"
+	"github.com/bsostech/vault-blockchain/internal/path/signedtxs"
+	"github.com/bsostech/vault-blockchain/internal/path/storagekey"
 	"github.com/bsostech/vault-blockchain/pkg/utils"
 )
 
@@ -28,15 +36,40 @@ func Paths(walletMu *sync.Map) []*framework.Path {
 		pathListWallets(),
 		pathWalletCreateAuto(),
 		pathWalletImport(),
+		pathWallet(walletMu),
+		pathWalletRestore(walletMu),
+		pathWalletPurge(walletMu),
+		pathWalletBackup(walletMu),
+		pathWalletBackupRestore(walletMu),
+		pathWalletShamir(walletMu),
+		pathWalletShamirImport(walletMu),
+		pathWalletPolicy(),
+		pathWalletLimits(),
+		pathWalletSpend(),
+		pathWalletXpub(),
+		pathWalletXpubAddresses(),
 		pathDerivedAccount(),
 		pathBatchDerivedAccounts(walletMu),
 		pathListDerivedAccounts(walletMu),
-		pathWalletSignTxLegacy(),
-		pathWalletSignTxEIP1559(),
-		pathWalletSign(),
-		pathWalletSignEIP712(),
+		pathWalletSignTxLegacy(walletMu),
+		pathWalletSignTxEIP2930(walletMu),
+		pathWalletSignTxEIP1559(walletMu),
+		pathWalletSignTxBlob(walletMu),
+		pathWalletSignTxEIP7702(walletMu),
+		pathWalletSignTxReplace(walletMu),
+		pathWalletSignTxCancel(walletMu),
+		pathWalletSign(walletMu),
+		pathWalletSignMessage(walletMu),
+		pathWalletSignEIP712(walletMu),
+		pathWalletSignAuthorization(walletMu),
 		pathWalletEncrypt(),
-		pathWalletDecrypt(),
+		pathWalletDecrypt(walletMu),
+		pathWalletJournal(),
+		pathWalletJournalVerify(),
+		pathWalletJournalRecord(),
+		pathWalletNonce(walletMu),
+		pathWalletNonceReserve(walletMu),
+		pathWalletNonceRelease(walletMu),
 	}
 }
 
@@ -63,6 +96,14 @@ func pathWalletCreateAuto() *framework.Path {
 				Type:        framework.TypeString,
 				Description: "Logical wallet identifier in the path.",
 			},
+			"passphrase": {
+				Type:        framework.TypeString,
+				Description: "Optional BIP-39 passphrase (\"25th word\"). Stored sealed with the mnemonic; never returned.",
+			},
+			"derivation_path": {
+				Type:        framework.TypeString,
+				Description: "Derivation path template fixed for the wallet, e.g. m/44'/60'/{index}'/0/0; {index} marks the address index, otherwise it is appended. Default: config default_derivation_path.",
+			},
 		},
 		ExistenceCheck: ExistenceWalletSeed(),
 		Callbacks: map[logical.Operation]framework.OperationFunc{
@@ -93,6 +134,14 @@ func pathWalletImport() *framework.Path {
 				Required:    true,
 				Description: "BIP-39 mnemonic phrase for this wallet.",
 			},
+			"passphrase": {
+				Type:        framework.TypeString,
+				Description: "Optional BIP-39 passphrase (\"25th word\") the mnemonic was backed up with. Stored sealed; never returned.",
+			},
+			"derivation_path": {
+				Type:        framework.TypeString,
+				Description: "Derivation path template fixed for the wallet, e.g. m/44'/60'/{index}'/0/0; {index} marks the address index, otherwise it is appended. Default: config default_derivation_path.",
+			},
 		},
 		ExistenceCheck: ExistenceWalletSeed(),
 		Callbacks: map[logical.Operation]framework.OperationFunc{
@@ -108,12 +157,441 @@ func pathWalletImport() *framework.Path {
 	}
 }
 
-// pathDerivedAccount registers read access on wallets/:wallet_id/accounts/:index.
+// pathWallet registers read (status) and soft-delete on wallets/:wallet_id.
+func pathWallet(walletMu *sync.Map) *framework.Path {
+	return &framework.Path{
+		Pattern:      "wallets/" + framework.GenericNameRegex("wallet_id"),
+		HelpSynopsis: "Read a wallet's status, or soft-delete it (restorable within config deletion_retention).",
+		Fields: map[string]*framework.FieldSchema{
+			"wallet_id": {
+				Type:        framework.TypeString,
+				Description: "Logical wallet identifier in the path.",
+			},
+		},
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.ReadOperation:   handleWalletRead,
+			logical.DeleteOperation: WithWalletLock(walletMu, handleWalletDelete),
+		},
+	}
+}
+
+// pathWalletRestore registers POST on wallets/:wallet_id/restore to undo a soft delete.
+func pathWalletRestore(walletMu *sync.Map) *framework.Path {
+	return &framework.Path{
+		Pattern:      "wallets/" + framework.GenericNameRegex("wallet_id") + "/restore",
+		HelpSynopsis: "Restore a soft-deleted wallet within the deletion retention window.",
+		Fields: map[string]*framework.FieldSchema{
+			"wallet_id": {
+				Type:        framework.TypeString,
+				Description: "Logical wallet identifier in the path.",
+			},
+		},
+		ExistenceCheck: ExistenceWalletSeed(),
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.CreateOperation: WithWalletLock(walletMu, handleWalletRestore),
+			logical.UpdateOperation: WithWalletLock(walletMu, handleWalletRestore),
+		},
+	}
+}
+
+// pathWalletPurge registers POST on wallets/:wallet_id/purge to permanently remove a soft-deleted wallet.
+func pathWalletPurge(walletMu *sync.Map) *framework.Path {
+	return &framework.Path{
+		Pattern:      "wallets/" + framework.GenericNameRegex("wallet_id") + "/purge",
+		HelpSynopsis: "Permanently remove a soft-deleted wallet: seed, counters, derived accounts, policies and ledgers.",
+		Fields: map[string]*framework.FieldSchema{
+			"wallet_id": {
+				Type:        framework.TypeString,
+				Description: "Logical wallet identifier in the path.",
+			},
+		},
+		ExistenceCheck: ExistenceWalletSeed(),
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.CreateOperation: WithWalletLock(walletMu, handleWalletPurge),
+			logical.UpdateOperation: WithWalletLock(walletMu, handleWalletPurge),
+		},
+	}
+}
+
+// pathWalletBackup registers read (backup history) and POST (take an encrypted backup) on wallets/:wallet_id/backup.
+func pathWalletBackup(walletMu *sync.Map) *framework.Path {
+	return &framework.Path{
+		Pattern:      "wallets/" + framework.GenericNameRegex("wallet_id") + "/backup",
+		HelpSynopsis: "Export the wallet seed encrypted to an age or secp256k1 recipient public key, or list past backups.",
+		Fields: map[string]*framework.FieldSchema{
+			"wallet_id": {
+				Type:        framework.TypeString,
+				Description: "Logical wallet identifier in the path.",
+			},
+			"recipient": {
+				Type:        framework.TypeString,
+				Description: "age X25519 recipient (age1...), or hex secp256k1 public key (compressed, uncompressed, or a single-key account's public_key) to encrypt the backup to with ECIES.",
+			},
+			"wrap_ttl": {
+				Type:        framework.TypeDurationSecond,
+				Description: "If set, response-wrap the backup with this TTL (e.g. 15m).",
+			},
+		},
+		ExistenceCheck: ExistenceWalletSeed(),
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.ReadOperation:   handleWalletBackupList,
+			logical.CreateOperation: WithWalletLock(walletMu, handleWalletBackupCreate),
+			logical.UpdateOperation: WithWalletLock(walletMu, handleWalletBackupCreate),
+		},
+	}
+}
+
+// pathWalletBackupRestore registers POST on wallets/:wallet_id/backup/restore to re-import an encrypted backup.
+func pathWalletBackupRestore(walletMu *sync.Map) *framework.Path {
+	return &framework.Path{
+		Pattern:      "wallets/" + framework.GenericNameRegex("wallet_id") + "/backup/restore",
+		HelpSynopsis: "Re-import a wallet from an encrypted backup, decrypting it with a single-key account of this mount or an age identity.",
+		Fields: map[string]*framework.FieldSchema{
+			"wallet_id": {
+				Type:        framework.TypeString,
+				Description: "Wallet identifier to restore into; must not exist yet.",
+			},
+			"backup": {
+				Type:        framework.TypeString,
+				Description: "Hex ciphertext returned by wallets/:wallet_id/backup.",
+			},
+			"recipient_account": {
+				Type:        framework.TypeString,
+				Description: "Single-key account whose key an ECIES backup was encrypted to.",
+			},
+			"identity": {
+				Type:        framework.TypeString,
+				Description: "age X25519 identity (AGE-SECRET-KEY-1...) of the recipient an age backup was encrypted to.",
+			},
+		},
+		ExistenceCheck: ExistenceWalletSeed(),
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.CreateOperation: WithWalletLock(walletMu, handleWalletBackupRestore),
+			logical.UpdateOperation: WithWalletLock(walletMu, handleWalletBackupRestore),
+		},
+	}
+}
+
+// pathWalletShamir registers POST on wallets/:wallet_id/shamir to split the seed into encrypted SLIP-39 shares.
+func pathWalletShamir(walletMu *sync.Map) *framework.Path {
+	return &framework.Path{
+		Pattern:      "wallets/" + framework.GenericNameRegex("wallet_id") + "/shamir",
+		HelpSynopsis: "Split the wallet mnemonic into threshold-of-N SLIP-39 shares, each encrypted to one custodian's public key.",
+		Fields: map[string]*framework.FieldSchema{
+			"wallet_id": {
+				Type:        framework.TypeString,
+				Description: "Logical wallet identifier in the path.",
+			},
+			"threshold": {
+				Type:        framework.TypeInt,
+				Description: "Number of shares required to reconstruct the mnemonic (2 to the number of recipients).",
+			},
+			"recipients": {
+				Type:        framework.TypeCommaStringSlice,
+				Description: "age X25519 recipients or hex secp256k1 public keys of the custodians, one share each (at most 16).",
+			},
+			"iteration_exponent": {
+				Type:        framework.TypeInt,
+				Default:     1,
+				Description: "SLIP-39 iteration exponent e (0-15); share encryption uses 10000<<e PBKDF2 iterations.",
+			},
+			"wrap_ttl": {
+				Type:        framework.TypeDurationSecond,
+				Description: "If set, response-wrap the shares with this TTL (e.g. 15m).",
+			},
+		},
+		ExistenceCheck: ExistenceWalletSeed(),
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.CreateOperation: WithWalletLock(walletMu, handleWalletShamirSplit),
+			logical.UpdateOperation: WithWalletLock(walletMu, handleWalletShamirSplit),
+		},
+	}
+}
+
+// pathWalletShamirImport registers wallets/:wallet_id/shamir/import to reconstruct a wallet from SLIP-39 shares.
+func pathWalletShamirImport(walletMu *sync.Map) *framework.Path {
+	return &framework.Path{
+		Pattern:      "wallets/" + framework.GenericNameRegex("wallet_id") + "/shamir/import",
+		HelpSynopsis: "Submit SLIP-39 shares toward reconstructing a wallet; it is imported once the threshold is met.",
+		Fields: map[string]*framework.FieldSchema{
+			"wallet_id": {
+				Type:        framework.TypeString,
+				Description: "Wallet identifier to reconstruct into; must not exist yet.",
+			},
+			"shares": {
+				Type:        framework.TypeCommaStringSlice,
+				Description: "One or more SLIP-39 share mnemonics (decrypted by their custodians).",
+			},
+			"passphrase": {
+				Type:        framework.TypeString,
+				Description: "Optional BIP-39 passphrase of the original wallet; read from the request that completes the threshold.",
+			},
+			"derivation_path": {
+				Type:        framework.TypeString,
+				Description: "Derivation path template of the original wallet; read from the request that completes the threshold. Default: config default_derivation_path.",
+			},
+		},
+		ExistenceCheck: ExistenceWalletSeed(),
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.ReadOperation:   handleWalletShamirImportRead,
+			logical.CreateOperation: WithWalletLock(walletMu, handleWalletShamirImport),
+			logical.UpdateOperation: WithWalletLock(walletMu, handleWalletShamirImport),
+			logical.DeleteOperation: WithWalletLock(walletMu, handleWalletShamirImportDelete),
+		},
+	}
+}
+
+// pathWalletPolicy registers read/write/delete on wallets/:wallet_id/policy.
+func pathWalletPolicy() *framework.Path {
+	return &framework.Path{
+		Pattern:      "wallets/" + framework.GenericNameRegex("wallet_id") + "/policy",
+		HelpSynopsis: "Restrict which to addresses and contract selectors the wallet's accounts may sign transactions for.",
+		Fields: map[string]*framework.FieldSchema{
+			"wallet_id": {
+				Type:        framework.TypeString,
+				Description: "Logical wallet identifier in the path.",
+			},
+			"allow": {
+				Type:        framework.TypeCommaStringSlice,
+				Description: "Allowed destinations as <address>:<selector> rules; either part may be *. Empty allows any destination not denied.",
+			},
+			"deny": {
+				Type:        framework.TypeCommaStringSlice,
+				Description: "Denied destinations as <address>:<selector> rules; deny wins over allow.",
+			},
+		},
+		ExistenceCheck: existenceWalletPolicy,
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.ReadOperation:   handleWalletPolicyRead,
+			logical.CreateOperation: handleWalletPolicyWrite,
+			logical.UpdateOperation: handleWalletPolicyWrite,
+			logical.DeleteOperation: handleWalletPolicyDelete,
+		},
+	}
+}
+
+// pathWalletLimits registers read/write/delete on wallets/:wallet_id/limits.
+func pathWalletLimits() *framework.Path {
+	return &framework.Path{
+		Pattern:      "wallets/" + framework.GenericNameRegex("wallet_id") + "/limits",
+		HelpSynopsis: "Cap the native value the wallet may sign for within a rolling window.",
+		Fields: map[string]*framework.FieldSchema{
+			"wallet_id": {
+				Type:        framework.TypeString,
+				Description: "Logical wallet identifier in the path.",
+			},
+			"window": {
+				Type:        framework.TypeDurationSecond,
+				Description: "Rolling window, e.g. 24h or 3600. Default 24h.",
+			},
+			"wallet_max_value": {
+				Type:        framework.TypeString,
+				Description: "Max value in wei (decimal) across all derived accounts per window. Empty means no limit.",
+			},
+			"account_max_value": {
+				Type:        framework.TypeString,
+				Description: "Max value in wei (decimal) per derived account per window. Empty means no limit.",
+			},
+		},
+		ExistenceCheck: existenceWalletLimits,
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.ReadOperation:   handleWalletLimitsRead,
+			logical.CreateOperation: handleWalletLimitsWrite,
+			logical.UpdateOperation: handleWalletLimitsWrite,
+			logical.DeleteOperation: handleWalletLimitsDelete,
+		},
+	}
+}
+
+// pathWalletSpend registers read access on wallets/:wallet_id/spend.
+func pathWalletSpend() *framework.Path {
+	return &framework.Path{
+		Pattern:      "wallets/" + framework.GenericNameRegex("wallet_id") + "/spend",
+		HelpSynopsis: "Read the native value signed within the current velocity window.",
+		Fields: map[string]*framework.FieldSchema{
+			"wallet_id": {
+				Type:        framework.TypeString,
+				Description: "Logical wallet identifier in the path.",
+			},
+		},
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.ReadOperation: handleWalletSpendRead,
+		},
+	}
+}
+
+// pathWalletXpub registers read access on wallets/:wallet_id/xpub.
+func pathWalletXpub() *framework.Path {
+	return &framework.Path{
+		Pattern:      "wallets/" + framework.GenericNameRegex("wallet_id") + "/xpub",
+		HelpSynopsis: "Read the account-level extended public key (xpub) of the wallet.",
+		Fields: map[string]*framework.FieldSchema{
+			"wallet_id": {
+				Type:        framework.TypeString,
+				Description: "Logical wallet identifier in the path.",
+			},
+			"account": {
+				Type:        framework.TypeString,
+				Description: "BIP-44 account' segment whose account-level xpub is returned. Default 0.",
+			},
+			"path": {
+				Type:        framework.TypeString,
+				Description: "Optional BIP-32 path (e.g. m/44'/60'/0') to export instead of the account level of the wallet's template.",
+			},
+		},
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.ReadOperation: handleWalletXpubRead,
+		},
+	}
+}
+
+// pathWalletXpubAddresses registers read access on wallets/:wallet_id/xpub/addresses for watch-only derivation.
+func pathWalletXpubAddresses() *framework.Path {
+	return &framework.Path{
+		Pattern:      "wallets/" + framework.GenericNameRegex("wallet_id") + "/xpub/addresses",
+		HelpSynopsis: "Compute addresses for an index range from the account-level xpub without loading the mnemonic.",
+		Fields: map[string]*framework.FieldSchema{
+			"wallet_id": {
+				Type:        framework.TypeString,
+				Description: "Logical wallet identifier in the path.",
+			},
+			"account": {
+				Type:        framework.TypeString,
+				Description: "BIP-44 account' segment. Default 0.",
+			},
+			"start": {
+				Type:        framework.TypeString,
+				Description: "Inclusive lower address index.",
+			},
+			"end": {
+				Type:        framework.TypeString,
+				Description: "Inclusive upper address index.",
+			},
+		},
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.ReadOperation: handleWalletXpubAddresses,
+		},
+	}
+}
+
+// patternWalletAccountJournalBase returns the path prefix for a derived account's signing journal.
+func patternWalletAccountJournalBase() string {
+	walletID := framework.GenericNameRegex("wallet_id")
+	return "wallets/" + walletID + "/accounts/" + patternAccountIndex + "/journal"
+}
+
+// walletJournalFields returns field schemas shared by the wallet journal paths.
+func walletJournalFields() map[string]*framework.FieldSchema {
+	return map[string]*framework.FieldSchema{
+		"wallet_id": {Type: framework.TypeString},
+		"index":     {Type: framework.TypeString},
+		"account":   {Type: framework.TypeString, Description: "Optional BIP-44 account' segment (path :account/:index). Default 0."},
+	}
+}
+
+// pathWalletJournal registers LIST on wallets/.../accounts/:index/journal for record sequence numbers.
+func pathWalletJournal() *framework.Path {
+	return &framework.Path{
+		Pattern:      patternWalletAccountJournalBase() + "/?",
+		HelpSynopsis: "List the sequence numbers of a derived account's signing journal.",
+		Fields:       walletJournalFields(),
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.ListOperation: journal.HandleList(walletJournalPrefix),
+		},
+	}
+}
+
+// pathWalletJournalVerify registers read on wallets/.../accounts/:index/journal/verify.
+func pathWalletJournalVerify() *framework.Path {
+	return &framework.Path{
+		Pattern:      patternWalletAccountJournalBase() + "/verify",
+		HelpSynopsis: "Check the hash chain of a derived account's signing journal.",
+		Fields:       walletJournalFields(),
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.ReadOperation: journal.HandleVerify(walletJournalPrefix),
+		},
+	}
+}
+
+// pathWalletJournalRecord registers read on wallets/.../accounts/:index/journal/:seq.
+func pathWalletJournalRecord() *framework.Path {
+	fields := walletJournalFields()
+	fields["seq"] = journal.SeqField()
+	return &framework.Path{
+		Pattern:      patternWalletAccountJournalBase() + "/" + journal.PatternSeq,
+		HelpSynopsis: "Read one record of a derived account's signing journal.",
+		Fields:       fields,
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.ReadOperation: journal.HandleRead(walletJournalPrefix),
+		},
+	}
+}
+
+// patternWalletAccountNonceBase returns the path prefix for a derived account's nonce tracker on one chain.
+func patternWalletAccountNonceBase() string {
+	walletID := framework.GenericNameRegex("wallet_id")
+	return "wallets/" + walletID + "/accounts/" + patternAccountIndex + "/nonces/" + nonces.PatternChainID
+}
+
+// walletNonceFields returns field schemas shared by the wallet nonce paths.
+func walletNonceFields() map[string]*framework.FieldSchema {
+	fields := nonces.Fields()
+	fields["wallet_id"] = &framework.FieldSchema{Type: framework.TypeString}
+	fields["index"] = &framework.FieldSchema{Type: framework.TypeString}
+	fields["account"] = &framework.FieldSchema{Type: framework.TypeString, Description: "Optional BIP-44 account' segment (path :account/:index). Default 0."}
+	return fields
+}
+
+// pathWalletNonce registers read and set on wallets/.../accounts/:index/nonces/:chain_id.
+func pathWalletNonce(walletMu *sync.Map) *framework.Path {
+	return &framework.Path{
+		Pattern:        patternWalletAccountNonceBase(),
+		HelpSynopsis:   "Read or set the nonce tracker used by sign-tx nonce \"auto\" for a derived account on a chain.",
+		Fields:         walletNonceFields(),
+		ExistenceCheck: ExistenceWalletDerivedAccount(),
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.ReadOperation:   WithWalletLock(walletMu, nonces.HandleRead(walletNonceAddress)),
+			logical.CreateOperation: WithWalletLock(walletMu, nonces.HandleWrite(walletNonceAddress)),
+			logical.UpdateOperation: WithWalletLock(walletMu, nonces.HandleWrite(walletNonceAddress)),
+		},
+	}
+}
+
+// pathWalletNonceReserve registers POST on wallets/.../accounts/:index/nonces/:chain_id/reserve.
+func pathWalletNonceReserve(walletMu *sync.Map) *framework.Path {
+	return &framework.Path{
+		Pattern:        patternWalletAccountNonceBase() + "/reserve",
+		HelpSynopsis:   "Issue the next nonce of a derived account on a chain without signing.",
+		Fields:         walletNonceFields(),
+		ExistenceCheck: ExistenceWalletDerivedAccount(),
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.CreateOperation: WithWalletLock(walletMu, nonces.HandleReserve(walletNonceAddress)),
+			logical.UpdateOperation: WithWalletLock(walletMu, nonces.HandleReserve(walletNonceAddress)),
+		},
+	}
+}
+
+// pathWalletNonceRelease registers POST on wallets/.../accounts/:index/nonces/:chain_id/release.
+func pathWalletNonceRelease(walletMu *sync.Map) *framework.Path {
+	return &framework.Path{
+		Pattern:        patternWalletAccountNonceBase() + "/release",
+		HelpSynopsis:   "Give an issued nonce of a derived account back for reuse, e.g. after its transaction was dropped.",
+		Fields:         walletNonceFields(),
+		ExistenceCheck: ExistenceWalletDerivedAccount(),
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.CreateOperation: WithWalletLock(walletMu, nonces.HandleRelease(walletNonceAddress)),
+			logical.UpdateOperation: WithWalletLock(walletMu, nonces.HandleRelease(walletNonceAddress)),
+		},
+	}
+}
+
+// pathDerivedAccount registers read access on wallets/:wallet_id/accounts/:index and
+// wallets/:wallet_id/accounts/:account/:index.
 func pathDerivedAccount() *framework.Path {
 	walletID := framework.GenericNameRegex("wallet_id")
 	return &framework.Path{
-		Pattern:      "wallets/" + walletID + "/accounts/(?P<index>\\d+)",
-		HelpSynopsis: "Read a derived Ethereum account at m/44'/60'/0'/0/<index>.",
+		Pattern:      "wallets/" + walletID + "/accounts/" + patternAccountIndex,
+		HelpSynopsis: "Read a derived Ethereum account at m/44'/60'/<account>'/0/<index>.",
 		Fields: map[string]*framework.FieldSchema{
 			"wallet_id": {
 				Type:        framework.TypeString,
@@ -123,6 +601,10 @@ func pathDerivedAccount() *framework.Path {
 				Type:        framework.TypeString,
 				Description: "BIP-44 address index (non-negative integer, max 2147483647).",
 			},
+			"account": {
+				Type:        framework.TypeString,
+				Description: "Optional BIP-44 account' segment (path :account/:index; max 2147483647). Default 0.",
+			},
 		},
 		ExistenceCheck: ExistenceWalletDerivedAccount(),
 		Callbacks: map[logical.Operation]framework.OperationFunc{
@@ -132,7 +614,7 @@ func pathDerivedAccount() *framework.Path {
 }
 
 // pathBatchDerivedAccounts registers POST on wallets/:wallet_id/accounts/batch to create
-// multiple derived accounts in one request (bounded by maxBatchDerivedAccounts in crud.go).
+// multiple derived accounts in one request (bounded by config max_batch_derived_accounts).
 func pathBatchDerivedAccounts(walletMu *sync.Map) *framework.Path {
 	walletID := framework.GenericNameRegex("wallet_id")
 	return &framework.Path{
@@ -145,6 +627,10 @@ func pathBatchDerivedAccounts(walletMu *sync.Map) *framework.Path {
 				Required:    true,
 				Description: "Number of accounts to create (positive integer, max 10000).",
 			},
+			"account": {
+				Type:        framework.TypeString,
+				Description: "BIP-44 account' segment to create the accounts under; each segment has its own counter. Default 0.",
+			},
 		},
 		ExistenceCheck: ExistenceWalletDerivedAccountsRoot(),
 		Callbacks: map[logical.Operation]framework.OperationFunc{
@@ -173,6 +659,10 @@ func pathListDerivedAccounts(walletMu *sync.Map) *framework.Path {
 				Type:        framework.TypeString,
 				Description: "Inclusive upper index for range read (required with start; use with GET).",
 			},
+			"account": {
+				Type:        framework.TypeString,
+				Description: "BIP-44 account' segment to list, create in, or range-read; each segment has its own counter. Default 0.",
+			},
 		},
 		ExistenceCheck: ExistenceWalletDerivedAccountsRoot(),
 		Callbacks: map[logical.Operation]framework.OperationFunc{
@@ -184,10 +674,14 @@ func pathListDerivedAccounts(walletMu *sync.Map) *framework.Path {
 	}
 }
 
+// patternAccountIndex matches :index or :account/:index, where :account is the optional BIP-44 account'
+// segment (default 0).
+const patternAccountIndex = "(?:(?P<account>\\d+)/)?(?P<index>\\d+)"
+
 // patternWalletAccountSignTxBase returns the path prefix for wallet sign-tx endpoints.
 func patternWalletAccountSignTxBase() string {
 	walletID := framework.GenericNameRegex("wallet_id")
-	return "wallets/" + walletID + "/accounts/(?P<index>\\d+)/sign-tx"
+	return "wallets/" + walletID + "/accounts/" + patternAccountIndex + "/sign-tx"
 }
 
 // walletSignTxType0Fields returns field schemas for wallet legacy transaction requests.
@@ -195,13 +689,14 @@ func walletSignTxType0Fields() map[string]*framework.FieldSchema {
 	return map[string]*framework.FieldSchema{
 		"wallet_id": {Type: framework.TypeString},
 		"index":     {Type: framework.TypeString},
+		"account":   {Type: framework.TypeString, Description: "Optional BIP-44 account' segment (path :account/:index). Default 0."},
 		"chain_id": {
 			Type:        framework.TypeString,
 			Description: "Chain ID (decimal). Alias: chainID.",
 		},
 		"nonce": {
 			Type:        framework.TypeString,
-			Description: "Transaction nonce (decimal).",
+			Description: "Transaction nonce (decimal), or \"auto\" for the next nonce from the nonce tracker.",
 		},
 		"to": {
 			Type:        framework.TypeString,
@@ -221,8 +716,7 @@ func walletSignTxType0Fields() map[string]*framework.FieldSchema {
 		},
 		"gas_limit": {
 			Type:        framework.TypeString,
-			Description: "Gas limit (decimal).",
-			Default:     "21000",
+			Description: "Gas limit (decimal). Default: config default_gas_limit (21000).",
 		},
 		"data": {
 			Type:        framework.TypeString,
@@ -241,16 +735,16 @@ func walletSignTxType0Fields() map[string]*framework.FieldSchema {
 }
 
 // pathWalletSignTxLegacy registers EIP-155 legacy signing on .../sign-tx/legacy.
-func pathWalletSignTxLegacy() *framework.Path {
+func pathWalletSignTxLegacy(walletMu *sync.Map) *framework.Path {
 	return &framework.Path{
 		Pattern:        patternWalletAccountSignTxBase() + "/legacy",
 		HelpSynopsis:   "Sign an EIP-155 type-0 EVM transaction (fixed gas price).",
 		Fields:         walletSignTxType0Fields(),
 		ExistenceCheck: ExistenceWalletDerivedAccount(),
 		Callbacks: map[logical.Operation]framework.OperationFunc{
-			logical.CreateOperation: handleWalletSignTxType0,
+			logical.CreateOperation: WithWalletLock(walletMu, handleWalletSignTxType0),
 			// Vault maps HTTP writes to Update when ExistenceCheck is true; register same handler.
-			logical.UpdateOperation: handleWalletSignTxType0,
+			logical.UpdateOperation: WithWalletLock(walletMu, handleWalletSignTxType0),
 		},
 	}
 }
@@ -266,21 +760,102 @@ func handleWalletSignTxType0(ctx context.Context, req *logical.Request, data *fr
 	if err != nil {
 		return nil, err
 	}
-	signingKey, acct, cleanup, err := loadSigningKeyForTx(ctx, req.Storage, walletID, indexStr)
-	if err != nil {
-		return RespondLoadWalletKeyError(err)
+	_, accountStr, errResp := accountSegmentFromRequest(wrapper)
+	if errResp != nil {
+		return errResp, nil
 	}
-	defer cleanup()
+	chainID, err := wrapper.MustGetBigIntAny("chain_id", "chainID")
+	if err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	cfg, err := config.Read(ctx, req.Storage)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.CheckSigning(model.SigningModeLegacy, chainID); err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	gasLimit := wrapper.GetUint64NonEmpty("gas_limit", cfg.DefaultGasLimit)
+	value := wrapper.BigIntWithAliases("value", "amount", big.NewInt(0))
+	inputStr := wrapper.GetString("data", "")
+	var txData []byte
+	if strings.TrimSpace(inputStr) != "" {
+		txData, err = hexutil.Decode(inputStr)
+		if err != nil {
+			return logical.ErrorResponse("invalid data hex: %s", err.Error()), nil
+		}
+	}
+	toStr := wrapper.GetStringFirstNonEmpty("to", "address_to")
+	var toPtr *common.Address
+	if toStr != "" {
+		addr := common.HexToAddress(toStr)
+		toPtr = &addr
+	}
+	policyIn := chain.TxPolicyInputFromRequest(wrapper, chainID, value, toPtr, txData, "gas_price")
+	signingKey, acct, cleanup, err := loadSigningKeyForTx(ctx, req.Storage, walletID, accountStr, indexStr, policyIn)
+	if err != nil {
+		return RespondLoadWalletKeyError(err)
+	}
+	defer cleanup()
+	nonce, errResp, err := nonces.FromRequest(ctx, req.Storage, wrapper, chainID, acct.AddressStr)
+	if errResp != nil || err != nil {
+		return errResp, err
+	}
+	resp, err := signType0Tx(wrapper, chainID, nonce, gasLimit, value, txData, toPtr, signingKey, acct)
+	return recordWalletSignedTx(ctx, req, walletID, accountStr, indexStr, chainID, resp, err)
+}
 
+// walletSignTxEIP2930Fields returns field schemas for wallet EIP-2930 transaction requests.
+func walletSignTxEIP2930Fields() map[string]*framework.FieldSchema {
+	fields := walletSignTxType0Fields()
+	fields["access_list"] = &framework.FieldSchema{
+		Type:        framework.TypeString,
+		Description: "Optional EIP-2930 access list as JSON array.",
+	}
+	return fields
+}
+
+// pathWalletSignTxEIP2930 registers EIP-2930 signing on .../sign-tx/eip2930.
+func pathWalletSignTxEIP2930(walletMu *sync.Map) *framework.Path {
+	return &framework.Path{
+		Pattern:        patternWalletAccountSignTxBase() + "/eip2930",
+		HelpSynopsis:   "Sign an EIP-2930 (type-1) EVM transaction with an access list (fixed gas price).",
+		Fields:         walletSignTxEIP2930Fields(),
+		ExistenceCheck: ExistenceWalletDerivedAccount(),
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.CreateOperation: WithWalletLock(walletMu, handleWalletSignTxEIP2930),
+			logical.UpdateOperation: WithWalletLock(walletMu, handleWalletSignTxEIP2930),
+		},
+	}
+}
+
+// handleWalletSignTxEIP2930 parses access-list tx fields and returns a signed type-1 transaction.
+func handleWalletSignTxEIP2930(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	wrapper := model.NewFieldDataWrapper(data)
+	walletID, err := wrapper.MustGetString("wallet_id")
+	if err != nil {
+		return nil, err
+	}
+	indexStr, err := wrapper.MustGetString("index")
+	if err != nil {
+		return nil, err
+	}
+	_, accountStr, errResp := accountSegmentFromRequest(wrapper)
+	if errResp != nil {
+		return errResp, nil
+	}
 	chainID, err := wrapper.MustGetBigIntAny("chain_id", "chainID")
 	if err != nil {
 		return logical.ErrorResponse("%s", err.Error()), nil
 	}
-	gasLimit, err := wrapper.MustGetUint64("gas_limit")
+	cfg, err := config.Read(ctx, req.Storage)
 	if err != nil {
 		return nil, err
 	}
-	nonce := wrapper.GetUint64("nonce", 0)
+	if err := cfg.CheckSigning(model.SigningModeEIP2930, chainID); err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	gasLimit := wrapper.GetUint64NonEmpty("gas_limit", cfg.DefaultGasLimit)
 	value := wrapper.BigIntWithAliases("value", "amount", big.NewInt(0))
 	inputStr := wrapper.GetString("data", "")
 	var txData []byte
@@ -296,7 +871,18 @@ func handleWalletSignTxType0(ctx context.Context, req *logical.Request, data *fr
 		addr := common.HexToAddress(toStr)
 		toPtr = &addr
 	}
-	return signType0Tx(wrapper, chainID, nonce, gasLimit, value, txData, toPtr, signingKey, acct)
+	policyIn := chain.TxPolicyInputFromRequest(wrapper, chainID, value, toPtr, txData, "gas_price")
+	signingKey, acct, cleanup, err := loadSigningKeyForTx(ctx, req.Storage, walletID, accountStr, indexStr, policyIn)
+	if err != nil {
+		return RespondLoadWalletKeyError(err)
+	}
+	defer cleanup()
+	nonce, errResp, err := nonces.FromRequest(ctx, req.Storage, wrapper, chainID, acct.AddressStr)
+	if errResp != nil || err != nil {
+		return errResp, err
+	}
+	resp, err := signEIP2930Tx(wrapper, chainID, nonce, gasLimit, value, txData, toPtr, signingKey, acct)
+	return recordWalletSignedTx(ctx, req, walletID, accountStr, indexStr, chainID, resp, err)
 }
 
 // walletSignTxEIP1559Fields returns field schemas for wallet EIP-1559 transaction requests.
@@ -304,13 +890,14 @@ func walletSignTxEIP1559Fields() map[string]*framework.FieldSchema {
 	return map[string]*framework.FieldSchema{
 		"wallet_id": {Type: framework.TypeString},
 		"index":     {Type: framework.TypeString},
+		"account":   {Type: framework.TypeString, Description: "Optional BIP-44 account' segment (path :account/:index). Default 0."},
 		"chain_id": {
 			Type:        framework.TypeString,
 			Description: "Chain ID (decimal). Alias: chainID.",
 		},
 		"nonce": {
 			Type:        framework.TypeString,
-			Description: "Transaction nonce (decimal).",
+			Description: "Transaction nonce (decimal), or \"auto\" for the next nonce from the nonce tracker.",
 		},
 		"to": {
 			Type:        framework.TypeString,
@@ -330,8 +917,7 @@ func walletSignTxEIP1559Fields() map[string]*framework.FieldSchema {
 		},
 		"gas_limit": {
 			Type:        framework.TypeString,
-			Description: "Gas limit (decimal).",
-			Default:     "21000",
+			Description: "Gas limit (decimal). Default: config default_gas_limit (21000).",
 		},
 		"data": {
 			Type:        framework.TypeString,
@@ -365,15 +951,15 @@ func walletSignTxEIP1559Fields() map[string]*framework.FieldSchema {
 }
 
 // pathWalletSignTxEIP1559 registers EIP-1559 signing on .../sign-tx/eip1559.
-func pathWalletSignTxEIP1559() *framework.Path {
+func pathWalletSignTxEIP1559(walletMu *sync.Map) *framework.Path {
 	return &framework.Path{
 		Pattern:        patternWalletAccountSignTxBase() + "/eip1559",
 		HelpSynopsis:   "Sign an EIP-1559 (type-2) EVM transaction with dynamic fees.",
 		Fields:         walletSignTxEIP1559Fields(),
 		ExistenceCheck: ExistenceWalletDerivedAccount(),
 		Callbacks: map[logical.Operation]framework.OperationFunc{
-			logical.CreateOperation: handleWalletSignTxEIP1559,
-			logical.UpdateOperation: handleWalletSignTxEIP1559,
+			logical.CreateOperation: WithWalletLock(walletMu, handleWalletSignTxEIP1559),
+			logical.UpdateOperation: WithWalletLock(walletMu, handleWalletSignTxEIP1559),
 		},
 	}
 }
@@ -383,68 +969,474 @@ func handleWalletSignTxEIP1559(ctx context.Context, req *logical.Request, data *
 	wrapper := model.NewFieldDataWrapper(data)
 	walletID, err := wrapper.MustGetString("wallet_id")
 	if err != nil {
-		return nil, err
+		return nil, err
+	}
+	indexStr, err := wrapper.MustGetString("index")
+	if err != nil {
+		return nil, err
+	}
+	_, accountStr, errResp := accountSegmentFromRequest(wrapper)
+	if errResp != nil {
+		return errResp, nil
+	}
+	chainID, err := wrapper.MustGetBigIntAny("chain_id", "chainID")
+	if err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	cfg, err := config.Read(ctx, req.Storage)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.CheckSigning(model.SigningModeEIP1559, chainID); err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	gasLimit := wrapper.GetUint64NonEmpty("gas_limit", cfg.DefaultGasLimit)
+	value := wrapper.BigIntWithAliases("value", "amount", big.NewInt(0))
+	inputStr := wrapper.GetString("data", "")
+	var txData []byte
+	if strings.TrimSpace(inputStr) != "" {
+		txData, err = hexutil.Decode(inputStr)
+		if err != nil {
+			return logical.ErrorResponse("invalid data hex: %s", err.Error()), nil
+		}
+	}
+	toStr := wrapper.GetStringFirstNonEmpty("to", "address_to")
+	var toPtr *common.Address
+	if toStr != "" {
+		addr := common.HexToAddress(toStr)
+		toPtr = &addr
+	}
+	policyIn := chain.TxPolicyInputFromRequest(wrapper, chainID, value, toPtr, txData, "max_fee_per_gas", "maxFeePerGas")
+	signingKey, acct, cleanup, err := loadSigningKeyForTx(ctx, req.Storage, walletID, accountStr, indexStr, policyIn)
+	if err != nil {
+		return RespondLoadWalletKeyError(err)
+	}
+	defer cleanup()
+	nonce, errResp, err := nonces.FromRequest(ctx, req.Storage, wrapper, chainID, acct.AddressStr)
+	if errResp != nil || err != nil {
+		return errResp, err
+	}
+	resp, err := signEIP1559Tx(wrapper, chainID, nonce, gasLimit, value, txData, toPtr, signingKey, acct)
+	return recordWalletSignedTx(ctx, req, walletID, accountStr, indexStr, chainID, resp, err)
+}
+
+// walletSignTxBlobFields returns field schemas for wallet EIP-4844 blob transaction requests.
+func walletSignTxBlobFields() map[string]*framework.FieldSchema {
+	fields := walletSignTxEIP1559Fields()
+	fields["max_fee_per_blob_gas"] = &framework.FieldSchema{
+		Type:        framework.TypeString,
+		Description: "Max fee per blob gas (wei, decimal). Alias: maxFeePerBlobGas.",
+	}
+	fields["maxFeePerBlobGas"] = &framework.FieldSchema{
+		Type:        framework.TypeString,
+		Description: "CamelCase alias for max_fee_per_blob_gas.",
+	}
+	fields["blob_versioned_hashes"] = &framework.FieldSchema{
+		Type:        framework.TypeString,
+		Description: "Blob versioned hashes as JSON array of hex strings. Required unless blobs is set.",
+	}
+	fields["blobs"] = &framework.FieldSchema{
+		Type:        framework.TypeString,
+		Description: "Raw blobs as JSON array of hex strings; commitments and proofs are computed by the plugin.",
+	}
+	fields["blob_sidecar_version"] = &framework.FieldSchema{
+		Type:        framework.TypeString,
+		Description: "Sidecar proof layout when blobs is set: 0 (one proof per blob) or 1 (cell proofs, Osaka).",
+		Default:     "1",
+	}
+	return fields
+}
+
+// pathWalletSignTxBlob registers EIP-4844 signing on .../sign-tx/blob.
+func pathWalletSignTxBlob(walletMu *sync.Map) *framework.Path {
+	return &framework.Path{
+		Pattern:        patternWalletAccountSignTxBase() + "/blob",
+		HelpSynopsis:   "Sign an EIP-4844 (type-3) blob transaction; returns the network wrapper form when blobs are supplied.",
+		Fields:         walletSignTxBlobFields(),
+		ExistenceCheck: ExistenceWalletDerivedAccount(),
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.CreateOperation: WithWalletLock(walletMu, handleWalletSignTxBlob),
+			logical.UpdateOperation: WithWalletLock(walletMu, handleWalletSignTxBlob),
+		},
+	}
+}
+
+// handleWalletSignTxBlob parses blob tx fields and returns a signed type-3 transaction.
+func handleWalletSignTxBlob(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	wrapper := model.NewFieldDataWrapper(data)
+	walletID, err := wrapper.MustGetString("wallet_id")
+	if err != nil {
+		return nil, err
+	}
+	indexStr, err := wrapper.MustGetString("index")
+	if err != nil {
+		return nil, err
+	}
+	_, accountStr, errResp := accountSegmentFromRequest(wrapper)
+	if errResp != nil {
+		return errResp, nil
+	}
+	chainID, err := wrapper.MustGetBigIntAny("chain_id", "chainID")
+	if err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	cfg, err := config.Read(ctx, req.Storage)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.CheckSigning(model.SigningModeBlob, chainID); err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	gasLimit := wrapper.GetUint64NonEmpty("gas_limit", cfg.DefaultGasLimit)
+	value := wrapper.BigIntWithAliases("value", "amount", big.NewInt(0))
+	inputStr := wrapper.GetString("data", "")
+	var txData []byte
+	if strings.TrimSpace(inputStr) != "" {
+		txData, err = hexutil.Decode(inputStr)
+		if err != nil {
+			return logical.ErrorResponse("invalid data hex: %s", err.Error()), nil
+		}
+	}
+	toStr := wrapper.GetStringFirstNonEmpty("to", "address_to")
+	var toPtr *common.Address
+	if toStr != "" {
+		addr := common.HexToAddress(toStr)
+		toPtr = &addr
+	}
+	policyIn := chain.TxPolicyInputFromRequest(wrapper, chainID, value, toPtr, txData, "max_fee_per_gas", "maxFeePerGas")
+	signingKey, acct, cleanup, err := loadSigningKeyForTx(ctx, req.Storage, walletID, accountStr, indexStr, policyIn)
+	if err != nil {
+		return RespondLoadWalletKeyError(err)
+	}
+	defer cleanup()
+	nonce, errResp, err := nonces.FromRequest(ctx, req.Storage, wrapper, chainID, acct.AddressStr)
+	if errResp != nil || err != nil {
+		return errResp, err
+	}
+	resp, err := signBlobTx(wrapper, chainID, nonce, gasLimit, value, txData, toPtr, signingKey, acct)
+	return recordWalletSignedTx(ctx, req, walletID, accountStr, indexStr, chainID, resp, err)
+}
+
+// walletSignTxEIP7702Fields returns field schemas for wallet EIP-7702 set-code transaction requests.
+func walletSignTxEIP7702Fields() map[string]*framework.FieldSchema {
+	fields := walletSignTxEIP1559Fields()
+	fields["authorization_list"] = &framework.FieldSchema{
+		Type:        framework.TypeString,
+		Description: "EIP-7702 authorization list as JSON array of signed tuples (see sign-authorization). Required.",
+	}
+	return fields
+}
+
+// pathWalletSignTxEIP7702 registers EIP-7702 signing on .../sign-tx/eip7702.
+func pathWalletSignTxEIP7702(walletMu *sync.Map) *framework.Path {
+	return &framework.Path{
+		Pattern:        patternWalletAccountSignTxBase() + "/eip7702",
+		HelpSynopsis:   "Sign an EIP-7702 (type-4) set-code transaction carrying an authorization list.",
+		Fields:         walletSignTxEIP7702Fields(),
+		ExistenceCheck: ExistenceWalletDerivedAccount(),
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.CreateOperation: WithWalletLock(walletMu, handleWalletSignTxEIP7702),
+			logical.UpdateOperation: WithWalletLock(walletMu, handleWalletSignTxEIP7702),
+		},
+	}
+}
+
+// handleWalletSignTxEIP7702 parses set-code tx fields and returns a signed type-4 transaction.
+func handleWalletSignTxEIP7702(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	wrapper := model.NewFieldDataWrapper(data)
+	walletID, err := wrapper.MustGetString("wallet_id")
+	if err != nil {
+		return nil, err
+	}
+	indexStr, err := wrapper.MustGetString("index")
+	if err != nil {
+		return nil, err
+	}
+	_, accountStr, errResp := accountSegmentFromRequest(wrapper)
+	if errResp != nil {
+		return errResp, nil
+	}
+	chainID, err := wrapper.MustGetBigIntAny("chain_id", "chainID")
+	if err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	cfg, err := config.Read(ctx, req.Storage)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.CheckSigning(model.SigningModeEIP7702, chainID); err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	gasLimit := wrapper.GetUint64NonEmpty("gas_limit", cfg.DefaultGasLimit)
+	value := wrapper.BigIntWithAliases("value", "amount", big.NewInt(0))
+	inputStr := wrapper.GetString("data", "")
+	var txData []byte
+	if strings.TrimSpace(inputStr) != "" {
+		txData, err = hexutil.Decode(inputStr)
+		if err != nil {
+			return logical.ErrorResponse("invalid data hex: %s", err.Error()), nil
+		}
+	}
+	toStr := wrapper.GetStringFirstNonEmpty("to", "address_to")
+	var toPtr *common.Address
+	if toStr != "" {
+		addr := common.HexToAddress(toStr)
+		toPtr = &addr
+	}
+	policyIn := chain.TxPolicyInputFromRequest(wrapper, chainID, value, toPtr, txData, "max_fee_per_gas", "maxFeePerGas")
+	policyIn.Delegates = chain.DelegatesFromRequest(wrapper)
+	signingKey, acct, cleanup, err := loadSigningKeyForTx(ctx, req.Storage, walletID, accountStr, indexStr, policyIn)
+	if err != nil {
+		return RespondLoadWalletKeyError(err)
+	}
+	defer cleanup()
+	nonce, errResp, err := nonces.FromRequest(ctx, req.Storage, wrapper, chainID, acct.AddressStr)
+	if errResp != nil || err != nil {
+		return errResp, err
+	}
+	resp, err := signEIP7702Tx(wrapper, chainID, nonce, gasLimit, value, txData, toPtr, signingKey, acct)
+	return recordWalletSignedTx(ctx, req, walletID, accountStr, indexStr, chainID, resp, err)
+}
+
+// walletSignTxReplacementFields returns field schemas for wallet replace and cancel requests.
+func walletSignTxReplacementFields() map[string]*framework.FieldSchema {
+	fields := signedtxs.Fields()
+	fields["wallet_id"] = &framework.FieldSchema{Type: framework.TypeString}
+	fields["index"] = &framework.FieldSchema{Type: framework.TypeString}
+	fields["account"] = &framework.FieldSchema{Type: framework.TypeString, Description: "Optional BIP-44 account' segment (path :account/:index). Default 0."}
+	return fields
+}
+
+// pathWalletSignTxReplace registers speed-up of a pending transaction on .../sign-tx/replace.
+func pathWalletSignTxReplace(walletMu *sync.Map) *framework.Path {
+	return &framework.Path{
+		Pattern:        patternWalletAccountSignTxBase() + "/replace",
+		HelpSynopsis:   "Re-sign a pending transaction of a derived account with the same nonce and higher fees.",
+		Fields:         walletSignTxReplacementFields(),
+		ExistenceCheck: ExistenceWalletDerivedAccount(),
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.CreateOperation: WithWalletLock(walletMu, makeHandleWalletSignTxReplacement(false)),
+			logical.UpdateOperation: WithWalletLock(walletMu, makeHandleWalletSignTxReplacement(false)),
+		},
+	}
+}
+
+// pathWalletSignTxCancel registers cancellation of a pending transaction on .../sign-tx/cancel.
+func pathWalletSignTxCancel(walletMu *sync.Map) *framework.Path {
+	return &framework.Path{
+		Pattern:        patternWalletAccountSignTxBase() + "/cancel",
+		HelpSynopsis:   "Sign a zero-value self-transfer with the nonce and higher fees of a pending transaction of a derived account.",
+		Fields:         walletSignTxReplacementFields(),
+		ExistenceCheck: ExistenceWalletDerivedAccount(),
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.CreateOperation: WithWalletLock(walletMu, makeHandleWalletSignTxReplacement(true)),
+			logical.UpdateOperation: WithWalletLock(walletMu, makeHandleWalletSignTxReplacement(true)),
+		},
+	}
+}
+
+// makeHandleWalletSignTxReplacement returns the replace handler, or the cancel handler when cancel is set. The
+// replacement goes through the same policies as sign-tx; the replaced transaction's spend is not counted twice.
+func makeHandleWalletSignTxReplacement(cancel bool) framework.OperationFunc {
+	return func(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+		wrapper := model.NewFieldDataWrapper(data)
+		walletID, err := wrapper.MustGetString("wallet_id")
+		if err != nil {
+			return nil, err
+		}
+		indexStr, err := wrapper.MustGetString("index")
+		if err != nil {
+			return nil, err
+		}
+		_, accountStr, errResp := accountSegmentFromRequest(wrapper)
+		if errResp != nil {
+			return errResp, nil
+		}
+		replacement, errResp, err := signedtxs.Prepare(ctx, req.Storage, storagekey.WalletSignedTxsPrefix(walletID), wrapper, cancel)
+		if errResp != nil || err != nil {
+			return errResp, err
+		}
+		signingKey, acct, cleanup, err := loadSigningKeyForTx(ctx, req.Storage, walletID, accountStr, indexStr, replacement.PolicyInput())
+		if err != nil {
+			return RespondLoadWalletKeyError(err)
+		}
+		defer cleanup()
+		resp, err := replacement.Sign(acct.AddressStr, signingKey)
+		return recordWalletSignedTx(ctx, req, walletID, accountStr, indexStr, replacement.ChainID, resp, err)
+	}
+}
+
+// loadSigningKeyForTx enforces the registered chain policy, the wallet's destination policy and its velocity
+// limits on policyIn, then loads the derived signing key and builds a model.Account for tx response helpers.
+// Rejections wrap model.ErrChainPolicyViolation, model.ErrDestinationPolicyViolation or model.ErrVelocityLimitExceeded.
+func loadSigningKeyForTx(
+	ctx context.Context,
+	storage logical.Storage,
+	walletID, accountStr, indexStr string,
+	policyIn *model.TxPolicyInput,
+) (signingKey *ecdsa.PrivateKey, account *model.Account, cleanup func(), err error) {
+	if err := chain.CheckTx(ctx, storage, policyIn); err != nil {
+		return nil, nil, nil, err
+	}
+	if policyIn != nil {
+		dest, err := ReadWalletDestinationPolicy(ctx, storage, walletID)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if dest != nil {
+			if err := dest.Check("wallet "+walletID, policyIn.To, policyIn.Data); err != nil {
+				return nil, nil, nil, err
+			}
+			for _, delegate := range policyIn.Delegates {
+				if err := dest.CheckDelegate("wallet "+walletID, delegate); err != nil {
+					return nil, nil, nil, err
+				}
+			}
+		}
+		limits, err := ReadWalletVelocityLimits(ctx, storage, walletID)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if limits != nil {
+			ledger, err := ReadWalletSpendLedger(ctx, storage, walletID)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			now := time.Now()
+			value := policyIn.Value
+			if policyIn.Replaces != "" {
+				value = ledger.ReplacementValue(policyIn.Replaces, value, now.Add(-limits.Window()))
+			}
+			if err := limits.Check(ledger, spendLedgerIndex(accountStr, indexStr), value, now); err != nil {
+				return nil, nil, nil, err
+			}
+		}
+	}
+	pk, derived, err := LoadSegmentDerivedPrivateKey(ctx, storage, walletID, accountStr, indexStr)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	signingKey = pk
+	account = &model.Account{AddressStr: derived.Address}
+	cleanup = func() {
+		utils.ZeroKey(pk)
+	}
+	return signingKey, account, cleanup, nil
+}
+
+// txTypeLabelEthereumType0 is the response `type` value for EIP-155 type-0 (Ethereum convention).
+const txTypeLabelEthereumType0 = "legacy"
+
+// signType0Tx signs a type-0 tx for a wallet-derived account and builds the Vault response map.
+func signType0Tx(
+	wrapper *model.FieldDataWrapper,
+	chainID *big.Int,
+	nonce, gasLimit uint64,
+	value *big.Int,
+	txData []byte,
+	toPtr *common.Address,
+	signingKey *ecdsa.PrivateKey,
+	account *model.Account,
+) (*logical.Response, error) {
+	gasPrice, err := wrapper.MustGetBigIntAny("gas_price")
+	if err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	signedTx, err := ethutil.SignType0EIP155(
+		chainID, nonce, gasLimit, value, txData, toPtr, gasPrice, signingKey,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("sign type-0 tx: %w", err)
+	}
+	data, err := ethutil.SignedTxResponseData(
+		signedTx,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &logical.Response{Data: data}, nil
+}
+
+// signEIP2930Tx signs a type-1 tx with access list and builds the Vault response map.
+func signEIP2930Tx(
+	wrapper *model.FieldDataWrapper,
+	chainID *big.Int,
+	nonce, gasLimit uint64,
+	value *big.Int,
+	txData []byte,
+	toPtr *common.Address,
+	signingKey *ecdsa.PrivateKey,
+	account *model.Account,
+) (*logical.Response, error) {
+	gasPrice, err := wrapper.MustGetBigIntAny("gas_price")
+	if err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	al, err := ethutil.ParseAccessListJSON(wrapper.GetString("access_list", ""))
+	if err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	signedTx, err := ethutil.SignEIP2930(
+		chainID, nonce, gasLimit, value, txData, toPtr, gasPrice, al, signingKey,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("sign eip2930 tx: %w", err)
+	}
+	data, err := ethutil.SignedTxResponseData(
+		signedTx,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &logical.Response{Data: data}, nil
+}
+
+// signEIP1559Tx signs a type-2 tx with access list and builds the Vault response map.
+func signEIP1559Tx(
+	wrapper *model.FieldDataWrapper,
+	chainID *big.Int,
+	nonce, gasLimit uint64,
+	value *big.Int,
+	txData []byte,
+	toPtr *common.Address,
+	signingKey *ecdsa.PrivateKey,
+	account *model.Account,
+) (*logical.Response, error) {
+	tip, err := wrapper.MustGetBigIntAny(
+		"max_priority_fee_per_gas", "maxPriorityFeePerGas",
+	)
+	if err != nil {
+		return logical.ErrorResponse("eip1559 requires max_priority_fee_per_gas: %s", err.Error()), nil
 	}
-	indexStr, err := wrapper.MustGetString("index")
+	feeCap, err := wrapper.MustGetBigIntAny("max_fee_per_gas", "maxFeePerGas")
 	if err != nil {
-		return nil, err
+		return logical.ErrorResponse("eip1559 requires max_fee_per_gas: %s", err.Error()), nil
 	}
-	signingKey, acct, cleanup, err := loadSigningKeyForTx(ctx, req.Storage, walletID, indexStr)
-	if err != nil {
-		return RespondLoadWalletKeyError(err)
+	if feeCap.Cmp(tip) < 0 {
+		return logical.ErrorResponse("max_fee_per_gas must be >= max_priority_fee_per_gas"), nil
 	}
-	defer cleanup()
-
-	chainID, err := wrapper.MustGetBigIntAny("chain_id", "chainID")
+	al, err := ethutil.ParseAccessListJSON(wrapper.GetString("access_list", ""))
 	if err != nil {
 		return logical.ErrorResponse("%s", err.Error()), nil
 	}
-	gasLimit, err := wrapper.MustGetUint64("gas_limit")
+	signedTx, err := ethutil.SignEIP1559(
+		chainID, nonce, gasLimit, value, txData, toPtr, tip, feeCap, al, signingKey,
+	)
 	if err != nil {
-		return nil, err
-	}
-	nonce := wrapper.GetUint64("nonce", 0)
-	value := wrapper.BigIntWithAliases("value", "amount", big.NewInt(0))
-	inputStr := wrapper.GetString("data", "")
-	var txData []byte
-	if strings.TrimSpace(inputStr) != "" {
-		txData, err = hexutil.Decode(inputStr)
-		if err != nil {
-			return logical.ErrorResponse("invalid data hex: %s", err.Error()), nil
-		}
-	}
-	toStr := wrapper.GetStringFirstNonEmpty("to", "address_to")
-	var toPtr *common.Address
-	if toStr != "" {
-		addr := common.HexToAddress(toStr)
-		toPtr = &addr
+		return nil, fmt.Errorf("sign eip1559 tx: %w", err)
 	}
-	return signEIP1559Tx(wrapper, chainID, nonce, gasLimit, value, txData, toPtr, signingKey, acct)
-}
-
-// loadSigningKeyForTx loads the derived signing key and builds a model.Account for tx response helpers.
-func loadSigningKeyForTx(
-	ctx context.Context,
-	storage logical.Storage,
-	walletID, indexStr string,
-) (signingKey *ecdsa.PrivateKey, account *model.Account, cleanup func(), err error) {
-	pk, derived, err := LoadWalletDerivedPrivateKey(ctx, storage, walletID, indexStr)
+	data, err := ethutil.SignedTxResponseData(
+		signedTx,
+	)
 	if err != nil {
-		return nil, nil, nil, err
-	}
-	signingKey = pk
-	account = &model.Account{AddressStr: derived.Address}
-	cleanup = func() {
-		utils.ZeroKey(pk)
+		return nil, err
 	}
-	return signingKey, account, cleanup, nil
+	return &logical.Response{Data: data}, nil
 }
 
-// txTypeLabelEthereumType0 is the response `type` value for EIP-155 type-0 (Ethereum convention).
-const txTypeLabelEthereumType0 = "legacy"
-
-// signType0Tx signs a type-0 tx for a wallet-derived account and builds the Vault response map.
-func signType0Tx(
+// signBlobTx signs a type-3 blob tx and builds the Vault response map.
+// Raw blobs are turned into a sidecar (commitments and proofs) before signing.
+func signBlobTx(
 	wrapper *model.FieldDataWrapper,
 	chainID *big.Int,
 	nonce, gasLimit uint64,
@@ -454,15 +1446,57 @@ func signType0Tx(
 	signingKey *ecdsa.PrivateKey,
 	account *model.Account,
 ) (*logical.Response, error) {
-	gasPrice, err := wrapper.MustGetBigIntAny("gas_price")
+	if toPtr == nil {
+		return logical.ErrorResponse("blob transactions require to (contract creation is not allowed)"), nil
+	}
+	tip, err := wrapper.MustGetBigIntAny(
+		"max_priority_fee_per_gas", "maxPriorityFeePerGas",
+	)
+	if err != nil {
+		return logical.ErrorResponse("blob tx requires max_priority_fee_per_gas: %s", err.Error()), nil
+	}
+	feeCap, err := wrapper.MustGetBigIntAny("max_fee_per_gas", "maxFeePerGas")
+	if err != nil {
+		return logical.ErrorResponse("blob tx requires max_fee_per_gas: %s", err.Error()), nil
+	}
+	if feeCap.Cmp(tip) < 0 {
+		return logical.ErrorResponse("max_fee_per_gas must be >= max_priority_fee_per_gas"), nil
+	}
+	blobFeeCap, err := wrapper.MustGetBigIntAny("max_fee_per_blob_gas", "maxFeePerBlobGas")
+	if err != nil {
+		return logical.ErrorResponse("blob tx requires max_fee_per_blob_gas: %s", err.Error()), nil
+	}
+	al, err := ethutil.ParseAccessListJSON(wrapper.GetString("access_list", ""))
 	if err != nil {
 		return logical.ErrorResponse("%s", err.Error()), nil
 	}
-	signedTx, err := ethutil.SignType0EIP155(
-		chainID, nonce, gasLimit, value, txData, toPtr, gasPrice, signingKey,
+	blobHashes, err := ethutil.ParseBlobHashesJSON(wrapper.GetString("blob_versioned_hashes", ""))
+	if err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	blobs, err := ethutil.ParseBlobsJSON(wrapper.GetString("blobs", ""))
+	if err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	if len(blobHashes) == 0 && len(blobs) == 0 {
+		return logical.ErrorResponse("blob tx requires blob_versioned_hashes or blobs"), nil
+	}
+	var sidecar *ethtypes.BlobTxSidecar
+	if len(blobs) > 0 {
+		version, err := wrapper.MustGetUint64("blob_sidecar_version")
+		if err != nil || version > uint64(ethtypes.BlobSidecarVersion1) {
+			return logical.ErrorResponse("blob_sidecar_version must be 0 or 1"), nil
+		}
+		sidecar, err = ethutil.NewBlobSidecar(blobs, byte(version))
+		if err != nil {
+			return logical.ErrorResponse("%s", err.Error()), nil
+		}
+	}
+	signedTx, err := ethutil.SignEIP4844(
+		chainID, nonce, gasLimit, value, txData, *toPtr, tip, feeCap, blobFeeCap, blobHashes, sidecar, al, signingKey,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("sign type-0 tx: %w", err)
+		return logical.ErrorResponse("%s", err.Error()), nil
 	}
 	data, err := ethutil.SignedTxResponseData(
 		signedTx,
@@ -473,8 +1507,8 @@ func signType0Tx(
 	return &logical.Response{Data: data}, nil
 }
 
-// signEIP1559Tx signs a type-2 tx with access list and builds the Vault response map.
-func signEIP1559Tx(
+// signEIP7702Tx signs a type-4 set-code tx and builds the Vault response map.
+func signEIP7702Tx(
 	wrapper *model.FieldDataWrapper,
 	chainID *big.Int,
 	nonce, gasLimit uint64,
@@ -484,15 +1518,18 @@ func signEIP1559Tx(
 	signingKey *ecdsa.PrivateKey,
 	account *model.Account,
 ) (*logical.Response, error) {
+	if toPtr == nil {
+		return logical.ErrorResponse("set-code transactions require to (contract creation is not allowed)"), nil
+	}
 	tip, err := wrapper.MustGetBigIntAny(
 		"max_priority_fee_per_gas", "maxPriorityFeePerGas",
 	)
 	if err != nil {
-		return logical.ErrorResponse("eip1559 requires max_priority_fee_per_gas: %s", err.Error()), nil
+		return logical.ErrorResponse("eip7702 requires max_priority_fee_per_gas: %s", err.Error()), nil
 	}
 	feeCap, err := wrapper.MustGetBigIntAny("max_fee_per_gas", "maxFeePerGas")
 	if err != nil {
-		return logical.ErrorResponse("eip1559 requires max_fee_per_gas: %s", err.Error()), nil
+		return logical.ErrorResponse("eip7702 requires max_fee_per_gas: %s", err.Error()), nil
 	}
 	if feeCap.Cmp(tip) < 0 {
 		return logical.ErrorResponse("max_fee_per_gas must be >= max_priority_fee_per_gas"), nil
@@ -501,11 +1538,18 @@ func signEIP1559Tx(
 	if err != nil {
 		return logical.ErrorResponse("%s", err.Error()), nil
 	}
-	signedTx, err := ethutil.SignEIP1559(
-		chainID, nonce, gasLimit, value, txData, toPtr, tip, feeCap, al, signingKey,
+	authList, err := ethutil.ParseAuthorizationListJSON(wrapper.GetString("authorization_list", ""))
+	if err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	if len(authList) == 0 {
+		return logical.ErrorResponse("eip7702 requires a non-empty authorization_list"), nil
+	}
+	signedTx, err := ethutil.SignEIP7702(
+		chainID, nonce, gasLimit, value, txData, *toPtr, tip, feeCap, authList, al, signingKey,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("sign eip1559 tx: %w", err)
+		return nil, fmt.Errorf("sign eip7702 tx: %w", err)
 	}
 	data, err := ethutil.SignedTxResponseData(
 		signedTx,
@@ -517,14 +1561,15 @@ func signEIP1559Tx(
 }
 
 // pathWalletSign registers Keccak256-then-ECDSA sign on wallets/.../accounts/:index/sign.
-func pathWalletSign() *framework.Path {
+func pathWalletSign(walletMu *sync.Map) *framework.Path {
 	walletID := framework.GenericNameRegex("wallet_id")
 	return &framework.Path{
-		Pattern:      "wallets/" + walletID + "/accounts/(?P<index>\\d+)/sign",
+		Pattern:      "wallets/" + walletID + "/accounts/" + patternAccountIndex + "/sign",
 		HelpSynopsis: "Sign data (Keccak-256 hash then ECDSA) for a wallet-derived account",
 		Fields: map[string]*framework.FieldSchema{
 			"wallet_id": {Type: framework.TypeString},
 			"index":     {Type: framework.TypeString},
+			"account":   {Type: framework.TypeString, Description: "Optional BIP-44 account' segment (path :account/:index). Default 0."},
 			"data": {
 				Type:        framework.TypeString,
 				Description: "The data to hash (keccak) and sign.",
@@ -532,8 +1577,8 @@ func pathWalletSign() *framework.Path {
 		},
 		ExistenceCheck: ExistenceWalletDerivedAccount(),
 		Callbacks: map[logical.Operation]framework.OperationFunc{
-			logical.CreateOperation: handleWalletSign,
-			logical.UpdateOperation: handleWalletSign,
+			logical.CreateOperation: WithWalletLock(walletMu, handleWalletSign),
+			logical.UpdateOperation: WithWalletLock(walletMu, handleWalletSign),
 		},
 	}
 }
@@ -541,6 +1586,13 @@ func pathWalletSign() *framework.Path {
 // handleWalletSign signs hex-encoded payload data for a wallet-derived account.
 func handleWalletSign(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
 	dataWrapper := model.NewFieldDataWrapper(data)
+	cfg, err := config.Read(ctx, req.Storage)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.CheckSigning(model.SigningModeSign, nil); err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
 	walletID, err := dataWrapper.MustGetString("wallet_id")
 	if err != nil {
 		return nil, err
@@ -549,7 +1601,11 @@ func handleWalletSign(ctx context.Context, req *logical.Request, data *framework
 	if err != nil {
 		return nil, err
 	}
-	pk, derived, err := LoadWalletDerivedPrivateKey(ctx, req.Storage, walletID, indexStr)
+	_, accountStr, errResp := accountSegmentFromRequest(dataWrapper)
+	if errResp != nil {
+		return errResp, nil
+	}
+	pk, derived, err := LoadSegmentDerivedPrivateKey(ctx, req.Storage, walletID, accountStr, indexStr)
 	if err != nil {
 		return RespondLoadWalletKeyError(err)
 	}
@@ -567,23 +1623,139 @@ func handleWalletSign(ctx context.Context, req *logical.Request, data *framework
 	if err != nil {
 		return nil, fmt.Errorf("sign hash: %w", err)
 	}
-	return &logical.Response{
+	resp := &logical.Response{
 		Data: map[string]interface{}{
 			"signature": hexutil.Encode(signature),
 			"address":   derived.Address,
 		},
-	}, nil
+	}
+	prefix := storagekey.WalletJournalPrefix(walletID, accountStr, indexStr)
+	return journal.Record(ctx, req, prefix, model.JournalOpSign, derived.Address, dataBytes, resp, nil)
+}
+
+// pathWalletSignMessage registers EIP-191 message signing on wallets/.../accounts/:index/sign-message.
+func pathWalletSignMessage(walletMu *sync.Map) *framework.Path {
+	walletID := framework.GenericNameRegex("wallet_id")
+	return &framework.Path{
+		Pattern:      "wallets/" + walletID + "/accounts/" + patternAccountIndex + "/sign-message",
+		HelpSynopsis: "Sign a message under EIP-191 (personal_sign or intended validator) for a wallet-derived account",
+		Fields: map[string]*framework.FieldSchema{
+			"wallet_id": {Type: framework.TypeString},
+			"index":     {Type: framework.TypeString},
+			"account":   {Type: framework.TypeString, Description: "Optional BIP-44 account' segment (path :account/:index). Default 0."},
+			"message": {
+				Type:        framework.TypeString,
+				Description: "UTF-8 message to sign. Mutually exclusive with data.",
+			},
+			"data": {
+				Type:        framework.TypeString,
+				Description: "Hex-encoded message bytes to sign. Mutually exclusive with message.",
+			},
+			"version": {
+				Type:        framework.TypeString,
+				Description: "EIP-191 version: 0x45 (personal_sign) or 0x00 (intended validator).",
+				Default:     "0x45",
+			},
+			"validator": {
+				Type:        framework.TypeString,
+				Description: "Intended validator hex address. Required for version 0x00.",
+			},
+		},
+		ExistenceCheck: ExistenceWalletDerivedAccount(),
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.CreateOperation: WithWalletLock(walletMu, handleWalletSignMessage),
+			logical.UpdateOperation: WithWalletLock(walletMu, handleWalletSignMessage),
+		},
+	}
+}
+
+// handleWalletSignMessage signs an EIP-191 message with a derived key; v is returned as 27/28.
+func handleWalletSignMessage(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	wrapper := model.NewFieldDataWrapper(data)
+	cfg, err := config.Read(ctx, req.Storage)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.CheckSigning(model.SigningModeSignMessage, nil); err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	walletID, err := wrapper.MustGetString("wallet_id")
+	if err != nil {
+		return nil, err
+	}
+	indexStr, err := wrapper.MustGetString("index")
+	if err != nil {
+		return nil, err
+	}
+	_, accountStr, errResp := accountSegmentFromRequest(wrapper)
+	if errResp != nil {
+		return errResp, nil
+	}
+	version, validator, msg, err := eip191MessageFromRequestWallet(wrapper)
+	if err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	pk, derived, err := LoadSegmentDerivedPrivateKey(ctx, req.Storage, walletID, accountStr, indexStr)
+	if err != nil {
+		return RespondLoadWalletKeyError(err)
+	}
+	defer utils.ZeroKey(pk)
+	hash, sig, err := ethutil.SignEIP191(version, validator, msg, pk)
+	if err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	resp := &logical.Response{
+		Data: map[string]interface{}{
+			"signature":    hexutil.Encode(sig),
+			"address":      derived.Address,
+			"message_hash": hash.Hex(),
+			"version":      fmt.Sprintf("0x%02x", version),
+		},
+	}
+	prefix := storagekey.WalletJournalPrefix(walletID, accountStr, indexStr)
+	return journal.Record(ctx, req, prefix, model.JournalOpSignMessage, derived.Address, msg, resp, nil)
+}
+
+// eip191MessageFromRequestWallet resolves the EIP-191 version, validator and message bytes from request fields.
+func eip191MessageFromRequestWallet(wrapper *model.FieldDataWrapper) (byte, *common.Address, []byte, error) {
+	version, err := ethutil.ParseEIP191Version(wrapper.GetString("version", ""))
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	text := wrapper.GetString("message", "")
+	hexStr := strings.TrimSpace(wrapper.GetString("data", ""))
+	if text != "" && hexStr != "" {
+		return 0, nil, nil, fmt.Errorf("set only one of message or data")
+	}
+	msg := []byte(text)
+	if hexStr != "" {
+		msg, err = hexutil.Decode(hexStr)
+		if err != nil {
+			return 0, nil, nil, fmt.Errorf("invalid data hex: %w", err)
+		}
+	}
+	var validator *common.Address
+	if version == ethutil.EIP191VersionIntendedValidator {
+		validatorStr := strings.TrimSpace(wrapper.GetString("validator", ""))
+		if !common.IsHexAddress(validatorStr) {
+			return 0, nil, nil, fmt.Errorf("version 0x00 requires validator to be a hex address")
+		}
+		addr := common.HexToAddress(validatorStr)
+		validator = &addr
+	}
+	return version, validator, msg, nil
 }
 
 // pathWalletSignEIP712 registers EIP-712 typed-data signing on wallets/.../sign-eip712.
-func pathWalletSignEIP712() *framework.Path {
+func pathWalletSignEIP712(walletMu *sync.Map) *framework.Path {
 	walletID := framework.GenericNameRegex("wallet_id")
 	return &framework.Path{
-		Pattern:      "wallets/" + walletID + "/accounts/(?P<index>\\d+)/sign-eip712",
+		Pattern:      "wallets/" + walletID + "/accounts/" + patternAccountIndex + "/sign-eip712",
 		HelpSynopsis: "Sign EIP-712 typed data for a wallet-derived account",
 		Fields: map[string]*framework.FieldSchema{
 			"wallet_id": {Type: framework.TypeString},
 			"index":     {Type: framework.TypeString},
+			"account":   {Type: framework.TypeString, Description: "Optional BIP-44 account' segment (path :account/:index). Default 0."},
 			"payload": {
 				Type:        framework.TypeString,
 				Description: "The complete EIP-712 JSON payload (contains domain, types, primaryType, message).",
@@ -591,8 +1763,8 @@ func pathWalletSignEIP712() *framework.Path {
 		},
 		ExistenceCheck: ExistenceWalletDerivedAccount(),
 		Callbacks: map[logical.Operation]framework.OperationFunc{
-			logical.CreateOperation: handleWalletSignEIP712,
-			logical.UpdateOperation: handleWalletSignEIP712,
+			logical.CreateOperation: WithWalletLock(walletMu, handleWalletSignEIP712),
+			logical.UpdateOperation: WithWalletLock(walletMu, handleWalletSignEIP712),
 		},
 	}
 }
@@ -600,6 +1772,13 @@ func pathWalletSignEIP712() *framework.Path {
 // handleWalletSignEIP712 parses a TypedData payload and returns an EIP-712 signature for a derived address.
 func handleWalletSignEIP712(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
 	wrapper := model.NewFieldDataWrapper(data)
+	cfg, err := config.Read(ctx, req.Storage)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.CheckSigning(model.SigningModeSignEIP712, nil); err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
 	payload, err := wrapper.MustGetString("payload")
 	if err != nil {
 		return nil, err
@@ -616,7 +1795,11 @@ func handleWalletSignEIP712(ctx context.Context, req *logical.Request, data *fra
 	if err != nil {
 		return nil, err
 	}
-	pk, derived, err := LoadWalletDerivedPrivateKey(ctx, req.Storage, walletID, indexStr)
+	_, accountStr, errResp := accountSegmentFromRequest(wrapper)
+	if errResp != nil {
+		return errResp, nil
+	}
+	pk, derived, err := LoadSegmentDerivedPrivateKey(ctx, req.Storage, walletID, accountStr, indexStr)
 	if err != nil {
 		return RespondLoadWalletKeyError(err)
 	}
@@ -625,12 +1808,14 @@ func handleWalletSignEIP712(ctx context.Context, req *logical.Request, data *fra
 	if err != nil {
 		return logical.ErrorResponse("%s", err.Error()), nil
 	}
-	return &logical.Response{
+	resp := &logical.Response{
 		Data: map[string]interface{}{
 			"signature": hexutil.Encode(sig),
 			"address":   derived.Address,
 		},
-	}, nil
+	}
+	prefix := storagekey.WalletJournalPrefix(walletID, accountStr, indexStr)
+	return journal.Record(ctx, req, prefix, model.JournalOpSignEIP712, derived.Address, []byte(payload), resp, nil)
 }
 
 // typedDataFromPayloadWallet parses a single JSON payload into go-ethereum TypedData.
@@ -666,15 +1851,116 @@ func typedDataFromPayloadWallet(payload string) (*apitypes.TypedData, error) {
 	return &td, nil
 }
 
+// pathWalletSignAuthorization registers EIP-7702 authorization signing on wallets/.../accounts/:index/sign-authorization.
+func pathWalletSignAuthorization(walletMu *sync.Map) *framework.Path {
+	walletID := framework.GenericNameRegex("wallet_id")
+	return &framework.Path{
+		Pattern:      "wallets/" + walletID + "/accounts/" + patternAccountIndex + "/sign-authorization",
+		HelpSynopsis: "Sign an EIP-7702 authorization tuple (chain_id, address, nonce) for a wallet-derived account",
+		Fields: map[string]*framework.FieldSchema{
+			"wallet_id": {Type: framework.TypeString},
+			"index":     {Type: framework.TypeString},
+			"account":   {Type: framework.TypeString, Description: "Optional BIP-44 account' segment (path :account/:index). Default 0."},
+			"chain_id": {
+				Type:        framework.TypeString,
+				Description: "Chain ID (decimal); 0 authorizes on every chain. Alias: chainID.",
+			},
+			"chainID": {
+				Type:        framework.TypeString,
+				Description: "Alias for chain_id.",
+			},
+			"address": {
+				Type:        framework.TypeString,
+				Description: "Hex address of the contract whose code is delegated to.",
+			},
+			"nonce": {
+				Type:        framework.TypeString,
+				Description: "Authority account nonce (decimal) at the time the authorization is applied.",
+			},
+		},
+		ExistenceCheck: ExistenceWalletDerivedAccount(),
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.CreateOperation: WithWalletLock(walletMu, handleWalletSignAuthorization),
+			logical.UpdateOperation: WithWalletLock(walletMu, handleWalletSignAuthorization),
+		},
+	}
+}
+
+// handleWalletSignAuthorization signs an EIP-7702 authorization tuple with a derived key.
+func handleWalletSignAuthorization(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	wrapper := model.NewFieldDataWrapper(data)
+	walletID, err := wrapper.MustGetString("wallet_id")
+	if err != nil {
+		return nil, err
+	}
+	indexStr, err := wrapper.MustGetString("index")
+	if err != nil {
+		return nil, err
+	}
+	_, accountStr, errResp := accountSegmentFromRequest(wrapper)
+	if errResp != nil {
+		return errResp, nil
+	}
+	chainID, err := wrapper.MustGetBigIntAny("chain_id", "chainID")
+	if err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	cfg, err := config.Read(ctx, req.Storage)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.CheckSigning(model.SigningModeSignAuthorization, chainID); err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	addrStr := strings.TrimSpace(wrapper.GetString("address", ""))
+	if !common.IsHexAddress(addrStr) {
+		return logical.ErrorResponse("address must be a hex address"), nil
+	}
+	if err := chain.CheckAuthorization(ctx, req.Storage, chainID, common.HexToAddress(addrStr)); err != nil {
+		return RespondLoadWalletKeyError(err)
+	}
+	dest, err := ReadWalletDestinationPolicy(ctx, req.Storage, walletID)
+	if err != nil {
+		return nil, err
+	}
+	if dest != nil {
+		if err := dest.CheckDelegate("wallet "+walletID, common.HexToAddress(addrStr)); err != nil {
+			return RespondLoadWalletKeyError(err)
+		}
+	}
+	nonce, err := wrapper.MustGetUint64("nonce")
+	if err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	pk, derived, err := LoadSegmentDerivedPrivateKey(ctx, req.Storage, walletID, accountStr, indexStr)
+	if err != nil {
+		return RespondLoadWalletKeyError(err)
+	}
+	defer utils.ZeroKey(pk)
+	auth, err := ethutil.SignSetCodeAuthorization(chainID, common.HexToAddress(addrStr), nonce, pk)
+	if err != nil {
+		return logical.ErrorResponse("%s", err.Error()), nil
+	}
+	respData, err := ethutil.SetCodeAuthorizationResponseData(auth)
+	if err != nil {
+		return nil, err
+	}
+	encoded, _ := respData["authorization"].(string)
+	prefix := storagekey.WalletJournalPrefix(walletID, accountStr, indexStr)
+	return journal.Record(ctx, req, prefix, model.JournalOpSignAuthorization, derived.Address, []byte(encoded),
+		&logical.Response{Data: respData}, nil)
+}
+
 // pathWalletEncrypt registers ECIES encrypt on wallets/.../accounts/:index/encrypt.
 func pathWalletEncrypt() *framework.Path {
 	walletID := framework.GenericNameRegex("wallet_id")
 	return &framework.Path{
-		Pattern:      "wallets/" + walletID + "/accounts/(?P<index>\\d+)/encrypt",
+		Pattern:      "wallets/" + walletID + "/accounts/" + patternAccountIndex + "/encrypt",
 		HelpSynopsis: "Encrypt data with the derived account public key (ECIES)",
 		Fields: map[string]*framework.FieldSchema{
 			"wallet_id": {Type: framework.TypeString},
 			"index":     {Type: framework.TypeString},
+			"account":   {Type: framework.TypeString, Description: "Optional BIP-44 account' segment (path :account/:index). Default 0."},
 			"data": {
 				Type:        framework.TypeString,
 				Description: "The data to encrypt.",
@@ -699,7 +1985,11 @@ func handleWalletEncrypt(ctx context.Context, req *logical.Request, data *framew
 	if err != nil {
 		return nil, err
 	}
-	pk, _, err := LoadWalletDerivedPrivateKey(ctx, req.Storage, walletID, indexStr)
+	_, accountStr, errResp := accountSegmentFromRequest(dataWrapper)
+	if errResp != nil {
+		return errResp, nil
+	}
+	pk, _, err := LoadSegmentDerivedPrivateKey(ctx, req.Storage, walletID, accountStr, indexStr)
 	if err != nil {
 		return RespondLoadWalletKeyError(err)
 	}
@@ -726,14 +2016,15 @@ func handleWalletEncrypt(ctx context.Context, req *logical.Request, data *framew
 }
 
 // pathWalletDecrypt registers ECIES decrypt on wallets/.../accounts/:index/decrypt.
-func pathWalletDecrypt() *framework.Path {
+func pathWalletDecrypt(walletMu *sync.Map) *framework.Path {
 	walletID := framework.GenericNameRegex("wallet_id")
 	return &framework.Path{
-		Pattern:      "wallets/" + walletID + "/accounts/(?P<index>\\d+)/decrypt",
+		Pattern:      "wallets/" + walletID + "/accounts/" + patternAccountIndex + "/decrypt",
 		HelpSynopsis: "Decrypt data with the derived account private key (ECIES)",
 		Fields: map[string]*framework.FieldSchema{
 			"wallet_id": {Type: framework.TypeString},
 			"index":     {Type: framework.TypeString},
+			"account":   {Type: framework.TypeString, Description: "Optional BIP-44 account' segment (path :account/:index). Default 0."},
 			"data": {
 				Type:        framework.TypeString,
 				Description: "The data to decrypt.",
@@ -741,8 +2032,8 @@ func pathWalletDecrypt() *framework.Path {
 		},
 		ExistenceCheck: ExistenceWalletDerivedAccount(),
 		Callbacks: map[logical.Operation]framework.OperationFunc{
-			logical.CreateOperation: handleWalletDecrypt,
-			logical.UpdateOperation: handleWalletDecrypt,
+			logical.CreateOperation: WithWalletLock(walletMu, handleWalletDecrypt),
+			logical.UpdateOperation: WithWalletLock(walletMu, handleWalletDecrypt),
 		},
 	}
 }
@@ -758,7 +2049,11 @@ func handleWalletDecrypt(ctx context.Context, req *logical.Request, data *framew
 	if err != nil {
 		return nil, err
 	}
-	pk, _, err := LoadWalletDerivedPrivateKey(ctx, req.Storage, walletID, indexStr)
+	_, accountStr, errResp := accountSegmentFromRequest(dataWrapper)
+	if errResp != nil {
+		return errResp, nil
+	}
+	pk, derived, err := LoadSegmentDerivedPrivateKey(ctx, req.Storage, walletID, accountStr, indexStr)
 	if err != nil {
 		return RespondLoadWalletKeyError(err)
 	}
@@ -777,9 +2072,11 @@ func handleWalletDecrypt(ctx context.Context, req *logical.Request, data *framew
 	if err != nil {
 		return nil, fmt.Errorf("ecies decrypt: %w", err)
 	}
-	return &logical.Response{
+	resp := &logical.Response{
 		Data: map[string]interface{}{
 			"plaintext": hexutil.Encode(plainText),
 		},
-	}, nil
+	}
+	prefix := storagekey.WalletJournalPrefix(walletID, accountStr, indexStr)
+	return journal.Record(ctx, req, prefix, model.JournalOpDecrypt, derived.Address, dataBytes, resp, nil)
 }
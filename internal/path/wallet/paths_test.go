@@ -18,6 +18,7 @@
 package wallet
 
 import (
+	"regexp"
 	"strings"
 	"sync"
 	"testing"
@@ -63,12 +64,19 @@ func TestPaths_registerUpdateOnWriteEndpoints(t *testing.T) {
 	// These endpoints use ExistenceCheck; Vault often routes HTTP writes to UpdateOperation.
 	// Ensure both create and update are wired to avoid 405s during `vault write`.
 	wantSuffixes := []string{
-		"/accounts/(?P<index>\\d+)/sign",
-		"/accounts/(?P<index>\\d+)/sign-eip712",
-		"/accounts/(?P<index>\\d+)/encrypt",
-		"/accounts/(?P<index>\\d+)/decrypt",
-		"/accounts/(?P<index>\\d+)/sign-tx/legacy",
-		"/accounts/(?P<index>\\d+)/sign-tx/eip1559",
+		"/accounts/(?:(?P<account>\\d+)/)?(?P<index>\\d+)/sign",
+		"/accounts/(?:(?P<account>\\d+)/)?(?P<index>\\d+)/sign-eip712",
+		"/accounts/(?:(?P<account>\\d+)/)?(?P<index>\\d+)/sign-message",
+		"/accounts/(?:(?P<account>\\d+)/)?(?P<index>\\d+)/encrypt",
+		"/accounts/(?:(?P<account>\\d+)/)?(?P<index>\\d+)/decrypt",
+		"/accounts/(?:(?P<account>\\d+)/)?(?P<index>\\d+)/sign-tx/legacy",
+		"/accounts/(?:(?P<account>\\d+)/)?(?P<index>\\d+)/sign-tx/eip2930",
+		"/accounts/(?:(?P<account>\\d+)/)?(?P<index>\\d+)/sign-tx/eip1559",
+		"/accounts/(?:(?P<account>\\d+)/)?(?P<index>\\d+)/sign-tx/blob",
+		"/accounts/(?:(?P<account>\\d+)/)?(?P<index>\\d+)/sign-tx/eip7702",
+		"/accounts/(?:(?P<account>\\d+)/)?(?P<index>\\d+)/sign-authorization",
+		"/shamir",
+		"/shamir/import",
 	}
 
 	for _, suffix := range wantSuffixes {
@@ -144,3 +152,34 @@ func TestPaths_listDerivedAccounts_registersRead(t *testing.T) {
 	}
 	t.Fatal("missing wallets/*/accounts/? path.")
 }
+
+// TestPaths_accountIndexPattern verifies the derived account path accepts :index and :account/:index,
+// extracts both groups, and leaves the batch and sign paths to their own patterns.
+func TestPaths_accountIndexPattern(t *testing.T) {
+	t.Parallel()
+
+	re := regexp.MustCompile("^" + pathDerivedAccount().Pattern + "$")
+	cases := []struct {
+		path, account, index string
+	}{
+		{"wallets/w1/accounts/3", "", "3"},
+		{"wallets/w1/accounts/2/3", "2", "3"},
+	}
+	for _, tc := range cases {
+		m := re.FindStringSubmatch(tc.path)
+		if m == nil {
+			t.Fatalf("%s: no match.", tc.path)
+		}
+		if got := m[re.SubexpIndex("account")]; got != tc.account {
+			t.Fatalf("%s: account=%q want %q.", tc.path, got, tc.account)
+		}
+		if got := m[re.SubexpIndex("index")]; got != tc.index {
+			t.Fatalf("%s: index=%q want %q.", tc.path, got, tc.index)
+		}
+	}
+	for _, other := range []string{"wallets/w1/accounts/batch", "wallets/w1/accounts/2/3/sign", "wallets/w1/accounts/1/2/3"} {
+		if re.MatchString(other) {
+			t.Fatalf("%s: unexpected match.", other)
+		}
+	}
+}
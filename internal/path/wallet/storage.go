@@ -23,6 +23,7 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -30,6 +31,7 @@ import (
 	"github.com/hashicorp/vault/sdk/logical"
 
 	"github.com/bsostech/vault-blockchain/internal/model"
+	"github.com/bsostech/vault-blockchain/internal/path/addressindex"
 	"github.com/bsostech/vault-blockchain/internal/path/storagekey"
 	"github.com/bsostech/vault-blockchain/pkg/utils"
 )
@@ -39,8 +41,12 @@ var (
 	ErrInvalidPathIndexFormat = errors.New("index must be a non-negative decimal integer")
 	// ErrInvalidPathIndexRange means the index exceeds the BIP-44 address_index bound.
 	ErrInvalidPathIndexRange = errors.New("index must be 0..2147483647")
+	// ErrInvalidPathAccount means the account segment is not a decimal integer within the BIP-44 account' bound.
+	ErrInvalidPathAccount = errors.New("account must be a decimal integer 0..2147483647")
 	// ErrDerivedAccountMissing is returned when the wallet or derived account entry is absent.
 	ErrDerivedAccountMissing = errors.New("derived account not found")
+	// ErrWalletDeleted is returned when the wallet is soft-deleted and must be restored before use.
+	ErrWalletDeleted = errors.New("wallet is deleted")
 )
 
 // ExistenceWalletSeed reports whether a seed entry exists for wallet_id in the path data.
@@ -79,7 +85,7 @@ func ExistenceWalletDerivedAccountsRoot() framework.ExistenceFunc {
 	}
 }
 
-// ExistenceWalletDerivedAccount returns true when storage has a derived account for wallet_id and index.
+// ExistenceWalletDerivedAccount returns true when storage has a derived account for wallet_id, account and index.
 func ExistenceWalletDerivedAccount() framework.ExistenceFunc {
 	return func(ctx context.Context, req *logical.Request, data *framework.FieldData) (bool, error) {
 		walletID := model.NewFieldDataWrapper(data).GetString("wallet_id", "")
@@ -87,7 +93,11 @@ func ExistenceWalletDerivedAccount() framework.ExistenceFunc {
 		if walletID == "" || indexStr == "" {
 			return false, nil
 		}
-		entry, err := req.Storage.Get(ctx, storagekey.AccountKey(walletID, indexStr))
+		_, accountStr, err := ParseAccountSegment(model.NewFieldDataWrapper(data).GetString("account", ""))
+		if err != nil {
+			return false, nil
+		}
+		entry, err := req.Storage.Get(ctx, storagekey.SegmentAccountKey(walletID, accountStr, indexStr))
 		if err != nil {
 			return false, fmt.Errorf("existence check derived account %s/%s: %w", walletID, indexStr, err)
 		}
@@ -111,34 +121,305 @@ func ReadWalletSeed(ctx context.Context, s logical.Storage, walletID string) (*m
 	return &seed, nil
 }
 
+// ReadSegmentDerivedAccount loads the derived account record under account segment accountStr, or returns nil if
+// it is absent.
+func ReadSegmentDerivedAccount(ctx context.Context, s logical.Storage, walletID, accountStr, indexStr string) (*model.DerivedAccount, error) {
+	entry, err := s.Get(ctx, storagekey.SegmentAccountKey(walletID, accountStr, indexStr))
+	if err != nil {
+		return nil, fmt.Errorf("get derived account %s/%s/%s: %w", walletID, accountStr, indexStr, err)
+	}
+	if entry == nil {
+		return nil, nil
+	}
+	var derived model.DerivedAccount
+	if err := entry.DecodeJSON(&derived); err != nil {
+		return nil, fmt.Errorf("decode derived account %s/%s/%s: %w", walletID, accountStr, indexStr, err)
+	}
+	return &derived, nil
+}
+
+// ReadWalletTombstone loads the wallet's tombstone, or returns nil when the wallet is not soft-deleted.
+func ReadWalletTombstone(ctx context.Context, s logical.Storage, walletID string) (*model.Tombstone, error) {
+	entry, err := s.Get(ctx, storagekey.WalletTombstoneKey(walletID))
+	if err != nil {
+		return nil, fmt.Errorf("get wallet tombstone %s: %w", walletID, err)
+	}
+	if entry == nil {
+		return nil, nil
+	}
+	var t model.Tombstone
+	if err := entry.DecodeJSON(&t); err != nil {
+		return nil, fmt.Errorf("decode wallet tombstone %s: %w", walletID, err)
+	}
+	return &t, nil
+}
+
+// WriteWalletTombstone persists the wallet's tombstone.
+func WriteWalletTombstone(ctx context.Context, s logical.Storage, walletID string, t *model.Tombstone) error {
+	entry, err := logical.StorageEntryJSON(storagekey.WalletTombstoneKey(walletID), t)
+	if err != nil {
+		return fmt.Errorf("encode wallet tombstone %s: %w", walletID, err)
+	}
+	if err := s.Put(ctx, entry); err != nil {
+		return fmt.Errorf("put wallet tombstone %s: %w", walletID, err)
+	}
+	return nil
+}
+
+// ReadWalletDestinationPolicy loads the wallet's destination policy, or returns nil when none is set.
+func ReadWalletDestinationPolicy(ctx context.Context, s logical.Storage, walletID string) (*model.DestinationPolicy, error) {
+	entry, err := s.Get(ctx, storagekey.WalletPolicyKey(walletID))
+	if err != nil {
+		return nil, fmt.Errorf("get wallet policy %s: %w", walletID, err)
+	}
+	if entry == nil {
+		return nil, nil
+	}
+	var p model.DestinationPolicy
+	if err := entry.DecodeJSON(&p); err != nil {
+		return nil, fmt.Errorf("decode wallet policy %s: %w", walletID, err)
+	}
+	return &p, nil
+}
+
+// WriteWalletDestinationPolicy persists the wallet's destination policy. Callers validate it first.
+func WriteWalletDestinationPolicy(ctx context.Context, s logical.Storage, walletID string, p *model.DestinationPolicy) error {
+	entry, err := logical.StorageEntryJSON(storagekey.WalletPolicyKey(walletID), p)
+	if err != nil {
+		return fmt.Errorf("encode wallet policy %s: %w", walletID, err)
+	}
+	if err := s.Put(ctx, entry); err != nil {
+		return fmt.Errorf("put wallet policy %s: %w", walletID, err)
+	}
+	return nil
+}
+
+// ReadWalletVelocityLimits loads the wallet's velocity limits, or returns nil when none are set.
+func ReadWalletVelocityLimits(ctx context.Context, s logical.Storage, walletID string) (*model.VelocityLimits, error) {
+	entry, err := s.Get(ctx, storagekey.WalletLimitsKey(walletID))
+	if err != nil {
+		return nil, fmt.Errorf("get wallet limits %s: %w", walletID, err)
+	}
+	if entry == nil {
+		return nil, nil
+	}
+	var l model.VelocityLimits
+	if err := entry.DecodeJSON(&l); err != nil {
+		return nil, fmt.Errorf("decode wallet limits %s: %w", walletID, err)
+	}
+	return &l, nil
+}
+
+// WriteWalletVelocityLimits persists the wallet's velocity limits. Callers validate them first.
+func WriteWalletVelocityLimits(ctx context.Context, s logical.Storage, walletID string, l *model.VelocityLimits) error {
+	entry, err := logical.StorageEntryJSON(storagekey.WalletLimitsKey(walletID), l)
+	if err != nil {
+		return fmt.Errorf("encode wallet limits %s: %w", walletID, err)
+	}
+	if err := s.Put(ctx, entry); err != nil {
+		return fmt.Errorf("put wallet limits %s: %w", walletID, err)
+	}
+	return nil
+}
+
+// ReadWalletSpendLedger loads the wallet's spend ledger, returning an empty ledger if none is stored.
+func ReadWalletSpendLedger(ctx context.Context, s logical.Storage, walletID string) (*model.SpendLedger, error) {
+	entry, err := s.Get(ctx, storagekey.WalletSpendKey(walletID))
+	if err != nil {
+		return nil, fmt.Errorf("get wallet spend %s: %w", walletID, err)
+	}
+	var ledger model.SpendLedger
+	if entry == nil {
+		return &ledger, nil
+	}
+	if err := entry.DecodeJSON(&ledger); err != nil {
+		return nil, fmt.Errorf("decode wallet spend %s: %w", walletID, err)
+	}
+	return &ledger, nil
+}
+
+// WriteWalletSpendLedger persists the wallet's spend ledger.
+func WriteWalletSpendLedger(ctx context.Context, s logical.Storage, walletID string, ledger *model.SpendLedger) error {
+	entry, err := logical.StorageEntryJSON(storagekey.WalletSpendKey(walletID), ledger)
+	if err != nil {
+		return fmt.Errorf("encode wallet spend %s: %w", walletID, err)
+	}
+	if err := s.Put(ctx, entry); err != nil {
+		return fmt.Errorf("put wallet spend %s: %w", walletID, err)
+	}
+	return nil
+}
+
+// ReadWalletXpub loads the cached extended public key of an account segment, or returns nil if none is stored.
+func ReadWalletXpub(ctx context.Context, s logical.Storage, walletID, accountStr string) (*model.WalletXpub, error) {
+	entry, err := s.Get(ctx, storagekey.WalletXpubKey(walletID, accountStr))
+	if err != nil {
+		return nil, fmt.Errorf("get wallet xpub %s/%s: %w", walletID, accountStr, err)
+	}
+	if entry == nil {
+		return nil, nil
+	}
+	var x model.WalletXpub
+	if err := entry.DecodeJSON(&x); err != nil {
+		return nil, fmt.Errorf("decode wallet xpub %s/%s: %w", walletID, accountStr, err)
+	}
+	return &x, nil
+}
+
+// WriteWalletXpub persists the extended public key of an account segment.
+func WriteWalletXpub(ctx context.Context, s logical.Storage, walletID, accountStr string, x *model.WalletXpub) error {
+	entry, err := logical.StorageEntryJSON(storagekey.WalletXpubKey(walletID, accountStr), x)
+	if err != nil {
+		return fmt.Errorf("encode wallet xpub %s/%s: %w", walletID, accountStr, err)
+	}
+	if err := s.Put(ctx, entry); err != nil {
+		return fmt.Errorf("put wallet xpub %s/%s: %w", walletID, accountStr, err)
+	}
+	return nil
+}
+
+// ReadWalletBackupRecords loads the audit records of the wallet's backups, oldest first.
+func ReadWalletBackupRecords(ctx context.Context, s logical.Storage, walletID string) ([]model.WalletBackupRecord, error) {
+	entry, err := s.Get(ctx, storagekey.WalletBackupsKey(walletID))
+	if err != nil {
+		return nil, fmt.Errorf("get wallet backups %s: %w", walletID, err)
+	}
+	if entry == nil {
+		return nil, nil
+	}
+	var records []model.WalletBackupRecord
+	if err := entry.DecodeJSON(&records); err != nil {
+		return nil, fmt.Errorf("decode wallet backups %s: %w", walletID, err)
+	}
+	return records, nil
+}
+
+// WriteWalletBackupRecords persists the audit records of the wallet's backups.
+func WriteWalletBackupRecords(ctx context.Context, s logical.Storage, walletID string, records []model.WalletBackupRecord) error {
+	entry, err := logical.StorageEntryJSON(storagekey.WalletBackupsKey(walletID), records)
+	if err != nil {
+		return fmt.Errorf("encode wallet backups %s: %w", walletID, err)
+	}
+	if err := s.Put(ctx, entry); err != nil {
+		return fmt.Errorf("put wallet backups %s: %w", walletID, err)
+	}
+	return nil
+}
+
+// ReadShamirImport loads the SLIP-39 shares submitted so far toward reconstructing wallet_id, or nil if none.
+func ReadShamirImport(ctx context.Context, s logical.Storage, walletID string) (*model.ShamirImport, error) {
+	entry, err := s.Get(ctx, storagekey.ShamirImportKey(walletID))
+	if err != nil {
+		return nil, fmt.Errorf("get shamir import %s: %w", walletID, err)
+	}
+	if entry == nil {
+		return nil, nil
+	}
+	var pending model.ShamirImport
+	if err := entry.DecodeJSON(&pending); err != nil {
+		return nil, fmt.Errorf("decode shamir import %s: %w", walletID, err)
+	}
+	return &pending, nil
+}
+
+// WriteShamirImport persists the SLIP-39 shares submitted toward reconstructing wallet_id.
+func WriteShamirImport(ctx context.Context, s logical.Storage, walletID string, pending *model.ShamirImport) error {
+	entry, err := logical.StorageEntryJSON(storagekey.ShamirImportKey(walletID), pending)
+	if err != nil {
+		return fmt.Errorf("encode shamir import %s: %w", walletID, err)
+	}
+	if err := s.Put(ctx, entry); err != nil {
+		return fmt.Errorf("put shamir import %s: %w", walletID, err)
+	}
+	return nil
+}
+
+// ReadWalletSegmentCounters returns the next auto-increment index of every account segment that has allocated
+// an account, keyed by canonical segment string.
+func ReadWalletSegmentCounters(ctx context.Context, s logical.Storage, walletID string) (map[string]uint32, error) {
+	segments, err := s.List(ctx, storagekey.SegmentsPrefix(walletID))
+	if err != nil {
+		return nil, fmt.Errorf("list wallet segments %s: %w", walletID, err)
+	}
+	accounts := []string{"0"}
+	for _, segment := range segments {
+		if accountStr := strings.TrimSuffix(segment, "/"); accountStr != "" && accountStr != "0" {
+			accounts = append(accounts, accountStr)
+		}
+	}
+	counters := make(map[string]uint32, len(accounts))
+	for _, accountStr := range accounts {
+		next, err := ReadSegmentCounter(ctx, s, walletID, accountStr)
+		if err != nil {
+			return nil, err
+		}
+		if next > 0 {
+			counters[accountStr] = next
+		}
+	}
+	return counters, nil
+}
+
 // ReadWalletCounter loads the auto-increment counter for walletID, returning 0 if not yet set.
 func ReadWalletCounter(ctx context.Context, s logical.Storage, walletID string) (uint32, error) {
-	entry, err := s.Get(ctx, storagekey.CounterKey(walletID))
+	return ReadSegmentCounter(ctx, s, walletID, "0")
+}
+
+// WriteWalletCounter persists the next auto-increment index for walletID.
+func WriteWalletCounter(ctx context.Context, s logical.Storage, walletID string, next uint32) error {
+	return WriteSegmentCounter(ctx, s, walletID, "0", next)
+}
+
+// ReadSegmentCounter loads the auto-increment counter for an account segment of walletID, returning 0 if not yet set.
+func ReadSegmentCounter(ctx context.Context, s logical.Storage, walletID, accountStr string) (uint32, error) {
+	entry, err := s.Get(ctx, storagekey.SegmentCounterKey(walletID, accountStr))
 	if err != nil {
-		return 0, fmt.Errorf("get wallet counter %s: %w", walletID, err)
+		return 0, fmt.Errorf("get wallet counter %s/%s: %w", walletID, accountStr, err)
 	}
 	if entry == nil {
 		return 0, nil
 	}
 	var counter model.WalletCounter
 	if err := entry.DecodeJSON(&counter); err != nil {
-		return 0, fmt.Errorf("decode wallet counter %s: %w", walletID, err)
+		return 0, fmt.Errorf("decode wallet counter %s/%s: %w", walletID, accountStr, err)
 	}
 	return counter.NextIndex, nil
 }
 
-// WriteWalletCounter persists the next auto-increment index for walletID.
-func WriteWalletCounter(ctx context.Context, s logical.Storage, walletID string, next uint32) error {
-	entry, err := logical.StorageEntryJSON(storagekey.CounterKey(walletID), &model.WalletCounter{NextIndex: next})
+// WriteSegmentCounter persists the next auto-increment index for an account segment of walletID.
+func WriteSegmentCounter(ctx context.Context, s logical.Storage, walletID, accountStr string, next uint32) error {
+	entry, err := logical.StorageEntryJSON(storagekey.SegmentCounterKey(walletID, accountStr), &model.WalletCounter{NextIndex: next})
 	if err != nil {
-		return fmt.Errorf("encode wallet counter %s: %w", walletID, err)
+		return fmt.Errorf("encode wallet counter %s/%s: %w", walletID, accountStr, err)
 	}
 	if err := s.Put(ctx, entry); err != nil {
-		return fmt.Errorf("put wallet counter %s: %w", walletID, err)
+		return fmt.Errorf("put wallet counter %s/%s: %w", walletID, accountStr, err)
 	}
 	return nil
 }
 
+// ParseAccountSegment parses the optional BIP-44 account segment from a path; empty means 0.
+// It returns the value and its canonical decimal form used in storage keys.
+func ParseAccountSegment(accountStr string) (uint32, string, error) {
+	if accountStr == "" {
+		return 0, "0", nil
+	}
+	v, err := strconv.ParseUint(accountStr, 10, 32)
+	if err != nil || model.ValidateAddressIndex(v) != nil {
+		return 0, "", ErrInvalidPathAccount
+	}
+	return uint32(v), strconv.FormatUint(v, 10), nil
+}
+
+// spendLedgerIndex is the ledger key for a derived account: the bare index under account segment 0, so
+// ledgers written before account segments existed keep matching, and <account>/<index> otherwise.
+func spendLedgerIndex(accountStr, indexStr string) string {
+	if accountStr == "0" {
+		return indexStr
+	}
+	return accountStr + "/" + indexStr
+}
+
 // ParseAddressIndex parses a non-negative decimal index string within BIP-44 address_index bounds.
 func ParseAddressIndex(indexStr string) (uint32, error) {
 	v, err := strconv.ParseUint(indexStr, 10, 32)
@@ -158,11 +439,25 @@ func LoadWalletDerivedPrivateKey(
 	s logical.Storage,
 	walletID, indexStr string,
 ) (*ecdsa.PrivateKey, *model.DerivedAccount, error) {
+	return LoadSegmentDerivedPrivateKey(ctx, s, walletID, "", indexStr)
+}
+
+// LoadSegmentDerivedPrivateKey is LoadWalletDerivedPrivateKey for a derived account under BIP-44 account
+// segment accountStr (empty means 0). The caller must invoke utils.ZeroKey on the key after use.
+func LoadSegmentDerivedPrivateKey(
+	ctx context.Context,
+	s logical.Storage,
+	walletID, accountStr, indexStr string,
+) (*ecdsa.PrivateKey, *model.DerivedAccount, error) {
+	account, accountStr, err := ParseAccountSegment(accountStr)
+	if err != nil {
+		return nil, nil, err
+	}
 	indexU32, err := ParseAddressIndex(indexStr)
 	if err != nil {
 		return nil, nil, err
 	}
-	acctEntry, err := s.Get(ctx, storagekey.AccountKey(walletID, indexStr))
+	acctEntry, err := s.Get(ctx, storagekey.SegmentAccountKey(walletID, accountStr, indexStr))
 	if err != nil {
 		return nil, nil, fmt.Errorf("get derived account %s/%s: %w", walletID, indexStr, err)
 	}
@@ -180,15 +475,23 @@ func LoadWalletDerivedPrivateKey(
 	if seed == nil || seed.Mnemonic == "" {
 		return nil, nil, ErrDerivedAccountMissing
 	}
-	pk, err := model.PrivateKeyECDSA(seed.Mnemonic, indexU32)
+	tomb, err := ReadWalletTombstone(ctx, s, walletID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if tomb != nil {
+		return nil, nil, ErrWalletDeleted
+	}
+	pk, err := seed.PrivateKeyECDSAAt(account, indexU32)
 	if err != nil {
 		return nil, nil, fmt.Errorf("derive private key: %w", err)
 	}
 	if common.HexToAddress(derived.Address) != crypto.PubkeyToAddress(pk.PublicKey) {
 		utils.ZeroKey(pk)
 		return nil, nil, fmt.Errorf(
-			"derived key does not match stored address for wallet %s index %s",
+			"derived key does not match stored address for wallet %s account %s index %s",
 			walletID,
+			accountStr,
 			indexStr,
 		)
 	}
@@ -200,10 +503,70 @@ func RespondLoadWalletKeyError(err error) (*logical.Response, error) {
 	switch {
 	case errors.Is(err, ErrDerivedAccountMissing):
 		return logical.ErrorResponse("derived account not found"), nil
-	case errors.Is(err, ErrInvalidPathIndexFormat), errors.Is(err, ErrInvalidPathIndexRange):
+	case errors.Is(err, ErrInvalidPathIndexFormat), errors.Is(err, ErrInvalidPathIndexRange),
+		errors.Is(err, ErrInvalidPathAccount), errors.Is(err, ErrWalletDeleted),
+		errors.Is(err, model.ErrChainPolicyViolation), errors.Is(err, model.ErrDestinationPolicyViolation),
+		errors.Is(err, model.ErrVelocityLimitExceeded):
 		return logical.ErrorResponse("%s", err.Error()), nil
 	default:
 		return nil, err
 	}
 }
 
+// IndexDerivedAccountAddresses writes address index entries for every stored derived account, including those
+// of soft-deleted wallets, and returns how many entries were written. It backfills accounts created before the
+// index existed; addresses already owned by another wallet or account are left unchanged.
+func IndexDerivedAccountAddresses(ctx context.Context, s logical.Storage) (int, error) {
+	walletIDs, err := s.List(ctx, "wallets/")
+	if err != nil {
+		return 0, fmt.Errorf("list wallets: %w", err)
+	}
+	indexed := 0
+	for _, child := range walletIDs {
+		walletID := strings.TrimSuffix(child, "/")
+		if walletID == "" {
+			continue
+		}
+		segments, err := s.List(ctx, storagekey.SegmentsPrefix(walletID))
+		if err != nil {
+			return indexed, fmt.Errorf("list wallet segments %s: %w", walletID, err)
+		}
+		accounts := []string{"0"}
+		for _, segment := range segments {
+			if accountStr := strings.TrimSuffix(segment, "/"); accountStr != "" && accountStr != "0" {
+				accounts = append(accounts, accountStr)
+			}
+		}
+		for _, accountStr := range accounts {
+			indices, err := s.List(ctx, storagekey.SegmentAccountsListPrefix(walletID, accountStr))
+			if err != nil {
+				return indexed, fmt.Errorf("list derived accounts %s/%s: %w", walletID, accountStr, err)
+			}
+			for _, indexStr := range indices {
+				if strings.HasSuffix(indexStr, "/") {
+					continue
+				}
+				entry, err := s.Get(ctx, storagekey.SegmentAccountKey(walletID, accountStr, indexStr))
+				if err != nil {
+					return indexed, fmt.Errorf("get derived account %s/%s/%s: %w", walletID, accountStr, indexStr, err)
+				}
+				if entry == nil {
+					continue
+				}
+				var derived model.DerivedAccount
+				if err := entry.DecodeJSON(&derived); err != nil {
+					return indexed, fmt.Errorf("decode derived account %s/%s/%s: %w", walletID, accountStr, indexStr, err)
+				}
+				written, err := addressindex.IndexAddress(ctx, s, derived.Address, model.NewWalletAddressOwner(walletID, accountStr, indexStr))
+				if err != nil {
+					return indexed, err
+				}
+				if written {
+					indexed++
+				}
+			}
+		}
+	}
+	return indexed, nil
+}
+
@@ -55,6 +55,9 @@ func walletStorageFieldData(raw map[string]interface{}) *framework.FieldData {
 	if _, ok := schema["index"]; !ok {
 		schema["index"] = &framework.FieldSchema{Type: framework.TypeString}
 	}
+	if _, ok := schema["account"]; !ok {
+		schema["account"] = &framework.FieldSchema{Type: framework.TypeString}
+	}
 	return &framework.FieldData{Raw: raw, Schema: schema}
 }
 
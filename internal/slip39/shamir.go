@@ -0,0 +1,134 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package slip39
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+)
+
+// rawShare is a Shamir share: the x coordinate and the per-byte polynomial values at x.
+type rawShare struct {
+	x     uint8
+	value []byte
+}
+
+// GF(256) exponent and logarithm tables for the Rijndael polynomial x^8+x^4+x^3+x+1 with generator 3.
+var expTable, logTable = func() ([255]byte, [256]byte) {
+	var exp [255]byte
+	var log [256]byte
+	x := byte(1)
+	for i := 0; i < 255; i++ {
+		exp[i] = x
+		log[x] = byte(i)
+		// Multiply x by the generator 3: x*2 (reduced) xor x.
+		double := x << 1
+		if x&0x80 != 0 {
+			double ^= 0x1b
+		}
+		x ^= double
+	}
+	return exp, log
+}()
+
+// interpolate evaluates at x the polynomials through shares, byte by byte (Lagrange interpolation over GF(256)).
+// Share x coordinates must be distinct.
+func interpolate(shares []rawShare, x uint8) []byte {
+	for _, s := range shares {
+		if s.x == x {
+			return append([]byte(nil), s.value...)
+		}
+	}
+	logProd := 0
+	for _, s := range shares {
+		logProd += int(logTable[s.x^x])
+	}
+	result := make([]byte, len(shares[0].value))
+	for i, si := range shares {
+		logBasis := logProd - int(logTable[si.x^x])
+		for j, sj := range shares {
+			if j != i {
+				logBasis -= int(logTable[sj.x^si.x])
+			}
+		}
+		logBasis = (logBasis%255 + 255) % 255
+		for k, v := range si.value {
+			if v != 0 {
+				result[k] ^= expTable[(int(logTable[v])+logBasis)%255]
+			}
+		}
+	}
+	return result
+}
+
+// shareDigest returns the first digestLength bytes of HMAC-SHA256(randomPart, secret).
+func shareDigest(randomPart, secret []byte) []byte {
+	mac := hmac.New(sha256.New, randomPart)
+	mac.Write(secret)
+	return mac.Sum(nil)[:digestLength]
+}
+
+// splitSecret splits secret into count shares with the given threshold. Beyond threshold-2 random shares, the
+// polynomial is pinned by the secret at x=255 and a digest share at x=254 that lets recoverSecret detect
+// wrong combinations.
+func splitSecret(threshold, count int, secret []byte) ([]rawShare, error) {
+	if threshold == 1 {
+		shares := make([]rawShare, count)
+		for i := range shares {
+			shares[i] = rawShare{x: uint8(i), value: append([]byte(nil), secret...)}
+		}
+		return shares, nil
+	}
+	randomShareCount := threshold - 2
+	shares := make([]rawShare, 0, count)
+	for i := 0; i < randomShareCount; i++ {
+		value := make([]byte, len(secret))
+		if _, err := rand.Read(value); err != nil {
+			return nil, fmt.Errorf("generate share: %w", err)
+		}
+		shares = append(shares, rawShare{x: uint8(i), value: value})
+	}
+	randomPart := make([]byte, len(secret)-digestLength)
+	if _, err := rand.Read(randomPart); err != nil {
+		return nil, fmt.Errorf("generate digest share: %w", err)
+	}
+	digest := append(shareDigest(randomPart, secret), randomPart...)
+	base := append(append([]rawShare(nil), shares...),
+		rawShare{x: digestIndex, value: digest},
+		rawShare{x: secretIndex, value: secret},
+	)
+	for i := randomShareCount; i < count; i++ {
+		shares = append(shares, rawShare{x: uint8(i), value: interpolate(base, uint8(i))})
+	}
+	return shares, nil
+}
+
+// recoverSecret interpolates the secret from threshold shares and checks the digest share.
+func recoverSecret(threshold int, shares []rawShare) ([]byte, error) {
+	if threshold == 1 {
+		return append([]byte(nil), shares[0].value...), nil
+	}
+	secret := interpolate(shares, secretIndex)
+	digest := interpolate(shares, digestIndex)
+	if !hmac.Equal(digest[:digestLength], shareDigest(digest[digestLength:], secret)) {
+		return nil, ErrInvalidDigest
+	}
+	return secret, nil
+}
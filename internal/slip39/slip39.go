@@ -0,0 +1,376 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package slip39 implements SLIP-0039 (Shamir's Secret-Sharing for Mnemonic Codes): splitting a master secret
+// into threshold-of-count share mnemonics and combining a threshold of shares back into it.
+package slip39
+
+import (
+	"bytes"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+const (
+	radixBits          = 10
+	radixWords         = 1 << radixBits
+	idBits             = 15
+	headerWords        = 4
+	checksumWords      = 3
+	digestLength       = 4
+	secretIndex        = 255
+	digestIndex        = 254
+	roundCount         = 4
+	baseIterationCount = 10000
+	maxShareCount      = 16
+	maxIterationExp    = 15
+	minSecretBytes     = 16
+	minMnemonicWords   = headerWords + (minSecretBytes*8+radixBits-1)/radixBits + checksumWords
+	customization      = "shamir"
+	customizationExt   = "shamir_extendable"
+)
+
+var (
+	// ErrInvalidMnemonic is returned for a share that is not a well-formed SLIP-39 mnemonic.
+	ErrInvalidMnemonic = errors.New("invalid slip39 share")
+	// ErrInvalidChecksum is returned when a share's RS1024 checksum does not match (typo or missing word).
+	ErrInvalidChecksum = errors.New("invalid slip39 share checksum")
+	// ErrShareMismatch is returned when shares do not belong to the same split.
+	ErrShareMismatch = errors.New("slip39 shares do not belong to the same set")
+	// ErrInsufficientShares is returned when fewer shares than the threshold were supplied.
+	ErrInsufficientShares = errors.New("not enough slip39 shares")
+	// ErrInvalidDigest is returned when the shares combine to a secret that fails the digest check.
+	ErrInvalidDigest = errors.New("slip39 shares do not combine to a valid secret")
+)
+
+// wordIndex maps each wordlist entry to its 10-bit value.
+var wordIndex = func() map[string]int {
+	m := make(map[string]int, radixWords)
+	for i, w := range wordlist {
+		m[w] = i
+	}
+	return m
+}()
+
+// Share is a decoded SLIP-39 share mnemonic. Thresholds and counts hold their real values (1-16), not the
+// stored value minus one.
+type Share struct {
+	Identifier        uint16
+	Extendable        bool
+	IterationExponent uint8
+	GroupIndex        uint8
+	GroupThreshold    uint8
+	GroupCount        uint8
+	MemberIndex       uint8
+	MemberThreshold   uint8
+	Value             []byte
+}
+
+// ParseShare decodes and checksums a share mnemonic. Words are matched case-insensitively and separated by
+// whitespace.
+func ParseShare(mnemonic string) (*Share, error) {
+	words := strings.Fields(strings.ToLower(mnemonic))
+	if len(words) < minMnemonicWords {
+		return nil, fmt.Errorf("%w: at least %d words required, got %d", ErrInvalidMnemonic, minMnemonicWords, len(words))
+	}
+	data := make([]int, len(words))
+	for i, w := range words {
+		v, ok := wordIndex[w]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown word %q", ErrInvalidMnemonic, w)
+		}
+		data[i] = v
+	}
+	valueWords := len(words) - headerWords - checksumWords
+	valueBits := radixBits * valueWords
+	if valueBits%16 > 8 {
+		return nil, fmt.Errorf("%w: invalid length %d words", ErrInvalidMnemonic, len(words))
+	}
+
+	idExp := data[0]<<radixBits | data[1]
+	s := &Share{
+		Identifier:        uint16(idExp >> (radixBits*2 - idBits)),
+		Extendable:        idExp>>4&1 == 1,
+		IterationExponent: uint8(idExp & 0xf),
+	}
+	if !verifyChecksum(s.customization(), data) {
+		return nil, ErrInvalidChecksum
+	}
+	params := data[2]<<radixBits | data[3]
+	s.GroupIndex = uint8(params >> 16 & 0xf)
+	s.GroupThreshold = uint8(params>>12&0xf) + 1
+	s.GroupCount = uint8(params>>8&0xf) + 1
+	s.MemberIndex = uint8(params >> 4 & 0xf)
+	s.MemberThreshold = uint8(params&0xf) + 1
+	if s.GroupCount < s.GroupThreshold {
+		return nil, fmt.Errorf("%w: group threshold exceeds group count", ErrInvalidMnemonic)
+	}
+
+	value := new(big.Int)
+	for _, w := range data[headerWords : len(data)-checksumWords] {
+		value.Lsh(value, radixBits).Or(value, big.NewInt(int64(w)))
+	}
+	size := valueBits / 16 * 2
+	if value.BitLen() > size*8 {
+		return nil, fmt.Errorf("%w: non-zero padding", ErrInvalidMnemonic)
+	}
+	s.Value = value.FillBytes(make([]byte, size))
+	return s, nil
+}
+
+// Mnemonic encodes the share as words, appending the RS1024 checksum.
+func (s *Share) Mnemonic() string {
+	valueWords := (len(s.Value)*8 + radixBits - 1) / radixBits
+	data := make([]int, headerWords, headerWords+valueWords+checksumWords)
+	idExp := int(s.Identifier)<<5 | int(s.IterationExponent&0xf)
+	if s.Extendable {
+		idExp |= 1 << 4
+	}
+	params := int(s.GroupIndex)<<16 | int(s.GroupThreshold-1)<<12 | int(s.GroupCount-1)<<8 |
+		int(s.MemberIndex)<<4 | int(s.MemberThreshold-1)
+	data[0], data[1] = idExp>>radixBits, idExp&(radixWords-1)
+	data[2], data[3] = params>>radixBits, params&(radixWords-1)
+
+	value := new(big.Int).SetBytes(s.Value)
+	mask := big.NewInt(radixWords - 1)
+	valueData := make([]int, valueWords)
+	for i := valueWords - 1; i >= 0; i-- {
+		valueData[i] = int(new(big.Int).And(value, mask).Int64())
+		value.Rsh(value, radixBits)
+	}
+	data = append(data, valueData...)
+	data = append(data, createChecksum(s.customization(), data)...)
+
+	words := make([]string, len(data))
+	for i, v := range data {
+		words[i] = wordlist[v]
+	}
+	return strings.Join(words, " ")
+}
+
+// customization returns the RS1024 customization string for the share's extendable flag.
+func (s *Share) customization() string {
+	if s.Extendable {
+		return customizationExt
+	}
+	return customization
+}
+
+// Split encrypts secret with passphrase and splits it into count share mnemonics in a single group, any
+// threshold of which recover it. secret must be at least 16 bytes and of even length (BIP-39 entropy
+// qualifies); passphrase must be printable ASCII. iterationExponent raises the PBKDF2 cost to 10000<<e.
+func Split(secret, passphrase []byte, threshold, count int, iterationExponent uint8) ([]string, error) {
+	if len(secret) < minSecretBytes || len(secret)%2 != 0 {
+		return nil, fmt.Errorf("secret must be at least %d bytes and of even length, got %d", minSecretBytes, len(secret))
+	}
+	if threshold < 1 || count > maxShareCount || threshold > count {
+		return nil, fmt.Errorf("threshold must be between 1 and count, and count at most %d", maxShareCount)
+	}
+	if threshold == 1 && count > 1 {
+		return nil, fmt.Errorf("threshold 1 with more than one share is not allowed; use 1-of-1")
+	}
+	if iterationExponent > maxIterationExp {
+		return nil, fmt.Errorf("iteration exponent must be at most %d", maxIterationExp)
+	}
+	if err := validatePassphrase(passphrase); err != nil {
+		return nil, err
+	}
+	var idBytes [2]byte
+	if _, err := rand.Read(idBytes[:]); err != nil {
+		return nil, fmt.Errorf("generate identifier: %w", err)
+	}
+	id := (uint16(idBytes[0])<<8 | uint16(idBytes[1])) & (1<<idBits - 1)
+
+	encrypted, err := feistel(secret, passphrase, iterationExponent, id, false, false)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := splitSecret(threshold, count, encrypted)
+	if err != nil {
+		return nil, err
+	}
+	mnemonics := make([]string, len(raw))
+	for i, r := range raw {
+		mnemonics[i] = (&Share{
+			Identifier:        id,
+			IterationExponent: iterationExponent,
+			GroupThreshold:    1,
+			GroupCount:        1,
+			MemberIndex:       r.x,
+			MemberThreshold:   uint8(threshold),
+			Value:             r.value,
+		}).Mnemonic()
+	}
+	return mnemonics, nil
+}
+
+// Combine recovers the master secret from share mnemonics and the passphrase used at split time. Shares may
+// span several groups; surplus shares beyond each threshold are ignored. A wrong passphrase yields a different
+// secret, not an error, as SLIP-39 specifies.
+func Combine(mnemonics []string, passphrase []byte) ([]byte, error) {
+	if err := validatePassphrase(passphrase); err != nil {
+		return nil, err
+	}
+	if len(mnemonics) == 0 {
+		return nil, ErrInsufficientShares
+	}
+	shares := make([]*Share, len(mnemonics))
+	for i, m := range mnemonics {
+		s, err := ParseShare(m)
+		if err != nil {
+			return nil, err
+		}
+		shares[i] = s
+	}
+	first := shares[0]
+	groups := make(map[uint8][]*Share)
+	for _, s := range shares {
+		if s.Identifier != first.Identifier || s.Extendable != first.Extendable ||
+			s.IterationExponent != first.IterationExponent || s.GroupThreshold != first.GroupThreshold ||
+			s.GroupCount != first.GroupCount || len(s.Value) != len(first.Value) {
+			return nil, ErrShareMismatch
+		}
+		duplicate := false
+		for _, m := range groups[s.GroupIndex] {
+			if m.MemberThreshold != s.MemberThreshold {
+				return nil, fmt.Errorf("%w: member thresholds differ within group %d", ErrShareMismatch, s.GroupIndex)
+			}
+			if m.MemberIndex == s.MemberIndex {
+				if !bytes.Equal(m.Value, s.Value) {
+					return nil, fmt.Errorf("%w: conflicting shares for member %d", ErrShareMismatch, s.MemberIndex)
+				}
+				duplicate = true
+			}
+		}
+		if !duplicate {
+			groups[s.GroupIndex] = append(groups[s.GroupIndex], s)
+		}
+	}
+
+	var groupShares []rawShare
+	for index := uint8(0); index < maxShareCount && len(groupShares) < int(first.GroupThreshold); index++ {
+		members := groups[index]
+		if len(members) == 0 || len(members) < int(members[0].MemberThreshold) {
+			continue
+		}
+		raw := make([]rawShare, members[0].MemberThreshold)
+		for i := range raw {
+			raw[i] = rawShare{x: members[i].MemberIndex, value: members[i].Value}
+		}
+		value, err := recoverSecret(len(raw), raw)
+		if err != nil {
+			return nil, err
+		}
+		groupShares = append(groupShares, rawShare{x: index, value: value})
+	}
+	if len(groupShares) < int(first.GroupThreshold) {
+		return nil, ErrInsufficientShares
+	}
+	encrypted, err := recoverSecret(len(groupShares), groupShares)
+	if err != nil {
+		return nil, err
+	}
+	return feistel(encrypted, passphrase, first.IterationExponent, first.Identifier, first.Extendable, true)
+}
+
+// validatePassphrase rejects passphrases with characters outside printable ASCII.
+func validatePassphrase(passphrase []byte) error {
+	for _, c := range passphrase {
+		if c < 32 || c > 126 {
+			return fmt.Errorf("passphrase must contain only printable ASCII characters")
+		}
+	}
+	return nil
+}
+
+// feistel runs the four-round Feistel network SLIP-39 uses to encrypt the master secret (decrypt reverses the
+// round order). Each round function is PBKDF2-HMAC-SHA256 keyed by the round number and passphrase.
+func feistel(input, passphrase []byte, iterationExponent uint8, id uint16, extendable, decrypt bool) ([]byte, error) {
+	half := len(input) / 2
+	l := append([]byte(nil), input[:half]...)
+	r := append([]byte(nil), input[half:]...)
+	var saltPrefix []byte
+	if !extendable {
+		saltPrefix = append([]byte(customization), byte(id>>8), byte(id))
+	}
+	iterations := (baseIterationCount / roundCount) << iterationExponent
+	for i := 0; i < roundCount; i++ {
+		round := byte(i)
+		if decrypt {
+			round = byte(roundCount - 1 - i)
+		}
+		password := append([]byte{round}, passphrase...)
+		salt := append(append([]byte(nil), saltPrefix...), r...)
+		f, err := pbkdf2.Key(sha256.New, string(password), salt, iterations, half)
+		if err != nil {
+			return nil, fmt.Errorf("slip39 round function: %w", err)
+		}
+		for j := range f {
+			f[j] ^= l[j]
+		}
+		l, r = r, f
+	}
+	return append(r, l...), nil
+}
+
+// rs1024Polymod is the SLIP-39 RS1024 checksum polynomial over GF(1024).
+func rs1024Polymod(values []int) int {
+	gen := [10]int{
+		0xe0e040, 0x1c1c080, 0x3838100, 0x7070200, 0xe0e0009,
+		0x1c0c2412, 0x38086c24, 0x3090fc48, 0x21b1f890, 0x3f3f120,
+	}
+	chk := 1
+	for _, v := range values {
+		b := chk >> 20
+		chk = (chk&0xfffff)<<10 ^ v
+		for i := 0; i < 10; i++ {
+			if (b>>i)&1 == 1 {
+				chk ^= gen[i]
+			}
+		}
+	}
+	return chk
+}
+
+// checksumInput prefixes data with the customization string bytes.
+func checksumInput(cs string, data []int) []int {
+	values := make([]int, 0, len(cs)+len(data)+checksumWords)
+	for i := 0; i < len(cs); i++ {
+		values = append(values, int(cs[i]))
+	}
+	return append(values, data...)
+}
+
+// createChecksum returns the three checksum words for data.
+func createChecksum(cs string, data []int) []int {
+	polymod := rs1024Polymod(append(checksumInput(cs, data), 0, 0, 0)) ^ 1
+	out := make([]int, checksumWords)
+	for i := range out {
+		out[i] = polymod >> (radixBits * (checksumWords - 1 - i)) & (radixWords - 1)
+	}
+	return out
+}
+
+// verifyChecksum reports whether data (including its trailing checksum words) is valid.
+func verifyChecksum(cs string, data []int) bool {
+	return rs1024Polymod(checksumInput(cs, data)) == 1
+}
@@ -0,0 +1,168 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package slip39
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+// Test vectors from the SLIP-0039 reference implementation (passphrase "TREZOR").
+const (
+	vectorSingle = "duckling enlarge academic academic agency result length solution fridge kidney coal piece deal " +
+		"husband erode duke ajar critical decision keyboard"
+	vectorShareA = "shadow pistol academic always adequate wildlife fancy gross oasis cylinder mustang wrist rescue " +
+		"view short owner flip making coding armed"
+	vectorShareB = "shadow pistol academic acid actress prayer class unknown daughter sweater depict flip twice " +
+		"unkind craft early superior advocate guest smoking"
+)
+
+// TestCombine_referenceVectors verifies 1-of-1 and 2-of-3 reference shares decode to their master secrets.
+func TestCombine_referenceVectors(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name   string
+		shares []string
+		want   string
+	}{
+		{"single", []string{vectorSingle}, "bb54aac4b89dc868ba37d9cc21b2cece"},
+		{"two of three", []string{vectorShareA, vectorShareB}, "b43ceb7e57a0ea8766221624d01b0864"},
+	}
+	for _, tc := range cases {
+		got, err := Combine(tc.shares, []byte("TREZOR"))
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if hex.EncodeToString(got) != tc.want {
+			t.Fatalf("%s: secret=%x want %s.", tc.name, got, tc.want)
+		}
+	}
+}
+
+// TestParseShare_roundTrip verifies decoding a reference share and re-encoding it yields the same words.
+func TestParseShare_roundTrip(t *testing.T) {
+	t.Parallel()
+
+	s, err := ParseShare(vectorShareA)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.MemberThreshold != 2 || s.GroupThreshold != 1 || s.GroupCount != 1 {
+		t.Fatalf("threshold=%d group=%d/%d want 2 and 1/1.", s.MemberThreshold, s.GroupThreshold, s.GroupCount)
+	}
+	if got := s.Mnemonic(); got != vectorShareA {
+		t.Fatalf("mnemonic=%q want %q.", got, vectorShareA)
+	}
+}
+
+// TestParseShare_invalid verifies checksum errors, unknown words and short mnemonics are rejected.
+func TestParseShare_invalid(t *testing.T) {
+	t.Parallel()
+
+	badChecksum := vectorSingle[:len(vectorSingle)-len("keyboard")] + "kidney"
+	if _, err := ParseShare(badChecksum); !errors.Is(err, ErrInvalidChecksum) {
+		t.Fatalf("err=%v want ErrInvalidChecksum.", err)
+	}
+	if _, err := ParseShare(vectorSingle[:len(vectorSingle)-len("keyboard")] + "keyboardx"); !errors.Is(err, ErrInvalidMnemonic) {
+		t.Fatalf("err=%v want ErrInvalidMnemonic for unknown word.", err)
+	}
+	if _, err := ParseShare("duckling enlarge academic"); !errors.Is(err, ErrInvalidMnemonic) {
+		t.Fatalf("err=%v want ErrInvalidMnemonic for short share.", err)
+	}
+}
+
+// TestSplitCombine_thresholdSubsets verifies every threshold subset recovers the secret and fewer shares do not.
+func TestSplitCombine_thresholdSubsets(t *testing.T) {
+	t.Parallel()
+
+	secret, _ := hex.DecodeString("0c1e24e5917779d297e14d45f14e1a1a0c1e24e5917779d297e14d45f14e1a1a")
+	shares, err := Split(secret, nil, 3, 5, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(shares) != 5 {
+		t.Fatalf("shares=%d want 5.", len(shares))
+	}
+	subsets := [][]int{{0, 1, 2}, {4, 2, 0}, {1, 3, 4}, {0, 1, 2, 3, 4}}
+	for _, subset := range subsets {
+		var picked []string
+		for _, i := range subset {
+			picked = append(picked, shares[i])
+		}
+		got, err := Combine(picked, nil)
+		if err != nil {
+			t.Fatalf("subset %v: %v", subset, err)
+		}
+		if !bytes.Equal(got, secret) {
+			t.Fatalf("subset %v: secret=%x want %x.", subset, got, secret)
+		}
+	}
+	if _, err := Combine(shares[:2], nil); !errors.Is(err, ErrInsufficientShares) {
+		t.Fatalf("err=%v want ErrInsufficientShares.", err)
+	}
+	if _, err := Combine([]string{shares[0], shares[0], shares[1]}, nil); !errors.Is(err, ErrInsufficientShares) {
+		t.Fatalf("err=%v want duplicate share not to count twice.", err)
+	}
+}
+
+// TestCombine_mixedSets verifies shares from different splits are rejected.
+func TestCombine_mixedSets(t *testing.T) {
+	t.Parallel()
+
+	secret := bytes.Repeat([]byte{0x42}, 16)
+	a, err := Split(secret, nil, 2, 2, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := Split(secret, nil, 2, 2, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Combine([]string{a[0], b[1]}, nil); err == nil {
+		t.Fatal("expected error combining shares of different splits.")
+	}
+}
+
+// TestSplit_invalidParameters verifies Split rejects bad thresholds, secret sizes and passphrases.
+func TestSplit_invalidParameters(t *testing.T) {
+	t.Parallel()
+
+	secret := bytes.Repeat([]byte{0x01}, 16)
+	cases := []struct {
+		name       string
+		secret     []byte
+		passphrase []byte
+		threshold  int
+		count      int
+	}{
+		{"threshold above count", secret, nil, 3, 2},
+		{"too many shares", secret, nil, 2, 17},
+		{"one of many", secret, nil, 1, 3},
+		{"short secret", secret[:14], nil, 2, 3},
+		{"odd secret", append(secret, 0x01), nil, 2, 3},
+		{"non-ascii passphrase", secret, []byte("pässword"), 2, 3},
+	}
+	for _, tc := range cases {
+		if _, err := Split(tc.secret, tc.passphrase, tc.threshold, tc.count, 0); err == nil {
+			t.Fatalf("%s: expected error.", tc.name)
+		}
+	}
+}
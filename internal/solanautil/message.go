@@ -0,0 +1,127 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package solanautil
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// messageVersionPrefix marks a versioned (v0) message; legacy messages start with the signer count instead,
+// which is below 128.
+const messageVersionPrefix = 0x80
+
+// Message holds the parts of a serialized Solana transaction message needed to sign it.
+type Message struct {
+	// Version is "legacy" or "v0".
+	Version string
+	// Signers are the public keys whose signatures the transaction requires, in signature order.
+	Signers []ed25519.PublicKey
+}
+
+// ParseMessage reads the header and static account keys of a serialized legacy or v0 transaction message.
+// The instructions and, for v0, the address table lookups are not interpreted.
+func ParseMessage(msg []byte) (*Message, error) {
+	r := bytes.NewReader(msg)
+	out := &Message{Version: "legacy"}
+	if len(msg) > 0 && msg[0]&messageVersionPrefix != 0 {
+		if v := msg[0] &^ messageVersionPrefix; v != 0 {
+			return nil, fmt.Errorf("message version %d is not supported", v)
+		}
+		out.Version = "v0"
+		r.Reset(msg[1:])
+	}
+	// Header: required signatures, read-only signed accounts, read-only unsigned accounts.
+	var header [3]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, errors.New("message header is truncated")
+	}
+	numSigners := header[0]
+	numKeys, err := readCompactU16(r)
+	if err != nil {
+		return nil, fmt.Errorf("account keys: %w", err)
+	}
+	if numSigners == 0 || int(numSigners) > numKeys {
+		return nil, fmt.Errorf("message requires %d signatures but has %d account keys", numSigners, numKeys)
+	}
+	if r.Len() < numKeys*ed25519.PublicKeySize {
+		return nil, errors.New("account keys are truncated")
+	}
+	for i := 0; i < int(numSigners); i++ {
+		key := make(ed25519.PublicKey, ed25519.PublicKeySize)
+		if _, err := io.ReadFull(r, key); err != nil {
+			return nil, errors.New("account keys are truncated")
+		}
+		out.Signers = append(out.Signers, key)
+	}
+	return out, nil
+}
+
+// SignerIndex returns the position of pub among the message's required signers, or -1.
+func (m *Message) SignerIndex(pub ed25519.PublicKey) int {
+	for i, s := range m.Signers {
+		if s.Equal(pub) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Transaction serializes a transaction from msg and one signature per required signer, in the wire format
+// accepted by sendTransaction.
+func Transaction(msg []byte, signatures [][]byte) []byte {
+	out := appendCompactU16(nil, len(signatures))
+	for _, sig := range signatures {
+		out = append(out, sig...)
+	}
+	return append(out, msg...)
+}
+
+// readCompactU16 reads Solana's compact-u16 (shortvec) length encoding.
+func readCompactU16(r *bytes.Reader) (int, error) {
+	var v int
+	for i := 0; i < 3; i++ {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, errors.New("compact-u16 is truncated")
+		}
+		v |= int(b&0x7f) << (7 * i)
+		if b&0x80 == 0 {
+			if v > 0xffff {
+				return 0, errors.New("compact-u16 overflows")
+			}
+			return v, nil
+		}
+	}
+	return 0, errors.New("compact-u16 is too long")
+}
+
+// appendCompactU16 appends n in Solana's compact-u16 (shortvec) encoding.
+func appendCompactU16(b []byte, n int) []byte {
+	for {
+		c := byte(n & 0x7f)
+		n >>= 7
+		if n == 0 {
+			return append(b, c)
+		}
+		b = append(b, c|0x80)
+	}
+}
@@ -0,0 +1,110 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package solanautil
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"testing"
+)
+
+// testMessage builds a message header and account key list with numSigners required signers; the
+// instructions are an opaque tail.
+func testMessage(versioned bool, numSigners byte, keys ...ed25519.PublicKey) []byte {
+	var msg []byte
+	if versioned {
+		msg = append(msg, messageVersionPrefix)
+	}
+	msg = append(msg, numSigners, 0, 1)
+	msg = appendCompactU16(msg, len(keys))
+	for _, k := range keys {
+		msg = append(msg, k...)
+	}
+	return append(msg, bytes.Repeat([]byte{0xab}, 40)...)
+}
+
+// testPublicKey returns a deterministic public key whose seed is b repeated.
+func testPublicKey(b byte) ed25519.PublicKey {
+	return ed25519.NewKeyFromSeed(bytes.Repeat([]byte{b}, ed25519.SeedSize)).Public().(ed25519.PublicKey)
+}
+
+// TestParseMessage reads the required signers of legacy and v0 messages.
+func TestParseMessage(t *testing.T) {
+	t.Parallel()
+	a, b, c := testPublicKey(1), testPublicKey(2), testPublicKey(3)
+	for _, versioned := range []bool{false, true} {
+		m, err := ParseMessage(testMessage(versioned, 2, a, b, c))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := "legacy"
+		if versioned {
+			want = "v0"
+		}
+		if m.Version != want || len(m.Signers) != 2 {
+			t.Fatalf("got version %s with %d signers, want %s with 2", m.Version, len(m.Signers), want)
+		}
+		if m.SignerIndex(b) != 1 || m.SignerIndex(c) != -1 {
+			t.Fatalf("signer index: got %d %d", m.SignerIndex(b), m.SignerIndex(c))
+		}
+	}
+}
+
+// TestParseMessage_rejectsMalformed rejects truncated headers and keys, unknown versions and signer counts
+// larger than the key list.
+func TestParseMessage_rejectsMalformed(t *testing.T) {
+	t.Parallel()
+	a := testPublicKey(1)
+	full := testMessage(false, 1, a)
+	for name, msg := range map[string][]byte{
+		"empty":        nil,
+		"header":       {1, 0},
+		"keys":         full[:20],
+		"version":      append([]byte{0x81}, full...),
+		"signers":      testMessage(false, 2, a),
+		"zero signers": testMessage(false, 0, a),
+		"compact-u16":  {1, 0, 0, 0xff, 0xff, 0xff},
+	} {
+		if _, err := ParseMessage(msg); err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+	}
+}
+
+// TestCompactU16 round-trips lengths across the one, two and three byte encodings.
+func TestCompactU16(t *testing.T) {
+	t.Parallel()
+	for _, n := range []int{0, 1, 127, 128, 16383, 16384, 0xffff} {
+		enc := appendCompactU16(nil, n)
+		got, err := readCompactU16(bytes.NewReader(enc))
+		if err != nil || got != n {
+			t.Fatalf("%d: got %d err %v (encoded %x)", n, got, err, enc)
+		}
+	}
+}
+
+// TestTransaction prefixes the message with the signature count and signatures.
+func TestTransaction(t *testing.T) {
+	t.Parallel()
+	msg := []byte{1, 2, 3}
+	sig := bytes.Repeat([]byte{9}, ed25519.SignatureSize)
+	tx := Transaction(msg, [][]byte{sig})
+	if tx[0] != 1 || !bytes.Equal(tx[1:65], sig) || !bytes.Equal(tx[65:], msg) {
+		t.Fatalf("got %x", tx)
+	}
+}
@@ -37,6 +37,13 @@ func ZeroKey(k *ecdsa.PrivateKey) {
 	k.D.SetInt64(0)
 }
 
+// ZeroBytes overwrites b with zeros, e.g. a serialized secret once it has been encrypted or parsed.
+func ZeroBytes(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
+
 // ValidNumber returns a valid positive integer
 func ValidNumber(input string) *big.Int {
 	if input == "" {
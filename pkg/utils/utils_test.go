@@ -53,6 +53,18 @@ func TestZeroKey_nilD(t *testing.T) {
 	ZeroKey(&pk)
 }
 
+// TestZeroBytes verifies every byte is cleared.
+func TestZeroBytes(t *testing.T) {
+	t.Parallel()
+	b := []byte("secret")
+	ZeroBytes(b)
+	for i, v := range b {
+		if v != 0 {
+			t.Fatalf("b[%d]=%d want 0.", i, v)
+		}
+	}
+}
+
 func TestValidNumber(t *testing.T) {
 	t.Parallel()
 	t.Run("empty", func(t *testing.T) {